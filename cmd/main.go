@@ -1,18 +1,35 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	grpc2 "github.com/dimuska139/cacher/internal/api/grpc"
 	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
-	"github.com/dimuska139/cacher/internal/cache/embedded"
+	memcached2 "github.com/dimuska139/cacher/internal/api/memcached"
+	resp2 "github.com/dimuska139/cacher/internal/api/resp"
+	rest2 "github.com/dimuska139/cacher/internal/api/rest"
+	embedded2 "github.com/dimuska139/cacher/internal/cache/embedded"
 	memcache2 "github.com/dimuska139/cacher/internal/cache/memcache"
+	redis2 "github.com/dimuska139/cacher/internal/cache/redis"
+	metrics2 "github.com/dimuska139/cacher/internal/metrics"
 	"github.com/dimuska139/cacher/pkg/config"
+	"github.com/dimuska139/cacher/pkg/embedded"
 	"github.com/dimuska139/cacher/pkg/logging"
 	"github.com/dimuska139/cacher/pkg/memcache"
+	"github.com/dimuska139/cacher/pkg/memcached"
+	"github.com/dimuska139/cacher/pkg/metrics"
+	"github.com/dimuska139/cacher/pkg/redis"
+	"github.com/dimuska139/cacher/pkg/resp"
+	"github.com/dimuska139/cacher/pkg/rest"
+	"github.com/dimuska139/cacher/pkg/tiered"
 	"github.com/urfave/cli/v2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -46,21 +63,96 @@ func main() {
 				return err
 			}
 
-			grpcServer := grpc.NewServer()
+			registry := metrics2.NewRegistry()
+			grpcMetrics := metrics2.NewGRPCMetrics()
+			registry.Register(grpcMetrics)
 
-			if cfg.Storage == "memcache" {
+			grpcServer := grpc.NewServer(
+				grpc.ChainUnaryInterceptor(grpcMetrics.UnaryServerInterceptor()),
+				grpc.ChainStreamInterceptor(grpcMetrics.StreamServerInterceptor()),
+			)
+			healthServer := health.NewServer()
+			healthpb.RegisterHealthServer(grpcServer, healthServer)
+			var (
+				snapshotter   *embedded2.Snapshotter
+				appendOnlyLog *embedded2.AppendOnlyLog
+				healthChecker grpc2.HealthChecker
+				storage       grpc2.Storage
+			)
+
+			switch cfg.Storage {
+			case "memcache":
+				memcacheClient, err := memcache.NewClient(cfg)
+				if err != nil {
+					return fmt.Errorf("can't initialize memcache client: %w", err)
+				}
+				memcacheStorage := memcache2.NewMemcacheStorage(memcacheClient)
+				if err := memcache.CheckVersion(memcacheStorage, logger); err != nil {
+					return fmt.Errorf("can't use memcache: %w", err)
+				}
+				registry.Register(
+					metrics2.NewStorageCollector(memcacheStorage, logger, 0),
+					metrics2.NewPoolCollector(memcacheClient),
+				)
+				healthChecker = memcacheStorage
+				storage = memcacheStorage
+			case "tiered":
 				memcacheClient, err := memcache.NewClient(cfg)
 				if err != nil {
 					return fmt.Errorf("can't initialize memcache client: %w", err)
 				}
-				v1.RegisterCacheAPIServer(grpcServer,
-					grpc2.NewCacheServer(logger, memcache2.NewMemcacheStorage(memcacheClient)))
-			} else {
-				v1.RegisterCacheAPIServer(grpcServer,
-					grpc2.NewCacheServer(logger, embedded.NewEmbeddedStorage(time.Millisecond*50)))
+				memcacheStorage := memcache2.NewMemcacheStorage(memcacheClient)
+				if err := memcache.CheckVersion(memcacheStorage, logger); err != nil {
+					return fmt.Errorf("can't use memcache: %w", err)
+				}
+				tieredStorage, err := tiered.NewStorage(cfg, memcacheStorage)
+				if err != nil {
+					return fmt.Errorf("can't initialize tiered storage: %w", err)
+				}
+				registry.Register(
+					metrics2.NewStorageCollector(tieredStorage, logger, 0),
+					metrics2.NewPoolCollector(memcacheClient),
+				)
+				healthChecker = tieredStorage
+				storage = tieredStorage
+			case "redis":
+				redisClient, err := redis.NewClient(cfg)
+				if err != nil {
+					return fmt.Errorf("can't initialize redis client: %w", err)
+				}
+				redisStorage := redis2.NewRedisStorage(redisClient)
+				registry.Register(metrics2.NewStorageCollector(redisStorage, logger, 0))
+				healthChecker = redisStorage
+				storage = redisStorage
+			default:
+				embeddedStorage, err := embedded.NewStorage(cfg)
+				if err != nil {
+					return fmt.Errorf("can't initialize embedded storage: %w", err)
+				}
+				registry.Register(metrics2.NewStorageCollector(embeddedStorage, logger, 0))
+				healthChecker = embeddedStorage
+				storage = embeddedStorage
+
+				snapshotter, err = embedded.NewSnapshotter(cfg, embeddedStorage, logger)
+				if err != nil {
+					return fmt.Errorf("can't initialize snapshots: %w", err)
+				}
+				if snapshotter != nil {
+					snapshotter.Start()
+				}
+
+				appendOnlyLog, err = embedded.NewAppendOnlyLog(cfg, embeddedStorage, logger)
+				if err != nil {
+					return fmt.Errorf("can't initialize append-only log: %w", err)
+				}
 			}
+			cacheServer := grpc2.NewCacheServer(logger, storage)
+			v1.RegisterCacheAPIServer(grpcServer, cacheServer)
 			reflection.Register(grpcServer)
 
+			healthMonitor := grpc2.NewHealthMonitor(healthServer, healthChecker, logger, cfg.HealthCheckInterval)
+			healthMonitor.Start()
+
 			go func(conf *config.Config) {
 				lis, err := net.Listen("tcp", fmt.Sprintf(":%d", conf.GrpcPort))
 				if err != nil {
@@ -72,19 +164,110 @@ func main() {
 				}
 			}(cfg)
 
-			stopSignal := make(chan os.Signal)
+			memcachedServer := memcached.NewServer(cfg, storage, logger)
+			if memcachedServer != nil {
+				go func() {
+					lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.MemcachedPort))
+					if err != nil {
+						logger.Fatalf("failed to listen: %v", err)
+					}
+
+					if err := memcachedServer.Serve(lis); err != nil && !errors.Is(err, memcached2.ErrServerClosed) {
+						logger.Fatalf("failed to serve memcached protocol: %v", err)
+					}
+				}()
+				logger.Info(fmt.Sprintf("%s started at 127.0.0.1:%d (memcached)", applicationName, cfg.MemcachedPort))
+			}
+
+			respServer := resp.NewServer(cfg, storage, logger)
+			if respServer != nil {
+				go func() {
+					lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.RedisPort))
+					if err != nil {
+						logger.Fatalf("failed to listen: %v", err)
+					}
+
+					if err := respServer.Serve(lis); err != nil && !errors.Is(err, resp2.ErrServerClosed) {
+						logger.Fatalf("failed to serve redis protocol: %v", err)
+					}
+				}()
+				logger.Info(fmt.Sprintf("%s started at 127.0.0.1:%d (redis)", applicationName, cfg.RedisPort))
+			}
+
+			restServer := rest.NewServer(cfg, rest2.NewGateway(cacheServer))
+			if restServer != nil {
+				go func() {
+					if err := restServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						logger.Fatalf("failed to serve http: %v", err)
+					}
+				}()
+				logger.Info(fmt.Sprintf("%s started at %s (http)", applicationName, cfg.HTTPAddress))
+			}
+
+			metricsServer := metrics.NewServer(cfg, registry)
+			if metricsServer != nil {
+				go func() {
+					if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						logger.Fatalf("failed to serve metrics: %v", err)
+					}
+				}()
+				logger.Info(fmt.Sprintf("%s metrics are available at 127.0.0.1:%d%s",
+					applicationName, cfg.MetricsPort, metrics.Path))
+			}
+
+			stopSignal := make(chan os.Signal, 1)
 			signal.Notify(stopSignal, syscall.SIGTERM)
 			signal.Notify(stopSignal, syscall.SIGINT)
 			signal.Notify(stopSignal, syscall.SIGKILL)
 
-			reloadSignal := make(chan os.Signal)
+			reloadSignal := make(chan os.Signal, 1)
 			signal.Notify(reloadSignal, syscall.SIGUSR1)
 			logger.Info(fmt.Sprintf("%s started at 127.0.0.1:%d (grpc)", applicationName, cfg.GrpcPort))
 			for {
 				select {
 				case <-stopSignal:
 					logger.Info(fmt.Sprintf("%s shutdown started...", applicationName))
-					grpcServer.GracefulStop()
+					healthMonitor.Shutdown()
+					cacheServer.Shutdown()
+					grpc2.GracefulStop(grpcServer, grpc2.DefaultStopTimeout)
+					if memcachedServer != nil {
+						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+						if err := memcachedServer.Shutdown(ctx); err != nil {
+							logger.Error("Can't stop memcached server", "err", err)
+						}
+						cancel()
+					}
+					if respServer != nil {
+						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+						if err := respServer.Shutdown(ctx); err != nil {
+							logger.Error("Can't stop redis server", "err", err)
+						}
+						cancel()
+					}
+					if restServer != nil {
+						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+						if err := restServer.Shutdown(ctx); err != nil {
+							logger.Error("Can't stop http server", "err", err)
+						}
+						cancel()
+					}
+					if metricsServer != nil {
+						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+						if err := metricsServer.Shutdown(ctx); err != nil {
+							logger.Error("Can't stop metrics server", "err", err)
+						}
+						cancel()
+					}
+					if snapshotter != nil {
+						if err := snapshotter.Stop(); err != nil {
+							logger.Error("Can't save snapshot", "err", err)
+						}
+					}
+					if appendOnlyLog != nil {
+						if err := appendOnlyLog.Close(); err != nil {
+							logger.Error("Can't close append-only log", "err", err)
+						}
+					}
 					logger.Info(fmt.Sprintf("%s shutdown finished", applicationName))
 					os.Exit(0)
 
@@ -92,7 +275,6 @@ func main() {
 					break
 				}
 			}
-			return nil
 		},
 	}
 
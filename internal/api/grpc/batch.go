@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"github.com/dimuska139/cacher/internal/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// MaxBatchSize максимальное количество ключей в одном пакетном запросе
+const MaxBatchSize = 1000
+
+// checkBatchSize проверяет количество ключей в пакетном запросе
+func checkBatchSize(size int) error {
+	if size > MaxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "too many keys in batch: %d (max %d)", size, MaxBatchSize)
+	}
+	return nil
+}
+
+// keyError преобразует ошибку хранилища при обработке одного ключа пакетного запроса в ошибку ответа.
+// Непредвиденные ошибки логируются с сообщением msg
+func (s *CacheServer) keyError(err error, msg string, key string) *v1.KeyError {
+	if err == nil {
+		return nil
+	}
+
+	var st *status.Status
+	switch {
+	case errors.Is(err, context.Canceled):
+		st = status.New(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		st = status.New(codes.DeadlineExceeded, "deadline exceeded")
+	case errors.Is(err, cache.ErrNotFound):
+		st = status.New(codes.NotFound, "key not found")
+	case errors.Is(err, cache.ErrTooLarge):
+		st = status.New(codes.InvalidArgument, "value is too large")
+	default:
+		s.logger.Error(msg,
+			"err", err,
+			"key", key)
+		st = status.New(codes.Internal, "something went wrong")
+	}
+
+	return &v1.KeyError{
+		Code:    uint32(st.Code()),
+		Message: st.Message(),
+	}
+}
+
+// MultiGet возвращает данные по нескольким ключам. Результаты возвращаются в том же порядке, что и ключи.
+// Как и в Get, отсутствие записи не является ошибкой: в этом случае в результате found = false
+func (s *CacheServer) MultiGet(ctx context.Context, request *v1.MultiGetRequest) (*v1.MultiGetResponse, error) {
+	keys := request.GetKeys()
+	if err := checkBatchSize(len(keys)); err != nil {
+		return nil, err
+	}
+
+	var results []cache.GetResult
+	if batchStorage, ok := s.storage.(BatchStorage); ok {
+		results = batchStorage.GetBatch(ctx, keys)
+	} else {
+		results = make([]cache.GetResult, len(keys))
+		for i, key := range keys {
+			results[i].Item, results[i].Err = s.storage.Get(ctx, key)
+		}
+	}
+
+	if st := contextError(ctx.Err()); st != nil {
+		return nil, st
+	}
+
+	response := &v1.MultiGetResponse{
+		Results: make([]*v1.MultiGetResult, len(keys)),
+	}
+	for i, key := range keys {
+		result := &v1.MultiGetResult{Key: key}
+		switch item, err := results[i].Item, results[i].Err; {
+		case errors.Is(err, cache.ErrNotFound):
+		case err != nil:
+			result.Error = s.keyError(err, "Can't get data from storage", key)
+		default:
+			result.Value = item.Value
+			result.Found = true
+			result.Flags = item.Flags
+			result.Cas = item.CasID
+			result.Ttl = ttlSeconds(item.TTL)
+		}
+		response.Results[i] = result
+	}
+
+	return response, nil
+}
+
+// MultiSet записывает несколько записей. Результаты возвращаются в том же порядке, что и записи
+func (s *CacheServer) MultiSet(ctx context.Context, request *v1.MultiSetRequest) (*v1.MultiSetResponse, error) {
+	items := request.GetItems()
+	if err := checkBatchSize(len(items)); err != nil {
+		return nil, err
+	}
+
+	entries := make([]cache.Entry, len(items))
+	for i, item := range items {
+		entries[i] = cache.Entry{
+			Key:   item.GetKey(),
+			Value: item.GetValue(),
+			TTL:   time.Second * time.Duration(item.GetTtl()),
+		}
+	}
+
+	var errs []error
+	if batchStorage, ok := s.storage.(BatchStorage); ok {
+		errs = batchStorage.SetBatch(ctx, entries)
+	} else {
+		errs = make([]error, len(entries))
+		for i, entry := range entries {
+			errs[i] = s.storage.Set(ctx, entry.Key, entry.Value, entry.TTL)
+		}
+	}
+
+	if st := contextError(ctx.Err()); st != nil {
+		return nil, st
+	}
+
+	response := &v1.MultiSetResponse{
+		Results: make([]*v1.KeyResult, len(entries)),
+	}
+	for i, entry := range entries {
+		response.Results[i] = &v1.KeyResult{
+			Key:   entry.Key,
+			Error: s.keyError(errs[i], "Can't save data to storage", entry.Key),
+		}
+	}
+
+	return response, nil
+}
+
+// MultiDelete удаляет данные по нескольким ключам. Результаты возвращаются в том же порядке, что и ключи
+func (s *CacheServer) MultiDelete(ctx context.Context, request *v1.MultiDeleteRequest) (*v1.MultiDeleteResponse, error) {
+	keys := request.GetKeys()
+	if err := checkBatchSize(len(keys)); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	if batchStorage, ok := s.storage.(BatchStorage); ok {
+		errs = batchStorage.DeleteBatch(ctx, keys)
+	} else {
+		errs = make([]error, len(keys))
+		for i, key := range keys {
+			errs[i] = s.storage.Delete(ctx, key)
+		}
+	}
+
+	if st := contextError(ctx.Err()); st != nil {
+		return nil, st
+	}
+
+	response := &v1.MultiDeleteResponse{
+		Results: make([]*v1.KeyResult, len(keys)),
+	}
+	for i, key := range keys {
+		response.Results[i] = &v1.KeyResult{
+			Key:   key,
+			Error: s.keyError(errs[i], "Can't delete data from storage", key),
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,203 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"testing"
+	"time"
+)
+
+// mockedBatchStorage хранилище с поддержкой пакетных операций
+type mockedBatchStorage struct {
+	*MockStorage
+	*MockBatchStorage
+}
+
+func TestCacheServer_MultiGet(t *testing.T) {
+	err := errors.New("error")
+	request := &v1.MultiGetRequest{Keys: []string{"found", "missing", "broken"}}
+	want := &v1.MultiGetResponse{
+		Results: []*v1.MultiGetResult{
+			{Key: "found", Value: []byte("value"), Found: true, Flags: 1, Cas: 2, Ttl: 10},
+			{Key: "missing"},
+			{Key: "broken", Error: &v1.KeyError{Code: uint32(codes.Internal), Message: "something went wrong"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		getStorage func(ctrl *gomock.Controller) Storage
+		want       *v1.MultiGetResponse
+	}{
+		{
+			name: "batch storage",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				mockedStorage := NewMockBatchStorage(ctrl)
+				mockedStorage.EXPECT().
+					GetBatch(gomock.Any(), request.Keys).
+					Return([]cache.GetResult{
+						{Item: &cache.Item{Value: []byte("value"), Flags: 1, CasID: 2, TTL: 10 * time.Second}},
+						{Err: cache.ErrNotFound},
+						{Err: err},
+					}).
+					Times(1)
+				return mockedBatchStorage{MockStorage: NewMockStorage(ctrl), MockBatchStorage: mockedStorage}
+			},
+			want: want,
+		},
+		{
+			name: "storage without batch support",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				mockedStorage := NewMockStorage(ctrl)
+				gomock.InOrder(
+					mockedStorage.EXPECT().
+						Get(gomock.Any(), "found").
+						Return(&cache.Item{Value: []byte("value"), Flags: 1, CasID: 2, TTL: 10 * time.Second}, nil),
+					mockedStorage.EXPECT().
+						Get(gomock.Any(), "missing").
+						Return(nil, cache.ErrNotFound),
+					mockedStorage.EXPECT().
+						Get(gomock.Any(), "broken").
+						Return(nil, err),
+				)
+				return mockedStorage
+			},
+			want: want,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockedLogger := NewMockLogger(ctrl)
+			mockedLogger.EXPECT().
+				Error("Can't get data from storage", "err", err, "key", "broken").
+				Times(1)
+
+			s := NewCacheServer(mockedLogger, tt.getStorage(ctrl))
+			got, err := s.MultiGet(context.Background(), request)
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(tt.want, got), got.String())
+		})
+	}
+}
+
+func TestCacheServer_MultiSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedStorage := NewMockBatchStorage(ctrl)
+	mockedStorage.EXPECT().
+		SetBatch(gomock.Any(), []cache.Entry{
+			{Key: "first", Value: []byte("value"), TTL: 10 * time.Second},
+			{Key: "second", Value: []byte("huge")},
+			{Key: "third", Value: []byte("value")},
+		}).
+		Return([]error{nil, cache.ErrTooLarge, fmt.Errorf("can't write data: %w", context.DeadlineExceeded)}).
+		Times(1)
+
+	s := NewCacheServer(nil, mockedBatchStorage{MockStorage: NewMockStorage(ctrl), MockBatchStorage: mockedStorage})
+	got, err := s.MultiSet(context.Background(), &v1.MultiSetRequest{
+		Items: []*v1.SetRequest{
+			{Key: "first", Value: []byte("value"), Ttl: 10},
+			{Key: "second", Value: []byte("huge")},
+			{Key: "third", Value: []byte("value")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&v1.MultiSetResponse{
+		Results: []*v1.KeyResult{
+			{Key: "first"},
+			{Key: "second", Error: &v1.KeyError{Code: uint32(codes.InvalidArgument), Message: "value is too large"}},
+			{Key: "third", Error: &v1.KeyError{Code: uint32(codes.DeadlineExceeded), Message: "deadline exceeded"}},
+		},
+	}, got), got.String())
+}
+
+func TestCacheServer_MultiDelete(t *testing.T) {
+	err := errors.New("error")
+
+	ctrl := gomock.NewController(t)
+	mockedStorage := NewMockStorage(ctrl)
+	mockedStorage.EXPECT().
+		Delete(gomock.Any(), "first").
+		Return(nil).
+		Times(1)
+	mockedStorage.EXPECT().
+		Delete(gomock.Any(), "second").
+		Return(err).
+		Times(1)
+	mockedLogger := NewMockLogger(ctrl)
+	mockedLogger.EXPECT().
+		Error("Can't delete data from storage", "err", err, "key", "second").
+		Times(1)
+
+	s := NewCacheServer(mockedLogger, mockedStorage)
+	got, err := s.MultiDelete(context.Background(), &v1.MultiDeleteRequest{Keys: []string{"first", "second"}})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&v1.MultiDeleteResponse{
+		Results: []*v1.KeyResult{
+			{Key: "first"},
+			{Key: "second", Error: &v1.KeyError{Code: uint32(codes.Internal), Message: "something went wrong"}},
+		},
+	}, got), got.String())
+}
+
+func TestCacheServer_Batch_Errors(t *testing.T) {
+	tooMany := make([]string, MaxBatchSize+1)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		keys     []string
+		wantCode codes.Code
+	}{
+		{
+			name:     "too many keys",
+			ctx:      context.Background(),
+			keys:     tooMany,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "canceled",
+			ctx:      canceled,
+			keys:     []string{"first"},
+			wantCode: codes.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockedStorage := NewMockStorage(ctrl)
+			mockedStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, context.Canceled).AnyTimes()
+			mockedStorage.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(context.Canceled).AnyTimes()
+			mockedStorage.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(context.Canceled).AnyTimes()
+
+			s := NewCacheServer(nil, mockedStorage)
+
+			getResponse, err := s.MultiGet(tt.ctx, &v1.MultiGetRequest{Keys: tt.keys})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Nil(t, getResponse)
+
+			items := make([]*v1.SetRequest, len(tt.keys))
+			for i, key := range tt.keys {
+				items[i] = &v1.SetRequest{Key: key}
+			}
+			setResponse, err := s.MultiSet(tt.ctx, &v1.MultiSetRequest{Items: items})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Nil(t, setResponse)
+
+			deleteResponse, err := s.MultiDelete(tt.ctx, &v1.MultiDeleteRequest{Keys: tt.keys})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Nil(t, deleteResponse)
+		})
+	}
+}
@@ -2,9 +2,13 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"github.com/dimuska139/cacher/internal/cache"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -17,15 +21,39 @@ type Logger interface {
 
 // Storage хранилище
 type Storage interface {
-	Get(key string) ([]byte, error)
-	Set(key string, value []byte, ttl time.Duration) error
-	Delete(key string) error
+	Get(ctx context.Context, key string) (*cache.Item, error)
+	GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Increment(ctx context.Context, key string, delta uint64) (uint64, error)
+	Decrement(ctx context.Context, key string, delta uint64) (uint64, error)
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+	Stats(ctx context.Context) (*cache.Stats, error)
+}
+
+// BatchStorage хранилище, которое умеет обрабатывать несколько ключей за один раз эффективнее, чем по одному.
+// Реализовывать его необязательно: для остальных хранилищ пакетные запросы выполняются по одному ключу.
+// Результаты и ошибки возвращаются в том же порядке, что и ключи
+type BatchStorage interface {
+	GetBatch(ctx context.Context, keys []string) []cache.GetResult
+	SetBatch(ctx context.Context, entries []cache.Entry) []error
+	DeleteBatch(ctx context.Context, keys []string) []error
+}
+
+// WatchStorage хранилище, которое умеет сообщать об изменениях записей. Реализовывать его необязательно:
+// для остальных хранилищ Watch возвращает codes.Unimplemented
+type WatchStorage interface {
+	Watch(ctx context.Context, filter cache.KeyFilter) (cache.Subscription, error)
 }
 
 // CacheServer контроллер для сервиса кеширования
 type CacheServer struct {
 	logger  Logger
 	storage Storage
+
+	// Закрывается при завершении работы сервера, чтобы завершить открытые подписки Watch
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
 }
 
 // NewCacheServer создаёт контроллер для сервиса кеширования
@@ -34,15 +62,58 @@ func NewCacheServer(
 	storage Storage,
 ) *CacheServer {
 	return &CacheServer{
-		logger:  logger,
-		storage: storage,
+		logger:   logger,
+		storage:  storage,
+		shutdown: make(chan struct{}),
+	}
+}
+
+// Shutdown завершает открытые подписки Watch со статусом codes.Unavailable. Вызывается перед остановкой
+// gRPC-сервера: иначе GracefulStop ждёт, пока клиенты сами отменят подписки
+func (s *CacheServer) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdown)
+	})
+}
+
+// contextError возвращает gRPC-статус, если запрос к хранилищу прерван из-за отмены запроса клиентом
+// или истечения его дедлайна. Для остальных ошибок возвращает nil
+func contextError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Errorf(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Errorf(codes.DeadlineExceeded, "deadline exceeded")
 	}
+	return nil
 }
 
-// Get возвращает данные по ключу из кеша
+// Get возвращает данные по ключу из кеша вместе с метаданными. Если в запросе указан touch_ttl,
+// то одновременно с чтением обновляется время жизни записи. Отсутствие записи не является ошибкой:
+// в этом случае в ответе found = false
 func (s *CacheServer) Get(ctx context.Context, request *v1.GetRequest) (*v1.GetResponse, error) {
-	data, err := s.storage.Get(request.GetKey())
+	var (
+		item *cache.Item
+		err  error
+	)
+
+	if request.TouchTtl != nil {
+		item, err = s.storage.GetAndTouch(ctx, request.GetKey(), time.Second*time.Duration(request.GetTouchTtl()))
+	} else {
+		item, err = s.storage.Get(ctx, request.GetKey())
+	}
+
 	if err != nil {
+		if st := contextError(err); st != nil {
+			return nil, st
+		}
+
+		if errors.Is(err, cache.ErrNotFound) {
+			return &v1.GetResponse{
+				Found: false,
+			}, nil
+		}
+
 		s.logger.Error("Can't get data from storage",
 			"err", err,
 			"key", request.GetKey())
@@ -50,14 +121,35 @@ func (s *CacheServer) Get(ctx context.Context, request *v1.GetRequest) (*v1.GetR
 	}
 
 	return &v1.GetResponse{
-		Value: data,
+		Value: item.Value,
+		Found: true,
+		Flags: item.Flags,
+		Cas:   item.CasID,
+		Ttl:   ttlSeconds(item.TTL),
 	}, nil
 }
 
+// ttlSeconds округляет оставшееся время жизни вверх до целых секунд, чтобы запись,
+// у которой осталось меньше секунды, не выглядела бессрочной
+func ttlSeconds(ttl time.Duration) uint64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return uint64((ttl + time.Second - 1) / time.Second)
+}
+
 // Set записывает данные в кеш
 func (s *CacheServer) Set(ctx context.Context, request *v1.SetRequest) (*v1.SetResponse, error) {
-	err := s.storage.Set(request.GetKey(), request.GetValue(), time.Second*time.Duration(request.GetTtl()))
+	err := s.storage.Set(ctx, request.GetKey(), request.GetValue(), time.Second*time.Duration(request.GetTtl()))
 	if err != nil {
+		if st := contextError(err); st != nil {
+			return nil, st
+		}
+
+		if errors.Is(err, cache.ErrTooLarge) {
+			return nil, status.Errorf(codes.InvalidArgument, "value is too large")
+		}
+
 		s.logger.Error("Can't get save data to storage", "err", err)
 		return nil, status.Errorf(codes.Internal, "something went wrong")
 	}
@@ -67,8 +159,12 @@ func (s *CacheServer) Set(ctx context.Context, request *v1.SetRequest) (*v1.SetR
 
 // Delete удаляет данные из кеша
 func (s *CacheServer) Delete(ctx context.Context, request *v1.DeleteRequest) (*v1.DeleteResponse, error) {
-	err := s.storage.Delete(request.GetKey())
+	err := s.storage.Delete(ctx, request.GetKey())
 	if err != nil {
+		if st := contextError(err); st != nil {
+			return nil, st
+		}
+
 		s.logger.Error("Can't delete data from storage",
 			"err", err,
 			"key", request.GetKey())
@@ -77,3 +173,97 @@ func (s *CacheServer) Delete(ctx context.Context, request *v1.DeleteRequest) (*v
 
 	return &v1.DeleteResponse{}, nil
 }
+
+// Increment атомарно изменяет числовое значение в кеше и возвращает новое значение
+func (s *CacheServer) Increment(ctx context.Context, request *v1.IncrementRequest) (*v1.IncrementResponse, error) {
+	var (
+		value uint64
+		err   error
+	)
+
+	if delta := request.GetDelta(); delta >= 0 {
+		value, err = s.storage.Increment(ctx, request.GetKey(), uint64(delta))
+	} else {
+		value, err = s.storage.Decrement(ctx, request.GetKey(), uint64(-delta))
+	}
+
+	if err != nil {
+		if st := contextError(err); st != nil {
+			return nil, st
+		}
+
+		switch {
+		case errors.Is(err, cache.ErrNotFound):
+			return nil, status.Errorf(codes.NotFound, "key not found")
+		case errors.Is(err, cache.ErrNotNumeric):
+			return nil, status.Errorf(codes.FailedPrecondition, "value is not a number")
+		}
+
+		s.logger.Error("Can't increment value in storage",
+			"err", err,
+			"key", request.GetKey())
+		return nil, status.Errorf(codes.Internal, "something went wrong")
+	}
+
+	return &v1.IncrementResponse{
+		Value: value,
+	}, nil
+}
+
+// Touch обновляет время жизни записи в кеше
+func (s *CacheServer) Touch(ctx context.Context, request *v1.TouchRequest) (*v1.TouchResponse, error) {
+	err := s.storage.Touch(ctx, request.GetKey(), time.Second*time.Duration(request.GetTtl()))
+	if err != nil {
+		if st := contextError(err); st != nil {
+			return nil, st
+		}
+
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "key not found")
+		}
+
+		s.logger.Error("Can't touch data in storage",
+			"err", err,
+			"key", request.GetKey())
+		return nil, status.Errorf(codes.Internal, "something went wrong")
+	}
+
+	return &v1.TouchResponse{}, nil
+}
+
+// Stats возвращает статистику работы хранилища. Статистика серверов отсортирована по адресу
+func (s *CacheServer) Stats(ctx context.Context, _ *v1.StatsRequest) (*v1.StatsResponse, error) {
+	stats, err := s.storage.Stats(ctx)
+	if err != nil {
+		if st := contextError(err); st != nil {
+			return nil, st
+		}
+
+		s.logger.Error("Can't get stats from storage",
+			"err", err)
+		return nil, status.Errorf(codes.Internal, "something went wrong")
+	}
+
+	servers := make([]*v1.ServerStats, 0, len(stats.Servers))
+	for address, serverStats := range stats.Servers {
+		servers = append(servers, &v1.ServerStats{
+			Address: address,
+			Stats:   serverStats,
+		})
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Address < servers[j].Address
+	})
+
+	return &v1.StatsResponse{
+		GetHits:     stats.GetHits,
+		GetMisses:   stats.GetMisses,
+		Sets:        stats.Sets,
+		Deletes:     stats.Deletes,
+		Evictions:   stats.Evictions,
+		Expirations: stats.Expirations,
+		Items:       stats.Items,
+		Bytes:       stats.Bytes,
+		Servers:     servers,
+	}, nil
+}
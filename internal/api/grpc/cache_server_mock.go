@@ -5,9 +5,11 @@
 package grpc
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
+	cache "github.com/dimuska139/cacher/internal/cache"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -74,45 +76,222 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// Decrement mocks base method.
+func (m *MockStorage) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrement", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrement indicates an expected call of Decrement.
+func (mr *MockStorageMockRecorder) Decrement(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrement", reflect.TypeOf((*MockStorage)(nil).Decrement), ctx, key, delta)
+}
+
 // Delete mocks base method.
-func (m *MockStorage) Delete(key string) error {
+func (m *MockStorage) Delete(ctx context.Context, key string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", key)
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockStorageMockRecorder) Delete(key interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), ctx, key)
 }
 
 // Get mocks base method.
-func (m *MockStorage) Get(key string) ([]byte, error) {
+func (m *MockStorage) Get(ctx context.Context, key string) (*cache.Item, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", key)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*cache.Item)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockStorageMockRecorder) Get(key interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStorage)(nil).Get), ctx, key)
+}
+
+// GetAndTouch mocks base method.
+func (m *MockStorage) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAndTouch", ctx, key, ttl)
+	ret0, _ := ret[0].(*cache.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAndTouch indicates an expected call of GetAndTouch.
+func (mr *MockStorageMockRecorder) GetAndTouch(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAndTouch", reflect.TypeOf((*MockStorage)(nil).GetAndTouch), ctx, key, ttl)
+}
+
+// Increment mocks base method.
+func (m *MockStorage) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockStorageMockRecorder) Increment(ctx, key, delta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStorage)(nil).Get), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockStorage)(nil).Increment), ctx, key, delta)
 }
 
 // Set mocks base method.
-func (m *MockStorage) Set(key string, value []byte, ttl time.Duration) error {
+func (m *MockStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", key, value, ttl)
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, ttl)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockStorageMockRecorder) Set(key, value, ttl interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Set(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockStorage)(nil).Set), ctx, key, value, ttl)
+}
+
+// Stats mocks base method.
+func (m *MockStorage) Stats(ctx context.Context) (*cache.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(*cache.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockStorageMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockStorage)(nil).Stats), ctx)
+}
+
+// Touch mocks base method.
+func (m *MockStorage) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, key, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockStorageMockRecorder) Touch(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockStorage)(nil).Touch), ctx, key, ttl)
+}
+
+// MockBatchStorage is a mock of BatchStorage interface.
+type MockBatchStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchStorageMockRecorder
+}
+
+// MockBatchStorageMockRecorder is the mock recorder for MockBatchStorage.
+type MockBatchStorageMockRecorder struct {
+	mock *MockBatchStorage
+}
+
+// NewMockBatchStorage creates a new mock instance.
+func NewMockBatchStorage(ctrl *gomock.Controller) *MockBatchStorage {
+	mock := &MockBatchStorage{ctrl: ctrl}
+	mock.recorder = &MockBatchStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchStorage) EXPECT() *MockBatchStorageMockRecorder {
+	return m.recorder
+}
+
+// DeleteBatch mocks base method.
+func (m *MockBatchStorage) DeleteBatch(ctx context.Context, keys []string) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBatch", ctx, keys)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// DeleteBatch indicates an expected call of DeleteBatch.
+func (mr *MockBatchStorageMockRecorder) DeleteBatch(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockBatchStorage)(nil).DeleteBatch), ctx, keys)
+}
+
+// GetBatch mocks base method.
+func (m *MockBatchStorage) GetBatch(ctx context.Context, keys []string) []cache.GetResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, keys)
+	ret0, _ := ret[0].([]cache.GetResult)
+	return ret0
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockBatchStorageMockRecorder) GetBatch(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockBatchStorage)(nil).GetBatch), ctx, keys)
+}
+
+// SetBatch mocks base method.
+func (m *MockBatchStorage) SetBatch(ctx context.Context, entries []cache.Entry) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBatch", ctx, entries)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// SetBatch indicates an expected call of SetBatch.
+func (mr *MockBatchStorageMockRecorder) SetBatch(ctx, entries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBatch", reflect.TypeOf((*MockBatchStorage)(nil).SetBatch), ctx, entries)
+}
+
+// MockWatchStorage is a mock of WatchStorage interface.
+type MockWatchStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchStorageMockRecorder
+}
+
+// MockWatchStorageMockRecorder is the mock recorder for MockWatchStorage.
+type MockWatchStorageMockRecorder struct {
+	mock *MockWatchStorage
+}
+
+// NewMockWatchStorage creates a new mock instance.
+func NewMockWatchStorage(ctrl *gomock.Controller) *MockWatchStorage {
+	mock := &MockWatchStorage{ctrl: ctrl}
+	mock.recorder = &MockWatchStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchStorage) EXPECT() *MockWatchStorageMockRecorder {
+	return m.recorder
+}
+
+// Watch mocks base method.
+func (m *MockWatchStorage) Watch(ctx context.Context, filter cache.KeyFilter) (cache.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, filter)
+	ret0, _ := ret[0].(cache.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockWatchStorageMockRecorder) Watch(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockStorage)(nil).Set), key, value, ttl)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockWatchStorage)(nil).Watch), ctx, filter)
 }
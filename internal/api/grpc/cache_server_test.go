@@ -3,9 +3,14 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"github.com/dimuska139/cacher/internal/cache"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"testing"
 	"time"
 )
@@ -31,7 +36,7 @@ func TestCacheServer_Delete(t *testing.T) {
 			name: "without error",
 			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
 				mockedStorage.EXPECT().
-					Delete("key").
+					Delete(gomock.Any(), "key").
 					Return(nil).
 					Times(1)
 				return fields{
@@ -58,7 +63,7 @@ func TestCacheServer_Delete(t *testing.T) {
 					Times(1)
 
 				mockedStorage.EXPECT().
-					Delete("key").
+					Delete(gomock.Any(), "key").
 					Return(err).
 					Times(1)
 				return fields{
@@ -121,8 +126,13 @@ func TestCacheServer_Get(t *testing.T) {
 			name: "without error",
 			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
 				mockedStorage.EXPECT().
-					Get("key").
-					Return([]byte("data"), nil).
+					Get(gomock.Any(), "key").
+					Return(&cache.Item{
+						Value: []byte("data"),
+						Flags: 42,
+						CasID: 7,
+						TTL:   1500 * time.Millisecond,
+					}, nil).
 					Times(1)
 				return fields{
 					storage: mockedStorage,
@@ -137,6 +147,64 @@ func TestCacheServer_Get(t *testing.T) {
 			},
 			want: &v1.GetResponse{
 				Value: []byte("data"),
+				Found: true,
+				Flags: 42,
+				Cas:   7,
+				Ttl:   2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "not found",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Get(gomock.Any(), "key").
+					Return(nil, fmt.Errorf("can't get data from memcache: %w", cache.ErrNotFound)).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.GetRequest{
+					Key: "key",
+				},
+			},
+			want: &v1.GetResponse{
+				Found: false,
+			},
+			wantErr: false,
+		},
+		{
+			name: "with touch",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					GetAndTouch(gomock.Any(), "key", time.Minute).
+					Return(&cache.Item{
+						Value: []byte("data"),
+						CasID: 7,
+						TTL:   time.Minute,
+					}, nil).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.GetRequest{
+					Key:      "key",
+					TouchTtl: proto.Uint64(60),
+				},
+			},
+			want: &v1.GetResponse{
+				Value: []byte("data"),
+				Found: true,
+				Cas:   7,
+				Ttl:   60,
 			},
 			wantErr: false,
 		},
@@ -150,7 +218,7 @@ func TestCacheServer_Get(t *testing.T) {
 					Times(1)
 
 				mockedStorage.EXPECT().
-					Get("key").
+					Get(gomock.Any(), "key").
 					Return(nil, err).
 					Times(1)
 				return fields{
@@ -213,7 +281,7 @@ func TestCacheServer_Set(t *testing.T) {
 			name: "without error",
 			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
 				mockedStorage.EXPECT().
-					Set("key", []byte("test"), time.Second*time.Duration(10)).
+					Set(gomock.Any(), "key", []byte("test"), time.Second*time.Duration(10)).
 					Return(nil).
 					Times(1)
 				return fields{
@@ -242,7 +310,7 @@ func TestCacheServer_Set(t *testing.T) {
 					Times(1)
 
 				mockedStorage.EXPECT().
-					Set("key", []byte("test"), time.Second*time.Duration(10)).
+					Set(gomock.Any(), "key", []byte("test"), time.Second*time.Duration(10)).
 					Return(err).
 					Times(1)
 				return fields{
@@ -286,6 +354,434 @@ func TestCacheServer_Set(t *testing.T) {
 	}
 }
 
+func TestCacheServer_Increment(t *testing.T) {
+	type fields struct {
+		logger  Logger
+		storage Storage
+	}
+	type args struct {
+		ctx     context.Context
+		request *v1.IncrementRequest
+	}
+
+	tests := []struct {
+		name      string
+		getFields func(storage *MockStorage, logger *MockLogger) fields
+		args      args
+		want      *v1.IncrementResponse
+		wantCode  codes.Code
+	}{
+		{
+			name: "increment",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Increment(gomock.Any(), "counter", uint64(5)).
+					Return(uint64(15), nil).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.IncrementRequest{
+					Key:   "counter",
+					Delta: 5,
+				},
+			},
+			want: &v1.IncrementResponse{
+				Value: 15,
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "decrement",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Decrement(gomock.Any(), "counter", uint64(5)).
+					Return(uint64(5), nil).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.IncrementRequest{
+					Key:   "counter",
+					Delta: -5,
+				},
+			},
+			want: &v1.IncrementResponse{
+				Value: 5,
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "not found",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Increment(gomock.Any(), "counter", uint64(1)).
+					Return(uint64(0), cache.ErrNotFound).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.IncrementRequest{
+					Key:   "counter",
+					Delta: 1,
+				},
+			},
+			want:     nil,
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "not numeric",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Increment(gomock.Any(), "counter", uint64(1)).
+					Return(uint64(0), cache.ErrNotNumeric).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.IncrementRequest{
+					Key:   "counter",
+					Delta: 1,
+				},
+			},
+			want:     nil,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "with error",
+			getFields: func(mockedStorage *MockStorage, mockedLogger *MockLogger) fields {
+				err := errors.New("error")
+
+				mockedLogger.EXPECT().
+					Error("Can't increment value in storage", "err", err, "key", "counter").
+					Times(1)
+
+				mockedStorage.EXPECT().
+					Increment(gomock.Any(), "counter", uint64(1)).
+					Return(uint64(0), err).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  mockedLogger,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.IncrementRequest{
+					Key:   "counter",
+					Delta: 1,
+				},
+			},
+			want:     nil,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			mockedStorage := NewMockStorage(ctrl)
+			mockedLogger := NewMockLogger(ctrl)
+
+			mockedFields := tt.getFields(mockedStorage, mockedLogger)
+
+			s := &CacheServer{
+				logger:  mockedFields.logger,
+				storage: mockedFields.storage,
+			}
+			got, err := s.Increment(tt.args.ctx, tt.args.request)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCacheServer_Touch(t *testing.T) {
+	type fields struct {
+		logger  Logger
+		storage Storage
+	}
+	type args struct {
+		ctx     context.Context
+		request *v1.TouchRequest
+	}
+
+	tests := []struct {
+		name      string
+		getFields func(storage *MockStorage, logger *MockLogger) fields
+		args      args
+		want      *v1.TouchResponse
+		wantCode  codes.Code
+	}{
+		{
+			name: "without error",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Touch(gomock.Any(), "key", time.Second*time.Duration(10)).
+					Return(nil).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.TouchRequest{
+					Key: "key",
+					Ttl: uint64(10),
+				},
+			},
+			want:     &v1.TouchResponse{},
+			wantCode: codes.OK,
+		},
+		{
+			name: "not found",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Touch(gomock.Any(), "key", time.Second*time.Duration(10)).
+					Return(cache.ErrNotFound).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.TouchRequest{
+					Key: "key",
+					Ttl: uint64(10),
+				},
+			},
+			want:     nil,
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "deadline exceeded",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Touch(gomock.Any(), "key", time.Second*time.Duration(10)).
+					Return(fmt.Errorf("can't touch data in memcache: %w", context.DeadlineExceeded)).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.TouchRequest{
+					Key: "key",
+					Ttl: uint64(10),
+				},
+			},
+			want:     nil,
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name: "canceled",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Touch(gomock.Any(), "key", time.Second*time.Duration(10)).
+					Return(fmt.Errorf("can't touch data in memcache: %w", context.Canceled)).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.TouchRequest{
+					Key: "key",
+					Ttl: uint64(10),
+				},
+			},
+			want:     nil,
+			wantCode: codes.Canceled,
+		},
+		{
+			name: "with error",
+			getFields: func(mockedStorage *MockStorage, mockedLogger *MockLogger) fields {
+				err := errors.New("error")
+
+				mockedLogger.EXPECT().
+					Error("Can't touch data in storage", "err", err, "key", "key").
+					Times(1)
+
+				mockedStorage.EXPECT().
+					Touch(gomock.Any(), "key", time.Second*time.Duration(10)).
+					Return(err).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  mockedLogger,
+				}
+			},
+			args: args{
+				ctx: context.Background(),
+				request: &v1.TouchRequest{
+					Key: "key",
+					Ttl: uint64(10),
+				},
+			},
+			want:     nil,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			mockedStorage := NewMockStorage(ctrl)
+			mockedLogger := NewMockLogger(ctrl)
+
+			mockedFields := tt.getFields(mockedStorage, mockedLogger)
+
+			s := &CacheServer{
+				logger:  mockedFields.logger,
+				storage: mockedFields.storage,
+			}
+			got, err := s.Touch(tt.args.ctx, tt.args.request)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCacheServer_Stats(t *testing.T) {
+	type fields struct {
+		logger  Logger
+		storage Storage
+	}
+
+	tests := []struct {
+		name      string
+		getFields func(storage *MockStorage, logger *MockLogger) fields
+		want      *v1.StatsResponse
+		wantCode  codes.Code
+	}{
+		{
+			name: "without error",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Stats(gomock.Any()).
+					Return(&cache.Stats{
+						GetHits:     10,
+						GetMisses:   5,
+						Sets:        7,
+						Deletes:     2,
+						Evictions:   3,
+						Expirations: 1,
+						Items:       4,
+						Bytes:       100,
+						Servers: map[string]map[string]string{
+							"127.0.0.1:11212": {"version": "1.6.21"},
+							"127.0.0.1:11211": {"version": "1.6.20"},
+						},
+					}, nil).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			want: &v1.StatsResponse{
+				GetHits:     10,
+				GetMisses:   5,
+				Sets:        7,
+				Deletes:     2,
+				Evictions:   3,
+				Expirations: 1,
+				Items:       4,
+				Bytes:       100,
+				Servers: []*v1.ServerStats{
+					{
+						Address: "127.0.0.1:11211",
+						Stats:   map[string]string{"version": "1.6.20"},
+					},
+					{
+						Address: "127.0.0.1:11212",
+						Stats:   map[string]string{"version": "1.6.21"},
+					},
+				},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "deadline exceeded",
+			getFields: func(mockedStorage *MockStorage, _ *MockLogger) fields {
+				mockedStorage.EXPECT().
+					Stats(gomock.Any()).
+					Return(nil, fmt.Errorf("can't get stats from memcache: %w", context.DeadlineExceeded)).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  nil,
+				}
+			},
+			want:     nil,
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name: "with error",
+			getFields: func(mockedStorage *MockStorage, mockedLogger *MockLogger) fields {
+				err := errors.New("error")
+
+				mockedLogger.EXPECT().
+					Error("Can't get stats from storage", "err", err).
+					Times(1)
+
+				mockedStorage.EXPECT().
+					Stats(gomock.Any()).
+					Return(nil, err).
+					Times(1)
+				return fields{
+					storage: mockedStorage,
+					logger:  mockedLogger,
+				}
+			},
+			want:     nil,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			mockedStorage := NewMockStorage(ctrl)
+			mockedLogger := NewMockLogger(ctrl)
+
+			mockedFields := tt.getFields(mockedStorage, mockedLogger)
+
+			s := &CacheServer{
+				logger:  mockedFields.logger,
+				storage: mockedFields.storage,
+			}
+			got, err := s.Stats(context.Background(), &v1.StatsRequest{})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.True(t, proto.Equal(tt.want, got))
+		})
+	}
+}
+
 func TestNewCacheServer(t *testing.T) {
 	type args struct {
 		logger  Logger
@@ -315,7 +811,10 @@ func TestNewCacheServer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.want, NewCacheServer(tt.args.logger, tt.args.storage))
+			got := NewCacheServer(tt.args.logger, tt.args.storage)
+			assert.Equal(t, tt.want.logger, got.logger)
+			assert.Equal(t, tt.want.storage, got.storage)
+			assert.NotNil(t, got.shutdown)
 		})
 	}
 }
@@ -27,7 +27,7 @@ var file_cacher_cache_v1_cache_api_proto_rawDesc = []byte{
 	0x6f, 0x12, 0x0f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e,
 	0x76, 0x31, 0x1a, 0x1b, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2f, 0x63, 0x61, 0x63, 0x68, 0x65,
 	0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32,
-	0xd9, 0x01, 0x0a, 0x08, 0x43, 0x61, 0x63, 0x68, 0x65, 0x41, 0x50, 0x49, 0x12, 0x40, 0x0a, 0x03,
+	0x80, 0x06, 0x0a, 0x08, 0x43, 0x61, 0x63, 0x68, 0x65, 0x41, 0x50, 0x49, 0x12, 0x40, 0x0a, 0x03,
 	0x47, 0x65, 0x74, 0x12, 0x1b, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63,
 	0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x1a, 0x1c, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e,
@@ -40,31 +40,93 @@ var file_cacher_cache_v1_cache_api_proto_rawDesc = []byte{
 	0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
 	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x63, 0x61, 0x63,
 	0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x11, 0x5a, 0x0f, 0x63,
-	0x61, 0x63, 0x68, 0x65, 0x72, 0x2f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2f, 0x76, 0x31, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x09, 0x49,
+	0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65,
+	0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x63, 0x72, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e,
+	0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x46, 0x0a, 0x05, 0x54, 0x6f, 0x75, 0x63, 0x68, 0x12, 0x1d, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65,
+	0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x75, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72,
+	0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x75, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x12, 0x1d, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1e, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4f, 0x0a, 0x08, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x12, 0x20, 0x2e, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e,
+	0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x4d, 0x75, 0x6c, 0x74, 0x69, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x4f, 0x0a, 0x08, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x53, 0x65, 0x74, 0x12, 0x20, 0x2e, 0x63,
+	0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d,
+	0x75, 0x6c, 0x74, 0x69, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21,
+	0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x53, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x58, 0x0a, 0x0b, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x12, 0x23, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63,
+	0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x05, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x1d, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x63, 0x61, 0x63,
+	0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x30, 0x01, 0x42, 0x11, 0x5a, 0x0f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x72, 0x2f, 0x63, 0x61, 0x63,
+	0x68, 0x65, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_cacher_cache_v1_cache_api_proto_goTypes = []interface{}{
-	(*GetRequest)(nil),     // 0: cacher.cache.v1.GetRequest
-	(*SetRequest)(nil),     // 1: cacher.cache.v1.SetRequest
-	(*DeleteRequest)(nil),  // 2: cacher.cache.v1.DeleteRequest
-	(*GetResponse)(nil),    // 3: cacher.cache.v1.GetResponse
-	(*SetResponse)(nil),    // 4: cacher.cache.v1.SetResponse
-	(*DeleteResponse)(nil), // 5: cacher.cache.v1.DeleteResponse
+	(*GetRequest)(nil),          // 0: cacher.cache.v1.GetRequest
+	(*SetRequest)(nil),          // 1: cacher.cache.v1.SetRequest
+	(*DeleteRequest)(nil),       // 2: cacher.cache.v1.DeleteRequest
+	(*IncrementRequest)(nil),    // 3: cacher.cache.v1.IncrementRequest
+	(*TouchRequest)(nil),        // 4: cacher.cache.v1.TouchRequest
+	(*StatsRequest)(nil),        // 5: cacher.cache.v1.StatsRequest
+	(*MultiGetRequest)(nil),     // 6: cacher.cache.v1.MultiGetRequest
+	(*MultiSetRequest)(nil),     // 7: cacher.cache.v1.MultiSetRequest
+	(*MultiDeleteRequest)(nil),  // 8: cacher.cache.v1.MultiDeleteRequest
+	(*WatchRequest)(nil),        // 9: cacher.cache.v1.WatchRequest
+	(*GetResponse)(nil),         // 10: cacher.cache.v1.GetResponse
+	(*SetResponse)(nil),         // 11: cacher.cache.v1.SetResponse
+	(*DeleteResponse)(nil),      // 12: cacher.cache.v1.DeleteResponse
+	(*IncrementResponse)(nil),   // 13: cacher.cache.v1.IncrementResponse
+	(*TouchResponse)(nil),       // 14: cacher.cache.v1.TouchResponse
+	(*StatsResponse)(nil),       // 15: cacher.cache.v1.StatsResponse
+	(*MultiGetResponse)(nil),    // 16: cacher.cache.v1.MultiGetResponse
+	(*MultiSetResponse)(nil),    // 17: cacher.cache.v1.MultiSetResponse
+	(*MultiDeleteResponse)(nil), // 18: cacher.cache.v1.MultiDeleteResponse
+	(*WatchEvent)(nil),          // 19: cacher.cache.v1.WatchEvent
 }
 var file_cacher_cache_v1_cache_api_proto_depIdxs = []int32{
-	0, // 0: cacher.cache.v1.CacheAPI.Get:input_type -> cacher.cache.v1.GetRequest
-	1, // 1: cacher.cache.v1.CacheAPI.Set:input_type -> cacher.cache.v1.SetRequest
-	2, // 2: cacher.cache.v1.CacheAPI.Delete:input_type -> cacher.cache.v1.DeleteRequest
-	3, // 3: cacher.cache.v1.CacheAPI.Get:output_type -> cacher.cache.v1.GetResponse
-	4, // 4: cacher.cache.v1.CacheAPI.Set:output_type -> cacher.cache.v1.SetResponse
-	5, // 5: cacher.cache.v1.CacheAPI.Delete:output_type -> cacher.cache.v1.DeleteResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: cacher.cache.v1.CacheAPI.Get:input_type -> cacher.cache.v1.GetRequest
+	1,  // 1: cacher.cache.v1.CacheAPI.Set:input_type -> cacher.cache.v1.SetRequest
+	2,  // 2: cacher.cache.v1.CacheAPI.Delete:input_type -> cacher.cache.v1.DeleteRequest
+	3,  // 3: cacher.cache.v1.CacheAPI.Increment:input_type -> cacher.cache.v1.IncrementRequest
+	4,  // 4: cacher.cache.v1.CacheAPI.Touch:input_type -> cacher.cache.v1.TouchRequest
+	5,  // 5: cacher.cache.v1.CacheAPI.Stats:input_type -> cacher.cache.v1.StatsRequest
+	6,  // 6: cacher.cache.v1.CacheAPI.MultiGet:input_type -> cacher.cache.v1.MultiGetRequest
+	7,  // 7: cacher.cache.v1.CacheAPI.MultiSet:input_type -> cacher.cache.v1.MultiSetRequest
+	8,  // 8: cacher.cache.v1.CacheAPI.MultiDelete:input_type -> cacher.cache.v1.MultiDeleteRequest
+	9,  // 9: cacher.cache.v1.CacheAPI.Watch:input_type -> cacher.cache.v1.WatchRequest
+	10, // 10: cacher.cache.v1.CacheAPI.Get:output_type -> cacher.cache.v1.GetResponse
+	11, // 11: cacher.cache.v1.CacheAPI.Set:output_type -> cacher.cache.v1.SetResponse
+	12, // 12: cacher.cache.v1.CacheAPI.Delete:output_type -> cacher.cache.v1.DeleteResponse
+	13, // 13: cacher.cache.v1.CacheAPI.Increment:output_type -> cacher.cache.v1.IncrementResponse
+	14, // 14: cacher.cache.v1.CacheAPI.Touch:output_type -> cacher.cache.v1.TouchResponse
+	15, // 15: cacher.cache.v1.CacheAPI.Stats:output_type -> cacher.cache.v1.StatsResponse
+	16, // 16: cacher.cache.v1.CacheAPI.MultiGet:output_type -> cacher.cache.v1.MultiGetResponse
+	17, // 17: cacher.cache.v1.CacheAPI.MultiSet:output_type -> cacher.cache.v1.MultiSetResponse
+	18, // 18: cacher.cache.v1.CacheAPI.MultiDelete:output_type -> cacher.cache.v1.MultiDeleteResponse
+	19, // 19: cacher.cache.v1.CacheAPI.Watch:output_type -> cacher.cache.v1.WatchEvent
+	10, // [10:20] is the sub-list for method output_type
+	0,  // [0:10] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_cacher_cache_v1_cache_api_proto_init() }
@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"context"
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=health.go -destination=./health_mock.go -package=grpc
+
+// DefaultHealthCheckInterval период проверки работоспособности хранилища по умолчанию
+const DefaultHealthCheckInterval = 5 * time.Second
+
+// HealthChecker хранилище, которое умеет проверять свою работоспособность
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthMonitor периодически проверяет работоспособность хранилища и по результатам проверки выставляет
+// статус сервиса кеширования (и сервера в целом) в стандартном сервисе grpc.health.v1.Health
+type HealthMonitor struct {
+	server   *health.Server
+	checker  HealthChecker
+	logger   Logger
+	interval time.Duration
+
+	mx      sync.Mutex
+	healthy bool
+
+	stop     chan struct{}
+	done     chan struct{}
+	started  bool
+	stopOnce sync.Once
+}
+
+// NewHealthMonitor создаёт HealthMonitor, который проверяет checker каждые interval (0 - DefaultHealthCheckInterval).
+// Проверка ограничена по времени тем же interval
+func NewHealthMonitor(server *health.Server, checker HealthChecker, logger Logger, interval time.Duration) *HealthMonitor {
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+
+	return &HealthMonitor{
+		server:   server,
+		checker:  checker,
+		logger:   logger,
+		interval: interval,
+		healthy:  true,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start выполняет первую проверку и запускает периодические проверки
+func (m *HealthMonitor) Start() {
+	m.Check(context.Background())
+
+	m.started = true
+	go m.run()
+}
+
+// run проверяет хранилище, пока HealthMonitor не остановлен
+func (m *HealthMonitor) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.Check(context.Background())
+		}
+	}
+}
+
+// Check проверяет хранилище и выставляет статус SERVING или NOT_SERVING. Ошибка проверки логируется
+// только при переходе из работоспособного состояния в неработоспособное
+func (m *HealthMonitor) Check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	err := m.checker.HealthCheck(ctx)
+
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	if err != nil && m.healthy {
+		m.logger.Error("Storage is unhealthy", "err", err)
+	}
+	m.healthy = err == nil
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if err != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	m.server.SetServingStatus("", status)
+	m.server.SetServingStatus(v1.CacheAPI_ServiceDesc.ServiceName, status)
+}
+
+// Shutdown останавливает проверки и переводит все сервисы в статус NOT_SERVING. Вызывается в начале
+// корректного завершения работы, чтобы клиенты перестали отправлять новые запросы
+func (m *HealthMonitor) Shutdown() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+		if m.started {
+			<-m.done
+		}
+		m.server.Shutdown()
+	})
+}
@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"testing"
+	"time"
+)
+
+// servingStatus возвращает статус сервиса в сервисе проверки работоспособности
+func servingStatus(t *testing.T, server *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	assert.NoError(t, err)
+	return resp.GetStatus()
+}
+
+func TestHealthMonitor_Check(t *testing.T) {
+	err := errors.New("memcache is unavailable")
+
+	tests := []struct {
+		name       string
+		getChecker func(checker *MockHealthChecker, logger *MockLogger)
+		want       healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name: "healthy",
+			getChecker: func(checker *MockHealthChecker, _ *MockLogger) {
+				checker.EXPECT().
+					HealthCheck(gomock.Any()).
+					Return(nil).
+					Times(2)
+			},
+			want: healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name: "unhealthy",
+			getChecker: func(checker *MockHealthChecker, logger *MockLogger) {
+				checker.EXPECT().
+					HealthCheck(gomock.Any()).
+					Return(err).
+					Times(2)
+				// Повторная ошибка не логируется
+				logger.EXPECT().
+					Error("Storage is unhealthy", "err", err).
+					Times(1)
+			},
+			want: healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name: "recovered",
+			getChecker: func(checker *MockHealthChecker, logger *MockLogger) {
+				gomock.InOrder(
+					checker.EXPECT().
+						HealthCheck(gomock.Any()).
+						Return(err).
+						Times(1),
+					checker.EXPECT().
+						HealthCheck(gomock.Any()).
+						Return(nil).
+						Times(1),
+				)
+				logger.EXPECT().
+					Error("Storage is unhealthy", "err", err).
+					Times(1)
+			},
+			want: healthpb.HealthCheckResponse_SERVING,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockedChecker := NewMockHealthChecker(ctrl)
+			mockedLogger := NewMockLogger(ctrl)
+			tt.getChecker(mockedChecker, mockedLogger)
+
+			server := health.NewServer()
+			monitor := NewHealthMonitor(server, mockedChecker, mockedLogger, time.Second)
+			monitor.Check(context.Background())
+			monitor.Check(context.Background())
+
+			assert.Equal(t, tt.want, servingStatus(t, server, ""))
+			assert.Equal(t, tt.want, servingStatus(t, server, v1.CacheAPI_ServiceDesc.ServiceName))
+		})
+	}
+}
+
+func TestHealthMonitor_Shutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedChecker := NewMockHealthChecker(ctrl)
+	mockedChecker.EXPECT().
+		HealthCheck(gomock.Any()).
+		Return(nil).
+		MinTimes(2)
+
+	server := health.NewServer()
+	monitor := NewHealthMonitor(server, mockedChecker, nil, 10*time.Millisecond)
+	monitor.Start()
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, server, v1.CacheAPI_ServiceDesc.ServiceName))
+
+	// Проверки продолжаются периодически
+	time.Sleep(30 * time.Millisecond)
+
+	monitor.Shutdown()
+	monitor.Shutdown()
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, server, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, server, v1.CacheAPI_ServiceDesc.ServiceName))
+
+	// После остановки успешная проверка не возвращает статус SERVING
+	monitor.Check(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, server, ""))
+}
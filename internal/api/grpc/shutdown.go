@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"google.golang.org/grpc"
+	"time"
+)
+
+// DefaultStopTimeout время, в течение которого GracefulStop ждёт завершения запросов по умолчанию
+const DefaultStopTimeout = 5 * time.Second
+
+// GracefulStop останавливает сервер, дожидаясь завершения выполняющихся запросов не дольше timeout
+// (0 - DefaultStopTimeout). Если запросы не завершились за это время, соединения закрываются принудительно
+func GracefulStop(server *grpc.Server, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+	case <-timer.C:
+		server.Stop()
+		<-stopped
+	}
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"testing"
+	"time"
+)
+
+func TestGracefulStop(t *testing.T) {
+	tests := []struct {
+		name string
+		// Открыть долгий запрос, который не завершается сам
+		openStream bool
+	}{
+		{
+			name: "no requests",
+		},
+		{
+			name:       "request is not finished in time",
+			openStream: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := bufconn.Listen(1 << 20)
+			server := grpc.NewServer()
+			healthpb.RegisterHealthServer(server, health.NewServer())
+			go server.Serve(listener)
+
+			if tt.openStream {
+				ctx, cancel := context.WithCancel(context.Background())
+				t.Cleanup(cancel)
+
+				stream, err := healthpb.NewHealthClient(dialTestServer(t, listener)).
+					Watch(ctx, &healthpb.HealthCheckRequest{})
+				assert.NoError(t, err)
+				_, err = stream.Recv()
+				assert.NoError(t, err)
+			}
+
+			stopped := make(chan struct{})
+			go func() {
+				defer close(stopped)
+				GracefulStop(server, 100*time.Millisecond)
+			}()
+
+			select {
+			case <-stopped:
+			case <-time.After(5 * time.Second):
+				server.Stop()
+				t.Fatal("server is not stopped after timeout")
+			}
+		})
+	}
+}
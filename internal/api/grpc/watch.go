@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"errors"
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"github.com/dimuska139/cacher/internal/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// eventTypes соответствие типов событий хранилища типам событий API
+var eventTypes = map[cache.EventType]v1.EventType{
+	cache.EventSet:    v1.EventType_EVENT_TYPE_SET,
+	cache.EventDelete: v1.EventType_EVENT_TYPE_DELETE,
+	cache.EventExpire: v1.EventType_EVENT_TYPE_EXPIRE,
+}
+
+// Watch передаёт клиенту события изменения записей с указанными ключами или префиксом, пока клиент
+// не отменит запрос или сервер не завершит работу. Сразу после подписки отправляются заголовки ответа: после их получения клиент может
+// рассчитывать, что не пропустит изменений. Если хранилище не поддерживает подписку, возвращается
+// codes.Unimplemented, а если клиент не успевает получать события и хранилище закрыло подписку -
+// codes.ResourceExhausted
+func (s *CacheServer) Watch(request *v1.WatchRequest, stream v1.CacheAPI_WatchServer) error {
+	watchStorage, ok := s.storage.(WatchStorage)
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "watch is not supported by the storage")
+	}
+
+	filter := cache.KeyFilter{
+		Keys:   request.GetKeys(),
+		Prefix: request.GetPrefix(),
+	}
+	if filter.Empty() {
+		return status.Errorf(codes.InvalidArgument, "keys or prefix must be specified")
+	}
+
+	ctx := stream.Context()
+	subscription, err := watchStorage.Watch(ctx, filter)
+	if err != nil {
+		if st := contextError(err); st != nil {
+			return st
+		}
+
+		s.logger.Error("Can't watch storage", "err", err)
+		return status.Errorf(codes.Internal, "something went wrong")
+	}
+	defer subscription.Close()
+
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return contextError(ctx.Err())
+
+		case <-s.shutdown:
+			return status.Errorf(codes.Unavailable, "server is shutting down")
+
+		case event, ok := <-subscription.Events():
+			if !ok {
+				if errors.Is(subscription.Err(), cache.ErrSlowConsumer) {
+					return status.Errorf(codes.ResourceExhausted, "client is too slow to receive events")
+				}
+				return status.Errorf(codes.Unavailable, "watch is closed by the storage")
+			}
+
+			err := stream.Send(&v1.WatchEvent{
+				Type:    eventTypes[event.Type],
+				Key:     event.Key,
+				Dropped: event.Dropped,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"github.com/dimuska139/cacher/internal/cache/embedded"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+// newTestClient запускает gRPC-сервер с хранилищем storage в памяти и возвращает подключенного к нему клиента
+func newTestClient(t *testing.T, storage Storage) v1.CacheAPIClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	v1.RegisterCacheAPIServer(server, NewCacheServer(nil, storage))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return v1.NewCacheAPIClient(dialTestServer(t, listener))
+}
+
+// dialTestServer подключается к gRPC-серверу в памяти
+func dialTestServer(t *testing.T, listener *bufconn.Listener) *grpc.ClientConn {
+	t.Helper()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+}
+
+// startWatch подписывается на события и дожидается заголовков ответа, то есть момента подписки
+func startWatch(t *testing.T, client v1.CacheAPIClient, request *v1.WatchRequest) v1.CacheAPI_WatchClient {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	stream, err := client.Watch(ctx, request)
+	assert.NoError(t, err)
+	_, err = stream.Header()
+	assert.NoError(t, err)
+	return stream
+}
+
+func TestCacheServer_Watch(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(10 * time.Millisecond))
+	client := newTestClient(t, storage)
+	ctx := context.Background()
+
+	stream := startWatch(t, client, &v1.WatchRequest{Keys: []string{"key"}, Prefix: "user:"})
+
+	_, err := client.Set(ctx, &v1.SetRequest{Key: "other", Value: []byte("value")})
+	assert.NoError(t, err)
+	_, err = client.Set(ctx, &v1.SetRequest{Key: "user:1", Value: []byte("value"), Ttl: 1})
+	assert.NoError(t, err)
+	_, err = client.Set(ctx, &v1.SetRequest{Key: "key", Value: []byte("value")})
+	assert.NoError(t, err)
+	_, err = client.Delete(ctx, &v1.DeleteRequest{Key: "key"})
+	assert.NoError(t, err)
+
+	want := []*v1.WatchEvent{
+		{Type: v1.EventType_EVENT_TYPE_SET, Key: "user:1"},
+		{Type: v1.EventType_EVENT_TYPE_SET, Key: "key"},
+		{Type: v1.EventType_EVENT_TYPE_DELETE, Key: "key"},
+		{Type: v1.EventType_EVENT_TYPE_EXPIRE, Key: "user:1"},
+	}
+	for _, wantEvent := range want {
+		event, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, wantEvent.GetType(), event.GetType())
+		assert.Equal(t, wantEvent.GetKey(), event.GetKey())
+		assert.Zero(t, event.GetDropped())
+	}
+}
+
+func TestCacheServer_Watch_Shutdown(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	cacheServer := NewCacheServer(nil, storage)
+	v1.RegisterCacheAPIServer(server, cacheServer)
+	go server.Serve(listener)
+
+	stream := startWatch(t, v1.NewCacheAPIClient(dialTestServer(t, listener)), &v1.WatchRequest{Prefix: "key"})
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		cacheServer.Shutdown()
+		// Сервер должен остановиться без принудительного закрытия соединений
+		GracefulStop(server, time.Minute)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		server.Stop()
+		t.Fatal("server is not stopped while watch is open")
+	}
+
+	_, err := stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestCacheServer_Watch_SlowConsumer(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour).
+		WithWatchBuffer(1).
+		WithWatchOverflow(embedded.OverflowDisconnect))
+	client := newTestClient(t, storage)
+
+	stream := startWatch(t, client, &v1.WatchRequest{Prefix: "key"})
+
+	// Отправка клиенту тоже буферизуется, поэтому событий должно быть больше, чем помещается в буферы
+	value := make([]byte, 1<<10)
+	for i := 0; i < 1000; i++ {
+		assert.NoError(t, storage.Set(context.Background(), "key", value, 0))
+	}
+
+	var err error
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestCacheServer_Watch_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		getStorage func(ctrl *gomock.Controller) Storage
+		request    *v1.WatchRequest
+		wantCode   codes.Code
+	}{
+		{
+			name: "storage without watch support",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				return NewMockStorage(ctrl)
+			},
+			request:  &v1.WatchRequest{Keys: []string{"key"}},
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name: "empty filter",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				return embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+			},
+			request:  &v1.WatchRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.getStorage(gomock.NewController(t)))
+
+			stream, err := client.Watch(context.Background(), tt.request)
+			assert.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,499 @@
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/dimuska139/cacher/internal/cache"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// errQuit клиент завершил сеанс командой quit
+	errQuit = errors.New("quit")
+	// errNotSupported хранилище не поддерживает команду
+	errNotSupported = errors.New("command is not supported by the storage")
+)
+
+// execute выполняет одну команду и записывает ответ в w. Ошибка означает, что соединение нужно закрыть
+func (s *Server) execute(r *bufio.Reader, w *bufio.Writer, line []byte) error {
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return reply(w, errorLine)
+	}
+
+	command, args := fields[0], fields[1:]
+	switch command {
+	case "get", "gets":
+		return s.retrieve(w, args, command == "gets")
+	case "gat", "gats":
+		return s.getAndTouch(w, args, command == "gats")
+	case "mg":
+		return s.metaGet(w, args)
+	case "set", "add", "replace", "cas":
+		return s.store(r, w, command, args)
+	case "delete":
+		return s.delete(w, args)
+	case "incr", "decr":
+		return s.incrDecr(w, command, args)
+	case "touch":
+		return s.touch(w, args)
+	case "stats":
+		return s.stats(w, args)
+	case "version":
+		return reply(w, "VERSION "+Version+"\r\n")
+	case "quit":
+		return errQuit
+	}
+
+	return reply(w, errorLine)
+}
+
+// reply записывает строку ответа
+func reply(w *bufio.Writer, line string) error {
+	_, err := w.WriteString(line)
+	return err
+}
+
+// replyUnlessQuiet записывает строку ответа, если в команде не указан noreply
+func replyUnlessQuiet(w *bufio.Writer, quiet bool, line string) error {
+	if quiet {
+		return nil
+	}
+	return reply(w, line)
+}
+
+// storageError отвечает на ошибку хранилища. Если команда прервана остановкой сервера, то ошибка
+// возвращается, чтобы закрыть соединение
+func (s *Server) storageError(w *bufio.Writer, err error, msg string, key string) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return err
+	case errors.Is(err, cache.ErrTooLarge):
+		return reply(w, tooLargeLine)
+	}
+
+	if key != "" {
+		s.logger.Error(msg, "err", err, "key", key)
+	} else {
+		s.logger.Error(msg, "err", err)
+	}
+	return reply(w, serverErrorLine)
+}
+
+// expire удаляет запись, которой команда установила уже истёкшее время жизни
+func (s *Server) expire(w *bufio.Writer, key string) error {
+	if err := s.storage.Delete(s.ctx, key); err != nil {
+		return s.storageError(w, err, "Can't delete expired data from storage", key)
+	}
+	return nil
+}
+
+// retrieve выполняет команды get и gets: "get <key>*"
+func (s *Server) retrieve(w *bufio.Writer, keys []string, withCas bool) error {
+	if len(keys) == 0 {
+		return reply(w, errorLine)
+	}
+	for _, key := range keys {
+		if !validKey(key) {
+			return reply(w, badFormatLine)
+		}
+	}
+
+	var results []cache.GetResult
+	if batch, ok := s.storage.(BatchGetter); ok && len(keys) > 1 {
+		results = batch.GetBatch(s.ctx, keys)
+	} else {
+		results = make([]cache.GetResult, len(keys))
+		for n, key := range keys {
+			results[n].Item, results[n].Err = s.storage.Get(s.ctx, key)
+		}
+	}
+
+	return s.writeValues(w, keys, results, withCas)
+}
+
+// getAndTouch выполняет команды gat и gats: "gat <exptime> <key>*"
+func (s *Server) getAndTouch(w *bufio.Writer, args []string, withCas bool) error {
+	if len(args) < 2 {
+		return reply(w, errorLine)
+	}
+
+	ttl, expired, err := parseExptime(args[0], time.Now())
+	if err != nil {
+		return reply(w, badFormatLine)
+	}
+
+	keys := args[1:]
+	for _, key := range keys {
+		if !validKey(key) {
+			return reply(w, badFormatLine)
+		}
+	}
+
+	results := make([]cache.GetResult, len(keys))
+	for n, key := range keys {
+		results[n].Item, results[n].Err = s.storage.GetAndTouch(s.ctx, key, ttl)
+		if results[n].Err == nil && expired {
+			if err := s.expire(w, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.writeValues(w, keys, results, withCas)
+}
+
+// writeValues записывает найденные записи в ответ на команды get, gets, gat и gats. Если хотя бы одну
+// запись не удалось получить из-за ошибки хранилища, то вместо записей отправляется SERVER_ERROR
+func (s *Server) writeValues(w *bufio.Writer, keys []string, results []cache.GetResult, withCas bool) error {
+	for n, result := range results {
+		if result.Err != nil && !errors.Is(result.Err, cache.ErrNotFound) {
+			return s.storageError(w, result.Err, "Can't get data from storage", keys[n])
+		}
+	}
+
+	var buf []byte
+	for n, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		buf = append(buf[:0], "VALUE "...)
+		buf = append(buf, keys[n]...)
+		buf = append(buf, ' ')
+		buf = strconv.AppendUint(buf, uint64(result.Item.Flags), 10)
+		buf = append(buf, ' ')
+		buf = strconv.AppendInt(buf, int64(len(result.Item.Value)), 10)
+		if withCas {
+			buf = append(buf, ' ')
+			buf = strconv.AppendUint(buf, result.Item.CasID, 10)
+		}
+		buf = append(buf, crlf...)
+		buf = append(buf, result.Item.Value...)
+		buf = append(buf, crlf...)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	return reply(w, endLine)
+}
+
+// metaGet выполняет мета-команду "mg <key> <flag>*". Поддерживаются флаги k (ключ), v (значение), f (флаги записи),
+// c (CAS-идентификатор), t (оставшееся время жизни, -1 - бессрочно), s (размер значения), O (непрозрачный токен)
+// и q (не отвечать, если записи нет)
+func (s *Server) metaGet(w *bufio.Writer, args []string) error {
+	if len(args) == 0 || !validKey(args[0]) {
+		return reply(w, badFormatLine)
+	}
+
+	key, flags := args[0], args[1:]
+	var withValue, quiet bool
+	for _, flag := range flags {
+		switch flag[0] {
+		case 'v':
+			withValue = true
+		case 'q':
+			quiet = true
+		case 'k', 'f', 'c', 't', 's', 'O':
+		default:
+			return reply(w, "CLIENT_ERROR invalid flag\r\n")
+		}
+	}
+
+	item, err := s.storage.Get(s.ctx, key)
+	if errors.Is(err, cache.ErrNotFound) {
+		return replyUnlessQuiet(w, quiet, metaMissLine)
+	}
+	if err != nil {
+		return s.storageError(w, err, "Can't get data from storage", key)
+	}
+
+	var buf []byte
+	if withValue {
+		buf = append(buf, "VA "...)
+		buf = strconv.AppendInt(buf, int64(len(item.Value)), 10)
+	} else {
+		buf = append(buf, "HD"...)
+	}
+	for _, flag := range flags {
+		switch flag[0] {
+		case 'k':
+			buf = append(append(buf, " k"...), key...)
+		case 'f':
+			buf = strconv.AppendUint(append(buf, " f"...), uint64(item.Flags), 10)
+		case 'c':
+			buf = strconv.AppendUint(append(buf, " c"...), item.CasID, 10)
+		case 't':
+			buf = strconv.AppendInt(append(buf, " t"...), ttlSeconds(item.TTL), 10)
+		case 's':
+			buf = strconv.AppendInt(append(buf, " s"...), int64(len(item.Value)), 10)
+		case 'O':
+			buf = append(append(buf, ' '), flag...)
+		}
+	}
+	buf = append(buf, crlf...)
+	if withValue {
+		buf = append(buf, item.Value...)
+		buf = append(buf, crlf...)
+	}
+
+	_, err = w.Write(buf)
+	return err
+}
+
+// store выполняет команды записи: "<set|add|replace> <key> <flags> <exptime> <bytes> [noreply]"
+// и "cas <key> <flags> <exptime> <bytes> <cas unique> [noreply]". Если хранилище не сохраняет флаги
+// (не реализует FlagsStorage), запись с ненулевыми флагами отклоняется
+func (s *Server) store(r *bufio.Reader, w *bufio.Writer, command string, args []string) error {
+	args, quiet := hasNoreply(args)
+	wantArgs := 4
+	if command == "cas" {
+		wantArgs = 5
+	}
+	if len(args) != wantArgs {
+		return reply(w, badFormatLine)
+	}
+
+	// Без размера данных непонятно, где заканчивается блок данных, поэтому он будет разобран как следующая команда
+	size, err := strconv.Atoi(args[3])
+	if err != nil || size < 0 {
+		return reply(w, badFormatLine)
+	}
+
+	key := args[0]
+	flags, flagsErr := strconv.ParseUint(args[1], 10, 32)
+	ttl, expired, exptimeErr := parseExptime(args[2], time.Now())
+	var (
+		casID  uint64
+		casErr error
+	)
+	if command == "cas" {
+		casID, casErr = strconv.ParseUint(args[4], 10, 64)
+	}
+
+	switch {
+	case !validKey(key) || flagsErr != nil || exptimeErr != nil || casErr != nil:
+		if err := discardData(r, size); err != nil {
+			return err
+		}
+		return reply(w, badFormatLine)
+	case flags != 0 && !s.storesFlags():
+		if err := discardData(r, size); err != nil {
+			return err
+		}
+		return reply(w, flagsLine)
+	case size > s.config.MaxValueSize():
+		if err := discardData(r, size); err != nil {
+			return err
+		}
+		return reply(w, tooLargeLine)
+	}
+
+	value, ok, err := readData(r, size)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if err := reply(w, badChunkLine); err != nil {
+			return err
+		}
+		return errBadChunk
+	}
+
+	err = s.storeValue(command, key, value, uint32(flags), ttl, casID)
+	switch {
+	case err == nil:
+		if expired {
+			if err := s.expire(w, key); err != nil {
+				return err
+			}
+		}
+		return replyUnlessQuiet(w, quiet, storedLine)
+	case errors.Is(err, cache.ErrNotStored):
+		return replyUnlessQuiet(w, quiet, notStoredLine)
+	case errors.Is(err, cache.ErrExists):
+		return replyUnlessQuiet(w, quiet, existsLine)
+	case errors.Is(err, cache.ErrNotFound):
+		return replyUnlessQuiet(w, quiet, notFoundLine)
+	case errors.Is(err, errNotSupported):
+		return reply(w, notSupportedPfx+command+"\r\n")
+	}
+
+	return s.storageError(w, err, "Can't save data to storage", key)
+}
+
+// storesFlags проверяет, сохраняет ли хранилище флаги записей
+func (s *Server) storesFlags() bool {
+	_, ok := s.storage.(FlagsStorage)
+	return ok
+}
+
+// storeValue записывает значение в хранилище согласно команде
+func (s *Server) storeValue(command string, key string, value []byte, flags uint32, ttl time.Duration, casID uint64) error {
+	if flagsStorage, ok := s.storage.(FlagsStorage); ok {
+		switch command {
+		case "set":
+			return flagsStorage.SetWithFlags(s.ctx, key, value, flags, ttl)
+		case "add":
+			return flagsStorage.AddWithFlags(s.ctx, key, value, flags, ttl)
+		case "replace":
+			return flagsStorage.ReplaceWithFlags(s.ctx, key, value, flags, ttl)
+		default:
+			return flagsStorage.CompareAndSwapWithFlags(s.ctx, key, value, flags, ttl, casID)
+		}
+	}
+
+	if command == "set" {
+		return s.storage.Set(s.ctx, key, value, ttl)
+	}
+
+	if command == "cas" {
+		casStorage, ok := s.storage.(CASStorage)
+		if !ok {
+			return errNotSupported
+		}
+		return casStorage.CompareAndSwap(s.ctx, key, value, ttl, casID)
+	}
+
+	conditional, ok := s.storage.(ConditionalStorage)
+	if !ok {
+		return errNotSupported
+	}
+
+	if command == "add" {
+		return conditional.Add(s.ctx, key, value, ttl)
+	}
+	return conditional.Replace(s.ctx, key, value, ttl)
+}
+
+// delete выполняет команду "delete <key> [0] [noreply]". Если хранилище не сообщает, была ли запись
+// (не реализует ExistingDeleter), ответ всегда DELETED
+func (s *Server) delete(w *bufio.Writer, args []string) error {
+	args, quiet := hasNoreply(args)
+	if len(args) == 2 && args[1] == "0" {
+		args = args[:1]
+	}
+	if len(args) != 1 || !validKey(args[0]) {
+		return reply(w, badFormatLine)
+	}
+
+	var err error
+	if deleter, ok := s.storage.(ExistingDeleter); ok {
+		err = deleter.DeleteExisting(s.ctx, args[0])
+	} else {
+		err = s.storage.Delete(s.ctx, args[0])
+	}
+	switch {
+	case err == nil:
+		return replyUnlessQuiet(w, quiet, deletedLine)
+	case errors.Is(err, cache.ErrNotFound):
+		return replyUnlessQuiet(w, quiet, notFoundLine)
+	}
+
+	return s.storageError(w, err, "Can't delete data from storage", args[0])
+}
+
+// incrDecr выполняет команды "<incr|decr> <key> <delta> [noreply]"
+func (s *Server) incrDecr(w *bufio.Writer, command string, args []string) error {
+	args, quiet := hasNoreply(args)
+	if len(args) != 2 || !validKey(args[0]) {
+		return reply(w, badFormatLine)
+	}
+
+	delta, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return reply(w, invalidDelta)
+	}
+
+	var value uint64
+	if command == "incr" {
+		value, err = s.storage.Increment(s.ctx, args[0], delta)
+	} else {
+		value, err = s.storage.Decrement(s.ctx, args[0], delta)
+	}
+
+	switch {
+	case err == nil:
+		return replyUnlessQuiet(w, quiet, strconv.FormatUint(value, 10)+"\r\n")
+	case errors.Is(err, cache.ErrNotFound):
+		return replyUnlessQuiet(w, quiet, notFoundLine)
+	case errors.Is(err, cache.ErrNotNumeric):
+		return reply(w, nonNumericLine)
+	}
+
+	return s.storageError(w, err, "Can't increment value in storage", args[0])
+}
+
+// touch выполняет команду "touch <key> <exptime> [noreply]"
+func (s *Server) touch(w *bufio.Writer, args []string) error {
+	args, quiet := hasNoreply(args)
+	if len(args) != 2 || !validKey(args[0]) {
+		return reply(w, badFormatLine)
+	}
+
+	ttl, expired, err := parseExptime(args[1], time.Now())
+	if err != nil {
+		return reply(w, badFormatLine)
+	}
+
+	err = s.storage.Touch(s.ctx, args[0], ttl)
+	switch {
+	case err == nil:
+		if expired {
+			if err := s.expire(w, args[0]); err != nil {
+				return err
+			}
+		}
+		return replyUnlessQuiet(w, quiet, touchedLine)
+	case errors.Is(err, cache.ErrNotFound):
+		return replyUnlessQuiet(w, quiet, notFoundLine)
+	}
+
+	return s.storageError(w, err, "Can't touch data in storage", args[0])
+}
+
+// stats выполняет команду stats без аргументов. Отдельные группы статистики (stats items, stats slabs и т.д.)
+// не поддерживаются
+func (s *Server) stats(w *bufio.Writer, args []string) error {
+	if len(args) > 0 {
+		return reply(w, errorLine)
+	}
+
+	stats, err := s.storage.Stats(s.ctx)
+	if err != nil {
+		return s.storageError(w, err, "Can't get stats from storage", "")
+	}
+
+	now := time.Now()
+	values := []struct {
+		name  string
+		value string
+	}{
+		{"pid", strconv.Itoa(os.Getpid())},
+		{"uptime", strconv.FormatInt(int64(now.Sub(s.started)/time.Second), 10)},
+		{"time", strconv.FormatInt(now.Unix(), 10)},
+		{"version", Version},
+		{"curr_connections", strconv.FormatInt(s.CurrConns(), 10)},
+		{"total_connections", strconv.FormatUint(s.TotalConns(), 10)},
+		{"cmd_get", strconv.FormatUint(stats.GetHits+stats.GetMisses, 10)},
+		{"cmd_set", strconv.FormatUint(stats.Sets, 10)},
+		{"get_hits", strconv.FormatUint(stats.GetHits, 10)},
+		{"get_misses", strconv.FormatUint(stats.GetMisses, 10)},
+		{"curr_items", strconv.FormatUint(stats.Items, 10)},
+		{"bytes", strconv.FormatUint(stats.Bytes, 10)},
+		{"evictions", strconv.FormatUint(stats.Evictions, 10)},
+		{"reclaimed", strconv.FormatUint(stats.Expirations, 10)},
+	}
+	for _, stat := range values {
+		if err := reply(w, "STAT "+stat.name+" "+stat.value+"\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return reply(w, endLine)
+}
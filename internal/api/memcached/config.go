@@ -0,0 +1,69 @@
+package memcached
+
+import "time"
+
+const (
+	// DefaultMaxLineLength максимальная длина строки команды по умолчанию. Её должно хватать на команду get
+	// с сотнями ключей максимальной длины
+	DefaultMaxLineLength = 64 << 10
+	// DefaultMaxValueSize максимальный размер значения по умолчанию, как у Memcache
+	DefaultMaxValueSize = 1 << 20
+)
+
+// minLineLength минимальная длина строки команды: в неё должна помещаться команда cas с ключом максимальной длины
+const minLineLength = 512
+
+// Config конфигурация сервера, работающего по текстовому протоколу Memcache
+type Config struct {
+	maxLineLength int
+	maxValueSize  int
+	idleTimeout   time.Duration
+}
+
+// NewConfig создаёт конфигурацию сервера с ограничениями по умолчанию
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// WithMaxLineLength устанавливает максимальную длину строки команды вместе с \r\n (0 - DefaultMaxLineLength).
+// Более длинная строка пропускается целиком, клиенту отвечается CLIENT_ERROR, а соединение продолжает работать
+func (c *Config) WithMaxLineLength(maxLineLength int) *Config {
+	c.maxLineLength = maxLineLength
+	return c
+}
+
+// MaxLineLength возвращает максимальную длину строки команды вместе с \r\n
+func (c *Config) MaxLineLength() int {
+	switch {
+	case c.maxLineLength <= 0:
+		return DefaultMaxLineLength
+	case c.maxLineLength < minLineLength:
+		return minLineLength
+	}
+	return c.maxLineLength
+}
+
+// WithMaxValueSize устанавливает максимальный размер значения в байтах (0 - DefaultMaxValueSize)
+func (c *Config) WithMaxValueSize(maxValueSize int) *Config {
+	c.maxValueSize = maxValueSize
+	return c
+}
+
+// MaxValueSize возвращает максимальный размер значения в байтах
+func (c *Config) MaxValueSize() int {
+	if c.maxValueSize <= 0 {
+		return DefaultMaxValueSize
+	}
+	return c.maxValueSize
+}
+
+// WithIdleTimeout устанавливает время, после которого соединение без команд закрывается (0 - без ограничений)
+func (c *Config) WithIdleTimeout(idleTimeout time.Duration) *Config {
+	c.idleTimeout = idleTimeout
+	return c
+}
+
+// IdleTimeout возвращает время, после которого соединение без команд закрывается (0 - без ограничений)
+func (c *Config) IdleTimeout() time.Duration {
+	return c.idleTimeout
+}
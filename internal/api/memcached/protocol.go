@@ -0,0 +1,156 @@
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxKeyLength максимальная длина ключа в протоколе Memcache
+	maxKeyLength = 250
+	// maxRelativeExptime время жизни больше 30 дней Memcache считает Unix-временем истечения
+	maxRelativeExptime = 60 * 60 * 24 * 30
+	// noreply последний аргумент команды, подавляющий ответ при успешном выполнении
+	noreply = "noreply"
+)
+
+// Строки ответов
+const (
+	storedLine      = "STORED\r\n"
+	notStoredLine   = "NOT_STORED\r\n"
+	existsLine      = "EXISTS\r\n"
+	notFoundLine    = "NOT_FOUND\r\n"
+	deletedLine     = "DELETED\r\n"
+	touchedLine     = "TOUCHED\r\n"
+	endLine         = "END\r\n"
+	errorLine       = "ERROR\r\n"
+	metaMissLine    = "EN\r\n"
+	badFormatLine   = "CLIENT_ERROR bad command line format\r\n"
+	badChunkLine    = "CLIENT_ERROR bad data chunk\r\n"
+	lineTooLongLine = "CLIENT_ERROR line is too long\r\n"
+	invalidDelta    = "CLIENT_ERROR invalid numeric delta argument\r\n"
+	flagsLine       = "CLIENT_ERROR flags are not supported\r\n"
+	nonNumericLine  = "CLIENT_ERROR cannot increment or decrement non-numeric value\r\n"
+	tooLargeLine    = "SERVER_ERROR object too large for cache\r\n"
+	notSupportedPfx = "SERVER_ERROR not supported by the storage: "
+	serverErrorLine = "SERVER_ERROR something went wrong\r\n"
+)
+
+var crlf = []byte("\r\n")
+
+var (
+	// errLineTooLong строка команды длиннее допустимой
+	errLineTooLong = errors.New("line is too long")
+	// errBadChunk блок данных не завершается \r\n. Где начинается следующая команда, неизвестно,
+	// поэтому соединение закрывается
+	errBadChunk = errors.New("bad data chunk")
+	// errBadFormat аргументы команды не соответствуют протоколу
+	errBadFormat = errors.New("bad command line format")
+)
+
+// readLine читает строку команды без завершающего \r\n (допускается и просто \n, как в Memcache).
+// Длина строки ограничена размером буфера r
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return nil, errLineTooLong
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line = line[:len(line)-1]
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line, nil
+}
+
+// discardLine пропускает остаток слишком длинной строки команды
+func discardLine(r *bufio.Reader) error {
+	for {
+		_, err := r.ReadSlice('\n')
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return err
+		}
+	}
+}
+
+// readData читает блок данных длиной size и завершающий его \r\n. Если блок не завершается \r\n,
+// то возвращается ok = false
+func readData(r *bufio.Reader, size int) (data []byte, ok bool, err error) {
+	data = make([]byte, size+len(crlf))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, false, err
+	}
+
+	if !bytes.HasSuffix(data, crlf) {
+		return nil, false, nil
+	}
+	return data[:size], true, nil
+}
+
+// discardData пропускает блок данных длиной size вместе с завершающим \r\n
+func discardData(r *bufio.Reader, size int) error {
+	_, err := r.Discard(size + len(crlf))
+	return err
+}
+
+// validKey проверяет, что ключ не длиннее maxKeyLength и не содержит управляющих символов
+func validKey(key string) bool {
+	if key == "" || len(key) > maxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// hasNoreply проверяет, что последний аргумент команды - noreply. Возвращает аргументы без него
+func hasNoreply(args []string) ([]string, bool) {
+	if n := len(args); n > 0 && args[n-1] == noreply {
+		return args[:n-1], true
+	}
+	return args, false
+}
+
+// parseExptime разбирает время жизни из команды. Как и в Memcache, 0 означает бессрочную запись,
+// значения больше 30 дней - Unix-время истечения, а отрицательные значения - уже истёкшую запись.
+// Для истёкшей записи возвращается expired = true
+func parseExptime(arg string, now time.Time) (ttl time.Duration, expired bool, err error) {
+	exptime, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, false, errBadFormat
+	}
+
+	switch {
+	case exptime == 0:
+		return 0, false, nil
+	case exptime < 0:
+		return 0, true, nil
+	case exptime <= maxRelativeExptime:
+		return time.Duration(exptime) * time.Second, false, nil
+	}
+
+	ttl = time.Unix(exptime, 0).Sub(now)
+	if ttl <= 0 {
+		return 0, true, nil
+	}
+	// Хранилища могут отбрасывать доли секунды, а запись не должна стать бессрочной, поэтому округляем вверх
+	return (ttl + time.Second - 1).Truncate(time.Second), false, nil
+}
+
+// ttlSeconds округляет оставшееся время жизни вверх до целых секунд (-1 - бессрочная запись)
+func ttlSeconds(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return -1
+	}
+	return int64((ttl + time.Second - 1) / time.Second)
+}
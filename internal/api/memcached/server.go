@@ -0,0 +1,147 @@
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/dimuska139/cacher/internal/api/tcpserver"
+	"github.com/dimuska139/cacher/internal/cache"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+//go:generate mockgen -source=server.go -destination=./server_mock.go -package=memcached
+
+// Version версия Memcache, совместимость с протоколом которой заявляет сервер (команды version и stats)
+const Version = "1.6.21"
+
+// ErrServerClosed сервер остановлен
+var ErrServerClosed = tcpserver.ErrServerClosed
+
+// Logger интерфейс для логгера
+type Logger interface {
+	Error(msg string, args ...interface{})
+}
+
+// Storage хранилище
+type Storage interface {
+	Get(ctx context.Context, key string) (*cache.Item, error)
+	GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Increment(ctx context.Context, key string, delta uint64) (uint64, error)
+	Decrement(ctx context.Context, key string, delta uint64) (uint64, error)
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+	Stats(ctx context.Context) (*cache.Stats, error)
+}
+
+// ConditionalStorage хранилище, которое умеет атомарно записывать данные в зависимости от наличия записи.
+// Реализовывать его необязательно: для остальных хранилищ команды add и replace возвращают SERVER_ERROR
+type ConditionalStorage interface {
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CASStorage хранилище, которое умеет атомарно записывать данные, если запись не изменилась с момента чтения.
+// Реализовывать его необязательно (например, Redis не хранит CAS-идентификаторы): для остальных хранилищ
+// команда cas возвращает SERVER_ERROR
+type CASStorage interface {
+	CompareAndSwap(ctx context.Context, key string, value []byte, ttl time.Duration, casID uint64) error
+}
+
+// FlagsStorage хранилище, которое умеет сохранять произвольные флаги вместе со значением и возвращать их при чтении.
+// Реализовывать его необязательно: для остальных хранилищ запись с ненулевыми флагами отклоняется,
+// чтобы клиент не получил при чтении другие флаги
+type FlagsStorage interface {
+	SetWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error
+	AddWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error
+	ReplaceWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error
+	CompareAndSwapWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration, casID uint64) error
+}
+
+// ExistingDeleter хранилище, которое умеет сообщать, была ли удалённая запись. Реализовывать его необязательно:
+// для остальных хранилищ delete всегда отвечает DELETED
+type ExistingDeleter interface {
+	DeleteExisting(ctx context.Context, key string) error
+}
+
+// BatchGetter хранилище, которое умеет получать несколько записей за один раз эффективнее, чем по одной.
+// Реализовывать его необязательно
+type BatchGetter interface {
+	GetBatch(ctx context.Context, keys []string) []cache.GetResult
+}
+
+// Server сервер, работающий по текстовому протоколу Memcache поверх хранилища. Позволяет использовать
+// сервис вместо Memcache без изменения клиентов. Флаги записей сохраняются, если хранилище реализует FlagsStorage
+type Server struct {
+	*tcpserver.Server
+
+	config  *Config
+	storage Storage
+	logger  Logger
+	started time.Time
+	// Контекст команд, отменяется при принудительном закрытии соединений
+	ctx context.Context
+}
+
+// NewServer создаёт сервер, работающий по текстовому протоколу Memcache поверх хранилища
+func NewServer(config *Config, storage Storage, logger Logger) *Server {
+	s := &Server{
+		config:  config,
+		storage: storage,
+		logger:  logger,
+		started: time.Now(),
+	}
+	s.Server = tcpserver.NewServer(s.serveConn)
+	s.ctx = s.Server.Context()
+	return s
+}
+
+// serveConn читает команды клиента и отвечает на них, пока клиент не закроет соединение или не пришлёт quit.
+// Ответы буферизуются и отправляются, когда прочитаны все уже полученные команды, поэтому несколько команд,
+// отправленных без ожидания ответа, обходятся одной записью в сокет
+func (s *Server) serveConn(c *tcpserver.Conn) {
+	r := bufio.NewReaderSize(c, s.config.MaxLineLength())
+	w := bufio.NewWriter(c)
+	for !s.Closing() {
+		if timeout := s.config.IdleTimeout(); timeout > 0 {
+			c.SetReadDeadline(time.Now().Add(timeout))
+		}
+
+		line, err := readLine(r)
+		if errors.Is(err, errLineTooLong) {
+			// Остаток строки пропускается, чтобы следующие команды разбирались правильно
+			err = discardLine(r)
+			if err == nil {
+				w.WriteString(lineTooLongLine)
+				err = w.Flush()
+			}
+			if err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) && !errors.Is(err, os.ErrDeadlineExceeded) {
+				s.logger.Error("Can't read memcached command", "err", err, "remote", c.RemoteAddr().String())
+			}
+			return
+		}
+
+		c.SetBusy(true)
+		err = s.execute(r, w, line)
+		if err == nil && r.Buffered() == 0 {
+			err = w.Flush()
+		}
+		c.SetBusy(false)
+
+		if err != nil {
+			// Ответ на последнюю команду (например, на неразборчивый блок данных) отправляется перед закрытием
+			if w.Flush() == nil && errors.Is(err, errBadChunk) {
+				c.Drain()
+			}
+			return
+		}
+	}
+}
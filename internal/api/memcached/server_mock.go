@@ -0,0 +1,435 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: server.go
+
+// Package memcached is a generated GoMock package.
+package memcached
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	cache "github.com/dimuska139/cacher/internal/cache"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLogger is a mock of Logger interface.
+type MockLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoggerMockRecorder
+}
+
+// MockLoggerMockRecorder is the mock recorder for MockLogger.
+type MockLoggerMockRecorder struct {
+	mock *MockLogger
+}
+
+// NewMockLogger creates a new mock instance.
+func NewMockLogger(ctrl *gomock.Controller) *MockLogger {
+	mock := &MockLogger{ctrl: ctrl}
+	mock.recorder = &MockLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogger) EXPECT() *MockLoggerMockRecorder {
+	return m.recorder
+}
+
+// Error mocks base method.
+func (m *MockLogger) Error(msg string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockLoggerMockRecorder) Error(msg interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockLogger)(nil).Error), varargs...)
+}
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// Decrement mocks base method.
+func (m *MockStorage) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrement", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrement indicates an expected call of Decrement.
+func (mr *MockStorageMockRecorder) Decrement(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrement", reflect.TypeOf((*MockStorage)(nil).Decrement), ctx, key, delta)
+}
+
+// Delete mocks base method.
+func (m *MockStorage) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockStorage) Get(ctx context.Context, key string) (*cache.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*cache.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockStorageMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStorage)(nil).Get), ctx, key)
+}
+
+// GetAndTouch mocks base method.
+func (m *MockStorage) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAndTouch", ctx, key, ttl)
+	ret0, _ := ret[0].(*cache.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAndTouch indicates an expected call of GetAndTouch.
+func (mr *MockStorageMockRecorder) GetAndTouch(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAndTouch", reflect.TypeOf((*MockStorage)(nil).GetAndTouch), ctx, key, ttl)
+}
+
+// Increment mocks base method.
+func (m *MockStorage) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockStorageMockRecorder) Increment(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockStorage)(nil).Increment), ctx, key, delta)
+}
+
+// Set mocks base method.
+func (m *MockStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockStorageMockRecorder) Set(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockStorage)(nil).Set), ctx, key, value, ttl)
+}
+
+// Stats mocks base method.
+func (m *MockStorage) Stats(ctx context.Context) (*cache.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(*cache.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockStorageMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockStorage)(nil).Stats), ctx)
+}
+
+// Touch mocks base method.
+func (m *MockStorage) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, key, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockStorageMockRecorder) Touch(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockStorage)(nil).Touch), ctx, key, ttl)
+}
+
+// MockConditionalStorage is a mock of ConditionalStorage interface.
+type MockConditionalStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockConditionalStorageMockRecorder
+}
+
+// MockConditionalStorageMockRecorder is the mock recorder for MockConditionalStorage.
+type MockConditionalStorageMockRecorder struct {
+	mock *MockConditionalStorage
+}
+
+// NewMockConditionalStorage creates a new mock instance.
+func NewMockConditionalStorage(ctrl *gomock.Controller) *MockConditionalStorage {
+	mock := &MockConditionalStorage{ctrl: ctrl}
+	mock.recorder = &MockConditionalStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConditionalStorage) EXPECT() *MockConditionalStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockConditionalStorage) Add(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockConditionalStorageMockRecorder) Add(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockConditionalStorage)(nil).Add), ctx, key, value, ttl)
+}
+
+// Replace mocks base method.
+func (m *MockConditionalStorage) Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockConditionalStorageMockRecorder) Replace(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockConditionalStorage)(nil).Replace), ctx, key, value, ttl)
+}
+
+// MockCASStorage is a mock of CASStorage interface.
+type MockCASStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockCASStorageMockRecorder
+}
+
+// MockCASStorageMockRecorder is the mock recorder for MockCASStorage.
+type MockCASStorageMockRecorder struct {
+	mock *MockCASStorage
+}
+
+// NewMockCASStorage creates a new mock instance.
+func NewMockCASStorage(ctrl *gomock.Controller) *MockCASStorage {
+	mock := &MockCASStorage{ctrl: ctrl}
+	mock.recorder = &MockCASStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCASStorage) EXPECT() *MockCASStorageMockRecorder {
+	return m.recorder
+}
+
+// CompareAndSwap mocks base method.
+func (m *MockCASStorage) CompareAndSwap(ctx context.Context, key string, value []byte, ttl time.Duration, casID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSwap", ctx, key, value, ttl, casID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareAndSwap indicates an expected call of CompareAndSwap.
+func (mr *MockCASStorageMockRecorder) CompareAndSwap(ctx, key, value, ttl, casID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSwap", reflect.TypeOf((*MockCASStorage)(nil).CompareAndSwap), ctx, key, value, ttl, casID)
+}
+
+// MockFlagsStorage is a mock of FlagsStorage interface.
+type MockFlagsStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlagsStorageMockRecorder
+}
+
+// MockFlagsStorageMockRecorder is the mock recorder for MockFlagsStorage.
+type MockFlagsStorageMockRecorder struct {
+	mock *MockFlagsStorage
+}
+
+// NewMockFlagsStorage creates a new mock instance.
+func NewMockFlagsStorage(ctrl *gomock.Controller) *MockFlagsStorage {
+	mock := &MockFlagsStorage{ctrl: ctrl}
+	mock.recorder = &MockFlagsStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlagsStorage) EXPECT() *MockFlagsStorageMockRecorder {
+	return m.recorder
+}
+
+// AddWithFlags mocks base method.
+func (m *MockFlagsStorage) AddWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWithFlags", ctx, key, value, flags, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWithFlags indicates an expected call of AddWithFlags.
+func (mr *MockFlagsStorageMockRecorder) AddWithFlags(ctx, key, value, flags, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWithFlags", reflect.TypeOf((*MockFlagsStorage)(nil).AddWithFlags), ctx, key, value, flags, ttl)
+}
+
+// CompareAndSwapWithFlags mocks base method.
+func (m *MockFlagsStorage) CompareAndSwapWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration, casID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSwapWithFlags", ctx, key, value, flags, ttl, casID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareAndSwapWithFlags indicates an expected call of CompareAndSwapWithFlags.
+func (mr *MockFlagsStorageMockRecorder) CompareAndSwapWithFlags(ctx, key, value, flags, ttl, casID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSwapWithFlags", reflect.TypeOf((*MockFlagsStorage)(nil).CompareAndSwapWithFlags), ctx, key, value, flags, ttl, casID)
+}
+
+// ReplaceWithFlags mocks base method.
+func (m *MockFlagsStorage) ReplaceWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceWithFlags", ctx, key, value, flags, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceWithFlags indicates an expected call of ReplaceWithFlags.
+func (mr *MockFlagsStorageMockRecorder) ReplaceWithFlags(ctx, key, value, flags, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceWithFlags", reflect.TypeOf((*MockFlagsStorage)(nil).ReplaceWithFlags), ctx, key, value, flags, ttl)
+}
+
+// SetWithFlags mocks base method.
+func (m *MockFlagsStorage) SetWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithFlags", ctx, key, value, flags, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWithFlags indicates an expected call of SetWithFlags.
+func (mr *MockFlagsStorageMockRecorder) SetWithFlags(ctx, key, value, flags, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithFlags", reflect.TypeOf((*MockFlagsStorage)(nil).SetWithFlags), ctx, key, value, flags, ttl)
+}
+
+// MockExistingDeleter is a mock of ExistingDeleter interface.
+type MockExistingDeleter struct {
+	ctrl     *gomock.Controller
+	recorder *MockExistingDeleterMockRecorder
+}
+
+// MockExistingDeleterMockRecorder is the mock recorder for MockExistingDeleter.
+type MockExistingDeleterMockRecorder struct {
+	mock *MockExistingDeleter
+}
+
+// NewMockExistingDeleter creates a new mock instance.
+func NewMockExistingDeleter(ctrl *gomock.Controller) *MockExistingDeleter {
+	mock := &MockExistingDeleter{ctrl: ctrl}
+	mock.recorder = &MockExistingDeleterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExistingDeleter) EXPECT() *MockExistingDeleterMockRecorder {
+	return m.recorder
+}
+
+// DeleteExisting mocks base method.
+func (m *MockExistingDeleter) DeleteExisting(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExisting", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExisting indicates an expected call of DeleteExisting.
+func (mr *MockExistingDeleterMockRecorder) DeleteExisting(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExisting", reflect.TypeOf((*MockExistingDeleter)(nil).DeleteExisting), ctx, key)
+}
+
+// MockBatchGetter is a mock of BatchGetter interface.
+type MockBatchGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchGetterMockRecorder
+}
+
+// MockBatchGetterMockRecorder is the mock recorder for MockBatchGetter.
+type MockBatchGetterMockRecorder struct {
+	mock *MockBatchGetter
+}
+
+// NewMockBatchGetter creates a new mock instance.
+func NewMockBatchGetter(ctrl *gomock.Controller) *MockBatchGetter {
+	mock := &MockBatchGetter{ctrl: ctrl}
+	mock.recorder = &MockBatchGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchGetter) EXPECT() *MockBatchGetterMockRecorder {
+	return m.recorder
+}
+
+// GetBatch mocks base method.
+func (m *MockBatchGetter) GetBatch(ctx context.Context, keys []string) []cache.GetResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, keys)
+	ret0, _ := ret[0].([]cache.GetResult)
+	return ret0
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockBatchGetterMockRecorder) GetBatch(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockBatchGetter)(nil).GetBatch), ctx, keys)
+}
@@ -0,0 +1,437 @@
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/dimuska139/cacher/internal/cache/embedded"
+	memcacheClient "github.com/dimuska139/cacher/libs/memcache"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startServer запускает сервер поверх хранилища storage на свободном порту и возвращает его адрес
+func startServer(t *testing.T, config *Config, storage Storage, logger Logger) (*Server, net.Addr) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := NewServer(config, storage, logger)
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Shutdown(context.Background())
+	})
+
+	return server, listener.Addr()
+}
+
+// newClient создаёт клиент Memcache, подключающийся к серверу по адресу addr
+func newClient(addr net.Addr) *memcacheClient.Client {
+	return memcacheClient.NewMemcacheClient(memcacheClient.NewConfig([]net.Addr{addr}, 1, time.Second))
+}
+
+func TestServer_Client(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+	_, addr := startServer(t, NewConfig(), storage, nil)
+	client := newClient(addr)
+	ctx := context.Background()
+
+	t.Run("set and get", func(t *testing.T) {
+		value := []byte("first\r\nEND\r\nVALUE key 0 1\r\n")
+		assert.NoError(t, client.Set(ctx, "plain", value, 0))
+
+		got, err := client.Get(ctx, "plain")
+		assert.NoError(t, err)
+		assert.Equal(t, value, got)
+
+		_, err = client.Get(ctx, "missing")
+		assert.ErrorIs(t, err, memcacheClient.ErrNotFound)
+	})
+
+	t.Run("get item", func(t *testing.T) {
+		assert.NoError(t, client.Set(ctx, "item", []byte("value"), 100))
+
+		item, err := client.GetItem(ctx, "item")
+		assert.NoError(t, err)
+		assert.Equal(t, "item", item.Key)
+		assert.Equal(t, []byte("value"), item.Value)
+		assert.Equal(t, int64(100), item.Expiration)
+		assert.NotZero(t, item.CasID)
+
+		_, err = client.GetItem(ctx, "missing")
+		assert.ErrorIs(t, err, memcacheClient.ErrNotFound)
+	})
+
+	t.Run("get multi", func(t *testing.T) {
+		assert.NoError(t, client.Set(ctx, "multi-1", []byte("first"), 0))
+		assert.NoError(t, client.Set(ctx, "multi-2", []byte("second"), 0))
+
+		items, err := client.GetMulti(ctx, []string{"multi-1", "multi-2", "missing"})
+		assert.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.Equal(t, []byte("first"), items["multi-1"].Value)
+		assert.Equal(t, []byte("second"), items["multi-2"].Value)
+	})
+
+	t.Run("add and replace", func(t *testing.T) {
+		assert.ErrorIs(t, client.Replace(ctx, "conditional", []byte("value"), 0), memcacheClient.ErrNotStored)
+		assert.NoError(t, client.Add(ctx, "conditional", []byte("first"), 0))
+		assert.ErrorIs(t, client.Add(ctx, "conditional", []byte("second"), 0), memcacheClient.ErrNotStored)
+		assert.NoError(t, client.Replace(ctx, "conditional", []byte("third"), 0))
+
+		got, err := client.Get(ctx, "conditional")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("third"), got)
+	})
+
+	t.Run("compare and swap", func(t *testing.T) {
+		assert.NoError(t, client.Set(ctx, "cas", []byte("first"), 0))
+		item, err := client.GetItem(ctx, "cas")
+		assert.NoError(t, err)
+
+		assert.NoError(t, client.CompareAndSwap(ctx, &memcacheClient.Item{Key: "cas", Value: []byte("second")}, item.CasID))
+		err = client.CompareAndSwap(ctx, &memcacheClient.Item{Key: "cas", Value: []byte("third")}, item.CasID)
+		assert.ErrorIs(t, err, memcacheClient.ErrExists)
+		err = client.CompareAndSwap(ctx, &memcacheClient.Item{Key: "missing", Value: []byte("value")}, item.CasID)
+		assert.ErrorIs(t, err, memcacheClient.ErrNotFound)
+
+		got, err := client.Get(ctx, "cas")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("second"), got)
+	})
+
+	t.Run("increment and decrement", func(t *testing.T) {
+		assert.NoError(t, client.Set(ctx, "counter", []byte("10"), 0))
+
+		value, err := client.Increment(ctx, "counter", 5)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(15), value)
+
+		value, err = client.Decrement(ctx, "counter", 20)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), value)
+
+		_, err = client.Increment(ctx, "missing", 1)
+		assert.ErrorIs(t, err, memcacheClient.ErrNotFound)
+
+		assert.NoError(t, client.Set(ctx, "text", []byte("text"), 0))
+		_, err = client.Increment(ctx, "text", 1)
+		assert.ErrorIs(t, err, memcacheClient.ErrNonNumeric)
+	})
+
+	t.Run("touch", func(t *testing.T) {
+		assert.NoError(t, client.Set(ctx, "touch", []byte("value"), 0))
+		assert.NoError(t, client.Touch(ctx, "touch", 100))
+		assert.ErrorIs(t, client.Touch(ctx, "missing", 100), memcacheClient.ErrNotFound)
+
+		item, err := client.GetAndTouch(ctx, "touch", 200)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), item.Value)
+
+		item, err = client.GetItem(ctx, "touch")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(200), item.Expiration)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, client.Set(ctx, "delete", []byte("value"), 0))
+		assert.NoError(t, client.Delete(ctx, "delete"))
+		_, err := client.Get(ctx, "delete")
+		assert.ErrorIs(t, err, memcacheClient.ErrNotFound)
+	})
+
+	t.Run("pipelined writes", func(t *testing.T) {
+		errs := client.SetMulti(ctx, []*memcacheClient.Item{
+			{Key: "pipeline-1", Value: []byte("first")},
+			{Key: "pipeline-2", Value: []byte("second")},
+		})
+		assert.Equal(t, []error{nil, nil}, errs)
+
+		errs = client.DeleteMulti(ctx, []string{"pipeline-1", "pipeline-2"})
+		assert.Equal(t, []error{nil, nil}, errs)
+
+		_, err := client.Get(ctx, "pipeline-1")
+		assert.ErrorIs(t, err, memcacheClient.ErrNotFound)
+	})
+
+	t.Run("stats and version", func(t *testing.T) {
+		stats, err := client.Stats(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, Version, stats[addr.String()]["version"])
+		assert.NotEmpty(t, stats[addr.String()]["curr_items"])
+
+		versions, err := client.Version(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{addr.String(): Version}, versions)
+	})
+}
+
+func TestServer_Protocol(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		want    string
+	}{
+		{
+			name:    "noreply",
+			request: "set key 0 0 2 noreply\r\n10\r\nincr key 1 noreply\r\ndelete key noreply\r\nget key\r\n",
+			want:    "END\r\n",
+		},
+		{
+			name:    "pipelined commands",
+			request: "set key 0 0 5\r\nvalue\r\ngets key\r\ndelete key\r\nget key\r\n",
+			want:    "STORED\r\nVALUE key 0 5 1\r\nvalue\r\nEND\r\nDELETED\r\nEND\r\n",
+		},
+		{
+			name:    "delete missing key",
+			request: "delete key\r\nset key 0 0 5\r\nvalue\r\ndelete key 0\r\ndelete key\r\n",
+			want:    "NOT_FOUND\r\nSTORED\r\nDELETED\r\nNOT_FOUND\r\n",
+		},
+		{
+			name:    "flags",
+			request: "set key 42 0 5\r\nvalue\r\nget key\r\nreplace key 0 0 5\r\nvalue\r\nmg key f\r\n",
+			want:    "STORED\r\nVALUE key 42 5\r\nvalue\r\nEND\r\nSTORED\r\nHD f0\r\n",
+		},
+		{
+			name:    "empty value",
+			request: "set key 0 0 0\r\n\r\nget key\r\n",
+			want:    "STORED\r\nVALUE key 0 0\r\n\r\nEND\r\n",
+		},
+		{
+			name:    "newline without carriage return",
+			request: "version\n",
+			want:    "VERSION " + Version + "\r\n",
+		},
+		{
+			name:    "unknown command",
+			request: "flush_all\r\n\r\n",
+			want:    "ERROR\r\nERROR\r\n",
+		},
+		{
+			name:    "get without keys",
+			request: "get\r\n",
+			want:    "ERROR\r\n",
+		},
+		{
+			name:    "too long key",
+			request: "get " + strings.Repeat("k", maxKeyLength+1) + "\r\n",
+			want:    "CLIENT_ERROR bad command line format\r\n",
+		},
+		{
+			name:    "bad storage command",
+			request: "set key flags 0 5\r\nvalue\r\nset key 0 0\r\n",
+			want:    "CLIENT_ERROR bad command line format\r\nCLIENT_ERROR bad command line format\r\n",
+		},
+		{
+			name:    "bad data chunk",
+			request: "set key 0 0 3\r\nvalue\r\n",
+			want:    "CLIENT_ERROR bad data chunk\r\n",
+		},
+		{
+			name:    "too large value",
+			request: "set key 0 0 20\r\n" + strings.Repeat("v", 20) + "\r\nget key\r\n",
+			want:    "SERVER_ERROR object too large for cache\r\nEND\r\n",
+		},
+		{
+			name:    "invalid delta",
+			request: "set key 0 0 1\r\n1\r\nincr key -1\r\n",
+			want:    "STORED\r\nCLIENT_ERROR invalid numeric delta argument\r\n",
+		},
+		{
+			name:    "negative exptime",
+			request: "set key 0 -1 5\r\nvalue\r\nget key\r\n",
+			want:    "STORED\r\nEND\r\n",
+		},
+		{
+			name:    "past absolute exptime",
+			request: "set key 0 0 5\r\nvalue\r\ntouch key 1000000000\r\nget key\r\n",
+			want:    "STORED\r\nTOUCHED\r\nEND\r\n",
+		},
+		{
+			name:    "meta get",
+			request: "set key 0 0 5\r\nvalue\r\nmg key s t v Oopaque\r\nmg key k\r\nmg missing v\r\nmg missing v q\r\nmg key x\r\n",
+			want: "STORED\r\nVA 5 s5 t-1 Oopaque\r\nvalue\r\nHD kkey\r\nEN\r\n" +
+				"CLIENT_ERROR invalid flag\r\n",
+		},
+		{
+			name:    "quit",
+			request: "quit\r\nversion\r\n",
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+			_, addr := startServer(t, NewConfig().WithMaxValueSize(10), storage, nil)
+
+			conn, err := net.Dial("tcp", addr.String())
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			_, err = conn.Write([]byte(tt.request))
+			assert.NoError(t, err)
+			// Соединение закрывается командой quit или сервером по истечении дедлайна
+			conn.(*net.TCPConn).CloseWrite()
+
+			got, err := io.ReadAll(conn)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestServer_LineTooLong(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+	_, addr := startServer(t, NewConfig().WithMaxLineLength(minLineLength), storage, nil)
+
+	conn, err := net.Dial("tcp", addr.String())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// После слишком длинной строки соединение продолжает работать
+	_, err = conn.Write([]byte("get " + strings.Repeat("key ", minLineLength) + "\r\nversion\r\n"))
+	assert.NoError(t, err)
+	conn.(*net.TCPConn).CloseWrite()
+
+	got, err := io.ReadAll(conn)
+	assert.NoError(t, err)
+	assert.Equal(t, lineTooLongLine+"VERSION "+Version+"\r\n", string(got))
+}
+
+// conditionalStorage хранилище с условной записью, но без CAS (как хранилище Redis)
+type conditionalStorage struct {
+	*MockStorage
+	*MockConditionalStorage
+}
+
+func TestServer_StorageErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		getStorage func(ctrl *gomock.Controller) Storage
+		getLogger  func(ctrl *gomock.Controller) Logger
+		request    string
+		want       string
+	}{
+		{
+			name: "conditional writes are not supported",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				return NewMockStorage(ctrl)
+			},
+			getLogger: func(ctrl *gomock.Controller) Logger {
+				return NewMockLogger(ctrl)
+			},
+			request: "add key 0 0 5\r\nvalue\r\n",
+			want:    "SERVER_ERROR not supported by the storage: add\r\n",
+		},
+		{
+			name: "add without cas support",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				conditional := NewMockConditionalStorage(ctrl)
+				conditional.EXPECT().
+					Add(gomock.Any(), "key", []byte("value"), time.Duration(0)).
+					Return(nil).
+					Times(1)
+				return &conditionalStorage{MockStorage: NewMockStorage(ctrl), MockConditionalStorage: conditional}
+			},
+			getLogger: func(ctrl *gomock.Controller) Logger {
+				return NewMockLogger(ctrl)
+			},
+			request: "add key 0 0 5\r\nvalue\r\n",
+			want:    "STORED\r\n",
+		},
+		{
+			name: "cas is not supported",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				return &conditionalStorage{MockStorage: NewMockStorage(ctrl), MockConditionalStorage: NewMockConditionalStorage(ctrl)}
+			},
+			getLogger: func(ctrl *gomock.Controller) Logger {
+				return NewMockLogger(ctrl)
+			},
+			request: "cas key 0 0 5 1\r\nvalue\r\n",
+			want:    "SERVER_ERROR not supported by the storage: cas\r\n",
+		},
+		{
+			name: "flags are not supported",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				return NewMockStorage(ctrl)
+			},
+			getLogger: func(ctrl *gomock.Controller) Logger {
+				return NewMockLogger(ctrl)
+			},
+			request: "set key 1 0 5\r\nvalue\r\n",
+			want:    "CLIENT_ERROR flags are not supported\r\n",
+		},
+		{
+			name: "storage error",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				storage := NewMockStorage(ctrl)
+				storage.EXPECT().
+					Get(gomock.Any(), "key").
+					Return(nil, errors.New("something went wrong")).
+					Times(1)
+				return storage
+			},
+			getLogger: func(ctrl *gomock.Controller) Logger {
+				logger := NewMockLogger(ctrl)
+				logger.EXPECT().
+					Error("Can't get data from storage", gomock.Any()).
+					Times(1)
+				return logger
+			},
+			request: "get key\r\n",
+			want:    "SERVER_ERROR something went wrong\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			_, addr := startServer(t, NewConfig(), tt.getStorage(ctrl), tt.getLogger(ctrl))
+
+			conn, err := net.Dial("tcp", addr.String())
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			_, err = conn.Write([]byte(tt.request))
+			assert.NoError(t, err)
+
+			got, err := bufio.NewReader(conn).ReadString('\n')
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := NewServer(NewConfig(), storage, nil)
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("version\r\n"))
+	assert.NoError(t, err)
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, server.Shutdown(ctx))
+	assert.ErrorIs(t, <-served, ErrServerClosed)
+
+	// Соединение без выполняющихся команд закрыто сервером
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,133 @@
+package resp
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// respError ответ-ошибка
+type respError string
+
+// testClient минимальный клиент RESP для тестов: отправляет команды массивами строк и разбирает ответы
+// RESP2 и RESP3. Простые строки возвращаются как string, двоичные - как []byte, числа - как int64,
+// Null - как nil, массивы - как []interface{}, словари - как map[string]interface{}
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// newTestClient подключается к серверу по адресу addr
+func newTestClient(t *testing.T, addr net.Addr) *testClient {
+	t.Helper()
+
+	conn, err := net.DialTimeout("tcp", addr.String(), time.Second)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	return &testClient{
+		t:    t,
+		conn: conn,
+		r:    bufio.NewReader(conn),
+	}
+}
+
+// encode кодирует команду массивом двоичных строк
+func encode(args ...string) []byte {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+	}
+	return buf
+}
+
+// Write отправляет произвольные байты
+func (c *testClient) Write(data []byte) {
+	_, err := c.conn.Write(data)
+	assert.NoError(c.t, err)
+}
+
+// Do отправляет команду и возвращает ответ
+func (c *testClient) Do(args ...string) interface{} {
+	c.Write(encode(args...))
+	return c.Receive()
+}
+
+// Receive читает и разбирает один ответ
+func (c *testClient) Receive() interface{} {
+	reply, err := c.read()
+	assert.NoError(c.t, err)
+	return reply
+}
+
+// read читает один ответ
+func (c *testClient) read() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2:] != "\r\n" {
+		return nil, fmt.Errorf("malformed line %q", line)
+	}
+	prefix, payload := line[0], line[1:len(line)-2]
+
+	switch prefix {
+	case '+':
+		return payload, nil
+	case '-':
+		return respError(payload), nil
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '_':
+		return nil, nil
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(payload)
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, count)
+		for n := range items {
+			if items[n], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case '%':
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		items := make(map[string]interface{}, count)
+		for n := 0; n < count; n++ {
+			key, err := c.read()
+			if err != nil {
+				return nil, err
+			}
+			if items[string(key.([]byte))], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("unknown reply type %q", prefix)
+}
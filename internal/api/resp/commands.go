@@ -0,0 +1,583 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dimuska139/cacher/internal/cache"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errQuit клиент завершил сеанс командой QUIT
+var errQuit = errors.New("quit")
+
+// Сообщения об ошибках, совпадающие с сообщениями Redis
+const (
+	syntaxError     = "ERR syntax error"
+	notIntegerError = "ERR value is not an integer or out of range"
+	serverError     = "ERR something went wrong"
+	tooLargeError   = "ERR value is too large"
+)
+
+// command команда и количество её аргументов вместе с названием команды, как в Redis:
+// положительное - точное количество, отрицательное - минимальное
+type command struct {
+	arity   int
+	handler func(s *Server, sess *session, args [][]byte) error
+}
+
+var commands = map[string]command{
+	"get":    {arity: 2, handler: (*Server).get},
+	"set":    {arity: -3, handler: (*Server).set},
+	"del":    {arity: -2, handler: (*Server).del},
+	"exists": {arity: -2, handler: (*Server).exists},
+	"expire": {arity: -3, handler: (*Server).expire},
+	"ttl":    {arity: 2, handler: (*Server).ttl},
+	"incr":   {arity: 2, handler: (*Server).incr},
+	"decr":   {arity: 2, handler: (*Server).decr},
+	"incrby": {arity: 3, handler: (*Server).incrBy},
+	"decrby": {arity: 3, handler: (*Server).decrBy},
+	"mget":   {arity: -2, handler: (*Server).mget},
+	"mset":   {arity: -3, handler: (*Server).mset},
+	"ping":   {arity: -1, handler: (*Server).ping},
+	"echo":   {arity: 2, handler: (*Server).echo},
+	"info":   {arity: -1, handler: (*Server).info},
+	"hello":  {arity: -1, handler: (*Server).hello},
+	"select": {arity: 2, handler: (*Server).selectDB},
+	"quit":   {arity: -1, handler: (*Server).quit},
+}
+
+// execute выполняет одну команду и записывает ответ. Ошибка означает, что соединение нужно закрыть
+func (s *Server) execute(sess *session, args [][]byte) error {
+	name := strings.ToLower(string(args[0]))
+	cmd, ok := commands[name]
+	if !ok {
+		var b strings.Builder
+		for _, arg := range args[1:] {
+			fmt.Fprintf(&b, "'%s' ", arg)
+		}
+		return sess.w.Error(fmt.Sprintf("ERR unknown command '%s', with args beginning with: %s", args[0], b.String()))
+	}
+
+	if (cmd.arity > 0 && len(args) != cmd.arity) || (cmd.arity < 0 && len(args) < -cmd.arity) {
+		return wrongArgs(sess, name)
+	}
+
+	return cmd.handler(s, sess, args)
+}
+
+// wrongArgs отвечает на команду с неправильным количеством аргументов
+func wrongArgs(sess *session, name string) error {
+	return sess.w.Error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", name))
+}
+
+// storageError отвечает на ошибку хранилища. Если команда прервана остановкой сервера, то ошибка
+// возвращается, чтобы закрыть соединение
+func (s *Server) storageError(sess *session, err error, msg string, key string) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return err
+	case errors.Is(err, cache.ErrTooLarge):
+		return sess.w.Error(tooLargeError)
+	}
+
+	if key != "" {
+		s.logger.Error(msg, "err", err, "key", key)
+	} else {
+		s.logger.Error(msg, "err", err)
+	}
+	return sess.w.Error(serverError)
+}
+
+// parseInt разбирает целочисленный аргумент
+func parseInt(arg []byte) (int64, bool) {
+	n, err := strconv.ParseInt(string(arg), 10, 64)
+	return n, err == nil
+}
+
+// get выполняет команду GET key
+func (s *Server) get(sess *session, args [][]byte) error {
+	key := string(args[1])
+	item, err := s.storage.Get(s.ctx, key)
+	switch {
+	case errors.Is(err, cache.ErrNotFound):
+		return sess.w.Null()
+	case err != nil:
+		return s.storageError(sess, err, "Can't get data from storage", key)
+	}
+
+	return sess.w.Bulk(item.Value)
+}
+
+// set выполняет команду SET key value [NX | XX] [EX seconds | PX milliseconds]. Если условие NX или XX
+// не выполнено, возвращается Null
+func (s *Server) set(sess *session, args [][]byte) error {
+	key, value := string(args[1]), args[2]
+
+	var (
+		ttl        time.Duration
+		nx, xx     bool
+		ttlOptions int
+	)
+	for n := 3; n < len(args); n++ {
+		switch option := strings.ToUpper(string(args[n])); option {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "EX", "PX":
+			if n+1 == len(args) {
+				return sess.w.Error(syntaxError)
+			}
+			n++
+			amount, ok := parseInt(args[n])
+			if !ok {
+				return sess.w.Error(notIntegerError)
+			}
+			if amount <= 0 {
+				return sess.w.Error("ERR invalid expire time in 'set' command")
+			}
+			ttlOptions++
+			if option == "EX" {
+				ttl = time.Duration(amount) * time.Second
+			} else {
+				ttl = time.Duration(amount) * time.Millisecond
+			}
+		default:
+			return sess.w.Error(syntaxError)
+		}
+	}
+	if (nx && xx) || ttlOptions > 1 {
+		return sess.w.Error(syntaxError)
+	}
+
+	var err error
+	if nx || xx {
+		conditional, ok := s.storage.(ConditionalStorage)
+		if !ok {
+			return sess.w.Error("ERR NX and XX options are not supported by the storage")
+		}
+		if nx {
+			err = conditional.Add(s.ctx, key, value, ttl)
+		} else {
+			err = conditional.Replace(s.ctx, key, value, ttl)
+		}
+	} else {
+		err = s.storage.Set(s.ctx, key, value, ttl)
+	}
+
+	switch {
+	case errors.Is(err, cache.ErrNotStored):
+		return sess.w.Null()
+	case err != nil:
+		return s.storageError(sess, err, "Can't save data to storage", key)
+	}
+
+	return sess.w.OK()
+}
+
+// keyExists проверяет, есть ли запись в хранилище
+func (s *Server) keyExists(key string) (bool, error) {
+	_, err := s.storage.Get(s.ctx, key)
+	switch {
+	case errors.Is(err, cache.ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+// del выполняет команду DEL key [key ...] и возвращает количество удалённых записей. Хранилище не сообщает,
+// была ли запись, поэтому записи проверяются перед удалением, и при одновременном изменении количество
+// может быть неточным
+func (s *Server) del(sess *session, args [][]byte) error {
+	var deleted int64
+	for _, arg := range args[1:] {
+		key := string(arg)
+		exists, err := s.keyExists(key)
+		if err != nil {
+			return s.storageError(sess, err, "Can't get data from storage", key)
+		}
+		if !exists {
+			continue
+		}
+
+		if err := s.storage.Delete(s.ctx, key); err != nil {
+			return s.storageError(sess, err, "Can't delete data from storage", key)
+		}
+		deleted++
+	}
+
+	return sess.w.Integer(deleted)
+}
+
+// exists выполняет команду EXISTS key [key ...]. Повторяющиеся ключи, как и в Redis, учитываются несколько раз
+func (s *Server) exists(sess *session, args [][]byte) error {
+	var count int64
+	for _, arg := range args[1:] {
+		exists, err := s.keyExists(string(arg))
+		if err != nil {
+			return s.storageError(sess, err, "Can't get data from storage", string(arg))
+		}
+		if exists {
+			count++
+		}
+	}
+
+	return sess.w.Integer(count)
+}
+
+// expire выполняет команду EXPIRE key seconds. Возвращает 1, если время жизни установлено, и 0, если записи нет.
+// Неположительное время жизни, как и в Redis, удаляет запись. Опции NX, XX, GT и LT не поддерживаются
+func (s *Server) expire(sess *session, args [][]byte) error {
+	if len(args) > 3 {
+		return sess.w.Error("ERR EXPIRE options are not supported")
+	}
+
+	key := string(args[1])
+	seconds, ok := parseInt(args[2])
+	if !ok {
+		return sess.w.Error(notIntegerError)
+	}
+
+	if seconds <= 0 {
+		return s.del(sess, args[:2])
+	}
+	if seconds > math.MaxInt64/int64(time.Second) {
+		return sess.w.Error("ERR invalid expire time in 'expire' command")
+	}
+
+	err := s.storage.Touch(s.ctx, key, time.Duration(seconds)*time.Second)
+	switch {
+	case errors.Is(err, cache.ErrNotFound):
+		return sess.w.Integer(0)
+	case err != nil:
+		return s.storageError(sess, err, "Can't touch data in storage", key)
+	}
+
+	return sess.w.Integer(1)
+}
+
+// ttl выполняет команду TTL key: оставшееся время жизни в секундах, -1 для бессрочной записи
+// и -2, если записи нет
+func (s *Server) ttl(sess *session, args [][]byte) error {
+	key := string(args[1])
+	item, err := s.storage.Get(s.ctx, key)
+	switch {
+	case errors.Is(err, cache.ErrNotFound):
+		return sess.w.Integer(-2)
+	case err != nil:
+		return s.storageError(sess, err, "Can't get data from storage", key)
+	case item.TTL <= 0:
+		return sess.w.Integer(-1)
+	}
+
+	// Как и Redis, округляем до ближайшей секунды
+	return sess.w.Integer(int64((item.TTL + time.Second/2) / time.Second))
+}
+
+// incr выполняет команду INCR key
+func (s *Server) incr(sess *session, args [][]byte) error {
+	return s.add(sess, string(args[1]), 1)
+}
+
+// decr выполняет команду DECR key
+func (s *Server) decr(sess *session, args [][]byte) error {
+	return s.add(sess, string(args[1]), -1)
+}
+
+// incrBy выполняет команду INCRBY key increment
+func (s *Server) incrBy(sess *session, args [][]byte) error {
+	delta, ok := parseInt(args[2])
+	if !ok {
+		return sess.w.Error(notIntegerError)
+	}
+	return s.add(sess, string(args[1]), delta)
+}
+
+// decrBy выполняет команду DECRBY key decrement
+func (s *Server) decrBy(sess *session, args [][]byte) error {
+	delta, ok := parseInt(args[2])
+	if !ok || delta == math.MinInt64 {
+		return sess.w.Error(notIntegerError)
+	}
+	return s.add(sess, string(args[1]), -delta)
+}
+
+// add изменяет числовое значение записи на delta. Как и в Redis, отсутствующая запись считается равной нулю.
+// Если хранилище поддерживает условную запись, то новая запись создаётся атомарно
+func (s *Server) add(sess *session, key string, delta int64) error {
+	for {
+		var (
+			value uint64
+			err   error
+		)
+		if delta >= 0 {
+			value, err = s.storage.Increment(s.ctx, key, uint64(delta))
+		} else {
+			value, err = s.storage.Decrement(s.ctx, key, uint64(-(delta+1))+1)
+		}
+
+		switch {
+		case err == nil:
+			if value > math.MaxInt64 {
+				return sess.w.Error("ERR increment or decrement would overflow")
+			}
+			return sess.w.Integer(int64(value))
+		case errors.Is(err, cache.ErrNotNumeric):
+			return sess.w.Error(notIntegerError)
+		case !errors.Is(err, cache.ErrNotFound):
+			return s.storageError(sess, err, "Can't increment value in storage", key)
+		}
+
+		initial := delta
+		if initial < 0 {
+			initial = 0
+		}
+		value = uint64(initial)
+		data := []byte(strconv.FormatUint(value, 10))
+
+		conditional, ok := s.storage.(ConditionalStorage)
+		if ok {
+			err = conditional.Add(s.ctx, key, data, 0)
+		} else {
+			err = s.storage.Set(s.ctx, key, data, 0)
+		}
+
+		switch {
+		case err == nil:
+			return sess.w.Integer(int64(value))
+		case errors.Is(err, cache.ErrNotStored):
+			// Запись успели создать, повторяем изменение
+			continue
+		}
+		return s.storageError(sess, err, "Can't save data to storage", key)
+	}
+}
+
+// mget выполняет команду MGET key [key ...]. Для отсутствующих записей возвращается Null
+func (s *Server) mget(sess *session, args [][]byte) error {
+	keys := make([]string, len(args)-1)
+	for n, arg := range args[1:] {
+		keys[n] = string(arg)
+	}
+
+	var results []cache.GetResult
+	if batch, ok := s.storage.(BatchStorage); ok {
+		results = batch.GetBatch(s.ctx, keys)
+	} else {
+		results = make([]cache.GetResult, len(keys))
+		for n, key := range keys {
+			results[n].Item, results[n].Err = s.storage.Get(s.ctx, key)
+		}
+	}
+
+	for n, result := range results {
+		if result.Err != nil && !errors.Is(result.Err, cache.ErrNotFound) {
+			return s.storageError(sess, result.Err, "Can't get data from storage", keys[n])
+		}
+	}
+
+	if err := sess.w.Array(len(results)); err != nil {
+		return err
+	}
+	for _, result := range results {
+		var err error
+		if result.Err != nil {
+			err = sess.w.Null()
+		} else {
+			err = sess.w.Bulk(result.Item.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mset выполняет команду MSET key value [key value ...]. Записи сохраняются без времени жизни
+func (s *Server) mset(sess *session, args [][]byte) error {
+	if len(args)%2 == 0 {
+		return wrongArgs(sess, "mset")
+	}
+
+	entries := make([]cache.Entry, 0, len(args)/2)
+	for n := 1; n < len(args); n += 2 {
+		entries = append(entries, cache.Entry{Key: string(args[n]), Value: args[n+1]})
+	}
+
+	var errs []error
+	if batch, ok := s.storage.(BatchStorage); ok {
+		errs = batch.SetBatch(s.ctx, entries)
+	} else {
+		errs = make([]error, len(entries))
+		for n, entry := range entries {
+			errs[n] = s.storage.Set(s.ctx, entry.Key, entry.Value, entry.TTL)
+		}
+	}
+
+	for n, err := range errs {
+		if err != nil {
+			return s.storageError(sess, err, "Can't save data to storage", entries[n].Key)
+		}
+	}
+
+	return sess.w.OK()
+}
+
+// ping выполняет команду PING [message]
+func (s *Server) ping(sess *session, args [][]byte) error {
+	switch len(args) {
+	case 1:
+		return sess.w.SimpleString("PONG")
+	case 2:
+		return sess.w.Bulk(args[1])
+	}
+	return wrongArgs(sess, "ping")
+}
+
+// echo выполняет команду ECHO message
+func (s *Server) echo(sess *session, args [][]byte) error {
+	return sess.w.Bulk(args[1])
+}
+
+// info выполняет команду INFO [section ...]. Поддерживаются разделы server, clients, stats и keyspace
+func (s *Server) info(sess *session, args [][]byte) error {
+	sections := map[string]bool{}
+	for _, arg := range args[1:] {
+		sections[strings.ToLower(string(arg))] = true
+	}
+	all := len(sections) == 0 || sections["all"] || sections["default"] || sections["everything"]
+
+	stats, err := s.storage.Stats(s.ctx)
+	if err != nil {
+		return s.storageError(sess, err, "Can't get stats from storage", "")
+	}
+
+	var b strings.Builder
+	section := func(name string, fields ...string) {
+		if !all && !sections[name] {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString("# " + strings.ToUpper(name[:1]) + name[1:] + "\r\n")
+		for n := 0; n < len(fields); n += 2 {
+			b.WriteString(fields[n] + ":" + fields[n+1] + "\r\n")
+		}
+	}
+
+	uptime := time.Since(s.started) / time.Second
+	section("server",
+		"redis_version", Version,
+		"redis_mode", "standalone",
+		"process_id", strconv.Itoa(os.Getpid()),
+		"uptime_in_seconds", strconv.FormatInt(int64(uptime), 10),
+		"uptime_in_days", strconv.FormatInt(int64(uptime/(24*60*60)), 10))
+	section("clients",
+		"connected_clients", strconv.FormatInt(s.CurrConns(), 10))
+	section("stats",
+		"total_connections_received", strconv.FormatUint(s.TotalConns(), 10),
+		"keyspace_hits", strconv.FormatUint(stats.GetHits, 10),
+		"keyspace_misses", strconv.FormatUint(stats.GetMisses, 10),
+		"expired_keys", strconv.FormatUint(stats.Expirations, 10),
+		"evicted_keys", strconv.FormatUint(stats.Evictions, 10))
+	section("keyspace",
+		"db0", fmt.Sprintf("keys=%d,expires=0,avg_ttl=0", stats.Items))
+
+	return sess.w.BulkString(b.String())
+}
+
+// hello выполняет команду HELLO [protover [SETNAME clientname]]: переключает версию протокола
+// и возвращает сведения о сервере. Аутентификация не поддерживается
+func (s *Server) hello(sess *session, args [][]byte) error {
+	proto := sess.w.proto
+	if len(args) > 1 {
+		version, ok := parseInt(args[1])
+		if !ok {
+			return sess.w.Error("ERR Protocol version is not an integer or out of range")
+		}
+		if version != resp2 && version != resp3 {
+			return sess.w.Error("NOPROTO unsupported protocol version")
+		}
+		proto = int(version)
+	}
+
+	for n := 2; n < len(args); n++ {
+		switch strings.ToUpper(string(args[n])) {
+		case "SETNAME":
+			if n+1 == len(args) {
+				return sess.w.Error(syntaxError)
+			}
+			n++
+		case "AUTH":
+			return sess.w.Error("ERR AUTH is not supported")
+		default:
+			return sess.w.Error(syntaxError)
+		}
+	}
+
+	sess.w.proto = proto
+	fields := []struct {
+		name  string
+		value interface{}
+	}{
+		{"server", "redis"},
+		{"version", Version},
+		{"proto", int64(proto)},
+		{"id", int64(sess.id)},
+		{"mode", "standalone"},
+		{"role", "master"},
+		{"modules", nil},
+	}
+	if err := sess.w.Map(len(fields)); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if err := sess.w.BulkString(field.name); err != nil {
+			return err
+		}
+
+		var err error
+		switch value := field.value.(type) {
+		case string:
+			err = sess.w.BulkString(value)
+		case int64:
+			err = sess.w.Integer(value)
+		default:
+			err = sess.w.Array(0)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// selectDB выполняет команду SELECT index. Есть только база 0
+func (s *Server) selectDB(sess *session, args [][]byte) error {
+	index, ok := parseInt(args[1])
+	if !ok {
+		return sess.w.Error(notIntegerError)
+	}
+	if index != 0 {
+		return sess.w.Error("ERR DB index is out of range")
+	}
+	return sess.w.OK()
+}
+
+// quit выполняет команду QUIT: отвечает OK и закрывает соединение
+func (s *Server) quit(sess *session, args [][]byte) error {
+	if err := sess.w.OK(); err != nil {
+		return err
+	}
+	return errQuit
+}
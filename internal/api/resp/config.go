@@ -0,0 +1,85 @@
+package resp
+
+import "time"
+
+const (
+	// DefaultMaxInlineLength максимальная длина inline-команды по умолчанию, как у Redis
+	DefaultMaxInlineLength = 64 << 10
+	// DefaultMaxBulkLength максимальная длина одного аргумента команды по умолчанию
+	DefaultMaxBulkLength = 1 << 20
+	// DefaultMaxArgs максимальное количество аргументов команды по умолчанию
+	DefaultMaxArgs = 1 << 16
+)
+
+// minInlineLength минимальная длина inline-команды: строки с длиной аргумента и количеством аргументов
+// читаются тем же буфером
+const minInlineLength = 512
+
+// Config конфигурация сервера, работающего по протоколу Redis (RESP)
+type Config struct {
+	maxInlineLength int
+	maxBulkLength   int
+	maxArgs         int
+	idleTimeout     time.Duration
+}
+
+// NewConfig создаёт конфигурацию сервера с ограничениями по умолчанию
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// WithMaxInlineLength устанавливает максимальную длину inline-команды вместе с \r\n (0 - DefaultMaxInlineLength)
+func (c *Config) WithMaxInlineLength(maxInlineLength int) *Config {
+	c.maxInlineLength = maxInlineLength
+	return c
+}
+
+// MaxInlineLength возвращает максимальную длину inline-команды вместе с \r\n
+func (c *Config) MaxInlineLength() int {
+	switch {
+	case c.maxInlineLength <= 0:
+		return DefaultMaxInlineLength
+	case c.maxInlineLength < minInlineLength:
+		return minInlineLength
+	}
+	return c.maxInlineLength
+}
+
+// WithMaxBulkLength устанавливает максимальную длину одного аргумента команды в байтах (0 - DefaultMaxBulkLength)
+func (c *Config) WithMaxBulkLength(maxBulkLength int) *Config {
+	c.maxBulkLength = maxBulkLength
+	return c
+}
+
+// MaxBulkLength возвращает максимальную длину одного аргумента команды в байтах
+func (c *Config) MaxBulkLength() int {
+	if c.maxBulkLength <= 0 {
+		return DefaultMaxBulkLength
+	}
+	return c.maxBulkLength
+}
+
+// WithMaxArgs устанавливает максимальное количество аргументов команды (0 - DefaultMaxArgs)
+func (c *Config) WithMaxArgs(maxArgs int) *Config {
+	c.maxArgs = maxArgs
+	return c
+}
+
+// MaxArgs возвращает максимальное количество аргументов команды
+func (c *Config) MaxArgs() int {
+	if c.maxArgs <= 0 {
+		return DefaultMaxArgs
+	}
+	return c.maxArgs
+}
+
+// WithIdleTimeout устанавливает время, после которого соединение без команд закрывается (0 - без ограничений)
+func (c *Config) WithIdleTimeout(idleTimeout time.Duration) *Config {
+	c.idleTimeout = idleTimeout
+	return c
+}
+
+// IdleTimeout возвращает время, после которого соединение без команд закрывается (0 - без ограничений)
+func (c *Config) IdleTimeout() time.Duration {
+	return c.idleTimeout
+}
@@ -0,0 +1,218 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	// resp2 версия протокола по умолчанию
+	resp2 = 2
+	// resp3 версия протокола, включаемая командой HELLO 3
+	resp3 = 3
+)
+
+var crlf = []byte("\r\n")
+
+// protocolError запрос не соответствует протоколу. Где начинается следующая команда, неизвестно,
+// поэтому после ответа на такую ошибку соединение закрывается, как и в Redis
+type protocolError string
+
+// Error возвращает текст ошибки
+func (e protocolError) Error() string {
+	return "Protocol error: " + string(e)
+}
+
+// commandReader читает команды клиента: массивы строк RESP ("*<count>\r\n$<len>\r\n<arg>\r\n...")
+// или inline-команды, аргументы которых разделены пробелами
+type commandReader struct {
+	r             *bufio.Reader
+	maxBulkLength int
+	maxArgs       int
+}
+
+// newCommandReader создаёт читатель команд. Длина inline-команды ограничена размером буфера r
+func newCommandReader(r *bufio.Reader, config *Config) *commandReader {
+	return &commandReader{
+		r:             r,
+		maxBulkLength: config.MaxBulkLength(),
+		maxArgs:       config.MaxArgs(),
+	}
+}
+
+// Buffered возвращает количество уже полученных, но ещё не прочитанных байт
+func (c *commandReader) Buffered() int {
+	return c.r.Buffered()
+}
+
+// ReadCommand читает следующую команду. Для пустых команд возвращает пустой список аргументов
+func (c *commandReader) ReadCommand() ([][]byte, error) {
+	prefix, err := c.r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix[0] != '*' {
+		line, err := c.readLine("too big inline request")
+		if err != nil {
+			return nil, err
+		}
+		return bytes.Fields(line), nil
+	}
+
+	line, err := c.readLine("too big mbulk count string")
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(string(line[1:]))
+	if err != nil || count > c.maxArgs {
+		return nil, protocolError("invalid multibulk length")
+	}
+	if count <= 0 {
+		return nil, nil
+	}
+
+	args := make([][]byte, count)
+	for n := range args {
+		line, err := c.readLine("too big bulk count string")
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, protocolError(fmt.Sprintf("expected '$', got '%c'", firstByte(line)))
+		}
+
+		size, err := strconv.Atoi(string(line[1:]))
+		if err != nil || size < 0 || size > c.maxBulkLength {
+			return nil, protocolError("invalid bulk length")
+		}
+
+		arg := make([]byte, size+len(crlf))
+		if _, err := io.ReadFull(c.r, arg); err != nil {
+			return nil, err
+		}
+		if !bytes.HasSuffix(arg, crlf) {
+			return nil, protocolError("bulk string is not terminated with CRLF")
+		}
+		args[n] = arg[:size]
+	}
+
+	return args, nil
+}
+
+// readLine читает строку без завершающего \r\n (допускается и просто \n). Если строка не помещается в буфер,
+// возвращается ошибка протокола tooLong
+func (c *commandReader) readLine(tooLong string) ([]byte, error) {
+	line, err := c.r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return nil, protocolError(tooLong)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line = line[:len(line)-1]
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line, nil
+}
+
+// firstByte возвращает первый байт строки или пробел для пустой строки
+func firstByte(line []byte) byte {
+	if len(line) == 0 {
+		return ' '
+	}
+	return line[0]
+}
+
+// replyWriter записывает ответы в формате выбранной версии протокола
+type replyWriter struct {
+	*bufio.Writer
+	proto int
+	buf   []byte
+}
+
+// newReplyWriter создаёт писатель ответов по протоколу RESP2
+func newReplyWriter(w *bufio.Writer) *replyWriter {
+	return &replyWriter{
+		Writer: w,
+		proto:  resp2,
+	}
+}
+
+// header записывает строку с префиксом типа и числом: "<prefix><n>\r\n"
+func (w *replyWriter) header(prefix byte, n int64) error {
+	w.buf = append(w.buf[:0], prefix)
+	w.buf = strconv.AppendInt(w.buf, n, 10)
+	w.buf = append(w.buf, crlf...)
+	_, err := w.Write(w.buf)
+	return err
+}
+
+// SimpleString записывает простую строку: "+<s>\r\n"
+func (w *replyWriter) SimpleString(s string) error {
+	_, err := w.WriteString("+" + s + "\r\n")
+	return err
+}
+
+// OK записывает ответ +OK
+func (w *replyWriter) OK() error {
+	return w.SimpleString("OK")
+}
+
+// Error записывает ошибку: "-<msg>\r\n". Сообщение должно начинаться с кода ошибки (ERR, WRONGTYPE и т.д.)
+func (w *replyWriter) Error(msg string) error {
+	_, err := w.WriteString("-" + msg + "\r\n")
+	return err
+}
+
+// Integer записывает целое число: ":<n>\r\n"
+func (w *replyWriter) Integer(n int64) error {
+	return w.header(':', n)
+}
+
+// Bulk записывает двоичную строку: "$<len>\r\n<data>\r\n"
+func (w *replyWriter) Bulk(data []byte) error {
+	if err := w.header('$', int64(len(data))); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	_, err := w.Write(crlf)
+	return err
+}
+
+// BulkString записывает строку как двоичную строку
+func (w *replyWriter) BulkString(s string) error {
+	return w.Bulk([]byte(s))
+}
+
+// Null записывает отсутствующее значение: "$-1\r\n" в RESP2 и "_\r\n" в RESP3
+func (w *replyWriter) Null() error {
+	if w.proto == resp3 {
+		_, err := w.WriteString("_\r\n")
+		return err
+	}
+	_, err := w.WriteString("$-1\r\n")
+	return err
+}
+
+// Array записывает заголовок массива из n элементов: "*<n>\r\n"
+func (w *replyWriter) Array(n int) error {
+	return w.header('*', int64(n))
+}
+
+// Map записывает заголовок словаря из n пар: "%<n>\r\n" в RESP3. В RESP2 словарь передаётся
+// массивом из 2n элементов
+func (w *replyWriter) Map(n int) error {
+	if w.proto == resp3 {
+		return w.header('%', int64(n))
+	}
+	return w.header('*', int64(2*n))
+}
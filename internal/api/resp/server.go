@@ -0,0 +1,137 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/dimuska139/cacher/internal/api/tcpserver"
+	"github.com/dimuska139/cacher/internal/cache"
+	"io"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+//go:generate mockgen -source=server.go -destination=./server_mock.go -package=resp
+
+// Version версия Redis, совместимость с протоколом которой заявляет сервер (команды HELLO и INFO)
+const Version = "7.0.0"
+
+// ErrServerClosed сервер остановлен
+var ErrServerClosed = tcpserver.ErrServerClosed
+
+// Logger интерфейс для логгера
+type Logger interface {
+	Error(msg string, args ...interface{})
+}
+
+// Storage хранилище
+type Storage interface {
+	Get(ctx context.Context, key string) (*cache.Item, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Increment(ctx context.Context, key string, delta uint64) (uint64, error)
+	Decrement(ctx context.Context, key string, delta uint64) (uint64, error)
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+	Stats(ctx context.Context) (*cache.Stats, error)
+}
+
+// ConditionalStorage хранилище, которое умеет атомарно записывать данные при выполнении условия.
+// Реализовывать его необязательно: для остальных хранилищ SET с NX или XX возвращает ошибку
+type ConditionalStorage interface {
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// BatchStorage хранилище, которое умеет обрабатывать несколько ключей за один раз эффективнее, чем по одному.
+// Реализовывать его необязательно
+type BatchStorage interface {
+	GetBatch(ctx context.Context, keys []string) []cache.GetResult
+	SetBatch(ctx context.Context, entries []cache.Entry) []error
+}
+
+// Server сервер, работающий по протоколу Redis (RESP2 и RESP3) поверх хранилища. Поддерживает строковые
+// команды, которые можно выразить через хранилище. Как и в Memcache, числа для INCRBY - 64-битные
+// беззнаковые, и уменьшение останавливается на нуле
+type Server struct {
+	*tcpserver.Server
+
+	config  *Config
+	storage Storage
+	logger  Logger
+	started time.Time
+	// Контекст команд, отменяется при принудительном закрытии соединений
+	ctx context.Context
+	// Последний выданный номер соединения
+	lastID atomic.Uint64
+}
+
+// NewServer создаёт сервер, работающий по протоколу Redis поверх хранилища
+func NewServer(config *Config, storage Storage, logger Logger) *Server {
+	s := &Server{
+		config:  config,
+		storage: storage,
+		logger:  logger,
+		started: time.Now(),
+	}
+	s.Server = tcpserver.NewServer(s.serveConn)
+	s.ctx = s.Server.Context()
+	return s
+}
+
+// session состояние соединения
+type session struct {
+	// Номер соединения (HELLO возвращает его как id)
+	id uint64
+	w  *replyWriter
+}
+
+// serveConn читает команды клиента и отвечает на них, пока клиент не закроет соединение или не пришлёт QUIT.
+// Ответы буферизуются и отправляются, когда прочитаны все уже полученные команды, поэтому конвейер команд,
+// отправленных без ожидания ответа, обходится одной записью в сокет
+func (s *Server) serveConn(c *tcpserver.Conn) {
+	r := newCommandReader(bufio.NewReaderSize(c, s.config.MaxInlineLength()), s.config)
+	sess := &session{
+		id: s.lastID.Add(1),
+		w:  newReplyWriter(bufio.NewWriter(c)),
+	}
+
+	for !s.Closing() {
+		if timeout := s.config.IdleTimeout(); timeout > 0 {
+			c.SetReadDeadline(time.Now().Add(timeout))
+		}
+
+		args, err := r.ReadCommand()
+		if err != nil {
+			var protoErr protocolError
+			switch {
+			case errors.As(err, &protoErr):
+				sess.w.Error("ERR " + protoErr.Error())
+				if sess.w.Flush() == nil {
+					c.Drain()
+				}
+			case !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) &&
+				!errors.Is(err, net.ErrClosed) && !errors.Is(err, os.ErrDeadlineExceeded):
+				s.logger.Error("Can't read redis command", "err", err, "remote", c.RemoteAddr().String())
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		c.SetBusy(true)
+		err = s.execute(sess, args)
+		if err == nil && r.Buffered() == 0 {
+			err = sess.w.Flush()
+		}
+		c.SetBusy(false)
+
+		if err != nil {
+			// Ответ на QUIT отправляется перед закрытием
+			sess.w.Flush()
+			return
+		}
+	}
+}
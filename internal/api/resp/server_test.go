@@ -0,0 +1,376 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"github.com/dimuska139/cacher/internal/cache/embedded"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startServer запускает сервер поверх хранилища storage на свободном порту и возвращает его адрес
+func startServer(t *testing.T, config *Config, storage Storage, logger Logger) net.Addr {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := NewServer(config, storage, logger)
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Shutdown(context.Background())
+	})
+
+	return listener.Addr()
+}
+
+func TestServer_Commands(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands [][]string
+		want     []interface{}
+	}{
+		{
+			name: "get and set",
+			commands: [][]string{
+				{"SET", "key", "value"},
+				{"GET", "key"},
+				{"get", "missing"},
+			},
+			want: []interface{}{"OK", []byte("value"), nil},
+		},
+		{
+			name: "set with options",
+			commands: [][]string{
+				{"SET", "key", "first", "NX", "EX", "100"},
+				{"SET", "key", "second", "nx"},
+				{"SET", "missing", "value", "XX"},
+				{"SET", "key", "third", "XX", "PX", "5000"},
+				{"GET", "key"},
+				{"TTL", "key"},
+				{"GET", "missing"},
+			},
+			want: []interface{}{"OK", nil, nil, "OK", []byte("third"), int64(5), nil},
+		},
+		{
+			name: "set with invalid options",
+			commands: [][]string{
+				{"SET", "key", "value", "NX", "XX"},
+				{"SET", "key", "value", "EX", "10", "PX", "100"},
+				{"SET", "key", "value", "EX"},
+				{"SET", "key", "value", "EX", "ten"},
+				{"SET", "key", "value", "EX", "0"},
+				{"SET", "key", "value", "KEEPTTL"},
+			},
+			want: []interface{}{
+				respError("ERR syntax error"),
+				respError("ERR syntax error"),
+				respError("ERR syntax error"),
+				respError("ERR value is not an integer or out of range"),
+				respError("ERR invalid expire time in 'set' command"),
+				respError("ERR syntax error"),
+			},
+		},
+		{
+			name: "del and exists",
+			commands: [][]string{
+				{"MSET", "first", "1", "second", "2"},
+				{"EXISTS", "first", "second", "first", "missing"},
+				{"DEL", "first", "missing"},
+				{"EXISTS", "first"},
+			},
+			want: []interface{}{"OK", int64(3), int64(1), int64(0)},
+		},
+		{
+			name: "expire and ttl",
+			commands: [][]string{
+				{"SET", "key", "value"},
+				{"TTL", "key"},
+				{"EXPIRE", "key", "100"},
+				{"TTL", "key"},
+				{"EXPIRE", "missing", "100"},
+				{"TTL", "missing"},
+				{"EXPIRE", "key", "-1"},
+				{"GET", "key"},
+				{"EXPIRE", "key", "10", "NX"},
+			},
+			want: []interface{}{
+				"OK", int64(-1), int64(1), int64(100), int64(0), int64(-2), int64(1), nil,
+				respError("ERR EXPIRE options are not supported"),
+			},
+		},
+		{
+			name: "increment",
+			commands: [][]string{
+				{"INCRBY", "counter", "10"},
+				{"INCR", "counter"},
+				{"DECRBY", "counter", "5"},
+				{"INCRBY", "counter", "-100"},
+				{"DECR", "missing"},
+				{"SET", "text", "value"},
+				{"INCRBY", "text", "1"},
+				{"INCRBY", "counter", "ten"},
+			},
+			want: []interface{}{
+				int64(10), int64(11), int64(6), int64(0), int64(0), "OK",
+				respError("ERR value is not an integer or out of range"),
+				respError("ERR value is not an integer or out of range"),
+			},
+		},
+		{
+			name: "mget and mset",
+			commands: [][]string{
+				{"MSET", "first", "1", "second", "2"},
+				{"MGET", "first", "missing", "second"},
+				{"MSET", "first", "1", "second"},
+			},
+			want: []interface{}{
+				"OK",
+				[]interface{}{[]byte("1"), nil, []byte("2")},
+				respError("ERR wrong number of arguments for 'mset' command"),
+			},
+		},
+		{
+			name: "connection commands",
+			commands: [][]string{
+				{"PING"},
+				{"PING", "hello"},
+				{"ECHO", "hello"},
+				{"SELECT", "0"},
+				{"SELECT", "1"},
+			},
+			want: []interface{}{
+				"PONG", []byte("hello"), []byte("hello"), "OK",
+				respError("ERR DB index is out of range"),
+			},
+		},
+		{
+			name: "unsupported commands",
+			commands: [][]string{
+				{"HSET", "key", "field", "value"},
+				{"GET"},
+				{"GET", "first", "second"},
+			},
+			want: []interface{}{
+				respError("ERR unknown command 'HSET', with args beginning with: 'key' 'field' 'value' "),
+				respError("ERR wrong number of arguments for 'get' command"),
+				respError("ERR wrong number of arguments for 'get' command"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+			client := newTestClient(t, startServer(t, NewConfig(), storage, nil))
+
+			// Все команды отправляются одним конвейером
+			var request []byte
+			for _, command := range tt.commands {
+				request = append(request, encode(command...)...)
+			}
+			client.Write(request)
+
+			for n, want := range tt.want {
+				assert.Equal(t, want, client.Receive(), strings.Join(tt.commands[n], " "))
+			}
+		})
+	}
+}
+
+func TestServer_Pipelining(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+	client := newTestClient(t, startServer(t, NewConfig(), storage, nil))
+
+	const count = 1000
+	var request []byte
+	for i := 0; i < count; i++ {
+		request = append(request, encode("INCR", "counter")...)
+	}
+	client.Write(request)
+
+	for i := 1; i <= count; i++ {
+		assert.Equal(t, int64(i), client.Receive())
+	}
+}
+
+func TestServer_Hello(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+	client := newTestClient(t, startServer(t, NewConfig(), storage, nil))
+
+	reply, ok := client.Do("HELLO", "3", "SETNAME", "test").(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, []byte("redis"), reply["server"])
+	assert.Equal(t, []byte(Version), reply["version"])
+	assert.Equal(t, int64(3), reply["proto"])
+
+	// В RESP3 отсутствующее значение передаётся отдельным типом
+	client.Write(encode("GET", "missing"))
+	line, err := client.r.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "_\r\n", line)
+
+	assert.Equal(t, respError("NOPROTO unsupported protocol version"), client.Do("HELLO", "4"))
+
+	reply2, ok := client.Do("HELLO", "2").([]interface{})
+	assert.True(t, ok)
+	assert.Len(t, reply2, 14)
+	assert.Nil(t, client.Do("GET", "missing"))
+}
+
+func TestServer_Info(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+	client := newTestClient(t, startServer(t, NewConfig(), storage, nil))
+
+	client.Do("SET", "key", "value")
+	client.Do("GET", "key")
+
+	info := string(client.Do("INFO").([]byte))
+	assert.Contains(t, info, "# Server\r\nredis_version:"+Version+"\r\n")
+	assert.Contains(t, info, "keyspace_hits:1\r\n")
+	assert.Contains(t, info, "db0:keys=1,")
+
+	info = string(client.Do("INFO", "clients").([]byte))
+	assert.Equal(t, "# Clients\r\nconnected_clients:1\r\n", info)
+}
+
+func TestServer_Protocol(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		want    string
+	}{
+		{
+			name:    "inline commands",
+			request: "PING\r\n\r\nSET key value\nGET key\r\nQUIT\r\nPING\r\n",
+			want:    "+PONG\r\n+OK\r\n$5\r\nvalue\r\n+OK\r\n",
+		},
+		{
+			name:    "binary value",
+			request: "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$4\r\n\r\n\x00\n\r\n*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n",
+			want:    "+OK\r\n$4\r\n\r\n\x00\n\r\n",
+		},
+		{
+			name:    "empty array",
+			request: "*0\r\nPING\r\n",
+			want:    "+PONG\r\n",
+		},
+		{
+			name:    "invalid multibulk length",
+			request: "*x\r\nPING\r\n",
+			want:    "-ERR Protocol error: invalid multibulk length\r\n",
+		},
+		{
+			name:    "too many arguments",
+			request: "*11\r\n",
+			want:    "-ERR Protocol error: invalid multibulk length\r\n",
+		},
+		{
+			name:    "too large argument",
+			request: "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$11\r\n",
+			want:    "-ERR Protocol error: invalid bulk length\r\n",
+		},
+		{
+			name:    "missing bulk prefix",
+			request: "*1\r\nPING\r\n",
+			want:    "-ERR Protocol error: expected '$', got 'P'\r\n",
+		},
+		{
+			name:    "too long inline command",
+			request: "PING " + strings.Repeat("a", minInlineLength) + "\r\n",
+			want:    "-ERR Protocol error: too big inline request\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+			config := NewConfig().
+				WithMaxInlineLength(minInlineLength).
+				WithMaxBulkLength(10).
+				WithMaxArgs(10)
+			client := newTestClient(t, startServer(t, config, storage, nil))
+
+			client.Write([]byte(tt.request))
+			client.conn.(*net.TCPConn).CloseWrite()
+
+			// Соединение закрывается после QUIT, ошибки протокола или когда прочитаны все команды
+			got, err := io.ReadAll(client.r)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestServer_StorageErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		getStorage func(ctrl *gomock.Controller) Storage
+		getLogger  func(ctrl *gomock.Controller) Logger
+		command    []string
+		want       interface{}
+	}{
+		{
+			name: "conditional writes are not supported",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				return NewMockStorage(ctrl)
+			},
+			getLogger: func(ctrl *gomock.Controller) Logger {
+				return NewMockLogger(ctrl)
+			},
+			command: []string{"SET", "key", "value", "NX"},
+			want:    respError("ERR NX and XX options are not supported by the storage"),
+		},
+		{
+			name: "storage error",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				storage := NewMockStorage(ctrl)
+				storage.EXPECT().
+					Get(gomock.Any(), "key").
+					Return(nil, errors.New("something went wrong")).
+					Times(1)
+				return storage
+			},
+			getLogger: func(ctrl *gomock.Controller) Logger {
+				logger := NewMockLogger(ctrl)
+				logger.EXPECT().
+					Error("Can't get data from storage", gomock.Any()).
+					Times(1)
+				return logger
+			},
+			command: []string{"GET", "key"},
+			want:    respError("ERR something went wrong"),
+		},
+		{
+			name: "increment without conditional writes",
+			getStorage: func(ctrl *gomock.Controller) Storage {
+				storage := NewMockStorage(ctrl)
+				storage.EXPECT().
+					Increment(gomock.Any(), "counter", uint64(5)).
+					Return(uint64(0), errors.New("not found")).
+					Times(1)
+				return storage
+			},
+			getLogger: func(ctrl *gomock.Controller) Logger {
+				logger := NewMockLogger(ctrl)
+				logger.EXPECT().
+					Error("Can't increment value in storage", gomock.Any()).
+					Times(1)
+				return logger
+			},
+			command: []string{"INCRBY", "counter", "5"},
+			want:    respError("ERR something went wrong"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			client := newTestClient(t, startServer(t, NewConfig(), tt.getStorage(ctrl), tt.getLogger(ctrl)))
+
+			assert.Equal(t, tt.want, client.Do(tt.command...))
+		})
+	}
+}
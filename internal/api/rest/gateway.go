@@ -0,0 +1,342 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+//go:generate mockgen -source=gateway.go -destination=./gateway_mock.go -package=rest
+
+const (
+	// MaxBodySize максимальный размер тела запроса, как и максимальный размер сообщения gRPC по умолчанию
+	MaxBodySize = 4 << 20
+
+	// keysPath путь к записям: /v1/keys/{key}
+	keysPath = "/v1/keys/"
+	// Пути пакетных запросов
+	batchGetPath    = "/v1/keys:batchGet"
+	batchSetPath    = "/v1/keys:batchSet"
+	batchDeletePath = "/v1/keys:batchDelete"
+
+	jsonContentType   = "application/json"
+	binaryContentType = "application/octet-stream"
+
+	// Заголовки с метаданными записи в ответе на GET со значением в теле
+	flagsHeader = "X-Cache-Flags"
+	casHeader   = "X-Cache-Cas"
+	ttlHeader   = "X-Cache-Ttl"
+)
+
+var (
+	marshalOptions = protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	}
+	unmarshalOptions = protojson.UnmarshalOptions{}
+)
+
+// CacheAPI методы сервиса кеширования, которые вызывает шлюз
+type CacheAPI interface {
+	Get(ctx context.Context, request *v1.GetRequest) (*v1.GetResponse, error)
+	Set(ctx context.Context, request *v1.SetRequest) (*v1.SetResponse, error)
+	Delete(ctx context.Context, request *v1.DeleteRequest) (*v1.DeleteResponse, error)
+	MultiGet(ctx context.Context, request *v1.MultiGetRequest) (*v1.MultiGetResponse, error)
+	MultiSet(ctx context.Context, request *v1.MultiSetRequest) (*v1.MultiSetResponse, error)
+	MultiDelete(ctx context.Context, request *v1.MultiDeleteRequest) (*v1.MultiDeleteResponse, error)
+}
+
+// Gateway HTTP-шлюз к сервису кеширования:
+//
+//	GET    /v1/keys/{key}[?touch_ttl=]  значение записи
+//	PUT    /v1/keys/{key}[?ttl=]        запись значения
+//	DELETE /v1/keys/{key}               удаление записи
+//	POST   /v1/keys:batchGet            пакетное чтение (MultiGetRequest)
+//	POST   /v1/keys:batchSet            пакетная запись (MultiSetRequest)
+//	POST   /v1/keys:batchDelete         пакетное удаление (MultiDeleteRequest)
+//
+// Ключ в пути экранируется, как сегмент URL (например, "/" - как %2F). Значение передаётся телом запроса
+// и ответа как есть, а если указан тип application/json (Content-Type для PUT, Accept для GET) - в JSON
+// с полями сообщений gRPC, в котором значение закодировано в base64. Пакетные запросы и ответы, а также ошибки
+// (google.rpc.Status) всегда передаются в JSON. Коды ошибок gRPC преобразуются в статусы HTTP, а неверный метод
+// и слишком большое тело запроса возвращаются как 405 и 413
+type Gateway struct {
+	api CacheAPI
+}
+
+// NewGateway создаёт HTTP-шлюз к сервису кеширования
+func NewGateway(api CacheAPI) *Gateway {
+	return &Gateway{
+		api: api,
+	}
+}
+
+// ServeHTTP обрабатывает запрос. Путь разбирается вручную, а не http.ServeMux, так как ServeMux
+// перенаправляет пути с "//" и "..", которые могут быть частью ключа
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.EscapedPath()
+	switch path {
+	case batchGetPath:
+		g.batch(w, r, &v1.MultiGetRequest{}, func(ctx context.Context, request proto.Message) (proto.Message, error) {
+			return g.api.MultiGet(ctx, request.(*v1.MultiGetRequest))
+		})
+		return
+	case batchSetPath:
+		g.batch(w, r, &v1.MultiSetRequest{}, func(ctx context.Context, request proto.Message) (proto.Message, error) {
+			return g.api.MultiSet(ctx, request.(*v1.MultiSetRequest))
+		})
+		return
+	case batchDeletePath:
+		g.batch(w, r, &v1.MultiDeleteRequest{}, func(ctx context.Context, request proto.Message) (proto.Message, error) {
+			return g.api.MultiDelete(ctx, request.(*v1.MultiDeleteRequest))
+		})
+		return
+	}
+
+	if !strings.HasPrefix(path, keysPath) {
+		writeError(w, status.Error(codes.NotFound, "not found"))
+		return
+	}
+
+	key, err := url.PathUnescape(strings.TrimPrefix(path, keysPath))
+	if err != nil || key == "" {
+		writeError(w, status.Error(codes.InvalidArgument, "invalid key"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		g.get(w, r, key)
+	case http.MethodPut:
+		g.set(w, r, key)
+	case http.MethodDelete:
+		g.delete(w, r, key)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+// get возвращает значение записи
+func (g *Gateway) get(w http.ResponseWriter, r *http.Request, key string) {
+	request := &v1.GetRequest{Key: key}
+	if value := r.URL.Query().Get("touch_ttl"); value != "" {
+		touchTTL, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, "invalid touch_ttl"))
+			return
+		}
+		request.TouchTtl = &touchTTL
+	}
+
+	response, err := g.api.Get(r.Context(), request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !response.GetFound() {
+		writeError(w, status.Error(codes.NotFound, "key not found"))
+		return
+	}
+
+	if acceptsJSON(r) {
+		writeMessage(w, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", binaryContentType)
+	w.Header().Set(flagsHeader, strconv.FormatUint(uint64(response.GetFlags()), 10))
+	w.Header().Set(casHeader, strconv.FormatUint(response.GetCas(), 10))
+	w.Header().Set(ttlHeader, strconv.FormatUint(response.GetTtl(), 10))
+	w.Write(response.GetValue())
+}
+
+// set записывает значение. Время жизни из параметра ttl важнее времени жизни из тела в JSON
+func (g *Gateway) set(w http.ResponseWriter, r *http.Request, key string) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	request := &v1.SetRequest{Key: key}
+	if isJSON(r.Header.Get("Content-Type")) {
+		if err := unmarshalOptions.Unmarshal(body, request); err != nil {
+			writeError(w, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err))
+			return
+		}
+		// Ключ в теле можно не указывать, но если он указан, то должен совпадать с ключом из пути
+		if request.GetKey() == "" {
+			request.Key = key
+		}
+		if request.GetKey() != key {
+			writeError(w, status.Error(codes.InvalidArgument, "key in the body does not match the path"))
+			return
+		}
+	} else {
+		request.Value = body
+	}
+
+	if value := r.URL.Query().Get("ttl"); value != "" {
+		ttl, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, "invalid ttl"))
+			return
+		}
+		request.Ttl = ttl
+	}
+
+	if _, err := g.api.Set(r.Context(), request); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// delete удаляет запись
+func (g *Gateway) delete(w http.ResponseWriter, r *http.Request, key string) {
+	if _, err := g.api.Delete(r.Context(), &v1.DeleteRequest{Key: key}); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// batch выполняет пакетный запрос: разбирает тело запроса в request и отвечает результатом call
+func (g *Gateway) batch(
+	w http.ResponseWriter,
+	r *http.Request,
+	request proto.Message,
+	call func(ctx context.Context, request proto.Message) (proto.Message, error),
+) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	if err := unmarshalOptions.Unmarshal(body, request); err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err))
+		return
+	}
+
+	response, err := call(r.Context(), request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeMessage(w, response)
+}
+
+// readBody читает тело запроса не больше MaxBodySize. Если прочитать тело не удалось, отвечает ошибкой
+// (413 Request Entity Too Large для слишком большого тела) и возвращает false
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeStatus(w, http.StatusRequestEntityTooLarge,
+				status.New(codes.ResourceExhausted, "request body is too large"))
+			return nil, false
+		}
+		writeError(w, status.Errorf(codes.InvalidArgument, "can't read request body: %v", err))
+		return nil, false
+	}
+	return body, true
+}
+
+// methodNotAllowed отвечает 405 Method Not Allowed со списком допустимых методов в заголовке Allow
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeStatus(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method not allowed"))
+}
+
+// isJSON проверяет, что тип содержимого - application/json
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == jsonContentType
+}
+
+// acceptsJSON проверяет, что клиент просит ответ в JSON
+func acceptsJSON(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if isJSON(strings.TrimSpace(accept)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeMessage отвечает сообщением gRPC в JSON
+func writeMessage(w http.ResponseWriter, message proto.Message) {
+	writeJSON(w, http.StatusOK, message)
+}
+
+// writeError отвечает ошибкой gRPC в виде google.rpc.Status в JSON со статусом HTTP, соответствующим коду ошибки
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeStatus(w, httpStatus(st.Code()), st)
+}
+
+// writeStatus отвечает ошибкой gRPC в виде google.rpc.Status в JSON с явно указанным статусом HTTP.
+// Используется для ошибок протокола HTTP, у которых нет точного аналога среди кодов gRPC
+func writeStatus(w http.ResponseWriter, code int, st *status.Status) {
+	writeJSON(w, code, st.Proto())
+}
+
+// writeJSON отвечает сообщением в JSON с указанным статусом
+func writeJSON(w http.ResponseWriter, code int, message proto.Message) {
+	body, err := marshalOptions.Marshal(message)
+	if err != nil {
+		http.Error(w, "can't encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(code)
+	w.Write(body)
+}
+
+// httpStatus возвращает статус HTTP, соответствующий коду ошибки gRPC. Соответствие такое же, как в grpc-gateway
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		// Нестандартный статус "Client Closed Request"
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: gateway.go
+
+// Package rest is a generated GoMock package.
+package rest
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCacheAPI is a mock of CacheAPI interface.
+type MockCacheAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheAPIMockRecorder
+}
+
+// MockCacheAPIMockRecorder is the mock recorder for MockCacheAPI.
+type MockCacheAPIMockRecorder struct {
+	mock *MockCacheAPI
+}
+
+// NewMockCacheAPI creates a new mock instance.
+func NewMockCacheAPI(ctrl *gomock.Controller) *MockCacheAPI {
+	mock := &MockCacheAPI{ctrl: ctrl}
+	mock.recorder = &MockCacheAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheAPI) EXPECT() *MockCacheAPIMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCacheAPI) Delete(ctx context.Context, request *v1.DeleteRequest) (*v1.DeleteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, request)
+	ret0, _ := ret[0].(*v1.DeleteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCacheAPIMockRecorder) Delete(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCacheAPI)(nil).Delete), ctx, request)
+}
+
+// Get mocks base method.
+func (m *MockCacheAPI) Get(ctx context.Context, request *v1.GetRequest) (*v1.GetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, request)
+	ret0, _ := ret[0].(*v1.GetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCacheAPIMockRecorder) Get(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacheAPI)(nil).Get), ctx, request)
+}
+
+// MultiDelete mocks base method.
+func (m *MockCacheAPI) MultiDelete(ctx context.Context, request *v1.MultiDeleteRequest) (*v1.MultiDeleteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiDelete", ctx, request)
+	ret0, _ := ret[0].(*v1.MultiDeleteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MultiDelete indicates an expected call of MultiDelete.
+func (mr *MockCacheAPIMockRecorder) MultiDelete(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiDelete", reflect.TypeOf((*MockCacheAPI)(nil).MultiDelete), ctx, request)
+}
+
+// MultiGet mocks base method.
+func (m *MockCacheAPI) MultiGet(ctx context.Context, request *v1.MultiGetRequest) (*v1.MultiGetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiGet", ctx, request)
+	ret0, _ := ret[0].(*v1.MultiGetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MultiGet indicates an expected call of MultiGet.
+func (mr *MockCacheAPIMockRecorder) MultiGet(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiGet", reflect.TypeOf((*MockCacheAPI)(nil).MultiGet), ctx, request)
+}
+
+// MultiSet mocks base method.
+func (m *MockCacheAPI) MultiSet(ctx context.Context, request *v1.MultiSetRequest) (*v1.MultiSetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiSet", ctx, request)
+	ret0, _ := ret[0].(*v1.MultiSetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MultiSet indicates an expected call of MultiSet.
+func (mr *MockCacheAPIMockRecorder) MultiSet(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiSet", reflect.TypeOf((*MockCacheAPI)(nil).MultiSet), ctx, request)
+}
+
+// Set mocks base method.
+func (m *MockCacheAPI) Set(ctx context.Context, request *v1.SetRequest) (*v1.SetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, request)
+	ret0, _ := ret[0].(*v1.SetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockCacheAPIMockRecorder) Set(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCacheAPI)(nil).Set), ctx, request)
+}
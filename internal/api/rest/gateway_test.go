@@ -0,0 +1,344 @@
+package rest
+
+import (
+	"context"
+	grpc2 "github.com/dimuska139/cacher/internal/api/grpc"
+	v1 "github.com/dimuska139/cacher/internal/api/grpc/gen/cacher/cache/v1"
+	"github.com/dimuska139/cacher/internal/cache/embedded"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	status2 "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// doRequest выполняет запрос к шлюзу и возвращает ответ с прочитанным телом
+func doRequest(t *testing.T, gateway http.Handler, method, target, contentType, body string, header ...string) (*http.Response, []byte) {
+	t.Helper()
+
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		request.Header.Set(header[i], header[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, request)
+	response := recorder.Result()
+	responseBody, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	return response, responseBody
+}
+
+// decode разбирает тело ответа в JSON в сообщение message
+func decode(t *testing.T, body []byte, message proto.Message) {
+	t.Helper()
+	assert.NoError(t, protojson.Unmarshal(body, message), string(body))
+}
+
+func TestGateway_Keys(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(10 * time.Millisecond))
+	gateway := NewGateway(grpc2.NewCacheServer(nil, storage))
+	value := []byte{0x00, 0xff, '\r', '\n', 'v'}
+
+	response, body := doRequest(t, gateway, http.MethodPut, "/v1/keys/a%2F%2Fb?ttl=60", "", string(value))
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Empty(t, body)
+
+	response, body = doRequest(t, gateway, http.MethodGet, "/v1/keys/a%2F%2Fb", "", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/octet-stream", response.Header.Get("Content-Type"))
+	assert.Equal(t, "60", response.Header.Get("X-Cache-Ttl"))
+	assert.Equal(t, "0", response.Header.Get("X-Cache-Flags"))
+	assert.NotEmpty(t, response.Header.Get("X-Cache-Cas"))
+	assert.Equal(t, value, body)
+
+	response, body = doRequest(t, gateway, http.MethodGet, "/v1/keys/a%2F%2Fb", "", "", "Accept", "text/html, application/json")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	getResponse := &v1.GetResponse{}
+	decode(t, body, getResponse)
+	assert.True(t, getResponse.GetFound())
+	assert.Equal(t, value, getResponse.GetValue())
+	assert.Equal(t, uint64(60), getResponse.GetTtl())
+
+	response, _ = doRequest(t, gateway, http.MethodPut, "/v1/keys/json?ttl=30", "application/json; charset=utf-8",
+		`{"value": "AP8NCnY=", "ttl": 10}`)
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	response, body = doRequest(t, gateway, http.MethodGet, "/v1/keys/json", "", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "30", response.Header.Get("X-Cache-Ttl"))
+	assert.Equal(t, value, body)
+
+	response, _ = doRequest(t, gateway, http.MethodDelete, "/v1/keys/a%2F%2Fb", "", "")
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+
+	response, body = doRequest(t, gateway, http.MethodGet, "/v1/keys/a%2F%2Fb", "", "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	st := &status.Status{}
+	decode(t, body, st)
+	assert.Equal(t, int32(codes.NotFound), st.GetCode())
+	assert.Equal(t, "key not found", st.GetMessage())
+}
+
+func TestGateway_Batch(t *testing.T) {
+	storage := embedded.NewEmbeddedStorage(embedded.NewConfig(10 * time.Millisecond))
+	gateway := NewGateway(grpc2.NewCacheServer(nil, storage))
+
+	response, body := doRequest(t, gateway, http.MethodPost, "/v1/keys:batchSet", "application/json",
+		`{"items": [{"key": "a", "value": "MQ==", "ttl": 60}, {"key": "b", "value": "Mg=="}]}`)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	setResponse := &v1.MultiSetResponse{}
+	decode(t, body, setResponse)
+	assert.Len(t, setResponse.GetResults(), 2)
+	assert.Nil(t, setResponse.GetResults()[0].GetError())
+	assert.Nil(t, setResponse.GetResults()[1].GetError())
+
+	response, body = doRequest(t, gateway, http.MethodPost, "/v1/keys:batchGet", "application/json",
+		`{"keys": ["a", "b", "c"]}`)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	getResponse := &v1.MultiGetResponse{}
+	decode(t, body, getResponse)
+	assert.Len(t, getResponse.GetResults(), 3)
+	assert.Equal(t, []byte("1"), getResponse.GetResults()[0].GetValue())
+	assert.Equal(t, uint64(60), getResponse.GetResults()[0].GetTtl())
+	assert.Equal(t, []byte("2"), getResponse.GetResults()[1].GetValue())
+	assert.False(t, getResponse.GetResults()[2].GetFound())
+
+	response, body = doRequest(t, gateway, http.MethodPost, "/v1/keys:batchDelete", "application/json",
+		`{"keys": ["a", "c"]}`)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	deleteResponse := &v1.MultiDeleteResponse{}
+	decode(t, body, deleteResponse)
+	assert.Len(t, deleteResponse.GetResults(), 2)
+
+	response, _ = doRequest(t, gateway, http.MethodGet, "/v1/keys/a", "", "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	response, _ = doRequest(t, gateway, http.MethodGet, "/v1/keys/b", "", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestGateway_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testCases := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		prepare     func(api *MockCacheAPI)
+		status      int
+		code        codes.Code
+		allow       string
+	}{
+		{
+			name:   "Unknown path",
+			method: http.MethodGet,
+			target: "/v2/keys/a",
+			status: http.StatusNotFound,
+			code:   codes.NotFound,
+		},
+		{
+			name:   "Empty key",
+			method: http.MethodGet,
+			target: "/v1/keys/",
+			status: http.StatusBadRequest,
+			code:   codes.InvalidArgument,
+		},
+		{
+			name:   "Method not allowed",
+			method: http.MethodPost,
+			target: "/v1/keys/a",
+			status: http.StatusMethodNotAllowed,
+			code:   codes.Unimplemented,
+			allow:  "GET, PUT, DELETE",
+		},
+		{
+			name:   "Batch method not allowed",
+			method: http.MethodGet,
+			target: "/v1/keys:batchGet",
+			status: http.StatusMethodNotAllowed,
+			code:   codes.Unimplemented,
+			allow:  "POST",
+		},
+		{
+			name:   "Invalid touch_ttl",
+			method: http.MethodGet,
+			target: "/v1/keys/a?touch_ttl=-1",
+			status: http.StatusBadRequest,
+			code:   codes.InvalidArgument,
+		},
+		{
+			name:   "Invalid ttl",
+			method: http.MethodPut,
+			target: "/v1/keys/a?ttl=x",
+			status: http.StatusBadRequest,
+			code:   codes.InvalidArgument,
+		},
+		{
+			name:        "Invalid JSON body",
+			method:      http.MethodPut,
+			target:      "/v1/keys/a",
+			contentType: "application/json",
+			body:        `{"value": 1}`,
+			status:      http.StatusBadRequest,
+			code:        codes.InvalidArgument,
+		},
+		{
+			name:        "Key in the body does not match the path",
+			method:      http.MethodPut,
+			target:      "/v1/keys/a",
+			contentType: "application/json",
+			body:        `{"key": "b", "value": "MQ=="}`,
+			status:      http.StatusBadRequest,
+			code:        codes.InvalidArgument,
+		},
+		{
+			name:   "Body is too large",
+			method: http.MethodPut,
+			target: "/v1/keys/a",
+			body:   strings.Repeat("v", MaxBodySize+1),
+			status: http.StatusRequestEntityTooLarge,
+			code:   codes.ResourceExhausted,
+		},
+		{
+			name:        "Invalid batch body",
+			method:      http.MethodPost,
+			target:      "/v1/keys:batchDelete",
+			contentType: "application/json",
+			body:        `{"keys": "a"}`,
+			status:      http.StatusBadRequest,
+			code:        codes.InvalidArgument,
+		},
+		{
+			name:   "Get: canceled",
+			method: http.MethodGet,
+			target: "/v1/keys/a?touch_ttl=10",
+			prepare: func(api *MockCacheAPI) {
+				touchTTL := uint64(10)
+				api.EXPECT().Get(gomock.Any(), &getRequestMatcher{&v1.GetRequest{Key: "a", TouchTtl: &touchTTL}}).
+					Return(nil, status2.Error(codes.Canceled, "context canceled"))
+			},
+			status: 499,
+			code:   codes.Canceled,
+		},
+		{
+			name:   "Set: too large",
+			method: http.MethodPut,
+			target: "/v1/keys/a",
+			body:   "v",
+			prepare: func(api *MockCacheAPI) {
+				api.EXPECT().Set(gomock.Any(), gomock.Any()).
+					Return(nil, status2.Error(codes.InvalidArgument, "value is too large"))
+			},
+			status: http.StatusBadRequest,
+			code:   codes.InvalidArgument,
+		},
+		{
+			name:   "Delete: internal error",
+			method: http.MethodDelete,
+			target: "/v1/keys/a",
+			prepare: func(api *MockCacheAPI) {
+				api.EXPECT().Delete(gomock.Any(), gomock.Any()).
+					Return(nil, status2.Error(codes.Internal, "something went wrong"))
+			},
+			status: http.StatusInternalServerError,
+			code:   codes.Internal,
+		},
+		{
+			name:        "MultiSet: unavailable",
+			method:      http.MethodPost,
+			target:      "/v1/keys:batchSet",
+			contentType: "application/json",
+			body:        `{"items": []}`,
+			prepare: func(api *MockCacheAPI) {
+				api.EXPECT().MultiSet(gomock.Any(), gomock.Any()).
+					Return(nil, status2.Error(codes.Unavailable, "unavailable"))
+			},
+			status: http.StatusServiceUnavailable,
+			code:   codes.Unavailable,
+		},
+		{
+			name:        "MultiGet: not a gRPC error",
+			method:      http.MethodPost,
+			target:      "/v1/keys:batchGet",
+			contentType: "application/json",
+			body:        `{}`,
+			prepare: func(api *MockCacheAPI) {
+				api.EXPECT().MultiGet(gomock.Any(), gomock.Any()).
+					Return(nil, context.DeadlineExceeded)
+			},
+			status: http.StatusInternalServerError,
+			code:   codes.Unknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			api := NewMockCacheAPI(ctrl)
+			if tc.prepare != nil {
+				tc.prepare(api)
+			}
+
+			response, body := doRequest(t, NewGateway(api), tc.method, tc.target, tc.contentType, tc.body)
+			assert.Equal(t, tc.status, response.StatusCode)
+			assert.Equal(t, tc.allow, response.Header.Get("Allow"))
+			st := &status.Status{}
+			decode(t, body, st)
+			assert.Equal(t, int32(tc.code), st.GetCode())
+		})
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	testCases := map[codes.Code]int{
+		codes.OK:                 http.StatusOK,
+		codes.Canceled:           499,
+		codes.Unknown:            http.StatusInternalServerError,
+		codes.InvalidArgument:    http.StatusBadRequest,
+		codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+		codes.NotFound:           http.StatusNotFound,
+		codes.AlreadyExists:      http.StatusConflict,
+		codes.PermissionDenied:   http.StatusForbidden,
+		codes.ResourceExhausted:  http.StatusTooManyRequests,
+		codes.FailedPrecondition: http.StatusBadRequest,
+		codes.Aborted:            http.StatusConflict,
+		codes.OutOfRange:         http.StatusBadRequest,
+		codes.Unimplemented:      http.StatusNotImplemented,
+		codes.Internal:           http.StatusInternalServerError,
+		codes.Unavailable:        http.StatusServiceUnavailable,
+		codes.DataLoss:           http.StatusInternalServerError,
+		codes.Unauthenticated:    http.StatusUnauthorized,
+	}
+
+	for code, expected := range testCases {
+		assert.Equal(t, expected, httpStatus(code), code.String())
+	}
+}
+
+// getRequestMatcher сравнивает запросы как сообщения protobuf
+type getRequestMatcher struct {
+	expected *v1.GetRequest
+}
+
+func (m *getRequestMatcher) Matches(x interface{}) bool {
+	request, ok := x.(*v1.GetRequest)
+	return ok && proto.Equal(m.expected, request)
+}
+
+func (m *getRequestMatcher) String() string {
+	return m.expected.String()
+}
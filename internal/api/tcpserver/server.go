@@ -0,0 +1,217 @@
+package tcpserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// shutdownPollInterval период проверки, остались ли соединения, выполняющие команды, при остановке сервера
+	shutdownPollInterval = 10 * time.Millisecond
+	// drainTimeout сколько времени Drain читает данные клиента перед закрытием соединения
+	drainTimeout = 100 * time.Millisecond
+)
+
+// ErrServerClosed сервер остановлен
+var ErrServerClosed = errors.New("tcpserver: server closed")
+
+// Handler обслуживает соединение, пока клиент его не закроет или сервер не начнёт останавливаться
+// (см. Server.Closing). Соединение закрывается после возврата из Handler
+type Handler func(c *Conn)
+
+// Conn соединение с клиентом
+type Conn struct {
+	net.Conn
+	// Выполняется ли сейчас команда. Соединения без выполняющихся команд при остановке сервера закрываются сразу
+	busy atomic.Bool
+}
+
+// SetBusy отмечает, что в соединении выполняется команда (busy = true) или оно ждёт следующую (busy = false)
+func (c *Conn) SetBusy(busy bool) {
+	c.busy.Store(busy)
+}
+
+// Drain читает и отбрасывает данные, которые клиент успел отправить, пока он их отправляет, но не дольше
+// drainTimeout. Вызывается перед закрытием соединения по инициативе сервера: если в сокете останутся
+// непрочитанные данные, то клиент вместо последнего ответа получит RST
+func (c *Conn) Drain() {
+	c.SetReadDeadline(time.Now().Add(drainTimeout))
+	io.Copy(io.Discard, c.Conn)
+}
+
+// Server TCP-сервер, который обслуживает каждое соединение в отдельной горутине и умеет корректно
+// останавливаться: ждать завершения выполняющихся команд и закрывать простаивающие соединения
+type Server struct {
+	handler Handler
+
+	// Контекст команд, отменяется при принудительном закрытии соединений
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mx        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[*Conn]struct{}
+	closing   atomic.Bool
+	wg        sync.WaitGroup
+
+	currConns  atomic.Int64
+	totalConns atomic.Uint64
+}
+
+// NewServer создаёт TCP-сервер, обслуживающий соединения с помощью handler
+func NewServer(handler Handler) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		handler:   handler,
+		ctx:       ctx,
+		cancel:    cancel,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[*Conn]struct{}),
+	}
+}
+
+// Context возвращает контекст для выполнения команд. Он отменяется, когда при остановке сервера
+// соединения закрываются принудительно
+func (s *Server) Context() context.Context {
+	return s.ctx
+}
+
+// Closing проверяет, начал ли сервер останавливаться. Handler должен проверять это перед чтением каждой команды
+func (s *Server) Closing() bool {
+	return s.closing.Load()
+}
+
+// CurrConns возвращает количество открытых соединений
+func (s *Server) CurrConns() int64 {
+	return s.currConns.Load()
+}
+
+// TotalConns возвращает количество соединений, принятых с момента запуска
+func (s *Server) TotalConns() uint64 {
+	return s.totalConns.Load()
+}
+
+// Serve принимает соединения и обслуживает каждое в отдельной горутине. Возвращает ErrServerClosed после
+// вызова Shutdown или ошибку, из-за которой соединения больше не принимаются
+func (s *Server) Serve(listener net.Listener) error {
+	if !s.trackListener(listener, true) {
+		return ErrServerClosed
+	}
+	defer s.trackListener(listener, false)
+
+	for {
+		c, err := listener.Accept()
+		if err != nil {
+			if s.closing.Load() {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		cn := &Conn{Conn: c}
+		if !s.trackConn(cn, true) {
+			c.Close()
+			return ErrServerClosed
+		}
+		go s.serveConn(cn)
+	}
+}
+
+// serveConn обслуживает соединение и закрывает его
+func (s *Server) serveConn(c *Conn) {
+	defer func() {
+		c.Close()
+		s.trackConn(c, false)
+	}()
+
+	s.handler(c)
+}
+
+// Shutdown останавливает сервер: перестаёт принимать соединения, закрывает соединения без выполняющихся команд
+// и ждёт завершения остальных. Если ctx завершится раньше, оставшиеся соединения закрываются принудительно
+func (s *Server) Shutdown(ctx context.Context) error {
+	// Флаг устанавливается под мьютексом, чтобы после него не появилось новых соединений
+	s.mx.Lock()
+	s.closing.Store(true)
+	for listener := range s.listeners {
+		listener.Close()
+	}
+	s.mx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		s.closeIdleConns()
+		select {
+		case <-done:
+			s.cancel()
+			return nil
+		case <-ctx.Done():
+			s.cancel()
+			s.mx.Lock()
+			for c := range s.conns {
+				c.Close()
+			}
+			s.mx.Unlock()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// closeIdleConns закрывает соединения, в которых сейчас не выполняются команды
+func (s *Server) closeIdleConns() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	for c := range s.conns {
+		if !c.busy.Load() {
+			c.Close()
+		}
+	}
+}
+
+// trackListener добавляет или удаляет слушателя. Новых слушателей после остановки сервера не добавляет
+func (s *Server) trackListener(listener net.Listener, add bool) bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if !add {
+		delete(s.listeners, listener)
+		return true
+	}
+	if s.closing.Load() {
+		return false
+	}
+	s.listeners[listener] = struct{}{}
+	return true
+}
+
+// trackConn добавляет или удаляет соединение. Новых соединений после остановки сервера не добавляет
+func (s *Server) trackConn(c *Conn, add bool) bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if !add {
+		delete(s.conns, c)
+		s.currConns.Add(-1)
+		s.wg.Done()
+		return true
+	}
+	if s.closing.Load() {
+		return false
+	}
+	s.conns[c] = struct{}{}
+	s.currConns.Add(1)
+	s.totalConns.Add(1)
+	s.wg.Add(1)
+	return true
+}
@@ -0,0 +1,47 @@
+package tcpserver
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestServer_Shutdown(t *testing.T) {
+	started := make(chan struct{})
+	var server *Server
+	server = NewServer(func(c *Conn) {
+		// Команда выполняется, пока контекст сервера не отменён
+		c.SetBusy(true)
+		close(started)
+		<-server.Context().Done()
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	<-started
+	assert.Equal(t, int64(1), server.CurrConns())
+	assert.Equal(t, uint64(1), server.TotalConns())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(t, <-served, ErrServerClosed)
+	assert.ErrorIs(t, server.Context().Err(), context.Canceled)
+
+	// Соединение с выполняющейся командой закрыто принудительно
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+
+	assert.ErrorIs(t, server.Serve(listener), ErrServerClosed)
+}
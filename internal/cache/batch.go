@@ -0,0 +1,21 @@
+package cache
+
+import "time"
+
+// Entry запись для пакетной записи в хранилище
+type Entry struct {
+	// Ключ
+	Key string
+	// Значение
+	Value []byte
+	// Время жизни (0 - бессрочная запись)
+	TTL time.Duration
+}
+
+// GetResult результат чтения одного ключа при пакетном чтении
+type GetResult struct {
+	// Запись (nil, если записи нет или чтение завершилось ошибкой)
+	Item *Item
+	// Ошибка чтения. Если записи нет, то ErrNotFound
+	Err error
+}
@@ -0,0 +1,572 @@
+package embedded
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Формат журнала (append-only log):
+//
+//	заголовок:  "CACHEAOF" | версия (uint32, big endian)
+//	запись:     длина операции (uint32, big endian) | CRC-32C операции (uint32, big endian) | операция
+//	операция:   тип (1 байт) | длина ключа (uvarint) | ключ | поля операции
+//
+// Поля операций: set - длина значения (uvarint), значение и момент истечения (int64, big endian);
+// set с флагами - поля set и флаги (uint32, big endian); delete - нет полей; expire - момент истечения. Момент истечения хранится как абсолютное время
+// в наносекундах Unix (0 - бессрочная запись). Все операции идемпотентны, поэтому повторное применение
+// части журнала (например, после перезаписи) не меняет результат
+const (
+	aofMagic = "CACHEAOF"
+	// AOFVersion версия формата журнала
+	AOFVersion = 1
+
+	aofHeaderSize       = len(aofMagic) + 4
+	aofRecordHeaderSize = 8
+)
+
+// FsyncPolicy определяет, когда записи журнала сбрасываются на диск
+type FsyncPolicy string
+
+const (
+	// FsyncAlways сбрасывает на диск каждую запись: ни одна подтверждённая операция не теряется, но это медленно
+	FsyncAlways FsyncPolicy = "always"
+	// FsyncEverySec сбрасывает записи на диск раз в секунду: при сбое питания теряется не больше секунды операций
+	FsyncEverySec FsyncPolicy = "everysec"
+	// FsyncNo оставляет сброс на диск операционной системе
+	FsyncNo FsyncPolicy = "no"
+
+	DefaultFsyncPolicy = FsyncEverySec
+)
+
+const (
+	// DefaultAOFRewriteMinSize размер журнала, до которого он не перезаписывается
+	DefaultAOFRewriteMinSize = 64 << 20
+	// DefaultAOFRewritePercent на сколько процентов журнал должен вырасти с последней перезаписи,
+	// чтобы его перезаписать
+	DefaultAOFRewritePercent = 100
+	// aofSyncInterval период сброса журнала на диск и проверки необходимости перезаписи
+	aofSyncInterval = time.Second
+)
+
+// ErrInvalidAOF журнал повреждён не в последней записи или записан в неподдерживаемом формате
+var ErrInvalidAOF = errors.New("invalid append-only log")
+
+// aofOp тип операции журнала
+type aofOp byte
+
+const (
+	aofOpSet    aofOp = 1
+	aofOpDelete aofOp = 2
+	aofOpExpire aofOp = 3
+	// aofOpSetFlags операция set для записи с ненулевыми флагами. При чтении превращается в aofOpSet,
+	// а запись без флагов кодируется как раньше, поэтому журналы без флагов не меняются
+	aofOpSetFlags aofOp = 4
+)
+
+// aofRecord операция журнала
+type aofRecord struct {
+	op         aofOp
+	key        string
+	value      []byte
+	expiration int64
+	flags      uint32
+}
+
+// AOFConfig конфигурация журнала
+type AOFConfig struct {
+	path           string
+	fsync          FsyncPolicy
+	rewriteMinSize int64
+	rewritePercent int
+}
+
+// NewAOFConfig создаёт конфигурацию журнала, который хранится в файле path
+func NewAOFConfig(path string) *AOFConfig {
+	return &AOFConfig{
+		path:           path,
+		fsync:          DefaultFsyncPolicy,
+		rewriteMinSize: DefaultAOFRewriteMinSize,
+		rewritePercent: DefaultAOFRewritePercent,
+	}
+}
+
+// Path возвращает путь к файлу журнала
+func (c *AOFConfig) Path() string {
+	return c.path
+}
+
+// WithFsync устанавливает политику сброса журнала на диск
+func (c *AOFConfig) WithFsync(fsync FsyncPolicy) *AOFConfig {
+	c.fsync = fsync
+	return c
+}
+
+// Fsync возвращает политику сброса журнала на диск
+func (c *AOFConfig) Fsync() FsyncPolicy {
+	return c.fsync
+}
+
+// WithRewriteMinSize устанавливает размер журнала в байтах, до которого он не перезаписывается
+func (c *AOFConfig) WithRewriteMinSize(size int64) *AOFConfig {
+	c.rewriteMinSize = size
+	return c
+}
+
+// RewriteMinSize возвращает размер журнала в байтах, до которого он не перезаписывается
+func (c *AOFConfig) RewriteMinSize() int64 {
+	return c.rewriteMinSize
+}
+
+// WithRewritePercent устанавливает, на сколько процентов журнал должен вырасти с последней перезаписи,
+// чтобы его перезаписать (0 - автоматическая перезапись отключена)
+func (c *AOFConfig) WithRewritePercent(percent int) *AOFConfig {
+	c.rewritePercent = percent
+	return c
+}
+
+// RewritePercent возвращает, на сколько процентов журнал должен вырасти с последней перезаписи,
+// чтобы его перезаписать (0 - автоматическая перезапись отключена)
+func (c *AOFConfig) RewritePercent() int {
+	return c.rewritePercent
+}
+
+// ParseFsyncPolicy проверяет название политики сброса журнала на диск
+func ParseFsyncPolicy(name string) (FsyncPolicy, error) {
+	switch policy := FsyncPolicy(name); policy {
+	case FsyncAlways, FsyncEverySec, FsyncNo:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown fsync policy: %s", name)
+}
+
+// AppendOnlyLog журнал изменений кеша. Каждая операция Set, Delete и изменение времени жизни дописывается
+// в конец файла, а при запуске журнал воспроизводится. Вытеснение записей из-за бюджета в журнал не попадает:
+// при воспроизведении бюджет соблюдается заново. Когда журнал разрастается, он в фоне перезаписывается
+// текущим содержимым кеша
+type AppendOnlyLog struct {
+	cfg     *AOFConfig
+	storage *EmbeddedStorage
+	logger  Logger
+
+	mx   sync.Mutex
+	file *os.File
+	// Текущий размер журнала и его размер после последней перезаписи
+	size            int64
+	lastRewriteSize int64
+	// Есть ли записи, ещё не сброшенные на диск
+	dirty bool
+	// Операции, записанные во время перезаписи журнала (nil, если перезапись не идёт)
+	rewriteBuf *bytes.Buffer
+	buf        []byte
+	// Не даёт запустить несколько перезаписей журнала одновременно
+	rewriteMx sync.Mutex
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewAppendOnlyLog создаёт журнал изменений кеша storage
+func NewAppendOnlyLog(storage *EmbeddedStorage, cfg *AOFConfig, logger Logger) *AppendOnlyLog {
+	return &AppendOnlyLog{
+		cfg:     cfg,
+		storage: storage,
+		logger:  logger,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Open воспроизводит журнал, подключает его к кешу и запускает фоновый сброс на диск и перезапись.
+// Возвращает количество воспроизведённых операций. Если последняя запись журнала записана не полностью
+// (например, из-за сбоя во время записи), она отбрасывается, а файл обрезается
+func (l *AppendOnlyLog) Open() (int, error) {
+	file, err := os.OpenFile(l.cfg.Path(), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("can't open append-only log: %w", err)
+	}
+
+	replayed, size, err := l.replay(file)
+	if err != nil {
+		_ = file.Close()
+		return 0, err
+	}
+
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		_ = file.Close()
+		return 0, fmt.Errorf("can't seek append-only log: %w", err)
+	}
+
+	if size == 0 {
+		if err := writeAOFHeader(file); err != nil {
+			_ = file.Close()
+			return 0, err
+		}
+		size = int64(aofHeaderSize)
+	}
+
+	l.file = file
+	l.size = size
+	l.lastRewriteSize = size
+	l.storage.log.Store(l)
+
+	go l.run()
+	return replayed, nil
+}
+
+// writeAOFHeader записывает заголовок журнала
+func writeAOFHeader(w io.Writer) error {
+	header := make([]byte, 0, aofHeaderSize)
+	header = append(header, aofMagic...)
+	header = binary.BigEndian.AppendUint32(header, AOFVersion)
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("can't write append-only log header: %w", err)
+	}
+	return nil
+}
+
+// replay воспроизводит журнал и возвращает количество операций и размер корректной части журнала.
+// Повреждённый хвост журнала обрезается
+func (l *AppendOnlyLog) replay(file *os.File) (int, int64, error) {
+	r := bufio.NewReader(file)
+
+	header := make([]byte, aofHeaderSize)
+	n, err := io.ReadFull(r, header)
+	switch {
+	case n == 0 && errors.Is(err, io.EOF):
+		// Новый журнал
+		return 0, 0, nil
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		// Сбой произошёл во время записи заголовка: журнал пуст
+		return 0, 0, l.truncate(file, 0)
+	case err != nil:
+		return 0, 0, fmt.Errorf("can't read append-only log header: %w", err)
+	}
+	if !bytes.Equal(header[:len(aofMagic)], []byte(aofMagic)) {
+		return 0, 0, fmt.Errorf("%w: unknown file format", ErrInvalidAOF)
+	}
+	if version := binary.BigEndian.Uint32(header[len(aofMagic):]); version != AOFVersion {
+		return 0, 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidAOF, version)
+	}
+
+	offset := int64(aofHeaderSize)
+	replayed := 0
+	for {
+		record, size, err := readAOFRecord(r)
+		if errors.Is(err, io.EOF) {
+			return replayed, offset, nil
+		}
+		if err != nil {
+			// Недописанная или повреждённая последняя запись отбрасывается. Повреждение в середине журнала
+			// так не исправить: после него идут корректные операции, которые пришлось бы потерять
+			if _, peekErr := r.Peek(1); !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(peekErr, io.EOF) {
+				return 0, 0, fmt.Errorf("%w: corrupted record at offset %d: %w", ErrInvalidAOF, offset, err)
+			}
+
+			l.logger.Error("Append-only log has truncated tail record, discarding it",
+				"err", err,
+				"offset", offset,
+				"path", l.cfg.Path())
+			return replayed, offset, l.truncate(file, offset)
+		}
+
+		l.storage.applyAOFRecord(record)
+		offset += size
+		replayed++
+	}
+}
+
+// applyAOFRecord применяет к кешу операцию журнала, не записывая её в журнал повторно
+func (s *EmbeddedStorage) applyAOFRecord(record aofRecord) {
+	sh := s.shardFor(record.key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
+
+	expired := record.expiration > 0 && record.expiration < time.Now().UnixNano()
+	switch record.op {
+	case aofOpSet:
+		if expired {
+			sh.removeItem(record.key)
+			return
+		}
+		// Запись, которая не помещается в бюджет кеша (например, после его уменьшения), пропускается
+		_ = sh.storeItem(record.key, item{
+			Value:      record.value,
+			Expiration: record.expiration,
+			CasID:      s.nextCasID(),
+			Flags:      record.flags,
+		})
+	case aofOpDelete:
+		sh.removeItem(record.key)
+	case aofOpExpire:
+		i, found := sh.get(record.key)
+		switch {
+		case !found:
+		case expired:
+			sh.removeItem(record.key)
+		default:
+			sh.touchItem(record.key, i, record.expiration)
+		}
+	}
+}
+
+// truncate обрезает журнал до размера size
+func (l *AppendOnlyLog) truncate(file *os.File, size int64) error {
+	if err := file.Truncate(size); err != nil {
+		return fmt.Errorf("can't truncate append-only log: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("can't sync append-only log: %w", err)
+	}
+	return nil
+}
+
+// errAOFChecksum контрольная сумма записи журнала не совпала
+var errAOFChecksum = errors.New("checksum mismatch")
+
+// readAOFRecord читает запись журнала и возвращает её вместе с размером в байтах. Если журнал закончился
+// ровно на границе записи, возвращается io.EOF, а если посреди записи - io.ErrUnexpectedEOF
+func readAOFRecord(r *bufio.Reader) (aofRecord, int64, error) {
+	header := make([]byte, aofRecordHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return aofRecord{}, 0, err
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size > snapshotMaxFieldSize*2 {
+		return aofRecord{}, 0, fmt.Errorf("record is too large: %d bytes", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return aofRecord{}, 0, err
+	}
+
+	if crc32.Checksum(payload, snapshotCRCTable) != binary.BigEndian.Uint32(header[4:]) {
+		return aofRecord{}, 0, errAOFChecksum
+	}
+
+	record, err := decodeAOFRecord(payload)
+	if err != nil {
+		return aofRecord{}, 0, err
+	}
+
+	return record, int64(aofRecordHeaderSize) + int64(size), nil
+}
+
+// decodeAOFRecord разбирает операцию журнала
+func decodeAOFRecord(payload []byte) (aofRecord, error) {
+	if len(payload) == 0 {
+		return aofRecord{}, errors.New("empty record")
+	}
+
+	record := aofRecord{op: aofOp(payload[0])}
+	rest := payload[1:]
+
+	key, rest, err := decodeAOFField(rest)
+	if err != nil {
+		return aofRecord{}, err
+	}
+	record.key = string(key)
+
+	switch record.op {
+	case aofOpDelete:
+	case aofOpSet, aofOpSetFlags:
+		if record.value, rest, err = decodeAOFField(rest); err != nil {
+			return aofRecord{}, err
+		}
+		if len(rest) < 8 {
+			return aofRecord{}, errors.New("record is too short")
+		}
+		record.expiration = int64(binary.BigEndian.Uint64(rest))
+		rest = rest[8:]
+
+		if record.op == aofOpSetFlags {
+			if len(rest) < 4 {
+				return aofRecord{}, errors.New("record is too short")
+			}
+			record.flags = binary.BigEndian.Uint32(rest)
+			rest = rest[4:]
+			record.op = aofOpSet
+		}
+	case aofOpExpire:
+		if len(rest) < 8 {
+			return aofRecord{}, errors.New("record is too short")
+		}
+		record.expiration = int64(binary.BigEndian.Uint64(rest))
+		rest = rest[8:]
+	default:
+		return aofRecord{}, fmt.Errorf("unknown operation %d", record.op)
+	}
+
+	if len(rest) != 0 {
+		return aofRecord{}, errors.New("unexpected data at the end of record")
+	}
+	return record, nil
+}
+
+// decodeAOFField разбирает поле переменной длины
+func decodeAOFField(data []byte) ([]byte, []byte, error) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < size {
+		return nil, nil, errors.New("invalid field length")
+	}
+
+	data = data[n:]
+	return data[:size:size], data[size:], nil
+}
+
+// appendAOFRecord добавляет запись журнала вместе с заголовком в buf
+func appendAOFRecord(buf []byte, record aofRecord) []byte {
+	start := len(buf)
+	buf = append(buf, make([]byte, aofRecordHeaderSize)...)
+
+	op := record.op
+	if op == aofOpSet && record.flags != 0 {
+		op = aofOpSetFlags
+	}
+	buf = append(buf, byte(op))
+	buf = binary.AppendUvarint(buf, uint64(len(record.key)))
+	buf = append(buf, record.key...)
+	switch op {
+	case aofOpSet, aofOpSetFlags:
+		buf = binary.AppendUvarint(buf, uint64(len(record.value)))
+		buf = append(buf, record.value...)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(record.expiration))
+		if op == aofOpSetFlags {
+			buf = binary.BigEndian.AppendUint32(buf, record.flags)
+		}
+	case aofOpExpire:
+		buf = binary.BigEndian.AppendUint64(buf, uint64(record.expiration))
+	}
+
+	payload := buf[start+aofRecordHeaderSize:]
+	binary.BigEndian.PutUint32(buf[start:], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[start+4:], crc32.Checksum(payload, snapshotCRCTable))
+	return buf
+}
+
+// append дописывает операцию в журнал. Вызывается под мьютексом сегмента, которому принадлежит ключ,
+// поэтому операции с одним ключом попадают в журнал в том же порядке, в котором применяются к кешу
+func (l *AppendOnlyLog) append(record aofRecord) error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if l.file == nil {
+		return errAOFClosed
+	}
+
+	l.buf = appendAOFRecord(l.buf[:0], record)
+	if _, err := l.file.Write(l.buf); err != nil {
+		return fmt.Errorf("can't write to append-only log: %w", err)
+	}
+	l.size += int64(len(l.buf))
+
+	if l.rewriteBuf != nil {
+		l.rewriteBuf.Write(l.buf)
+	}
+
+	if l.cfg.Fsync() == FsyncAlways {
+		if err := l.file.Sync(); err != nil {
+			return fmt.Errorf("can't sync append-only log: %w", err)
+		}
+		return nil
+	}
+
+	l.dirty = true
+	return nil
+}
+
+// sync сбрасывает журнал на диск, если в него были записи с прошлого сброса
+func (l *AppendOnlyLog) sync() error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if l.file == nil || !l.dirty {
+		return nil
+	}
+
+	l.dirty = false
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("can't sync append-only log: %w", err)
+	}
+	return nil
+}
+
+// run сбрасывает журнал на диск согласно политике и перезаписывает его, когда он разрастается
+func (l *AppendOnlyLog) run() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(aofSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if l.cfg.Fsync() == FsyncEverySec {
+				if err := l.sync(); err != nil {
+					l.logger.Error("Can't sync append-only log", "err", err, "path", l.cfg.Path())
+				}
+			}
+
+			if l.needsRewrite() {
+				if err := l.Rewrite(); err != nil {
+					l.logger.Error("Can't rewrite append-only log", "err", err, "path", l.cfg.Path())
+				}
+			}
+		}
+	}
+}
+
+// needsRewrite проверяет, разросся ли журнал настолько, что его пора перезаписать
+func (l *AppendOnlyLog) needsRewrite() bool {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if l.cfg.RewritePercent() <= 0 || l.size < l.cfg.RewriteMinSize() {
+		return false
+	}
+	return l.size >= l.lastRewriteSize+l.lastRewriteSize*int64(l.cfg.RewritePercent())/100
+}
+
+// Size возвращает текущий размер журнала в байтах
+func (l *AppendOnlyLog) Size() int64 {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	return l.size
+}
+
+// Close останавливает фоновые задачи, отключает журнал от кеша, сбрасывает его на диск и закрывает
+func (l *AppendOnlyLog) Close() error {
+	var err error
+	l.stopOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+
+		l.storage.log.CompareAndSwap(l, nil)
+
+		l.mx.Lock()
+		defer l.mx.Unlock()
+
+		if syncErr := l.file.Sync(); syncErr != nil {
+			err = fmt.Errorf("can't sync append-only log: %w", syncErr)
+		}
+		if closeErr := l.file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("can't close append-only log: %w", closeErr)
+		}
+		l.file = nil
+	})
+	return err
+}
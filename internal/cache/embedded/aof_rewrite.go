@@ -0,0 +1,124 @@
+package embedded
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// errAOFClosed журнал закрыт
+var errAOFClosed = errors.New("append-only log is closed")
+
+// Rewrite перезаписывает журнал текущим содержимым кеша, чтобы он не рос бесконечно. Содержимое кеша
+// пишется во временный файл без блокировки журнала, операции, выполненные за это время, дописываются
+// в его конец, после чего временный файл атомарно заменяет журнал
+func (l *AppendOnlyLog) Rewrite() (err error) {
+	l.rewriteMx.Lock()
+	defer l.rewriteMx.Unlock()
+
+	l.mx.Lock()
+	if l.file == nil {
+		l.mx.Unlock()
+		return errAOFClosed
+	}
+	// Операции, выполненные с этого момента, могут не попасть в копию кеша, поэтому они запоминаются отдельно.
+	// Операции, попавшие и в копию, и в буфер, просто применятся повторно
+	l.rewriteBuf = &bytes.Buffer{}
+	l.mx.Unlock()
+
+	path := l.cfg.Path()
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".rewrite-*")
+	if err != nil {
+		l.cancelRewrite()
+		return fmt.Errorf("can't create temporary append-only log: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			l.cancelRewrite()
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	size, err := l.writeStorage(tmp)
+	if err != nil {
+		return err
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if l.file == nil {
+		return errAOFClosed
+	}
+
+	written, err := tmp.Write(l.rewriteBuf.Bytes())
+	if err != nil {
+		return fmt.Errorf("can't write to temporary append-only log: %w", err)
+	}
+	size += int64(written)
+
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("can't sync temporary append-only log: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("can't rename temporary append-only log: %w", err)
+	}
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		l.logger.Error("Can't sync append-only log directory", "err", err, "path", path)
+	}
+
+	// Временный файл уже стал журналом, поэтому дальнейшие ошибки не должны его удалять
+	if closeErr := l.file.Close(); closeErr != nil {
+		l.logger.Error("Can't close previous append-only log", "err", closeErr, "path", path)
+	}
+	l.file = tmp
+	l.size = size
+	l.lastRewriteSize = size
+	l.rewriteBuf = nil
+	l.dirty = false
+
+	return nil
+}
+
+// writeStorage записывает в файл заголовок журнала и операции set для всех актуальных записей кеша.
+// Возвращает размер записанных данных
+func (l *AppendOnlyLog) writeStorage(file *os.File) (int64, error) {
+	w := bufio.NewWriter(file)
+	if err := writeAOFHeader(w); err != nil {
+		return 0, err
+	}
+
+	size := int64(aofHeaderSize)
+	var buf []byte
+	for _, sh := range l.storage.shards {
+		for _, entry := range sh.snapshotEntries() {
+			buf = appendAOFRecord(buf[:0], aofRecord{
+				op:         aofOpSet,
+				key:        entry.key,
+				value:      entry.value,
+				expiration: entry.expiration,
+				flags:      entry.flags,
+			})
+			if _, err := w.Write(buf); err != nil {
+				return 0, fmt.Errorf("can't write to temporary append-only log: %w", err)
+			}
+			size += int64(len(buf))
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return 0, fmt.Errorf("can't write to temporary append-only log: %w", err)
+	}
+	return size, nil
+}
+
+// cancelRewrite прекращает запоминать операции для перезаписи журнала
+func (l *AppendOnlyLog) cancelRewrite() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.rewriteBuf = nil
+}
@@ -0,0 +1,328 @@
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+// testLogger пишет ошибки в лог теста
+type testLogger struct {
+	t *testing.T
+}
+
+func (l testLogger) Error(msg string, args ...interface{}) {
+	l.t.Log(append([]interface{}{msg}, args...)...)
+}
+
+// openAOF создаёт кеш и подключает к нему журнал из файла path
+func openAOF(t *testing.T, cfg *AOFConfig) (*EmbeddedStorage, *AppendOnlyLog, int) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour).WithShards(4))
+	log := NewAppendOnlyLog(s, cfg, testLogger{t})
+	replayed, err := log.Open()
+	assert.NoError(t, err)
+	return s, log, replayed
+}
+
+// fillAOF выполняет все виды операций, которые попадают в журнал
+func fillAOF(t *testing.T, s *EmbeddedStorage) {
+	ctx := context.Background()
+	assert.NoError(t, s.Set(ctx, "eternal", []byte("value"), 0))
+	assert.NoError(t, s.Set(ctx, "touched", []byte("value"), time.Minute))
+	assert.NoError(t, s.Touch(ctx, "touched", time.Hour))
+	assert.NoError(t, s.SetWithFlags(ctx, "counter", []byte("10"), 42, 0))
+	_, err := s.Increment(ctx, "counter", 5)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Set(ctx, "deleted", []byte("value"), 0))
+	assert.NoError(t, s.Delete(ctx, "deleted"))
+	assert.NoError(t, s.Set(ctx, "expired", []byte("value"), 10*time.Millisecond))
+	_, err = s.GetAndTouch(ctx, "expired", 20*time.Millisecond)
+	assert.NoError(t, err)
+}
+
+// assertAOFState проверяет состояние кеша после fillAOF
+func assertAOFState(t *testing.T, s *EmbeddedStorage) {
+	ctx := context.Background()
+
+	eternal, err := s.Get(ctx, "eternal")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), eternal.Value)
+	assert.Zero(t, eternal.TTL)
+
+	touched, err := s.Get(ctx, "touched")
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Hour, touched.TTL, float64(time.Second))
+
+	counter, err := s.Get(ctx, "counter")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("15"), counter.Value)
+	assert.Equal(t, uint32(42), counter.Flags)
+
+	_, err = s.Get(ctx, "deleted")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+	_, err = s.Get(ctx, "expired")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+}
+
+func TestAppendOnlyLog_Replay(t *testing.T) {
+	for _, fsync := range []FsyncPolicy{FsyncAlways, FsyncEverySec, FsyncNo} {
+		t.Run(string(fsync), func(t *testing.T) {
+			cfg := NewAOFConfig(filepath.Join(t.TempDir(), "cache.aof")).WithFsync(fsync)
+
+			s, log, replayed := openAOF(t, cfg)
+			assert.Equal(t, 0, replayed)
+			fillAOF(t, s)
+			assert.NoError(t, log.Close())
+			assert.NoError(t, log.Close())
+
+			// После закрытия журнала изменения в него не пишутся
+			assert.NoError(t, s.Set(context.Background(), "after-close", []byte("value"), 0))
+
+			time.Sleep(30 * time.Millisecond)
+			restored, log, replayed := openAOF(t, cfg)
+			defer log.Close()
+			assert.Equal(t, 9, replayed)
+			assertAOFState(t, restored)
+
+			_, err := restored.Get(context.Background(), "after-close")
+			assert.ErrorIs(t, err, cache.ErrNotFound)
+		})
+	}
+}
+
+func TestAppendOnlyLog_TruncatedTail(t *testing.T) {
+	tests := []struct {
+		name         string
+		corrupt      func(t *testing.T, path string)
+		wantReplayed int
+	}{
+		{
+			name:         "truncated record",
+			wantReplayed: 1,
+			corrupt: func(t *testing.T, path string) {
+				info, err := os.Stat(path)
+				assert.NoError(t, err)
+				assert.NoError(t, os.Truncate(path, info.Size()-3))
+			},
+		},
+		{
+			name:         "truncated record header",
+			wantReplayed: 2,
+			corrupt: func(t *testing.T, path string) {
+				f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+				assert.NoError(t, err)
+				_, err = f.Write([]byte{0, 0})
+				assert.NoError(t, err)
+				assert.NoError(t, f.Close())
+			},
+		},
+		{
+			name:         "corrupted last record",
+			wantReplayed: 1,
+			corrupt: func(t *testing.T, path string) {
+				data, err := os.ReadFile(path)
+				assert.NoError(t, err)
+				data[len(data)-1] ^= 0xff
+				assert.NoError(t, os.WriteFile(path, data, 0o644))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewAOFConfig(filepath.Join(t.TempDir(), "cache.aof"))
+
+			s, log, _ := openAOF(t, cfg)
+			ctx := context.Background()
+			assert.NoError(t, s.Set(ctx, "first", []byte("value"), 0))
+			assert.NoError(t, s.Set(ctx, "second", []byte("value"), 0))
+			assert.NoError(t, log.Close())
+
+			tt.corrupt(t, cfg.Path())
+
+			restored, log, replayed := openAOF(t, cfg)
+			assert.Equal(t, tt.wantReplayed, replayed)
+			_, err := restored.Get(ctx, "first")
+			assert.NoError(t, err)
+
+			// После обрезки хвоста журнал продолжает работать
+			assert.NoError(t, restored.Set(ctx, "third", []byte("value"), 0))
+			assert.NoError(t, log.Close())
+
+			restored, log, _ = openAOF(t, cfg)
+			defer log.Close()
+			_, err = restored.Get(ctx, "third")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAppendOnlyLog_CorruptedMiddle(t *testing.T) {
+	cfg := NewAOFConfig(filepath.Join(t.TempDir(), "cache.aof"))
+
+	s, log, _ := openAOF(t, cfg)
+	ctx := context.Background()
+	assert.NoError(t, s.Set(ctx, "first", []byte("value"), 0))
+	assert.NoError(t, s.Set(ctx, "second", []byte("value"), 0))
+	assert.NoError(t, log.Close())
+
+	data, err := os.ReadFile(cfg.Path())
+	assert.NoError(t, err)
+	data[aofHeaderSize+aofRecordHeaderSize+2] ^= 0xff
+	assert.NoError(t, os.WriteFile(cfg.Path(), data, 0o644))
+
+	_, err = NewAppendOnlyLog(NewEmbeddedStorage(NewConfig(time.Hour)), cfg, testLogger{t}).Open()
+	assert.ErrorIs(t, err, ErrInvalidAOF)
+
+	assert.NoError(t, os.WriteFile(cfg.Path(), []byte("not a log at all"), 0o644))
+	_, err = NewAppendOnlyLog(NewEmbeddedStorage(NewConfig(time.Hour)), cfg, testLogger{t}).Open()
+	assert.ErrorIs(t, err, ErrInvalidAOF)
+}
+
+func TestAppendOnlyLog_Rewrite(t *testing.T) {
+	cfg := NewAOFConfig(filepath.Join(t.TempDir(), "cache.aof"))
+
+	s, log, _ := openAOF(t, cfg)
+	ctx := context.Background()
+	for i := 0; i < 1000; i++ {
+		assert.NoError(t, s.Set(ctx, fmt.Sprintf("key-%d", i%10), []byte(fmt.Sprintf("value-%d", i)), 0))
+	}
+	assert.NoError(t, s.SetWithFlags(ctx, "flagged", []byte("value"), 42, 0))
+	sizeBefore := log.Size()
+
+	assert.NoError(t, log.Rewrite())
+	assert.Less(t, log.Size(), sizeBefore/10)
+
+	// Операции после перезаписи дописываются в новый журнал
+	assert.NoError(t, s.Delete(ctx, "key-0"))
+	assert.NoError(t, log.Close())
+
+	files, err := os.ReadDir(filepath.Dir(cfg.Path()))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	// 11 записей из перезаписанного журнала и удаление
+	restored, log, replayed := openAOF(t, cfg)
+	defer log.Close()
+	assert.Equal(t, 12, replayed)
+
+	flagged, err := restored.Get(ctx, "flagged")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(42), flagged.Flags)
+
+	_, err = restored.Get(ctx, "key-0")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+	for i := 1; i < 10; i++ {
+		item, err := restored.Get(ctx, fmt.Sprintf("key-%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(fmt.Sprintf("value-%d", 990+i)), item.Value)
+	}
+}
+
+func TestAppendOnlyLog_RewriteConcurrent(t *testing.T) {
+	cfg := NewAOFConfig(filepath.Join(t.TempDir(), "cache.aof"))
+
+	s, log, _ := openAOF(t, cfg)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("key-%d-%d", w, i%50)
+				if i%7 == 0 {
+					assert.NoError(t, s.Delete(ctx, key))
+					continue
+				}
+				assert.NoError(t, s.Set(ctx, key, []byte(fmt.Sprintf("value-%d", i)), 0))
+			}
+		}(w)
+	}
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, log.Rewrite())
+	}
+	wg.Wait()
+	assert.NoError(t, log.Close())
+
+	restored, log, _ := openAOF(t, cfg)
+	defer log.Close()
+	for w := 0; w < 4; w++ {
+		for i := 0; i < 50; i++ {
+			key := fmt.Sprintf("key-%d-%d", w, i)
+			want, wantErr := s.Get(ctx, key)
+			got, err := restored.Get(ctx, key)
+			assert.Equal(t, wantErr, err, key)
+			if wantErr == nil {
+				assert.Equal(t, want.Value, got.Value, key)
+			}
+		}
+	}
+}
+
+func TestAppendOnlyLog_needsRewrite(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             *AOFConfig
+		size            int64
+		lastRewriteSize int64
+		want            bool
+	}{
+		{
+			name:            "smaller than min size",
+			cfg:             NewAOFConfig("").WithRewriteMinSize(1000),
+			size:            900,
+			lastRewriteSize: 100,
+			want:            false,
+		},
+		{
+			name:            "not grown enough",
+			cfg:             NewAOFConfig("").WithRewriteMinSize(1000).WithRewritePercent(100),
+			size:            1500,
+			lastRewriteSize: 1000,
+			want:            false,
+		},
+		{
+			name:            "grown",
+			cfg:             NewAOFConfig("").WithRewriteMinSize(1000).WithRewritePercent(100),
+			size:            2000,
+			lastRewriteSize: 1000,
+			want:            true,
+		},
+		{
+			name:            "disabled",
+			cfg:             NewAOFConfig("").WithRewriteMinSize(1000).WithRewritePercent(0),
+			size:            100000,
+			lastRewriteSize: 1000,
+			want:            false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &AppendOnlyLog{
+				cfg:             tt.cfg,
+				size:            tt.size,
+				lastRewriteSize: tt.lastRewriteSize,
+			}
+			assert.Equal(t, tt.want, log.needsRewrite())
+		})
+	}
+}
+
+func TestParseFsyncPolicy(t *testing.T) {
+	for _, name := range []string{"always", "everysec", "no"} {
+		policy, err := ParseFsyncPolicy(name)
+		assert.NoError(t, err)
+		assert.Equal(t, FsyncPolicy(name), policy)
+	}
+
+	_, err := ParseFsyncPolicy("sometimes")
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package embedded
+
+import (
+	"context"
+	"errors"
+	"github.com/dimuska139/cacher/internal/cache"
+)
+
+// GetBatch возвращает записи по нескольким ключам в том же порядке, что и keys. Каждый сегмент блокируется
+// один раз на все его ключи. Если записи нет, в результате для неё cache.ErrNotFound
+func (s *EmbeddedStorage) GetBatch(ctx context.Context, keys []string) []cache.GetResult {
+	results := make([]cache.GetResult, len(keys))
+	s.eachShard(ctx, keys, func(n int, err error) {
+		results[n].Err = err
+	}, func(sh *shard, indices []int) {
+		unlock := sh.lockForRead()
+		defer unlock()
+
+		for _, n := range indices {
+			i, found := sh.get(keys[n])
+			if !found {
+				s.counters.Miss()
+				results[n].Err = cache.ErrNotFound
+				continue
+			}
+
+			s.counters.Hit()
+			sh.accessItem(keys[n])
+			results[n].Item = i.toCacheItem()
+		}
+	})
+
+	return results
+}
+
+// SetBatch записывает несколько записей. Каждый сегмент блокируется один раз на все его записи.
+// Возвращает ошибку для каждой записи в том же порядке, что и entries (nil - запись сохранена)
+func (s *EmbeddedStorage) SetBatch(ctx context.Context, entries []cache.Entry) []error {
+	keys := make([]string, len(entries))
+	for n, entry := range entries {
+		keys[n] = entry.Key
+	}
+
+	errs := make([]error, len(entries))
+	s.eachShard(ctx, keys, func(n int, err error) {
+		errs[n] = err
+	}, func(sh *shard, indices []int) {
+		sh.mx.Lock()
+		defer sh.mx.Unlock()
+
+		for _, n := range indices {
+			entry := entries[n]
+			i := item{
+				Value:      entry.Value,
+				Expiration: expirationTime(entry.TTL),
+				CasID:      s.nextCasID(),
+			}
+			if errs[n] = sh.storeItem(entry.Key, i); errs[n] != nil {
+				continue
+			}
+			s.counters.Set()
+			s.events.publish(cache.EventSet, entry.Key)
+
+			errs[n] = s.appendLog(setRecord(entry.Key, i))
+		}
+	})
+
+	return errs
+}
+
+// DeleteBatch удаляет записи по нескольким ключам. Каждый сегмент блокируется один раз на все его ключи.
+// Возвращает ошибку для каждого ключа в том же порядке, что и keys. Отсутствие записи ошибкой не является
+func (s *EmbeddedStorage) DeleteBatch(ctx context.Context, keys []string) []error {
+	errs := make([]error, len(keys))
+	s.eachShard(ctx, keys, func(n int, err error) {
+		errs[n] = err
+	}, func(sh *shard, indices []int) {
+		sh.mx.Lock()
+		defer sh.mx.Unlock()
+
+		for _, n := range indices {
+			if err := s.deleteItem(sh, keys[n]); !errors.Is(err, cache.ErrNotFound) {
+				errs[n] = err
+			}
+		}
+	})
+
+	return errs
+}
+
+// eachShard группирует ключи по сегментам и вызывает fn для каждого сегмента с индексами его ключей в keys
+// (в исходном порядке). Сегменты обрабатываются по очереди. Если ctx отменён, то ключи необработанных сегментов
+// получают ошибку ctx через fail
+func (s *EmbeddedStorage) eachShard(ctx context.Context, keys []string, fail func(n int, err error), fn func(sh *shard, indices []int)) {
+	indicesByShard := make([][]int, len(s.shards))
+	for n, key := range keys {
+		idx := s.shardIndex(key)
+		indicesByShard[idx] = append(indicesByShard[idx], n)
+	}
+
+	for idx, indices := range indicesByShard {
+		if len(indices) == 0 {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			for _, n := range indices {
+				fail(n, err)
+			}
+			continue
+		}
+
+		fn(s.shards[idx], indices)
+	}
+}
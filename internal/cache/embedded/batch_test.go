@@ -0,0 +1,92 @@
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbedStorage_Batch(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour).WithShards(4))
+	ctx := context.Background()
+
+	entries := make([]cache.Entry, 0, 20)
+	keys := make([]string, 0, 21)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		keys = append(keys, key)
+		entries = append(entries, cache.Entry{Key: key, Value: []byte(fmt.Sprintf("value-%d", i))})
+	}
+	entries[0].TTL = time.Hour
+	keys = append(keys, "missing")
+
+	errs := s.SetBatch(ctx, entries)
+	assert.Len(t, errs, len(entries))
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	results := s.GetBatch(ctx, keys)
+	assert.Len(t, results, len(keys))
+	for i, entry := range entries {
+		assert.NoError(t, results[i].Err, entry.Key)
+		assert.Equal(t, entry.Value, results[i].Item.Value, entry.Key)
+	}
+	assert.InDelta(t, time.Hour, results[0].Item.TTL, float64(time.Second))
+	assert.ErrorIs(t, results[20].Err, cache.ErrNotFound)
+	assert.Nil(t, results[20].Item)
+
+	errs = s.DeleteBatch(ctx, keys[:10])
+	assert.Len(t, errs, 10)
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	results = s.GetBatch(ctx, keys)
+	for i := range entries {
+		if i < 10 {
+			assert.ErrorIs(t, results[i].Err, cache.ErrNotFound, keys[i])
+		} else {
+			assert.NoError(t, results[i].Err, keys[i])
+		}
+	}
+
+	stats, err := s.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(30), stats.GetHits)
+	assert.Equal(t, uint64(12), stats.GetMisses)
+	assert.Equal(t, uint64(20), stats.Sets)
+	assert.Equal(t, uint64(10), stats.Deletes)
+	assert.Equal(t, uint64(10), stats.Items)
+}
+
+func TestEmbedStorage_SetBatch_TooLarge(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour).WithMaxBytes(1024).WithShards(1))
+
+	errs := s.SetBatch(context.Background(), []cache.Entry{
+		{Key: "small", Value: []byte("value")},
+		{Key: "huge", Value: make([]byte, 2048)},
+	})
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], cache.ErrTooLarge)
+}
+
+func TestEmbedStorage_Batch_Canceled(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour).WithShards(4))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := s.SetBatch(ctx, []cache.Entry{{Key: "first"}, {Key: "second"}})
+	for _, err := range errs {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+
+	results := s.GetBatch(context.Background(), []string{"first", "second"})
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, cache.ErrNotFound)
+	}
+}
@@ -0,0 +1,69 @@
+package embedded
+
+import (
+	"context"
+	"github.com/dimuska139/cacher/internal/cache"
+	"time"
+)
+
+// Add записывает информацию в кеш, только если записи с таким ключом ещё нет.
+// Если запись уже есть, возвращается cache.ErrNotStored
+func (s *EmbeddedStorage) Add(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.AddWithFlags(ctx, key, value, 0, ttl)
+}
+
+// AddWithFlags как Add, но сохраняет вместе со значением произвольные флаги
+func (s *EmbeddedStorage) AddWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error {
+	sh := s.shardFor(key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
+
+	if _, found := sh.get(key); found {
+		return cache.ErrNotStored
+	}
+
+	return s.store(sh, key, value, flags, ttl)
+}
+
+// Replace перезаписывает значение, только если запись с таким ключом уже есть.
+// Если записи нет, возвращается cache.ErrNotStored
+func (s *EmbeddedStorage) Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.ReplaceWithFlags(ctx, key, value, 0, ttl)
+}
+
+// ReplaceWithFlags как Replace, но сохраняет вместе со значением произвольные флаги
+func (s *EmbeddedStorage) ReplaceWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error {
+	sh := s.shardFor(key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
+
+	if _, found := sh.get(key); !found {
+		return cache.ErrNotStored
+	}
+
+	return s.store(sh, key, value, flags, ttl)
+}
+
+// CompareAndSwap перезаписывает значение, только если с момента чтения запись не менялась,
+// то есть её идентификатор версии равен casID. Если запись изменилась, возвращается cache.ErrExists,
+// если записи нет - cache.ErrNotFound
+func (s *EmbeddedStorage) CompareAndSwap(ctx context.Context, key string, value []byte, ttl time.Duration, casID uint64) error {
+	return s.CompareAndSwapWithFlags(ctx, key, value, 0, ttl, casID)
+}
+
+// CompareAndSwapWithFlags как CompareAndSwap, но сохраняет вместе со значением произвольные флаги
+func (s *EmbeddedStorage) CompareAndSwapWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration, casID uint64) error {
+	sh := s.shardFor(key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
+
+	i, found := sh.get(key)
+	if !found {
+		return cache.ErrNotFound
+	}
+	if i.CasID != casID {
+		return cache.ErrExists
+	}
+
+	return s.store(sh, key, value, flags, ttl)
+}
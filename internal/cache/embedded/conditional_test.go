@@ -0,0 +1,98 @@
+package embedded
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbedStorage_Add(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour))
+	ctx := context.Background()
+
+	assert.NoError(t, s.Add(ctx, "key", []byte("first"), 0))
+	assert.ErrorIs(t, s.Add(ctx, "key", []byte("second"), 0), cache.ErrNotStored)
+
+	item, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first"), item.Value)
+
+	// Запись с истёкшим временем жизни не мешает добавлению
+	assert.NoError(t, s.Set(ctx, "expired", []byte("old"), time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, s.Add(ctx, "expired", []byte("new"), 0))
+}
+
+func TestEmbedStorage_Replace(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour))
+	ctx := context.Background()
+
+	assert.ErrorIs(t, s.Replace(ctx, "key", []byte("first"), 0), cache.ErrNotStored)
+	_, err := s.Get(ctx, "key")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+
+	assert.NoError(t, s.Set(ctx, "key", []byte("first"), 0))
+	assert.NoError(t, s.Replace(ctx, "key", []byte("second"), time.Hour))
+
+	item, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("second"), item.Value)
+	assert.InDelta(t, time.Hour, item.TTL, float64(time.Second))
+}
+
+func TestEmbedStorage_CompareAndSwap(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour))
+	ctx := context.Background()
+
+	assert.ErrorIs(t, s.CompareAndSwap(ctx, "key", []byte("value"), 0, 1), cache.ErrNotFound)
+
+	assert.NoError(t, s.Set(ctx, "key", []byte("first"), 0))
+	item, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.CompareAndSwap(ctx, "key", []byte("second"), 0, item.CasID))
+	// Версия записи изменилась, поэтому повторная запись с тем же идентификатором отклоняется
+	assert.ErrorIs(t, s.CompareAndSwap(ctx, "key", []byte("third"), 0, item.CasID), cache.ErrExists)
+
+	item, err = s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("second"), item.Value)
+}
+
+func TestEmbedStorage_WithFlags(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour))
+	ctx := context.Background()
+
+	assert.NoError(t, s.AddWithFlags(ctx, "key", []byte("first"), 1, 0))
+	item, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), item.Flags)
+
+	assert.NoError(t, s.ReplaceWithFlags(ctx, "key", []byte("second"), 2, 0))
+	item, err = s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), item.Flags)
+
+	assert.NoError(t, s.CompareAndSwapWithFlags(ctx, "key", []byte("third"), 3, 0, item.CasID))
+	item, err = s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), item.Flags)
+
+	// Флаги сохраняются при изменении времени жизни и значения счётчика, а запись без флагов их сбрасывает
+	assert.NoError(t, s.SetWithFlags(ctx, "key", []byte("10"), 4, 0))
+	assert.NoError(t, s.Touch(ctx, "key", time.Hour))
+	_, err = s.Increment(ctx, "key", 1)
+	assert.NoError(t, err)
+	item, err = s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("11"), item.Value)
+	assert.Equal(t, uint32(4), item.Flags)
+
+	assert.NoError(t, s.Set(ctx, "key", []byte("value"), 0))
+	item, err = s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Zero(t, item.Flags)
+}
@@ -0,0 +1,121 @@
+package embedded
+
+import "time"
+
+// DefaultShards количество сегментов кеша по умолчанию
+const DefaultShards = 16
+
+// Config конфигурация кеша внутри памяти приложения
+type Config struct {
+	cleanupInterval time.Duration
+	maxBytes        int64
+	maxItems        int
+	policy          EvictionPolicyFactory
+	shards          int
+	watchBuffer     int
+	watchOverflow   OverflowPolicy
+}
+
+// NewConfig создаёт конфигурацию кеша внутри памяти приложения. По умолчанию размер кеша не ограничен
+func NewConfig(cleanupInterval time.Duration) *Config {
+	return &Config{
+		cleanupInterval: cleanupInterval,
+	}
+}
+
+// CleanupInterval возвращает период удаления записей с истёкшим временем жизни
+func (c *Config) CleanupInterval() time.Duration {
+	return c.cleanupInterval
+}
+
+// WithMaxBytes устанавливает максимальный суммарный размер записей в байтах (0 - без ограничений)
+func (c *Config) WithMaxBytes(maxBytes int64) *Config {
+	c.maxBytes = maxBytes
+	return c
+}
+
+// MaxBytes возвращает максимальный суммарный размер записей в байтах (0 - без ограничений)
+func (c *Config) MaxBytes() int64 {
+	return c.maxBytes
+}
+
+// WithMaxItems устанавливает максимальное количество записей (0 - без ограничений)
+func (c *Config) WithMaxItems(maxItems int) *Config {
+	c.maxItems = maxItems
+	return c
+}
+
+// MaxItems возвращает максимальное количество записей (0 - без ограничений)
+func (c *Config) MaxItems() int {
+	return c.maxItems
+}
+
+// WithEvictionPolicy устанавливает фабрику политик вытеснения записей при превышении бюджета.
+// Каждый сегмент кеша получает собственную политику
+func (c *Config) WithEvictionPolicy(factory EvictionPolicyFactory) *Config {
+	c.policy = factory
+	return c
+}
+
+// EvictionPolicy возвращает фабрику политик вытеснения записей. Если она не задана, используется LRU
+func (c *Config) EvictionPolicy() EvictionPolicyFactory {
+	if c.policy != nil {
+		return c.policy
+	}
+	return func(int) EvictionPolicy {
+		return NewLRUPolicy()
+	}
+}
+
+// WithShards устанавливает количество сегментов кеша (0 - DefaultShards).
+// Количество округляется вверх до степени двойки
+func (c *Config) WithShards(shards int) *Config {
+	c.shards = shards
+	return c
+}
+
+// Shards возвращает количество сегментов кеша: степень двойки не меньше заданного значения
+func (c *Config) Shards() int {
+	if c.shards <= 0 {
+		return DefaultShards
+	}
+
+	shards := 1
+	for shards < c.shards {
+		shards <<= 1
+	}
+	return shards
+}
+
+// WithWatchBuffer устанавливает размер буфера событий одного подписчика (0 - DefaultWatchBuffer)
+func (c *Config) WithWatchBuffer(buffer int) *Config {
+	c.watchBuffer = buffer
+	return c
+}
+
+// WatchBuffer возвращает размер буфера событий одного подписчика
+func (c *Config) WatchBuffer() int {
+	if c.watchBuffer <= 0 {
+		return DefaultWatchBuffer
+	}
+	return c.watchBuffer
+}
+
+// WithWatchOverflow устанавливает политику переполнения буфера событий подписчика
+func (c *Config) WithWatchOverflow(overflow OverflowPolicy) *Config {
+	c.watchOverflow = overflow
+	return c
+}
+
+// WatchOverflow возвращает политику переполнения буфера событий подписчика (по умолчанию DefaultOverflowPolicy)
+func (c *Config) WatchOverflow() OverflowPolicy {
+	if c.watchOverflow == "" {
+		return DefaultOverflowPolicy
+	}
+	return c.watchOverflow
+}
+
+// bounded проверяет, ограничен ли размер кеша
+func (c *Config) bounded() bool {
+	return c.maxBytes > 0 || c.maxItems > 0
+}
@@ -1,15 +1,43 @@
 package embedded
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dimuska139/cacher/internal/cache"
+	"hash/maphash"
 	"runtime"
-	"sync"
+	"strconv"
+	"sync/atomic"
 	"time"
 )
 
+// cleanerStallFactor во сколько раз пауза между срабатываниями функции очистки кеша может превысить период
+// очистки, прежде чем кеш будет считаться неработоспособным
+const cleanerStallFactor = 3
+
+var (
+	// ErrCleanerStopped функция очистки кеша остановлена
+	ErrCleanerStopped = errors.New("cleaner is stopped")
+	// ErrCleanerStalled функция очистки кеша давно не срабатывала
+	ErrCleanerStalled = errors.New("cleaner is stalled")
+)
+
+// itemOverhead примерный объём памяти, который запись занимает помимо ключа и значения:
+// элемент map, структура item, заголовки строки и слайса, а также узел политики вытеснения
+const itemOverhead = 128
+
+// itemSize возвращает размер записи, учитываемый в бюджете кеша
+func itemSize(key string, value []byte) int64 {
+	return int64(len(key)+len(value)) + itemOverhead
+}
+
 // item элемент кеша
 type item struct {
 	Value      []byte
 	Expiration int64
+	CasID      uint64
+	Flags      uint32
 }
 
 // IsExpired проверяет, не истекло ли время жизни элемента кеша
@@ -17,79 +45,369 @@ func (i *item) IsExpired() bool {
 	return i.Expiration > 0 && i.Expiration < time.Now().UnixNano()
 }
 
-// EmbeddedStorage кеш внутри памяти приложения
+// ttl возвращает оставшееся время жизни элемента кеша (0 - бессрочный элемент)
+func (i *item) ttl() time.Duration {
+	if i.Expiration == 0 {
+		return 0
+	}
+
+	// Элемент, время жизни которого истекает прямо сейчас, не должен выглядеть бессрочным
+	if ttl := time.Until(time.Unix(0, i.Expiration)); ttl > 0 {
+		return ttl
+	}
+	return time.Nanosecond
+}
+
+// toCacheItem возвращает элемент кеша в виде записи хранилища
+func (i *item) toCacheItem() *cache.Item {
+	return &cache.Item{
+		Value: i.Value,
+		Flags: i.Flags,
+		CasID: i.CasID,
+		TTL:   i.ttl(),
+	}
+}
+
+// EmbeddedStorage кеш внутри памяти приложения. Ключи распределены по сегментам по хешу ключа,
+// у каждого сегмента свой мьютекс, поэтому операции с разными ключами редко блокируют друг друга.
+// Если задан бюджет по количеству записей или их суммарному размеру, то он поровну делится между сегментами,
+// и при его превышении записи сегмента вытесняются согласно политике вытеснения
 type EmbeddedStorage struct {
-	items           map[string]item
+	shards []*shard
+	// Маска для выбора сегмента по хешу ключа (количество сегментов - степень двойки)
+	shardMask uint64
+	seed      maphash.Seed
+	// Последний выданный идентификатор версии записи
+	casSeq          atomic.Uint64
 	cleanupInterval time.Duration
-	mx              sync.RWMutex
 	stopCleaning    chan bool
+	// Время последнего срабатывания функции очистки кеша в наносекундах Unix-времени (0 - очистка остановлена)
+	cleanerHeartbeat atomic.Int64
+	// Журнал изменений (nil, если журнал не используется)
+	log atomic.Pointer[AppendOnlyLog]
+	// Счётчики операций
+	counters cache.Counters
+	// Шина событий изменения записей
+	events *EventBus
 }
 
 // NewEmbeddedStorage создаёт кеш внутри памяти приложения
-func NewEmbeddedStorage(cleanupInterval time.Duration) *EmbeddedStorage {
+func NewEmbeddedStorage(cfg *Config) *EmbeddedStorage {
+	shardsCount := cfg.Shards()
+	// Сегментов не должно быть больше, чем записей или байт в бюджете, иначе бюджет некоторых сегментов будет нулевым
+	for shardsCount > 1 &&
+		((cfg.MaxItems() > 0 && shardsCount > cfg.MaxItems()) || (cfg.MaxBytes() > 0 && int64(shardsCount) > cfg.MaxBytes())) {
+		shardsCount >>= 1
+	}
+
 	cache := &EmbeddedStorage{
-		items:           make(map[string]item),
-		cleanupInterval: cleanupInterval,
+		shards:          make([]*shard, shardsCount),
+		shardMask:       uint64(shardsCount - 1),
+		seed:            maphash.MakeSeed(),
+		cleanupInterval: cfg.CleanupInterval(),
 		stopCleaning:    make(chan bool),
+		events:          NewEventBus(cfg.WatchBuffer(), cfg.WatchOverflow()),
+	}
+
+	for n := range cache.shards {
+		maxBytes := splitBudget(cfg.MaxBytes(), shardsCount, n)
+		maxItems := int(splitBudget(int64(cfg.MaxItems()), shardsCount, n))
+
+		var policy EvictionPolicy
+		if cfg.bounded() {
+			capacityHint := maxItems
+			if capacityHint == 0 {
+				capacityHint = DefaultCapacityHint / shardsCount
+			}
+			policy = cfg.EvictionPolicy()(capacityHint)
+		}
+
+		cache.shards[n] = newShard(maxBytes, maxItems, policy)
 	}
 
+	cache.cleanerHeartbeat.Store(time.Now().UnixNano())
 	go cache.cleaner()
 
 	runtime.SetFinalizer(cache, finalizer)
 	return cache
 }
 
+// splitBudget возвращает долю бюджета total, приходящуюся на сегмент n из shards. Остаток от деления
+// достаётся первым сегментам
+func splitBudget(total int64, shards int, n int) int64 {
+	budget := total / int64(shards)
+	if int64(n) < total%int64(shards) {
+		budget++
+	}
+	return budget
+}
+
+// shardFor возвращает сегмент, в котором хранится ключ
+func (s *EmbeddedStorage) shardFor(key string) *shard {
+	return s.shards[s.shardIndex(key)]
+}
+
+// shardIndex возвращает номер сегмента, в котором хранится ключ
+func (s *EmbeddedStorage) shardIndex(key string) int {
+	if len(s.shards) == 1 {
+		return 0
+	}
+	return int(maphash.String(s.seed, key) & s.shardMask)
+}
+
+// Evictions возвращает количество записей, вытесненных из кеша из-за превышения бюджета
+func (s *EmbeddedStorage) Evictions() uint64 {
+	var evictions uint64
+	for _, sh := range s.shards {
+		evictions += sh.evictions.Load()
+	}
+	return evictions
+}
+
+// Stats возвращает статистику работы кеша. Размер записей учитывается вместе с накладными расходами,
+// как и в бюджете кеша. Статистика собирается без блокировок, поэтому показатели разных сегментов
+// могут относиться к немного разным моментам времени
+func (s *EmbeddedStorage) Stats(ctx context.Context) (*cache.Stats, error) {
+	stats := s.counters.Stats()
+	for _, sh := range s.shards {
+		stats.Evictions += sh.evictions.Load()
+		stats.Expirations += sh.expired.Load()
+		stats.Items += uint64(sh.itemsCount.Load())
+		stats.Bytes += uint64(sh.usedBytes.Load())
+	}
+	return stats, nil
+}
+
+// HealthCheck проверяет работоспособность кеша: функция очистки кеша должна работать
+// и срабатывать не реже, чем раз в cleanerStallFactor периодов очистки
+func (s *EmbeddedStorage) HealthCheck(ctx context.Context) error {
+	heartbeat := s.cleanerHeartbeat.Load()
+	if heartbeat == 0 {
+		return ErrCleanerStopped
+	}
+
+	if since := time.Since(time.Unix(0, heartbeat)); since > cleanerStallFactor*s.cleanupInterval {
+		return fmt.Errorf("%w: last cleanup was %s ago", ErrCleanerStalled, since.Round(time.Millisecond))
+	}
+
+	return nil
+}
+
 // finalizer корректно завершает работу EmbedStorage, останавливая функцию очистки кеша
 func finalizer(c *EmbeddedStorage) {
 	c.stopCleaning <- true
 }
 
-// Get возвращает закешированные данные
-func (s *EmbeddedStorage) Get(key string) ([]byte, error) {
-	s.mx.RLock()
-	defer s.mx.RUnlock()
+// Get возвращает закешированные данные вместе с метаданными. Если записи нет, возвращается cache.ErrNotFound
+func (s *EmbeddedStorage) Get(ctx context.Context, key string) (*cache.Item, error) {
+	sh := s.shardFor(key)
+	unlock := sh.lockForRead()
+	defer unlock()
 
-	i, found := s.items[key]
-	if !found || i.IsExpired() {
-		return nil, nil
+	i, found := sh.get(key)
+	if !found {
+		s.counters.Miss()
+		return nil, cache.ErrNotFound
 	}
 
-	return i.Value, nil
+	s.counters.Hit()
+	sh.accessItem(key)
+	return i.toCacheItem(), nil
 }
 
-// Set записывает информацию в кеш. Если запись в кеше уже есть, то она обновится
-func (s *EmbeddedStorage) Set(key string, value []byte, ttl time.Duration) error {
-	s.mx.Lock()
-	defer s.mx.Unlock()
+// GetAndTouch возвращает закешированные данные и одновременно устанавливает новое время жизни записи.
+// Если записи нет, возвращается cache.ErrNotFound
+func (s *EmbeddedStorage) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error) {
+	sh := s.shardFor(key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
 
-	var expireAt int64
-	if ttl > 0 {
-		expireAt = time.Now().Add(ttl).UnixNano()
+	i, found := sh.get(key)
+	if !found {
+		s.counters.Miss()
+		return nil, cache.ErrNotFound
+	}
+
+	s.counters.Hit()
+	i = sh.touchItem(key, i, expirationTime(ttl))
+	if err := s.appendLog(aofRecord{op: aofOpExpire, key: key, expiration: i.Expiration}); err != nil {
+		return nil, err
+	}
+
+	return i.toCacheItem(), nil
+}
+
+// Touch устанавливает новое время жизни записи. Если записи нет, возвращается cache.ErrNotFound
+func (s *EmbeddedStorage) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	sh := s.shardFor(key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
+
+	i, found := sh.get(key)
+	if !found {
+		return cache.ErrNotFound
 	}
 
-	s.items[key] = item{
+	i = sh.touchItem(key, i, expirationTime(ttl))
+
+	return s.appendLog(aofRecord{op: aofOpExpire, key: key, expiration: i.Expiration})
+}
+
+// Set записывает информацию в кеш. Если запись в кеше уже есть, то она обновится.
+// Если запись больше бюджета сегмента кеша, возвращается cache.ErrTooLarge
+func (s *EmbeddedStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.SetWithFlags(ctx, key, value, 0, ttl)
+}
+
+// SetWithFlags записывает информацию в кеш вместе с произвольными флагами, которые возвращаются при чтении.
+// Если запись в кеше уже есть, то она обновится
+func (s *EmbeddedStorage) SetWithFlags(ctx context.Context, key string, value []byte, flags uint32, ttl time.Duration) error {
+	sh := s.shardFor(key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
+
+	return s.store(sh, key, value, flags, ttl)
+}
+
+// store записывает значение в сегмент, учитывает запись в статистике, публикует событие и записывает
+// операцию в журнал изменений. Вызывается под мьютексом сегмента
+func (s *EmbeddedStorage) store(sh *shard, key string, value []byte, flags uint32, ttl time.Duration) error {
+	i := item{
 		Value:      value,
-		Expiration: expireAt,
+		Expiration: expirationTime(ttl),
+		CasID:      s.nextCasID(),
+		Flags:      flags,
+	}
+	if err := sh.storeItem(key, i); err != nil {
+		return err
+	}
+	s.counters.Set()
+	s.events.publish(cache.EventSet, key)
+
+	return s.appendLog(setRecord(key, i))
+}
+
+// setRecord возвращает операцию журнала, записывающую элемент кеша
+func setRecord(key string, i item) aofRecord {
+	return aofRecord{op: aofOpSet, key: key, value: i.Value, expiration: i.Expiration, flags: i.Flags}
+}
+
+// appendLog записывает операцию в журнал изменений, если он используется. Вызывается под мьютексом сегмента
+func (s *EmbeddedStorage) appendLog(record aofRecord) error {
+	if log := s.log.Load(); log != nil {
+		return log.append(record)
 	}
 	return nil
 }
 
+// nextCasID возвращает новый идентификатор версии записи
+func (s *EmbeddedStorage) nextCasID() uint64 {
+	return s.casSeq.Add(1)
+}
+
+// expirationTime возвращает момент истечения времени жизни записи (0 - бессрочная запись)
+func expirationTime(ttl time.Duration) int64 {
+	if ttl > 0 {
+		return time.Now().Add(ttl).UnixNano()
+	}
+	return 0
+}
+
+// Increment увеличивает числовое значение записи на delta и возвращает новое значение.
+// Как и в Memcache, значение - 64-битное беззнаковое число, которое при переполнении начинается с нуля
+func (s *EmbeddedStorage) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	return s.incrDecr(key, func(value uint64) uint64 {
+		return value + delta
+	})
+}
+
+// Decrement уменьшает числовое значение записи на delta и возвращает новое значение.
+// Как и в Memcache, значение не может стать меньше нуля
+func (s *EmbeddedStorage) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	return s.incrDecr(key, func(value uint64) uint64 {
+		if delta > value {
+			return 0
+		}
+		return value - delta
+	})
+}
+
+// incrDecr атомарно изменяет числовое значение записи с помощью функции apply
+func (s *EmbeddedStorage) incrDecr(key string, apply func(value uint64) uint64) (uint64, error) {
+	sh := s.shardFor(key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
+
+	i, found := sh.get(key)
+	if !found {
+		return 0, cache.ErrNotFound
+	}
+
+	value, err := strconv.ParseUint(string(i.Value), 10, 64)
+	if err != nil {
+		return 0, cache.ErrNotNumeric
+	}
+
+	value = apply(value)
+	i.Value = []byte(strconv.FormatUint(value, 10))
+	i.CasID = s.nextCasID()
+	if err := sh.storeItem(key, i); err != nil {
+		return 0, err
+	}
+	s.events.publish(cache.EventSet, key)
+	if err := s.appendLog(setRecord(key, i)); err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
 // Delete удаляет запись из кеша по ключу
-func (s *EmbeddedStorage) Delete(key string) error {
-	s.mx.Lock()
-	defer s.mx.Unlock()
-	delete(s.items, key)
+func (s *EmbeddedStorage) Delete(ctx context.Context, key string) error {
+	if err := s.DeleteExisting(ctx, key); err != nil && !errors.Is(err, cache.ErrNotFound) {
+		return err
+	}
+
 	return nil
 }
 
-// deleteExpired удаляет из кеша записи с истёкшим временем жизни
+// DeleteExisting удаляет запись из кеша по ключу. Если актуальной записи нет, возвращается cache.ErrNotFound
+func (s *EmbeddedStorage) DeleteExisting(ctx context.Context, key string) error {
+	sh := s.shardFor(key)
+	sh.mx.Lock()
+	defer sh.mx.Unlock()
+
+	return s.deleteItem(sh, key)
+}
+
+// deleteItem удаляет запись из сегмента. Удаление учитывается в статистике и публикуется, только если
+// запись была актуальной. Запись с истёкшим временем жизни удаляется так же, как при очистке истёкших записей,
+// и для неё возвращается cache.ErrNotFound. Вызывается под мьютексом сегмента
+func (s *EmbeddedStorage) deleteItem(sh *shard, key string) error {
+	i, found := sh.items[key]
+	if !found {
+		return cache.ErrNotFound
+	}
+
+	sh.removeItem(key)
+	if i.IsExpired() {
+		sh.expired.Add(1)
+		s.events.publish(cache.EventExpire, key)
+		return cache.ErrNotFound
+	}
+
+	s.counters.Delete()
+	s.events.publish(cache.EventDelete, key)
+	return s.appendLog(aofRecord{op: aofOpDelete, key: key})
+}
+
+// deleteExpired удаляет из кеша записи с истёкшим временем жизни. Сегменты очищаются по очереди,
+// поэтому в каждый момент заблокирован только один из них
 func (s *EmbeddedStorage) deleteExpired() {
-	s.mx.Lock()
-	defer s.mx.Unlock()
-	for k, v := range s.items {
-		if v.IsExpired() {
-			delete(s.items, k)
-		}
+	for _, sh := range s.shards {
+		sh.deleteExpired(func(key string) {
+			s.events.publish(cache.EventExpire, key)
+		})
 	}
 }
 
@@ -100,9 +418,11 @@ func (s *EmbeddedStorage) cleaner() {
 		select {
 		case <-s.stopCleaning:
 			ticker.Stop()
+			s.cleanerHeartbeat.Store(0)
 			return
 		case <-ticker.C:
 			s.deleteExpired()
+			s.cleanerHeartbeat.Store(time.Now().UnixNano())
 		}
 	}
 }
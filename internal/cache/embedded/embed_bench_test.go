@@ -0,0 +1,74 @@
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// benchmarkKeys количество ключей, с которыми работают бенчмарки
+const benchmarkKeys = 100000
+
+// newBenchmarkStorage создаёт кеш из shards сегментов и заполняет его. Кеш из одного сегмента
+// соответствует прежней реализации с одним мьютексом на весь кеш
+func newBenchmarkStorage(b *testing.B, shards int, cleanupInterval time.Duration) (*EmbeddedStorage, []string) {
+	s := NewEmbeddedStorage(NewConfig(cleanupInterval).WithShards(shards))
+	b.Cleanup(func() {
+		finalizer(s)
+	})
+
+	keys := make([]string, benchmarkKeys)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		if err := s.Set(context.Background(), keys[i], []byte("value"), time.Hour); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return s, keys
+}
+
+// runParallel выполняет смешанную нагрузку: writePercent процентов записей, остальное - чтения
+func runParallel(b *testing.B, s *EmbeddedStorage, keys []string, writePercent int) {
+	ctx := context.Background()
+	var seed atomic.Int64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		random := rand.New(rand.NewSource(seed.Add(1)))
+		for pb.Next() {
+			key := keys[random.Intn(len(keys))]
+			if random.Intn(100) < writePercent {
+				_ = s.Set(ctx, key, []byte("value"), time.Hour)
+			} else {
+				_, _ = s.Get(ctx, key)
+			}
+		}
+	})
+}
+
+func BenchmarkEmbedStorage_Parallel(b *testing.B) {
+	for _, shards := range []int{1, 16, 64} {
+		for _, writePercent := range []int{10, 50} {
+			b.Run(fmt.Sprintf("shards=%d/writes=%d%%", shards, writePercent), func(b *testing.B) {
+				s, keys := newBenchmarkStorage(b, shards, time.Hour)
+				runParallel(b, s, keys, writePercent)
+			})
+		}
+	}
+}
+
+// BenchmarkEmbedStorage_ParallelWithCleanup измеряет пропускную способность, пока очистка
+// истёкших записей регулярно обходит кеш
+func BenchmarkEmbedStorage_ParallelWithCleanup(b *testing.B) {
+	for _, shards := range []int{1, 16, 64} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			s, keys := newBenchmarkStorage(b, shards, 50*time.Millisecond)
+			runParallel(b, s, keys, 10)
+		})
+	}
+}
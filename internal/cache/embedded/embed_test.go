@@ -1,7 +1,12 @@
 package embedded
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dimuska139/cacher/internal/cache"
 	"github.com/stretchr/testify/assert"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -46,13 +51,9 @@ func TestEmbedStorage_Delete(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &EmbeddedStorage{
-				items:           tt.fields.items,
-				cleanupInterval: tt.fields.cleanupInterval,
-				stopCleaning:    tt.fields.stopCleaning,
-			}
+			s := newTestStorage(tt.fields.items)
 
-			err := s.Delete(tt.args.key)
+			err := s.Delete(context.Background(), tt.args.key)
 			if tt.wantErr {
 				assert.Error(t, err)
 			}
@@ -62,6 +63,24 @@ func TestEmbedStorage_Delete(t *testing.T) {
 	}
 }
 
+func TestEmbedStorage_DeleteExisting(t *testing.T) {
+	s := newTestStorage(map[string]item{
+		"existing": {
+			Value: []byte("test"),
+		},
+		"expired": {
+			Value:      []byte("test"),
+			Expiration: time.Now().Add(-time.Minute).UnixNano(),
+		},
+	})
+	ctx := context.Background()
+
+	assert.NoError(t, s.DeleteExisting(ctx, "existing"))
+	assert.ErrorIs(t, s.DeleteExisting(ctx, "existing"), cache.ErrNotFound)
+	assert.ErrorIs(t, s.DeleteExisting(ctx, "expired"), cache.ErrNotFound)
+	assert.ErrorIs(t, s.DeleteExisting(ctx, "not-existing"), cache.ErrNotFound)
+}
+
 func TestEmbedStorage_Get(t *testing.T) {
 	type fields struct {
 		items           map[string]item
@@ -75,15 +94,17 @@ func TestEmbedStorage_Get(t *testing.T) {
 		name    string
 		fields  fields
 		args    args
-		want    []byte
-		wantErr bool
+		want    *cache.Item
+		wantTTL time.Duration
+		wantErr error
 	}{
 		{
 			name: "not found",
 			args: args{
 				key: "not-existing-key",
 			},
-			want: nil,
+			want:    nil,
+			wantErr: cache.ErrNotFound,
 		},
 		{
 			name: "existing",
@@ -94,10 +115,48 @@ func TestEmbedStorage_Get(t *testing.T) {
 				items: map[string]item{
 					"existing-key": {
 						Value: []byte("test"),
+						CasID: 3,
 					},
 				},
 			},
-			want: []byte("test"),
+			want: &cache.Item{
+				Value: []byte("test"),
+				CasID: 3,
+			},
+		},
+		{
+			name: "empty value",
+			args: args{
+				key: "empty-key",
+			},
+			fields: fields{
+				items: map[string]item{
+					"empty-key": {
+						Value: []byte{},
+					},
+				},
+			},
+			want: &cache.Item{
+				Value: []byte{},
+			},
+		},
+		{
+			name: "with ttl",
+			args: args{
+				key: "expiring-key",
+			},
+			fields: fields{
+				items: map[string]item{
+					"expiring-key": {
+						Value:      []byte("test"),
+						Expiration: time.Now().Add(time.Minute).UnixNano(),
+					},
+				},
+			},
+			want: &cache.Item{
+				Value: []byte("test"),
+			},
+			wantTTL: time.Minute,
 		},
 		{
 			name: "expired",
@@ -112,22 +171,24 @@ func TestEmbedStorage_Get(t *testing.T) {
 					},
 				},
 			},
-			want: nil,
+			want:    nil,
+			wantErr: cache.ErrNotFound,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &EmbeddedStorage{
-				items:           tt.fields.items,
-				cleanupInterval: tt.fields.cleanupInterval,
-				stopCleaning:    tt.fields.stopCleaning,
-			}
+			s := newTestStorage(tt.fields.items)
 
-			got, err := s.Get(tt.args.key)
-			if tt.wantErr {
-				assert.Error(t, err)
+			got, err := s.Get(context.Background(), tt.args.key)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
 			}
 
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.wantTTL, got.TTL, float64(time.Second))
+			got.TTL = 0
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -137,7 +198,6 @@ func TestEmbedStorage_Set(t *testing.T) {
 	type fields struct {
 		items           map[string]item
 		cleanupInterval time.Duration
-		mx              sync.RWMutex
 		stopCleaning    chan bool
 	}
 	type args struct {
@@ -166,16 +226,388 @@ func TestEmbedStorage_Set(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &EmbeddedStorage{
-				items:           tt.fields.items,
-				cleanupInterval: tt.fields.cleanupInterval,
-				stopCleaning:    tt.fields.stopCleaning,
+			s := newTestStorage(tt.fields.items)
+			assert.NoError(t, s.Set(context.Background(), tt.args.key, tt.args.value, tt.args.expiration))
+		})
+	}
+}
+
+func TestEmbedStorage_Touch(t *testing.T) {
+	type fields struct {
+		items map[string]item
+	}
+	type args struct {
+		key string
+		ttl time.Duration
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantTTL     time.Duration
+		wantErr     error
+		wantEternal bool
+	}{
+		{
+			name: "not found",
+			fields: fields{
+				items: map[string]item{},
+			},
+			args: args{
+				key: "key",
+				ttl: time.Minute,
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "expired",
+			fields: fields{
+				items: map[string]item{
+					"key": {
+						Value:      []byte("test"),
+						Expiration: time.Now().Add(-time.Second * 10).UnixNano(),
+					},
+				},
+			},
+			args: args{
+				key: "key",
+				ttl: time.Minute,
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "extend",
+			fields: fields{
+				items: map[string]item{
+					"key": {
+						Value:      []byte("test"),
+						Expiration: time.Now().Add(time.Second).UnixNano(),
+					},
+				},
+			},
+			args: args{
+				key: "key",
+				ttl: time.Minute,
+			},
+			wantTTL: time.Minute,
+		},
+		{
+			name: "make eternal",
+			fields: fields{
+				items: map[string]item{
+					"key": {
+						Value:      []byte("test"),
+						Expiration: time.Now().Add(time.Second).UnixNano(),
+					},
+				},
+			},
+			args: args{
+				key: "key",
+				ttl: 0,
+			},
+			wantEternal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(tt.fields.items)
+
+			err := s.Touch(context.Background(), tt.args.key, tt.args.ttl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			if tt.wantEternal {
+				assert.Zero(t, s.shards[0].items[tt.args.key].Expiration)
+			} else {
+				assert.InDelta(t, time.Now().Add(tt.wantTTL).UnixNano(), s.shards[0].items[tt.args.key].Expiration, float64(time.Second))
+			}
+		})
+	}
+}
+
+func TestEmbedStorage_GetAndTouch(t *testing.T) {
+	type fields struct {
+		items map[string]item
+	}
+	type args struct {
+		key string
+		ttl time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "not found",
+			fields: fields{
+				items: map[string]item{},
+			},
+			args: args{
+				key: "key",
+				ttl: time.Minute,
+			},
+			want:    nil,
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "existing",
+			fields: fields{
+				items: map[string]item{
+					"key": {
+						Value:      []byte("test"),
+						Expiration: time.Now().Add(time.Second).UnixNano(),
+					},
+				},
+			},
+			args: args{
+				key: "key",
+				ttl: time.Minute,
+			},
+			want: []byte("test"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(tt.fields.items)
+
+			got, err := s.GetAndTouch(context.Background(), tt.args.key, tt.args.ttl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Value)
+			assert.InDelta(t, tt.args.ttl, got.TTL, float64(time.Second))
+			assert.InDelta(t, time.Now().Add(tt.args.ttl).UnixNano(), s.shards[0].items[tt.args.key].Expiration, float64(time.Second))
+		})
+	}
+}
+
+func TestEmbedStorage_Increment(t *testing.T) {
+	type fields struct {
+		items map[string]item
+	}
+	type args struct {
+		key   string
+		delta uint64
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		want      uint64
+		wantValue []byte
+		wantErr   error
+	}{
+		{
+			name: "not found",
+			fields: fields{
+				items: map[string]item{},
+			},
+			args: args{
+				key:   "counter",
+				delta: 1,
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "expired",
+			fields: fields{
+				items: map[string]item{
+					"counter": {
+						Value:      []byte("1"),
+						Expiration: time.Now().Add(-time.Second * 10).UnixNano(),
+					},
+				},
+			},
+			args: args{
+				key:   "counter",
+				delta: 1,
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "not numeric",
+			fields: fields{
+				items: map[string]item{
+					"counter": {
+						Value: []byte("test"),
+					},
+				},
+			},
+			args: args{
+				key:   "counter",
+				delta: 1,
+			},
+			wantErr: cache.ErrNotNumeric,
+		},
+		{
+			name: "increment",
+			fields: fields{
+				items: map[string]item{
+					"counter": {
+						Value: []byte("10"),
+					},
+				},
+			},
+			args: args{
+				key:   "counter",
+				delta: 5,
+			},
+			want:      15,
+			wantValue: []byte("15"),
+		},
+		{
+			name: "wrap on overflow",
+			fields: fields{
+				items: map[string]item{
+					"counter": {
+						Value: []byte("18446744073709551615"),
+					},
+				},
+			},
+			args: args{
+				key:   "counter",
+				delta: 2,
+			},
+			want:      1,
+			wantValue: []byte("1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(tt.fields.items)
+
+			got, err := s.Increment(context.Background(), tt.args.key, tt.args.delta)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantValue, s.shards[0].items[tt.args.key].Value)
+		})
+	}
+}
+
+func TestEmbedStorage_Decrement(t *testing.T) {
+	type fields struct {
+		items map[string]item
+	}
+	type args struct {
+		key   string
+		delta uint64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    uint64
+		wantErr error
+	}{
+		{
+			name: "not found",
+			fields: fields{
+				items: map[string]item{},
+			},
+			args: args{
+				key:   "counter",
+				delta: 1,
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "not numeric",
+			fields: fields{
+				items: map[string]item{
+					"counter": {
+						Value: []byte("-1"),
+					},
+				},
+			},
+			args: args{
+				key:   "counter",
+				delta: 1,
+			},
+			wantErr: cache.ErrNotNumeric,
+		},
+		{
+			name: "decrement",
+			fields: fields{
+				items: map[string]item{
+					"counter": {
+						Value: []byte("10"),
+					},
+				},
+			},
+			args: args{
+				key:   "counter",
+				delta: 3,
+			},
+			want: 7,
+		},
+		{
+			name: "clamp at zero",
+			fields: fields{
+				items: map[string]item{
+					"counter": {
+						Value: []byte("10"),
+					},
+				},
+			},
+			args: args{
+				key:   "counter",
+				delta: 100,
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(tt.fields.items)
+
+			got, err := s.Decrement(context.Background(), tt.args.key, tt.args.delta)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
 			}
-			assert.NoError(t, s.Set(tt.args.key, tt.args.value, tt.args.expiration))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
+func TestEmbedStorage_Increment_Concurrent(t *testing.T) {
+	s := newTestStorage(map[string]item{
+		"counter": {
+			Value: []byte("0"),
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Increment(context.Background(), "counter", 1)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []byte("100"), s.shards[0].items["counter"].Value)
+}
+
 func TestEmbedStorage_cleaner(t *testing.T) {
 	type fields struct {
 		items           map[string]item
@@ -196,11 +628,9 @@ func TestEmbedStorage_cleaner(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &EmbeddedStorage{
-				items:           tt.fields.items,
-				cleanupInterval: tt.fields.cleanupInterval,
-				stopCleaning:    tt.fields.stopCleaning,
-			}
+			s := newTestStorage(tt.fields.items)
+			s.cleanupInterval = tt.fields.cleanupInterval
+			s.stopCleaning = tt.fields.stopCleaning
 			go s.cleaner()
 			time.Sleep(100 * time.Millisecond)
 			finalizer(s)
@@ -212,7 +642,6 @@ func TestEmbedStorage_deleteExpired(t *testing.T) {
 	type fields struct {
 		items           map[string]item
 		cleanupInterval time.Duration
-		mx              sync.RWMutex
 		stopCleaning    chan bool
 	}
 	tests := []struct {
@@ -236,43 +665,136 @@ func TestEmbedStorage_deleteExpired(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &EmbeddedStorage{
-				items:           tt.fields.items,
-				cleanupInterval: tt.fields.cleanupInterval,
-				stopCleaning:    tt.fields.stopCleaning,
-			}
+			s := newTestStorage(tt.fields.items)
 			s.deleteExpired()
 			assert.Equal(t, map[string]item{
 				"not-expired": {
 					Value: []byte("test-2"),
 				},
-			}, s.items)
+			}, s.shards[0].items)
 		})
 	}
 }
 
 func TestNewEmbedStorage(t *testing.T) {
+	lfu, err := NewEvictionPolicyFactory(PolicyLFU)
+	assert.NoError(t, err)
+
 	type args struct {
-		cleanupInterval time.Duration
+		cfg *Config
 	}
 	tests := []struct {
-		name string
-		args args
+		name         string
+		args         args
+		wantShards   int
+		wantPolicy   bool
+		wantMaxItems int
+		wantMaxBytes int64
 	}{
 		{
-			name: "creation",
+			name: "unbounded",
+			args: args{
+				cfg: NewConfig(time.Second),
+			},
+			wantShards: DefaultShards,
+			wantPolicy: false,
+		},
+		{
+			name: "shards are rounded up to power of two",
+			args: args{
+				cfg: NewConfig(time.Second).WithShards(5),
+			},
+			wantShards: 8,
+			wantPolicy: false,
+		},
+		{
+			name: "bounded with default policy",
+			args: args{
+				cfg: NewConfig(time.Second).WithShards(4).WithMaxItems(100),
+			},
+			wantShards:   4,
+			wantPolicy:   true,
+			wantMaxItems: 100,
+		},
+		{
+			name: "bounded with policy",
+			args: args{
+				cfg: NewConfig(time.Second).WithShards(4).WithMaxBytes(1026).WithEvictionPolicy(lfu),
+			},
+			wantShards:   4,
+			wantPolicy:   true,
+			wantMaxBytes: 1026,
+		},
+		{
+			name: "shards are limited by budget",
 			args: args{
-				cleanupInterval: time.Second,
+				cfg: NewConfig(time.Second).WithShards(16).WithMaxItems(3),
 			},
+			wantShards:   2,
+			wantPolicy:   true,
+			wantMaxItems: 3,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.NotNil(t, NewEmbeddedStorage(tt.args.cleanupInterval))
+			s := NewEmbeddedStorage(tt.args.cfg)
+			assert.NotNil(t, s)
+			assert.Len(t, s.shards, tt.wantShards)
+
+			// Бюджет делится между сегментами без остатка
+			var (
+				maxItems int
+				maxBytes int64
+			)
+			for _, sh := range s.shards {
+				assert.Equal(t, tt.wantPolicy, sh.policy != nil)
+				maxItems += sh.maxItems
+				maxBytes += sh.maxBytes
+			}
+			assert.Equal(t, tt.wantMaxItems, maxItems)
+			assert.Equal(t, tt.wantMaxBytes, maxBytes)
 		})
 	}
 }
 
+func TestEmbedStorage_Sharded(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour).WithShards(8))
+	ctx := context.Background()
+
+	for i := 0; i < 1000; i++ {
+		ttl := time.Duration(0)
+		if i%2 == 0 {
+			ttl = time.Nanosecond
+		}
+		assert.NoError(t, s.Set(ctx, fmt.Sprintf("key-%d", i), []byte(strconv.Itoa(i)), ttl))
+	}
+
+	// Ключи распределяются по всем сегментам
+	for _, sh := range s.shards {
+		assert.NotEmpty(t, sh.items)
+	}
+
+	time.Sleep(time.Millisecond)
+	s.deleteExpired()
+
+	for i := 0; i < 1000; i++ {
+		item, err := s.Get(ctx, fmt.Sprintf("key-%d", i))
+		if i%2 == 0 {
+			assert.ErrorIs(t, err, cache.ErrNotFound)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(strconv.Itoa(i)), item.Value)
+	}
+
+	var stored int
+	for _, sh := range s.shards {
+		stored += len(sh.items)
+	}
+	assert.Equal(t, 500, stored)
+}
+
 func Test_item_IsExpired(t *testing.T) {
 	type fields struct {
 		Value      []byte
@@ -319,3 +841,174 @@ func Test_item_IsExpired(t *testing.T) {
 		})
 	}
 }
+
+func TestEmbedStorage_CasID(t *testing.T) {
+	s := newTestStorage(map[string]item{})
+	ctx := context.Background()
+
+	assert.NoError(t, s.Set(ctx, "key", []byte("1"), 0))
+	first, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+
+	// Обновление времени жизни не меняет версию записи
+	assert.NoError(t, s.Touch(ctx, "key", time.Minute))
+	touched, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, first.CasID, touched.CasID)
+
+	_, err = s.Increment(ctx, "key", 1)
+	assert.NoError(t, err)
+	incremented, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Greater(t, incremented.CasID, first.CasID)
+
+	assert.NoError(t, s.Set(ctx, "key", []byte("3"), 0))
+	updated, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Greater(t, updated.CasID, incremented.CasID)
+}
+
+// newTestStorage создаёт кеш из одного сегмента с заданными записями без фоновой очистки
+func newTestStorage(items map[string]item) *EmbeddedStorage {
+	sh := newShard(0, 0, nil)
+	for key, i := range items {
+		_ = sh.storeItem(key, i)
+	}
+
+	return &EmbeddedStorage{
+		shards: []*shard{sh},
+	}
+}
+
+// newBoundedStorage создаёт кеш из одного сегмента с ограничением размера без фоновой очистки
+func newBoundedStorage(maxBytes int64, maxItems int, policy EvictionPolicy) *EmbeddedStorage {
+	return &EmbeddedStorage{
+		shards: []*shard{newShard(maxBytes, maxItems, policy)},
+	}
+}
+
+func TestEmbedStorage_MaxItems(t *testing.T) {
+	s := newBoundedStorage(0, 3, NewLRUPolicy())
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		assert.NoError(t, s.Set(ctx, key, []byte("value"), 0))
+	}
+
+	// Обращение к "a" делает самой давней запись "b"
+	_, err := s.Get(ctx, "a")
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Set(ctx, "d", []byte("value"), 0))
+
+	_, err = s.Get(ctx, "b")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+	for _, key := range []string{"a", "c", "d"} {
+		_, err := s.Get(ctx, key)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, uint64(1), s.Evictions())
+	assert.Len(t, s.shards[0].items, 3)
+}
+
+func TestEmbedStorage_MaxBytes(t *testing.T) {
+	value := make([]byte, 100)
+	maxBytes := 3 * itemSize("key-0", value)
+	s := newBoundedStorage(maxBytes, 0, NewLRUPolicy())
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, s.Set(ctx, fmt.Sprintf("key-%d", i), value, 0))
+		assert.LessOrEqual(t, s.shards[0].usedBytes.Load(), maxBytes)
+	}
+
+	assert.Len(t, s.shards[0].items, 3)
+	assert.Equal(t, uint64(7), s.Evictions())
+
+	// Перезапись записи значением большего размера вытесняет другие записи
+	assert.NoError(t, s.Set(ctx, "key-9", make([]byte, 200), 0))
+	assert.Len(t, s.shards[0].items, 2)
+	assert.Equal(t, uint64(8), s.Evictions())
+
+	assert.ErrorIs(t, s.Set(ctx, "huge", make([]byte, maxBytes), 0), cache.ErrTooLarge)
+
+	// Удаление освобождает место в бюджете
+	for key := range s.shards[0].items {
+		assert.NoError(t, s.Delete(ctx, key))
+	}
+	assert.Equal(t, int64(0), s.shards[0].usedBytes.Load())
+}
+
+func TestEmbedStorage_DeleteExpired_ReleasesBudget(t *testing.T) {
+	policy := NewLRUPolicy()
+	s := newBoundedStorage(0, 2, policy)
+	ctx := context.Background()
+
+	assert.NoError(t, s.Set(ctx, "expired", []byte("value"), time.Nanosecond))
+	assert.NoError(t, s.Set(ctx, "alive", []byte("value"), 0))
+	time.Sleep(time.Millisecond)
+
+	s.deleteExpired()
+	assert.Equal(t, itemSize("alive", []byte("value")), s.shards[0].usedBytes.Load())
+
+	// Удалённая по истечении времени жизни запись больше не может быть выбрана для вытеснения
+	victim, ok := policy.Victim()
+	assert.True(t, ok)
+	assert.Equal(t, "alive", victim)
+
+	assert.NoError(t, s.Set(ctx, "new", []byte("value"), 0))
+	assert.Equal(t, uint64(0), s.Evictions())
+}
+
+func TestEmbedStorage_Stats(t *testing.T) {
+	s := newBoundedStorage(0, 2, NewLRUPolicy())
+	ctx := context.Background()
+
+	assert.NoError(t, s.Set(ctx, "expiring", []byte("value"), 10*time.Millisecond))
+	assert.NoError(t, s.Set(ctx, "first", []byte("value"), 0))
+	assert.NoError(t, s.Set(ctx, "second", []byte("value"), 0))
+
+	_, err := s.Get(ctx, "first")
+	assert.NoError(t, err)
+	_, err = s.GetAndTouch(ctx, "second", time.Hour)
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, "missing")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+
+	assert.NoError(t, s.Delete(ctx, "second"))
+	assert.NoError(t, s.Set(ctx, "third", []byte("value"), 20*time.Millisecond))
+	time.Sleep(30 * time.Millisecond)
+	s.deleteExpired()
+
+	stats, err := s.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &cache.Stats{
+		GetHits:     2,
+		GetMisses:   1,
+		Sets:        4,
+		Deletes:     1,
+		Evictions:   1,
+		Expirations: 1,
+		Items:       1,
+		Bytes:       uint64(itemSize("first", []byte("value"))),
+	}, stats)
+}
+
+func TestEmbedStorage_HealthCheck(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(10 * time.Millisecond))
+	ctx := context.Background()
+
+	assert.NoError(t, s.HealthCheck(ctx))
+
+	// Функция очистки продолжает срабатывать
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, s.HealthCheck(ctx))
+
+	s.stopCleaning <- true
+	assert.Eventually(t, func() bool {
+		return errors.Is(s.HealthCheck(ctx), ErrCleanerStopped)
+	}, time.Second, 5*time.Millisecond)
+
+	s.cleanerHeartbeat.Store(time.Now().Add(-time.Second).UnixNano())
+	assert.ErrorIs(t, s.HealthCheck(ctx), ErrCleanerStalled)
+}
@@ -0,0 +1,237 @@
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"github.com/dimuska139/cacher/internal/cache"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultWatchBuffer размер буфера событий одного подписчика по умолчанию
+const DefaultWatchBuffer = 1024
+
+// OverflowPolicy определяет, что делать, если подписчик не успевает забирать события и его буфер заполнен
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest вытеснять из буфера самые старые события. Следующее доставленное подписчику событие
+	// содержит количество пропущенных событий
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowDisconnect закрывать подписку с ошибкой cache.ErrSlowConsumer
+	OverflowDisconnect OverflowPolicy = "disconnect"
+	// DefaultOverflowPolicy политика переполнения буфера по умолчанию
+	DefaultOverflowPolicy = OverflowDropOldest
+)
+
+// ParseOverflowPolicy возвращает политику переполнения буфера по названию
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(name); policy {
+	case OverflowDropOldest, OverflowDisconnect:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown watch overflow policy: %s", name)
+}
+
+// EventBus рассылает события изменения записей подписчикам. Публикация никогда не блокируется:
+// у каждого подписчика свой буфер, а при его переполнении применяется OverflowPolicy
+type EventBus struct {
+	buffer   int
+	overflow OverflowPolicy
+
+	mx sync.Mutex
+	// Текущие подписчики. Слайс не изменяется, а заменяется целиком, поэтому публикация обходится без блокировки шины
+	subscribers atomic.Pointer[[]*subscription]
+}
+
+// NewEventBus создаёт шину событий с буфером buffer событий на подписчика (0 - DefaultWatchBuffer)
+func NewEventBus(buffer int, overflow OverflowPolicy) *EventBus {
+	if buffer <= 0 {
+		buffer = DefaultWatchBuffer
+	}
+	if overflow == "" {
+		overflow = DefaultOverflowPolicy
+	}
+
+	return &EventBus{
+		buffer:   buffer,
+		overflow: overflow,
+	}
+}
+
+// Subscribe подписывает на события изменения записей, подходящих под фильтр
+func (b *EventBus) Subscribe(filter cache.KeyFilter) cache.Subscription {
+	sub := &subscription{
+		bus:    b,
+		prefix: filter.Prefix,
+		keys:   make(map[string]struct{}, len(filter.Keys)),
+		events: make(chan cache.Event, b.buffer),
+	}
+	for _, key := range filter.Keys {
+		sub.keys[key] = struct{}{}
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	var subscribers []*subscription
+	if current := b.subscribers.Load(); current != nil {
+		subscribers = append(subscribers, *current...)
+	}
+	subscribers = append(subscribers, sub)
+	b.subscribers.Store(&subscribers)
+
+	return sub
+}
+
+// Subscribers возвращает количество подписчиков
+func (b *EventBus) Subscribers() int {
+	if current := b.subscribers.Load(); current != nil {
+		return len(*current)
+	}
+	return 0
+}
+
+// remove удаляет подписчика из шины
+func (b *EventBus) remove(sub *subscription) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	current := b.subscribers.Load()
+	if current == nil {
+		return
+	}
+
+	subscribers := make([]*subscription, 0, len(*current))
+	for _, s := range *current {
+		if s != sub {
+			subscribers = append(subscribers, s)
+		}
+	}
+	b.subscribers.Store(&subscribers)
+}
+
+// publish отправляет событие подписчикам, которым оно интересно. Вызывается под мьютексом сегмента,
+// поэтому события об изменениях одного ключа приходят в том порядке, в котором ключ изменялся
+func (b *EventBus) publish(eventType cache.EventType, key string) {
+	if b == nil {
+		return
+	}
+
+	current := b.subscribers.Load()
+	if current == nil {
+		return
+	}
+
+	for _, sub := range *current {
+		if sub.match(key) {
+			sub.deliver(cache.Event{Type: eventType, Key: key}, b.overflow)
+		}
+	}
+}
+
+// subscription подписка на события шины
+type subscription struct {
+	bus    *EventBus
+	keys   map[string]struct{}
+	prefix string
+	events chan cache.Event
+
+	mx     sync.Mutex
+	closed bool
+	err    error
+	// Количество событий, пропущенных с момента последнего доставленного события
+	dropped uint64
+}
+
+// match проверяет, что подписчику интересны изменения ключа
+func (s *subscription) match(key string) bool {
+	if s.prefix != "" && strings.HasPrefix(key, s.prefix) {
+		return true
+	}
+	_, ok := s.keys[key]
+	return ok
+}
+
+// deliver кладёт событие в буфер подписчика, не блокируясь
+func (s *subscription) deliver(event cache.Event, overflow OverflowPolicy) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	event.Dropped = s.dropped
+	select {
+	case s.events <- event:
+		s.dropped = 0
+		return
+	default:
+	}
+
+	if overflow == OverflowDisconnect {
+		s.closeLocked(cache.ErrSlowConsumer)
+		// Шина не держит свой мьютекс во время публикации, поэтому удалить подписчика можно сразу
+		s.bus.remove(s)
+		return
+	}
+
+	// Вытесняем самое старое событие вместе с учтёнными в нём пропусками
+	select {
+	case oldest := <-s.events:
+		s.dropped += oldest.Dropped + 1
+	default:
+	}
+
+	event.Dropped = s.dropped
+	select {
+	case s.events <- event:
+		s.dropped = 0
+	default:
+		// Подписчик успел забрать вытесненное место, а буфер снова заполнился другими событиями
+		s.dropped++
+	}
+}
+
+// Events возвращает канал событий
+func (s *subscription) Events() <-chan cache.Event {
+	return s.events
+}
+
+// Err возвращает причину закрытия подписки шиной
+func (s *subscription) Err() error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.err
+}
+
+// Close закрывает подписку
+func (s *subscription) Close() {
+	s.mx.Lock()
+	closed := s.closed
+	s.closeLocked(nil)
+	s.mx.Unlock()
+
+	if !closed {
+		s.bus.remove(s)
+	}
+}
+
+// closeLocked закрывает канал событий. Вызывается под мьютексом подписки
+func (s *subscription) closeLocked(err error) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.err = err
+	close(s.events)
+}
+
+// Watch подписывает на события изменения записей, подходящих под фильтр: записи (в том числе Increment
+// и Decrement), удаления и истечения времени жизни. Вытеснение записей из-за нехватки места событием не считается
+func (s *EmbeddedStorage) Watch(ctx context.Context, filter cache.KeyFilter) (cache.Subscription, error) {
+	return s.events.Subscribe(filter), nil
+}
@@ -0,0 +1,171 @@
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+// receive забирает из подписки n событий
+func receive(t *testing.T, sub cache.Subscription, n int) []cache.Event {
+	t.Helper()
+
+	events := make([]cache.Event, 0, n)
+	for len(events) < n {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				t.Fatalf("subscription closed after %d events", len(events))
+			}
+			events = append(events, event)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d events of %d", len(events), n)
+		}
+	}
+	return events
+}
+
+func TestEmbedStorage_Watch(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(10 * time.Millisecond).WithShards(4))
+	ctx := context.Background()
+
+	sub, err := s.Watch(ctx, cache.KeyFilter{Keys: []string{"counter", "single"}, Prefix: "user:"})
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	assert.NoError(t, s.Set(ctx, "user:1", []byte("value"), 0))
+	assert.NoError(t, s.Set(ctx, "other", []byte("value"), 0))
+	assert.NoError(t, s.Set(ctx, "counter", []byte("1"), 0))
+	_, err = s.Increment(ctx, "counter", 1)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Touch(ctx, "counter", time.Hour))
+	assert.NoError(t, s.Delete(ctx, "counter"))
+	// Удаление отсутствующей записи события не порождает
+	assert.NoError(t, s.Delete(ctx, "single"))
+	for _, err := range s.SetBatch(ctx, []cache.Entry{{Key: "user:2"}, {Key: "other"}}) {
+		assert.NoError(t, err)
+	}
+	for _, err := range s.DeleteBatch(ctx, []string{"user:1", "user:3"}) {
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, s.Set(ctx, "single", []byte("value"), 20*time.Millisecond))
+
+	assert.Equal(t, []cache.Event{
+		{Type: cache.EventSet, Key: "user:1"},
+		{Type: cache.EventSet, Key: "counter"},
+		{Type: cache.EventSet, Key: "counter"},
+		{Type: cache.EventDelete, Key: "counter"},
+		{Type: cache.EventSet, Key: "user:2"},
+		{Type: cache.EventDelete, Key: "user:1"},
+		{Type: cache.EventSet, Key: "single"},
+		{Type: cache.EventExpire, Key: "single"},
+	}, receive(t, sub, 8))
+}
+
+func TestEventBus_DropOldest(t *testing.T) {
+	bus := NewEventBus(2, OverflowDropOldest)
+	sub := bus.Subscribe(cache.KeyFilter{Prefix: "key"})
+	defer sub.Close()
+
+	for i := 0; i < 5; i++ {
+		bus.publish(cache.EventSet, fmt.Sprintf("key-%d", i))
+	}
+
+	// В буфере остаются два последних события, а в сумме они сообщают обо всех трёх пропущенных
+	assert.Equal(t, []cache.Event{
+		{Type: cache.EventSet, Key: "key-3", Dropped: 1},
+		{Type: cache.EventSet, Key: "key-4", Dropped: 2},
+	}, receive(t, sub, 2))
+	assert.NoError(t, sub.Err())
+
+	bus.publish(cache.EventDelete, "key-5")
+	assert.Equal(t, []cache.Event{{Type: cache.EventDelete, Key: "key-5"}}, receive(t, sub, 1))
+}
+
+func TestEventBus_Disconnect(t *testing.T) {
+	bus := NewEventBus(2, OverflowDisconnect)
+	slow := bus.Subscribe(cache.KeyFilter{Prefix: "key"})
+	other := bus.Subscribe(cache.KeyFilter{Keys: []string{"other"}})
+	defer other.Close()
+	assert.Equal(t, 2, bus.Subscribers())
+
+	for i := 0; i < 3; i++ {
+		bus.publish(cache.EventSet, fmt.Sprintf("key-%d", i))
+	}
+
+	// Подписчик получает события из буфера, после чего канал закрывается
+	receive(t, slow, 2)
+	_, ok := <-slow.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, slow.Err(), cache.ErrSlowConsumer)
+	assert.Equal(t, 1, bus.Subscribers())
+
+	// Другие подписчики продолжают получать события
+	bus.publish(cache.EventSet, "other")
+	assert.Equal(t, []cache.Event{{Type: cache.EventSet, Key: "other"}}, receive(t, other, 1))
+
+	slow.Close()
+	assert.Equal(t, 1, bus.Subscribers())
+}
+
+func TestEventBus_Close(t *testing.T) {
+	bus := NewEventBus(0, "")
+	sub := bus.Subscribe(cache.KeyFilter{Keys: []string{"key"}})
+
+	sub.Close()
+	sub.Close()
+	assert.Equal(t, 0, bus.Subscribers())
+	assert.NoError(t, sub.Err())
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+
+	// Публикация после закрытия подписки не паникует
+	bus.publish(cache.EventSet, "key")
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	for _, name := range []string{"drop-oldest", "disconnect"} {
+		policy, err := ParseOverflowPolicy(name)
+		assert.NoError(t, err)
+		assert.Equal(t, OverflowPolicy(name), policy)
+	}
+
+	_, err := ParseOverflowPolicy("block")
+	assert.Error(t, err)
+}
+
+func TestEmbedStorage_DeleteExisting_Events(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour))
+	ctx := context.Background()
+
+	sub, err := s.Watch(ctx, cache.KeyFilter{Prefix: "key"})
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	assert.NoError(t, s.Set(ctx, "key-expired", []byte("value"), time.Millisecond))
+	assert.NoError(t, s.Set(ctx, "key-live", []byte("value"), 0))
+	time.Sleep(5 * time.Millisecond)
+
+	// Удаление отсутствующей и истёкшей записей не учитывается как удаление, а истёкшая запись
+	// удаляется с событием истечения времени жизни
+	assert.ErrorIs(t, s.DeleteExisting(ctx, "key-missing"), cache.ErrNotFound)
+	assert.ErrorIs(t, s.DeleteExisting(ctx, "key-expired"), cache.ErrNotFound)
+	assert.NoError(t, s.DeleteExisting(ctx, "key-live"))
+
+	assert.Equal(t, []cache.Event{
+		{Type: cache.EventSet, Key: "key-expired"},
+		{Type: cache.EventSet, Key: "key-live"},
+		{Type: cache.EventExpire, Key: "key-expired"},
+		{Type: cache.EventDelete, Key: "key-live"},
+	}, receive(t, sub, 4))
+
+	stats, err := s.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), stats.Deletes)
+	assert.Equal(t, uint64(1), stats.Expirations)
+}
@@ -0,0 +1,67 @@
+package embedded
+
+import "fmt"
+
+const (
+	// PolicyLRU вытесняет запись, к которой дольше всего не обращались
+	PolicyLRU = "lru"
+	// PolicyLFU вытесняет запись, к которой обращались реже всего
+	PolicyLFU = "lfu"
+	// PolicyTinyLFU W-TinyLFU: новые записи попадают в небольшое LRU-окно, а в основную часть кеша
+	// допускаются, только если по оценке частоты обращений они полезнее записи, которую придётся вытеснить
+	PolicyTinyLFU = "tinylfu"
+
+	DefaultEvictionPolicy = PolicyLRU
+
+	// DefaultCapacityHint ожидаемое количество записей в кеше, если оно неизвестно
+	DefaultCapacityHint = 1 << 16
+)
+
+// EvictionPolicy решает, какую запись вытеснить из кеша при превышении бюджета.
+// Хранилище сообщает политике обо всех добавлениях, обращениях и удалениях записей.
+// Реализации не потокобезопасны: хранилище вызывает их под своим мьютексом
+type EvictionPolicy interface {
+	// Add вызывается при добавлении новой записи
+	Add(key string)
+	// Access вызывается при чтении или перезаписи существующей записи
+	Access(key string)
+	// Remove вызывается при удалении записи, в том числе после её вытеснения
+	Remove(key string)
+	// Victim возвращает ключ записи, которую нужно вытеснить. Если записей нет, возвращает false
+	Victim() (string, bool)
+}
+
+// EvictionPolicyFactory создаёт политику вытеснения для сегмента кеша, в котором ожидается
+// примерно capacityHint записей
+type EvictionPolicyFactory func(capacityHint int) EvictionPolicy
+
+// NewEvictionPolicyFactory возвращает фабрику политик вытеснения по названию политики
+func NewEvictionPolicyFactory(name string) (EvictionPolicyFactory, error) {
+	switch name {
+	case PolicyLRU:
+		return func(int) EvictionPolicy {
+			return NewLRUPolicy()
+		}, nil
+	case PolicyLFU:
+		return func(int) EvictionPolicy {
+			return NewLFUPolicy()
+		}, nil
+	case PolicyTinyLFU:
+		return func(capacityHint int) EvictionPolicy {
+			return NewTinyLFUPolicy(capacityHint)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown eviction policy: %s", name)
+}
+
+// NewEvictionPolicy создаёт политику вытеснения по названию. capacityHint - ожидаемое количество записей в кеше,
+// используется политиками, которым нужно заранее выделить память
+func NewEvictionPolicy(name string, capacityHint int) (EvictionPolicy, error) {
+	factory, err := NewEvictionPolicyFactory(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory(capacityHint), nil
+}
@@ -0,0 +1,74 @@
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEvictionPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    EvictionPolicy
+		wantErr bool
+	}{
+		{
+			name:   "lru",
+			policy: PolicyLRU,
+			want:   &LRUPolicy{},
+		},
+		{
+			name:   "lfu",
+			policy: PolicyLFU,
+			want:   &LFUPolicy{},
+		},
+		{
+			name:   "tinylfu",
+			policy: PolicyTinyLFU,
+			want:   &TinyLFUPolicy{},
+		},
+		{
+			name:    "unknown",
+			policy:  "fifo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEvictionPolicy(tt.policy, 100)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.IsType(t, tt.want, got)
+		})
+	}
+}
+
+func TestEmbedStorage_EvictionPolicies(t *testing.T) {
+	for _, name := range []string{PolicyLRU, PolicyLFU, PolicyTinyLFU} {
+		t.Run(name, func(t *testing.T) {
+			policy, err := NewEvictionPolicy(name, 10)
+			assert.NoError(t, err)
+
+			s := newBoundedStorage(0, 10, policy)
+			for i := 0; i < 1000; i++ {
+				assert.NoError(t, s.Set(context.Background(), fmt.Sprintf("key-%d", i%50), []byte("value"), 0))
+				assert.LessOrEqual(t, len(s.shards[0].items), 10)
+			}
+			assert.Greater(t, s.Evictions(), uint64(0))
+
+			// Политика отслеживает ровно те записи, которые есть в кеше
+			stored := make([]string, 0, len(s.shards[0].items))
+			for key := range s.shards[0].items {
+				stored = append(stored, key)
+			}
+			assert.ElementsMatch(t, stored, victims(policy))
+		})
+	}
+}
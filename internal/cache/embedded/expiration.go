@@ -0,0 +1,103 @@
+package embedded
+
+import "container/heap"
+
+// expiryEntry момент истечения времени жизни записи в индексе
+type expiryEntry struct {
+	key        string
+	expiration int64
+	// Позиция в куче
+	index int
+}
+
+// expiryHeap min-куча записей по моменту истечения времени жизни, реализует heap.Interface
+type expiryHeap []*expiryEntry
+
+func (h expiryHeap) Len() int {
+	return len(h)
+}
+
+func (h expiryHeap) Less(i, j int) bool {
+	return h[i].expiration < h[j].expiration
+}
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x any) {
+	entry := x.(*expiryEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
+}
+
+// expirationIndex индекс записей с ограниченным временем жизни. Позволяет удалять истёкшие записи,
+// не обходя весь кеш: стоимость очистки зависит только от количества истёкших записей.
+// Бессрочные записи в индекс не попадают. Не потокобезопасен: вызывается под мьютексом сегмента
+type expirationIndex struct {
+	heap    expiryHeap
+	entries map[string]*expiryEntry
+}
+
+// newExpirationIndex создаёт индекс времени жизни записей
+func newExpirationIndex() *expirationIndex {
+	return &expirationIndex{
+		entries: make(map[string]*expiryEntry),
+	}
+}
+
+// Set устанавливает момент истечения времени жизни записи (0 - бессрочная запись, удаляется из индекса)
+func (x *expirationIndex) Set(key string, expiration int64) {
+	if expiration == 0 {
+		x.Remove(key)
+		return
+	}
+
+	if entry, ok := x.entries[key]; ok {
+		entry.expiration = expiration
+		heap.Fix(&x.heap, entry.index)
+		return
+	}
+
+	entry := &expiryEntry{key: key, expiration: expiration}
+	heap.Push(&x.heap, entry)
+	x.entries[key] = entry
+}
+
+// Remove удаляет запись из индекса
+func (x *expirationIndex) Remove(key string) {
+	entry, ok := x.entries[key]
+	if !ok {
+		return
+	}
+
+	heap.Remove(&x.heap, entry.index)
+	delete(x.entries, key)
+}
+
+// PopExpired удаляет из индекса и возвращает ключи записей, время жизни которых истекло до момента now
+func (x *expirationIndex) PopExpired(now int64) []string {
+	var keys []string
+	for len(x.heap) > 0 && x.heap[0].expiration < now {
+		entry := heap.Pop(&x.heap).(*expiryEntry)
+		delete(x.entries, entry.key)
+		keys = append(keys, entry.key)
+	}
+	return keys
+}
+
+// Len возвращает количество записей в индексе
+func (x *expirationIndex) Len() int {
+	return len(x.heap)
+}
@@ -0,0 +1,65 @@
+package embedded
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpirationIndex(t *testing.T) {
+	index := newExpirationIndex()
+
+	index.Set("c", 30)
+	index.Set("a", 10)
+	index.Set("b", 20)
+	index.Set("d", 40)
+	index.Set("eternal", 0)
+	assert.Equal(t, 4, index.Len())
+
+	// Обновление момента истечения меняет порядок, удаление и бессрочное время жизни убирают запись из индекса
+	index.Set("c", 5)
+	index.Remove("b")
+	index.Set("d", 0)
+	assert.Equal(t, 2, index.Len())
+
+	assert.Nil(t, index.PopExpired(5))
+	assert.Equal(t, []string{"c"}, index.PopExpired(6))
+	assert.Equal(t, []string{"a"}, index.PopExpired(100))
+	assert.Nil(t, index.PopExpired(100))
+	assert.Equal(t, 0, index.Len())
+}
+
+func TestEmbedStorage_ExpirationIndex(t *testing.T) {
+	s := newTestStorage(map[string]item{})
+	sh := s.shards[0]
+	ctx := context.Background()
+
+	assert.NoError(t, s.Set(ctx, "expiring", []byte("value"), 50*time.Millisecond))
+	assert.NoError(t, s.Set(ctx, "deleted", []byte("value"), 50*time.Millisecond))
+	assert.NoError(t, s.Set(ctx, "touched", []byte("value"), 50*time.Millisecond))
+	assert.NoError(t, s.Set(ctx, "overwritten", []byte("value"), 50*time.Millisecond))
+	assert.NoError(t, s.Set(ctx, "eternal", []byte("value"), 0))
+	assert.Equal(t, 4, sh.expirations.Len())
+
+	assert.NoError(t, s.Delete(ctx, "deleted"))
+	assert.NoError(t, s.Touch(ctx, "touched", time.Hour))
+	assert.NoError(t, s.Set(ctx, "overwritten", []byte("value"), 0))
+	assert.Equal(t, 2, sh.expirations.Len())
+
+	time.Sleep(100 * time.Millisecond)
+	s.deleteExpired()
+
+	assert.Equal(t, 1, sh.expirations.Len())
+	assert.ElementsMatch(t, []string{"touched", "overwritten", "eternal"}, keysOf(sh.items))
+}
+
+// keysOf возвращает ключи записей
+func keysOf(items map[string]item) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	return keys
+}
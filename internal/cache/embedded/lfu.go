@@ -0,0 +1,100 @@
+package embedded
+
+import "container/list"
+
+// lfuBucket записи с одинаковым количеством обращений
+type lfuBucket struct {
+	freq uint64
+	// Записи от самой недавно использованной к самой давней, элементы - *lfuEntry
+	entries list.List
+}
+
+// lfuEntry запись, отслеживаемая LFUPolicy
+type lfuEntry struct {
+	key string
+	// Элемент списка корзин, в которой находится запись
+	bucket *list.Element
+	// Элемент списка записей корзины
+	element *list.Element
+}
+
+// LFUPolicy вытесняет запись, к которой обращались реже всего, а среди таких - ту, к которой
+// дольше всего не обращались. Все операции выполняются за O(1)
+type LFUPolicy struct {
+	// Корзины в порядке возрастания количества обращений, элементы - *lfuBucket
+	buckets list.List
+	entries map[string]*lfuEntry
+}
+
+// NewLFUPolicy создаёт LFUPolicy
+func NewLFUPolicy() *LFUPolicy {
+	return &LFUPolicy{
+		entries: make(map[string]*lfuEntry),
+	}
+}
+
+// Add запоминает новую запись с одним обращением
+func (p *LFUPolicy) Add(key string) {
+	if _, ok := p.entries[key]; ok {
+		p.Access(key)
+		return
+	}
+
+	front := p.buckets.Front()
+	if front == nil || front.Value.(*lfuBucket).freq != 1 {
+		front = p.buckets.PushFront(&lfuBucket{freq: 1})
+	}
+
+	entry := &lfuEntry{key: key, bucket: front}
+	entry.element = front.Value.(*lfuBucket).entries.PushFront(entry)
+	p.entries[key] = entry
+}
+
+// Access увеличивает количество обращений к записи
+func (p *LFUPolicy) Access(key string) {
+	entry, ok := p.entries[key]
+	if !ok {
+		return
+	}
+
+	current := entry.bucket
+	freq := current.Value.(*lfuBucket).freq + 1
+
+	next := current.Next()
+	if next == nil || next.Value.(*lfuBucket).freq != freq {
+		next = p.buckets.InsertAfter(&lfuBucket{freq: freq}, current)
+	}
+
+	p.detach(entry)
+	entry.bucket = next
+	entry.element = next.Value.(*lfuBucket).entries.PushFront(entry)
+}
+
+// Remove забывает запись
+func (p *LFUPolicy) Remove(key string) {
+	entry, ok := p.entries[key]
+	if !ok {
+		return
+	}
+
+	p.detach(entry)
+	delete(p.entries, key)
+}
+
+// Victim возвращает запись, к которой обращались реже всего
+func (p *LFUPolicy) Victim() (string, bool) {
+	front := p.buckets.Front()
+	if front == nil {
+		return "", false
+	}
+	return front.Value.(*lfuBucket).entries.Back().Value.(*lfuEntry).key, true
+}
+
+// detach убирает запись из её корзины и удаляет корзину, если она опустела
+func (p *LFUPolicy) detach(entry *lfuEntry) {
+	bucket := entry.bucket.Value.(*lfuBucket)
+	bucket.entries.Remove(entry.element)
+	if bucket.entries.Len() == 0 {
+		p.buckets.Remove(entry.bucket)
+	}
+}
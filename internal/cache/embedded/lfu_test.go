@@ -0,0 +1,66 @@
+package embedded
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFUPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(policy *LFUPolicy)
+		want  []string
+	}{
+		{
+			name: "least frequently used first",
+			apply: func(policy *LFUPolicy) {
+				policy.Add("a")
+				policy.Add("b")
+				policy.Add("c")
+				policy.Access("a")
+				policy.Access("a")
+				policy.Access("c")
+			},
+			want: []string{"b", "c", "a"},
+		},
+		{
+			name: "least recently used among equal frequency",
+			apply: func(policy *LFUPolicy) {
+				policy.Add("a")
+				policy.Add("b")
+				policy.Access("b")
+				policy.Access("a")
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "new key is evicted before frequent ones",
+			apply: func(policy *LFUPolicy) {
+				policy.Add("a")
+				policy.Access("a")
+				policy.Add("b")
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "removed key is not a victim",
+			apply: func(policy *LFUPolicy) {
+				policy.Add("a")
+				policy.Add("b")
+				policy.Access("b")
+				policy.Remove("a")
+				policy.Remove("unknown")
+			},
+			want: []string{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewLFUPolicy()
+			tt.apply(policy)
+			assert.Equal(t, tt.want, victims(policy))
+			assert.Equal(t, 0, policy.buckets.Len())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package embedded
+
+import "container/list"
+
+// LRUPolicy вытесняет запись, к которой дольше всего не обращались
+type LRUPolicy struct {
+	// Записи от самой недавно использованной к самой давней, элементы - ключи
+	order    list.List
+	elements map[string]*list.Element
+}
+
+// NewLRUPolicy создаёт LRUPolicy
+func NewLRUPolicy() *LRUPolicy {
+	return &LRUPolicy{
+		elements: make(map[string]*list.Element),
+	}
+}
+
+// Add запоминает новую запись как самую недавно использованную
+func (p *LRUPolicy) Add(key string) {
+	if _, ok := p.elements[key]; ok {
+		p.Access(key)
+		return
+	}
+	p.elements[key] = p.order.PushFront(key)
+}
+
+// Access отмечает запись как самую недавно использованную
+func (p *LRUPolicy) Access(key string) {
+	if element, ok := p.elements[key]; ok {
+		p.order.MoveToFront(element)
+	}
+}
+
+// Remove забывает запись
+func (p *LRUPolicy) Remove(key string) {
+	if element, ok := p.elements[key]; ok {
+		p.order.Remove(element)
+		delete(p.elements, key)
+	}
+}
+
+// Victim возвращает запись, к которой дольше всего не обращались
+func (p *LRUPolicy) Victim() (string, bool) {
+	back := p.order.Back()
+	if back == nil {
+		return "", false
+	}
+	return back.Value.(string), true
+}
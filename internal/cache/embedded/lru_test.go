@@ -0,0 +1,71 @@
+package embedded
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// victims вытесняет из политики все записи и возвращает их ключи в порядке вытеснения
+func victims(policy EvictionPolicy) []string {
+	var keys []string
+	for {
+		key, ok := policy.Victim()
+		if !ok {
+			return keys
+		}
+		policy.Remove(key)
+		keys = append(keys, key)
+	}
+}
+
+func TestLRUPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(policy *LRUPolicy)
+		want  []string
+	}{
+		{
+			name: "insertion order",
+			apply: func(policy *LRUPolicy) {
+				policy.Add("a")
+				policy.Add("b")
+				policy.Add("c")
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "access moves to front",
+			apply: func(policy *LRUPolicy) {
+				policy.Add("a")
+				policy.Add("b")
+				policy.Add("c")
+				policy.Access("a")
+			},
+			want: []string{"b", "c", "a"},
+		},
+		{
+			name: "removed key is not a victim",
+			apply: func(policy *LRUPolicy) {
+				policy.Add("a")
+				policy.Add("b")
+				policy.Remove("a")
+				policy.Access("unknown")
+			},
+			want: []string{"b"},
+		},
+		{
+			name: "empty",
+			apply: func(policy *LRUPolicy) {
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewLRUPolicy()
+			tt.apply(policy)
+			assert.Equal(t, tt.want, victims(policy))
+		})
+	}
+}
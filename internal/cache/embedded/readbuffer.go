@@ -0,0 +1,50 @@
+package embedded
+
+import (
+	"sync/atomic"
+)
+
+// readBufferSize количество обращений к записям, которое накапливается в сегменте до передачи политике вытеснения
+const readBufferSize = 64
+
+// readBuffer буфер обращений к записям при чтении. Читатели добавляют в него ключи под блокировкой для чтения,
+// а политика вытеснения получает их пакетом под эксклюзивной блокировкой (как буферы чтения в W-TinyLFU).
+// Если буфер заполнен, обращения отбрасываются: чтение никогда не ждёт, а политике вытеснения для выбора
+// жертвы достаточно большинства обращений
+type readBuffer struct {
+	// Количество занятых ячеек. Может превышать размер буфера, если обращения отбрасываются
+	reserved atomic.Uint64
+	slots    [readBufferSize]atomic.Pointer[string]
+}
+
+// record добавляет ключ в буфер. Возвращает false, если буфер заполнен и обращение отброшено
+func (b *readBuffer) record(key string) bool {
+	n := b.reserved.Add(1) - 1
+	if n >= readBufferSize {
+		return false
+	}
+
+	b.slots[n].Store(&key)
+	return true
+}
+
+// full проверяет, заполнен ли буфер
+func (b *readBuffer) full() bool {
+	return b.reserved.Load() >= readBufferSize
+}
+
+// drain передаёт накопленные ключи в fn и очищает буфер. Вызывается под эксклюзивной блокировкой сегмента.
+// Ключ, который читатель ещё не успел записать в занятую ячейку, будет передан при следующем вызове
+func (b *readBuffer) drain(fn func(key string)) {
+	n := b.reserved.Load()
+	if n > readBufferSize {
+		n = readBufferSize
+	}
+
+	for i := uint64(0); i < n; i++ {
+		if key := b.slots[i].Swap(nil); key != nil {
+			fn(*key)
+		}
+	}
+	b.reserved.Store(0)
+}
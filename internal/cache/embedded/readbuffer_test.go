@@ -0,0 +1,99 @@
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadBuffer(t *testing.T) {
+	var b readBuffer
+	for i := 0; i < readBufferSize; i++ {
+		assert.True(t, b.record(fmt.Sprintf("key-%d", i)))
+	}
+	assert.True(t, b.full())
+
+	// Обращения сверх размера буфера отбрасываются
+	assert.False(t, b.record("dropped"))
+
+	var drained []string
+	b.drain(func(key string) {
+		drained = append(drained, key)
+	})
+	assert.Len(t, drained, readBufferSize)
+	assert.Equal(t, "key-0", drained[0])
+	assert.NotContains(t, drained, "dropped")
+	assert.False(t, b.full())
+
+	drained = nil
+	assert.True(t, b.record("next"))
+	b.drain(func(key string) {
+		drained = append(drained, key)
+	})
+	assert.Equal(t, []string{"next"}, drained)
+}
+
+func TestEmbedStorage_GetKeepsReadLock(t *testing.T) {
+	s := newBoundedStorage(0, 10, NewLRUPolicy())
+	ctx := context.Background()
+	assert.NoError(t, s.Set(ctx, "a", []byte("value"), 0))
+
+	// Пока сегмент заблокирован для чтения, чтение с политикой вытеснения не должно ждать
+	s.shards[0].mx.RLock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := s.Get(ctx, "a")
+		assert.NoError(t, err)
+		s.GetBatch(ctx, []string{"a"})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Get waits for exclusive lock")
+	}
+	s.shards[0].mx.RUnlock()
+}
+
+func TestEmbedStorage_ConcurrentReadsWithPolicy(t *testing.T) {
+	for _, name := range []string{PolicyLRU, PolicyLFU, PolicyTinyLFU} {
+		t.Run(name, func(t *testing.T) {
+			policy, err := NewEvictionPolicy(name, 10)
+			assert.NoError(t, err)
+
+			s := newBoundedStorage(0, 10, policy)
+			ctx := context.Background()
+
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 1000; i++ {
+						key := fmt.Sprintf("key-%d", (g+i)%20)
+						if i%10 == 0 {
+							assert.NoError(t, s.Set(ctx, key, []byte("value"), 0))
+						} else {
+							_, _ = s.Get(ctx, key)
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			assert.LessOrEqual(t, len(s.shards[0].items), 10)
+
+			// Политика отслеживает ровно те записи, которые есть в кеше
+			stored := make([]string, 0, len(s.shards[0].items))
+			for key := range s.shards[0].items {
+				stored = append(stored, key)
+			}
+			assert.ElementsMatch(t, stored, victims(policy))
+		})
+	}
+}
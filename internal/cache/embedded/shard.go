@@ -0,0 +1,174 @@
+package embedded
+
+import (
+	"github.com/dimuska139/cacher/internal/cache"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// shard сегмент кеша: часть ключей со своим мьютексом, бюджетом и политикой вытеснения.
+// Операции с разными сегментами не блокируют друг друга
+type shard struct {
+	items map[string]item
+	// Индекс времени жизни записей для очистки истёкших записей без обхода всего сегмента
+	expirations *expirationIndex
+	mx          sync.RWMutex
+
+	maxBytes int64
+	maxItems int
+	// Политика вытеснения (nil, если размер кеша не ограничен)
+	policy EvictionPolicy
+	// Обращения к записям, ещё не переданные политике вытеснения
+	reads readBuffer
+
+	// Суммарный размер и количество записей. Изменяются под мьютексом, но читаются без него
+	usedBytes  atomic.Int64
+	itemsCount atomic.Int64
+	// Количество вытесненных записей
+	evictions atomic.Uint64
+	// Количество записей, удалённых по истечении времени жизни
+	expired atomic.Uint64
+}
+
+// newShard создаёт сегмент кеша
+func newShard(maxBytes int64, maxItems int, policy EvictionPolicy) *shard {
+	return &shard{
+		items:       make(map[string]item),
+		expirations: newExpirationIndex(),
+		maxBytes:    maxBytes,
+		maxItems:    maxItems,
+		policy:      policy,
+	}
+}
+
+// lockForRead блокирует сегмент для чтения. Обращения к записям накапливаются в буфере, поэтому политика
+// вытеснения не требует эксклюзивной блокировки при чтении
+func (s *shard) lockForRead() (unlock func()) {
+	s.mx.RLock()
+	return s.unlockRead
+}
+
+// unlockRead снимает блокировку для чтения. Если буфер обращений заполнен и сегмент никем не заблокирован,
+// обращения передаются политике вытеснения
+func (s *shard) unlockRead() {
+	s.mx.RUnlock()
+	if s.policy != nil && s.reads.full() && s.mx.TryLock() {
+		s.drainReads()
+		s.mx.Unlock()
+	}
+}
+
+// drainReads передаёт политике вытеснения накопленные обращения к записям. Вызывается под мьютексом
+func (s *shard) drainReads() {
+	if s.policy != nil {
+		s.reads.drain(s.policy.Access)
+	}
+}
+
+// get возвращает актуальную запись. Вызывается под мьютексом
+func (s *shard) get(key string) (item, bool) {
+	i, found := s.items[key]
+	if !found || i.IsExpired() {
+		return item{}, false
+	}
+	return i, true
+}
+
+// storeItem записывает элемент в сегмент, учитывая его в бюджете, и при необходимости вытесняет другие записи.
+// Вызывается под мьютексом
+func (s *shard) storeItem(key string, i item) error {
+	size := itemSize(key, i.Value)
+	if s.maxBytes > 0 && size > s.maxBytes {
+		return cache.ErrTooLarge
+	}
+
+	s.drainReads()
+	if old, found := s.items[key]; found {
+		s.usedBytes.Add(-itemSize(key, old.Value))
+		if s.policy != nil {
+			s.policy.Access(key)
+		}
+	} else {
+		s.itemsCount.Add(1)
+		if s.policy != nil {
+			s.policy.Add(key)
+		}
+	}
+
+	s.items[key] = i
+	s.expirations.Set(key, i.Expiration)
+	s.usedBytes.Add(size)
+
+	s.evict()
+	return nil
+}
+
+// removeItem удаляет элемент из сегмента. Вызывается под мьютексом
+func (s *shard) removeItem(key string) {
+	i, found := s.items[key]
+	if !found {
+		return
+	}
+
+	delete(s.items, key)
+	s.expirations.Remove(key)
+	s.usedBytes.Add(-itemSize(key, i.Value))
+	s.itemsCount.Add(-1)
+	if s.policy != nil {
+		s.policy.Remove(key)
+	}
+}
+
+// touchItem устанавливает новый момент истечения времени жизни существующей записи. Вызывается под мьютексом
+func (s *shard) touchItem(key string, i item, expiration int64) item {
+	i.Expiration = expiration
+	s.items[key] = i
+	s.expirations.Set(key, expiration)
+	if s.policy != nil {
+		s.drainReads()
+		s.policy.Access(key)
+	}
+	return i
+}
+
+// accessItem учитывает обращение к записи в буфере обращений. Политика вытеснения получит его до выбора
+// следующей жертвы. Вызывается под блокировкой для чтения
+func (s *shard) accessItem(key string) {
+	if s.policy != nil {
+		s.reads.record(key)
+	}
+}
+
+// overBudget проверяет, превышен ли бюджет сегмента. Вызывается под мьютексом
+func (s *shard) overBudget() bool {
+	return (s.maxBytes > 0 && s.usedBytes.Load() > s.maxBytes) ||
+		(s.maxItems > 0 && len(s.items) > s.maxItems)
+}
+
+// evict вытесняет записи, пока бюджет сегмента превышен. Вызывается под мьютексом
+func (s *shard) evict() {
+	for s.policy != nil && s.overBudget() {
+		key, ok := s.policy.Victim()
+		if !ok {
+			return
+		}
+
+		s.removeItem(key)
+		s.evictions.Add(1)
+	}
+}
+
+// deleteExpired удаляет из сегмента записи с истёкшим временем жизни. Истёкшие записи берутся
+// из индекса времени жизни, поэтому стоимость не зависит от общего количества записей.
+// Для каждой удалённой записи под мьютексом вызывается onExpire
+func (s *shard) deleteExpired(onExpire func(key string)) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.drainReads()
+	for _, key := range s.expirations.PopExpired(time.Now().UnixNano()) {
+		s.removeItem(key)
+		s.expired.Add(1)
+		onExpire(key)
+	}
+}
@@ -0,0 +1,96 @@
+package embedded
+
+import "hash/maphash"
+
+const (
+	// sketchDepth количество строк count-min sketch (независимых хеш-функций)
+	sketchDepth = 4
+	// sketchMaxCount предел 4-битного счётчика
+	sketchMaxCount = 15
+	// sketchMinWidth минимальная ширина строки count-min sketch
+	sketchMinWidth = 64
+	// sketchCountersPerKey количество счётчиков в строке на один отслеживаемый ключ. Чем больше счётчиков,
+	// тем реже коллизии завышают оценку частоты редких ключей
+	sketchCountersPerKey = 8
+	// sketchResetFactor во сколько раз количество обращений должно превысить количество отслеживаемых ключей,
+	// чтобы все счётчики уменьшились вдвое
+	sketchResetFactor = 10
+)
+
+// countMinSketch приблизительно оценивает частоту обращений к ключам в небольшом объёме памяти.
+// Оценка может быть завышена из-за коллизий, но не занижена. Чтобы частоты отражали недавнее
+// поведение, после определённого количества обращений все счётчики уменьшаются вдвое
+type countMinSketch struct {
+	seed     maphash.Seed
+	counters [sketchDepth][]uint8
+	mask     uint64
+	// Количество обращений с последнего уменьшения счётчиков
+	additions int
+	resetAt   int
+}
+
+// newCountMinSketch создаёт count-min sketch для отслеживания примерно keys ключей
+func newCountMinSketch(keys int) *countMinSketch {
+	size := sketchMinWidth
+	for size < keys*sketchCountersPerKey {
+		size <<= 1
+	}
+
+	s := &countMinSketch{
+		seed:    maphash.MakeSeed(),
+		mask:    uint64(size - 1),
+		resetAt: keys * sketchResetFactor,
+	}
+	for i := range s.counters {
+		s.counters[i] = make([]uint8, size)
+	}
+	return s
+}
+
+// indexes возвращает позиции счётчиков ключа в каждой строке
+func (s *countMinSketch) indexes(key string) [sketchDepth]uint64 {
+	hash := maphash.String(s.seed, key)
+	// Из одного 64-битного хеша получаем sketchDepth хешей (double hashing)
+	h1, h2 := hash, hash>>32|hash<<32
+
+	var indexes [sketchDepth]uint64
+	for i := range indexes {
+		indexes[i] = (h1 + uint64(i)*h2) & s.mask
+	}
+	return indexes
+}
+
+// Increment учитывает обращение к ключу
+func (s *countMinSketch) Increment(key string) {
+	for i, index := range s.indexes(key) {
+		if s.counters[i][index] < sketchMaxCount {
+			s.counters[i][index]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+// Estimate возвращает оценку количества обращений к ключу
+func (s *countMinSketch) Estimate(key string) uint8 {
+	estimate := uint8(sketchMaxCount)
+	for i, index := range s.indexes(key) {
+		if s.counters[i][index] < estimate {
+			estimate = s.counters[i][index]
+		}
+	}
+	return estimate
+}
+
+// reset уменьшает все счётчики вдвое
+func (s *countMinSketch) reset() {
+	for i := range s.counters {
+		for j := range s.counters[i] {
+			s.counters[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
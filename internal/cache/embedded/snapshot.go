@@ -0,0 +1,340 @@
+package embedded
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Формат снимка:
+//
+//	заголовок:  "CACHESNP" | версия (uint32, big endian)
+//	запись:     1 | длина ключа (uvarint) | ключ | длина значения (uvarint) | значение | момент истечения (int64, big endian)
+//	с флагами:  2 | поля записи | флаги (uint32, big endian)
+//	окончание:  0 | количество записей (uvarint) | CRC-32C всех предыдущих байт (uint32, big endian)
+//
+// Момент истечения хранится как абсолютное время в наносекундах Unix (0 - бессрочная запись),
+// поэтому время, пока приложение было остановлено, тоже учитывается. Записи без флагов кодируются тегом 1,
+// поэтому снимки кеша без флагов не меняются
+const (
+	snapshotMagic = "CACHESNP"
+	// SnapshotVersion версия формата снимка
+	SnapshotVersion = 1
+
+	snapshotRecordTag      = 1
+	snapshotFlagsRecordTag = 2
+	snapshotEndTag         = 0
+	// snapshotMaxFieldSize ограничение длины ключа и значения, защищающее от огромных аллокаций при чтении повреждённого файла
+	snapshotMaxFieldSize = 1 << 30
+)
+
+// ErrInvalidSnapshot снимок повреждён или записан в неподдерживаемом формате
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+var snapshotCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// snapshotEntry запись кеша в снимке
+type snapshotEntry struct {
+	key        string
+	value      []byte
+	expiration int64
+	flags      uint32
+}
+
+// WriteSnapshot записывает снимок актуальных записей кеша. Сегменты копируются по очереди под блокировкой
+// на чтение, а кодирование и запись выполняются уже без блокировки, поэтому операции с кешем почти не ждут
+func (s *EmbeddedStorage) WriteSnapshot(w io.Writer) error {
+	checksum := crc32.New(snapshotCRCTable)
+	bw := bufio.NewWriter(io.MultiWriter(w, checksum))
+
+	header := make([]byte, 0, len(snapshotMagic)+4)
+	header = append(header, snapshotMagic...)
+	header = binary.BigEndian.AppendUint32(header, SnapshotVersion)
+	if _, err := bw.Write(header); err != nil {
+		return fmt.Errorf("can't write snapshot header: %w", err)
+	}
+
+	var (
+		count uint64
+		buf   []byte
+	)
+	for _, sh := range s.shards {
+		for _, entry := range sh.snapshotEntries() {
+			tag := byte(snapshotRecordTag)
+			if entry.flags != 0 {
+				tag = snapshotFlagsRecordTag
+			}
+			buf = append(buf[:0], tag)
+			buf = binary.AppendUvarint(buf, uint64(len(entry.key)))
+			buf = append(buf, entry.key...)
+			buf = binary.AppendUvarint(buf, uint64(len(entry.value)))
+			buf = append(buf, entry.value...)
+			buf = binary.BigEndian.AppendUint64(buf, uint64(entry.expiration))
+			if entry.flags != 0 {
+				buf = binary.BigEndian.AppendUint32(buf, entry.flags)
+			}
+			if _, err := bw.Write(buf); err != nil {
+				return fmt.Errorf("can't write snapshot entry: %w", err)
+			}
+			count++
+		}
+	}
+
+	buf = append(buf[:0], snapshotEndTag)
+	buf = binary.AppendUvarint(buf, count)
+	if _, err := bw.Write(buf); err != nil {
+		return fmt.Errorf("can't write snapshot trailer: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("can't write snapshot trailer: %w", err)
+	}
+
+	// Контрольная сумма не входит в саму себя, поэтому пишется мимо checksum
+	if _, err := w.Write(binary.BigEndian.AppendUint32(nil, checksum.Sum32())); err != nil {
+		return fmt.Errorf("can't write snapshot checksum: %w", err)
+	}
+
+	return nil
+}
+
+// snapshotEntries копирует актуальные записи сегмента для снимка
+func (s *shard) snapshotEntries() []snapshotEntry {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	entries := make([]snapshotEntry, 0, len(s.items))
+	for key, i := range s.items {
+		if i.IsExpired() {
+			continue
+		}
+		// Значения записей не изменяются после сохранения в кеш, поэтому копировать их не нужно
+		entries = append(entries, snapshotEntry{
+			key:        key,
+			value:      i.Value,
+			expiration: i.Expiration,
+			flags:      i.Flags,
+		})
+	}
+	return entries
+}
+
+// LoadSnapshot загружает записи из снимка и возвращает их количество. Записи с истёкшим временем жизни
+// пропускаются. Снимок применяется, только если он целиком прочитан и контрольная сумма совпала
+func (s *EmbeddedStorage) LoadSnapshot(r io.Reader) (int, error) {
+	checksum := crc32.New(snapshotCRCTable)
+	br := bufio.NewReader(r)
+	entries, err := readSnapshotEntries(&checksumReader{r: br, hash: checksum})
+	if err != nil {
+		return 0, err
+	}
+
+	sum := make([]byte, 4)
+	if _, err := io.ReadFull(br, sum); err != nil {
+		return 0, fmt.Errorf("%w: can't read checksum: %w", ErrInvalidSnapshot, err)
+	}
+	if binary.BigEndian.Uint32(sum) != checksum.Sum32() {
+		return 0, fmt.Errorf("%w: checksum mismatch", ErrInvalidSnapshot)
+	}
+
+	now := time.Now().UnixNano()
+	loaded := 0
+	for _, entry := range entries {
+		if entry.expiration > 0 && entry.expiration < now {
+			continue
+		}
+
+		sh := s.shardFor(entry.key)
+		sh.mx.Lock()
+		err := sh.storeItem(entry.key, item{
+			Value:      entry.value,
+			Expiration: entry.expiration,
+			CasID:      s.nextCasID(),
+			Flags:      entry.flags,
+		})
+		sh.mx.Unlock()
+		// Запись, которая не помещается в бюджет кеша (например, после его уменьшения), пропускается
+		if err == nil {
+			loaded++
+		}
+	}
+
+	return loaded, nil
+}
+
+// checksumReader считает контрольную сумму прочитанных данных
+type checksumReader struct {
+	r    *bufio.Reader
+	hash hash.Hash32
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+func (r *checksumReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.hash.Write([]byte{b})
+	}
+	return b, err
+}
+
+// readSnapshotEntries читает заголовок и записи снимка до окончания
+func readSnapshotEntries(r *checksumReader) ([]snapshotEntry, error) {
+	header := make([]byte, len(snapshotMagic)+4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: can't read header: %w", ErrInvalidSnapshot, err)
+	}
+	if !bytes.Equal(header[:len(snapshotMagic)], []byte(snapshotMagic)) {
+		return nil, fmt.Errorf("%w: unknown file format", ErrInvalidSnapshot)
+	}
+	if version := binary.BigEndian.Uint32(header[len(snapshotMagic):]); version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, version)
+	}
+
+	var entries []snapshotEntry
+	for {
+		tag, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: can't read entry: %w", ErrInvalidSnapshot, err)
+		}
+
+		switch tag {
+		case snapshotEndTag:
+			count, err := binary.ReadUvarint(r)
+			if err != nil {
+				return nil, fmt.Errorf("%w: can't read entries count: %w", ErrInvalidSnapshot, err)
+			}
+			if count != uint64(len(entries)) {
+				return nil, fmt.Errorf("%w: expected %d entries, got %d", ErrInvalidSnapshot, count, len(entries))
+			}
+			return entries, nil
+
+		case snapshotRecordTag, snapshotFlagsRecordTag:
+			entry, err := readSnapshotEntry(r, tag == snapshotFlagsRecordTag)
+			if err != nil {
+				return nil, fmt.Errorf("%w: can't read entry: %w", ErrInvalidSnapshot, err)
+			}
+			entries = append(entries, entry)
+
+		default:
+			return nil, fmt.Errorf("%w: unexpected tag %d", ErrInvalidSnapshot, tag)
+		}
+	}
+}
+
+// readSnapshotEntry читает одну запись снимка после её тега. Флаги читаются, если запись записана с флагами
+func readSnapshotEntry(r *checksumReader, withFlags bool) (snapshotEntry, error) {
+	key, err := readSnapshotField(r)
+	if err != nil {
+		return snapshotEntry{}, err
+	}
+
+	value, err := readSnapshotField(r)
+	if err != nil {
+		return snapshotEntry{}, err
+	}
+
+	expiration := make([]byte, 8)
+	if _, err := io.ReadFull(r, expiration); err != nil {
+		return snapshotEntry{}, err
+	}
+
+	entry := snapshotEntry{
+		key:        string(key),
+		value:      value,
+		expiration: int64(binary.BigEndian.Uint64(expiration)),
+	}
+	if withFlags {
+		flags := make([]byte, 4)
+		if _, err := io.ReadFull(r, flags); err != nil {
+			return snapshotEntry{}, err
+		}
+		entry.flags = binary.BigEndian.Uint32(flags)
+	}
+
+	return entry, nil
+}
+
+// readSnapshotField читает поле переменной длины
+func readSnapshotField(r *checksumReader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > snapshotMaxFieldSize {
+		return nil, fmt.Errorf("field is too large: %d bytes", size)
+	}
+
+	field := make([]byte, size)
+	if _, err := io.ReadFull(r, field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// SaveSnapshot атомарно сохраняет снимок кеша в файл: снимок пишется во временный файл в том же каталоге,
+// который затем переименовывается. Поэтому при сбое во время записи предыдущий снимок остаётся целым
+func (s *EmbeddedStorage) SaveSnapshot(path string) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("can't create temporary snapshot file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := s.WriteSnapshot(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("can't sync snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("can't close snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("can't rename snapshot file: %w", err)
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir сбрасывает на диск изменения каталога, чтобы переименование файла пережило сбой питания
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("can't open snapshot directory: %w", err)
+	}
+	defer d.Close()
+
+	// Не все платформы позволяют синхронизировать каталог, это не критично
+	_ = d.Sync()
+	return nil
+}
+
+// LoadSnapshotFile загружает записи из файла снимка и возвращает их количество.
+// Если файла нет, возвращается ошибка, для которой errors.Is(err, fs.ErrNotExist) истинно
+func (s *EmbeddedStorage) LoadSnapshotFile(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("can't open snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	return s.LoadSnapshot(f)
+}
@@ -0,0 +1,183 @@
+package embedded
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSnapshotSource создаёт кеш с бессрочной, истекающей и уже истёкшей записями
+func newSnapshotSource(t *testing.T) *EmbeddedStorage {
+	s := NewEmbeddedStorage(NewConfig(time.Hour).WithShards(4))
+	ctx := context.Background()
+
+	assert.NoError(t, s.Set(ctx, "eternal", []byte("value-1"), 0))
+	assert.NoError(t, s.SetWithFlags(ctx, "expiring", []byte("value-2"), 42, time.Hour))
+	assert.NoError(t, s.Set(ctx, "empty", []byte{}, 0))
+	assert.NoError(t, s.Set(ctx, "expired", []byte("value-3"), time.Nanosecond))
+	time.Sleep(time.Millisecond)
+
+	return s
+}
+
+func TestEmbedStorage_Snapshot(t *testing.T) {
+	src := newSnapshotSource(t)
+
+	var buf bytes.Buffer
+	assert.NoError(t, src.WriteSnapshot(&buf))
+
+	dst := NewEmbeddedStorage(NewConfig(time.Hour).WithShards(2))
+	loaded, err := dst.LoadSnapshot(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, loaded)
+
+	ctx := context.Background()
+	eternal, err := dst.Get(ctx, "eternal")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value-1"), eternal.Value)
+	assert.Zero(t, eternal.TTL)
+	assert.Zero(t, eternal.Flags)
+
+	// Момент истечения сохраняется абсолютным
+	expiring, err := dst.Get(ctx, "expiring")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value-2"), expiring.Value)
+	assert.InDelta(t, time.Hour, expiring.TTL, float64(time.Second))
+	assert.Equal(t, uint32(42), expiring.Flags)
+
+	empty, err := dst.Get(ctx, "empty")
+	assert.NoError(t, err)
+	assert.Empty(t, empty.Value)
+
+	_, err = dst.Get(ctx, "expired")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+}
+
+func TestEmbedStorage_LoadSnapshot_SkipsExpired(t *testing.T) {
+	s := NewEmbeddedStorage(NewConfig(time.Hour))
+	assert.NoError(t, s.Set(context.Background(), "short", []byte("value"), 10*time.Millisecond))
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.WriteSnapshot(&buf))
+	time.Sleep(20 * time.Millisecond)
+
+	loaded, err := NewEmbeddedStorage(NewConfig(time.Hour)).LoadSnapshot(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, loaded)
+}
+
+func TestEmbedStorage_LoadSnapshot_Invalid(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, newSnapshotSource(t).WriteSnapshot(&buf))
+	valid := buf.Bytes()
+
+	corrupted := bytes.Clone(valid)
+	corrupted[len(snapshotMagic)+10] ^= 0xff
+
+	unsupported := bytes.Clone(valid)
+	binary.BigEndian.PutUint32(unsupported[len(snapshotMagic):], SnapshotVersion+1)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "empty",
+			data: nil,
+		},
+		{
+			name: "unknown format",
+			data: []byte("not a snapshot at all"),
+		},
+		{
+			name: "unsupported version",
+			data: unsupported,
+		},
+		{
+			name: "corrupted",
+			data: corrupted,
+		},
+		{
+			name: "truncated",
+			data: valid[:len(valid)-10],
+		},
+		{
+			name: "without checksum",
+			data: valid[:len(valid)-4],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewEmbeddedStorage(NewConfig(time.Hour))
+			loaded, err := s.LoadSnapshot(bytes.NewReader(tt.data))
+			assert.ErrorIs(t, err, ErrInvalidSnapshot)
+			assert.Equal(t, 0, loaded)
+
+			// Повреждённый снимок не применяется даже частично
+			_, err = s.Get(context.Background(), "eternal")
+			assert.ErrorIs(t, err, cache.ErrNotFound)
+		})
+	}
+}
+
+func TestEmbedStorage_SaveSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache.snapshot")
+
+	_, err := NewEmbeddedStorage(NewConfig(time.Hour)).LoadSnapshotFile(path)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	src := newSnapshotSource(t)
+	assert.NoError(t, src.SaveSnapshot(path))
+
+	// Повторное сохранение заменяет файл целиком и не оставляет временных файлов
+	assert.NoError(t, src.Set(context.Background(), "new", []byte("value"), 0))
+	assert.NoError(t, src.SaveSnapshot(path))
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	loaded, err := NewEmbeddedStorage(NewConfig(time.Hour)).LoadSnapshotFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, loaded)
+
+	// Если сохранить снимок не удалось, предыдущий снимок остаётся целым
+	assert.Error(t, src.SaveSnapshot(filepath.Join(dir, "missing", "cache.snapshot")))
+	loaded, err = NewEmbeddedStorage(NewConfig(time.Hour)).LoadSnapshotFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, loaded)
+}
+
+func TestSnapshotter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	s := NewEmbeddedStorage(NewConfig(time.Hour))
+	ctx := context.Background()
+	assert.NoError(t, s.Set(ctx, "periodic", []byte("value"), 0))
+
+	snapshotter := NewSnapshotter(s, path, 10*time.Millisecond, nil)
+	snapshotter.Start()
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	// При остановке сохраняется последний снимок
+	assert.NoError(t, s.Set(ctx, "final", []byte("value"), 0))
+	assert.NoError(t, snapshotter.Stop())
+	assert.NoError(t, snapshotter.Stop())
+
+	restored := NewEmbeddedStorage(NewConfig(time.Hour))
+	loaded, err := restored.LoadSnapshotFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, loaded)
+}
@@ -0,0 +1,80 @@
+package embedded
+
+import (
+	"sync"
+	"time"
+)
+
+// Logger интерфейс для логгера
+type Logger interface {
+	Error(msg string, args ...interface{})
+}
+
+// Snapshotter периодически сохраняет снимок кеша в файл и сохраняет последний снимок при остановке
+type Snapshotter struct {
+	storage  *EmbeddedStorage
+	path     string
+	interval time.Duration
+	logger   Logger
+
+	stop     chan struct{}
+	done     chan struct{}
+	started  bool
+	stopOnce sync.Once
+}
+
+// NewSnapshotter создаёт Snapshotter, который сохраняет снимок storage в файл path каждые interval
+// (0 - только при остановке)
+func NewSnapshotter(storage *EmbeddedStorage, path string, interval time.Duration, logger Logger) *Snapshotter {
+	return &Snapshotter{
+		storage:  storage,
+		path:     path,
+		interval: interval,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start запускает периодическое сохранение снимков
+func (s *Snapshotter) Start() {
+	s.started = true
+	go s.run()
+}
+
+// run сохраняет снимки, пока Snapshotter не остановлен
+func (s *Snapshotter) run() {
+	defer close(s.done)
+
+	if s.interval <= 0 {
+		<-s.stop
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.storage.SaveSnapshot(s.path); err != nil {
+				s.logger.Error("Can't save snapshot", "err", err, "path", s.path)
+			}
+		}
+	}
+}
+
+// Stop останавливает периодическое сохранение, дожидается завершения текущего сохранения
+// и сохраняет последний снимок
+func (s *Snapshotter) Stop() error {
+	var err error
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		if s.started {
+			<-s.done
+		}
+		err = s.storage.SaveSnapshot(s.path)
+	})
+	return err
+}
@@ -0,0 +1,173 @@
+package embedded
+
+import "container/list"
+
+const (
+	// tinyLFUWindowPercent доля записей (в процентах), которая приходится на LRU-окно
+	tinyLFUWindowPercent = 1
+	// tinyLFUProtectedPercent доля основной части кеша (в процентах), которая приходится на защищённый сегмент
+	tinyLFUProtectedPercent = 80
+)
+
+// tinyLFUSegment сегмент, в котором находится запись
+type tinyLFUSegment int
+
+const (
+	segmentWindow tinyLFUSegment = iota
+	segmentProbation
+	segmentProtected
+)
+
+// tinyLFUEntry запись, отслеживаемая TinyLFUPolicy
+type tinyLFUEntry struct {
+	key     string
+	segment tinyLFUSegment
+	element *list.Element
+}
+
+// TinyLFUPolicy реализует W-TinyLFU. Новые записи попадают в небольшое LRU-окно. Когда окно переполнено,
+// его самая давняя запись соревнуется с кандидатом на вытеснение из основной части кеша (SLRU из испытательного
+// и защищённого сегментов): остаётся та, к которой по оценке count-min sketch обращались чаще.
+// Так редкие записи, например при однократном сканировании, не вытесняют из кеша часто используемые
+type TinyLFUPolicy struct {
+	sketch *countMinSketch
+	// Сегменты от самой недавно использованной записи к самой давней, элементы - *tinyLFUEntry
+	window    list.List
+	probation list.List
+	protected list.List
+	entries   map[string]*tinyLFUEntry
+}
+
+// NewTinyLFUPolicy создаёт TinyLFUPolicy. capacityHint - ожидаемое количество записей в кеше,
+// от него зависит размер count-min sketch
+func NewTinyLFUPolicy(capacityHint int) *TinyLFUPolicy {
+	if capacityHint <= 0 {
+		capacityHint = DefaultCapacityHint
+	}
+
+	return &TinyLFUPolicy{
+		sketch:  newCountMinSketch(capacityHint),
+		entries: make(map[string]*tinyLFUEntry),
+	}
+}
+
+// Add помещает новую запись в LRU-окно
+func (p *TinyLFUPolicy) Add(key string) {
+	if _, ok := p.entries[key]; ok {
+		p.Access(key)
+		return
+	}
+
+	p.sketch.Increment(key)
+
+	entry := &tinyLFUEntry{key: key, segment: segmentWindow}
+	entry.element = p.window.PushFront(entry)
+	p.entries[key] = entry
+}
+
+// Access учитывает обращение к записи. Запись из испытательного сегмента переходит в защищённый
+func (p *TinyLFUPolicy) Access(key string) {
+	p.sketch.Increment(key)
+
+	entry, ok := p.entries[key]
+	if !ok {
+		return
+	}
+
+	switch entry.segment {
+	case segmentWindow:
+		p.window.MoveToFront(entry.element)
+	case segmentProtected:
+		p.protected.MoveToFront(entry.element)
+	case segmentProbation:
+		p.probation.Remove(entry.element)
+		entry.segment = segmentProtected
+		entry.element = p.protected.PushFront(entry)
+
+		// Если защищённый сегмент переполнен, его самая давняя запись возвращается в испытательный
+		mainSize := p.probation.Len() + p.protected.Len()
+		if p.protected.Len() > mainSize*tinyLFUProtectedPercent/100 {
+			demoted := p.protected.Remove(p.protected.Back()).(*tinyLFUEntry)
+			demoted.segment = segmentProbation
+			demoted.element = p.probation.PushFront(demoted)
+		}
+	}
+}
+
+// Remove забывает запись. Оценка частоты обращений к ней в count-min sketch сохраняется
+func (p *TinyLFUPolicy) Remove(key string) {
+	entry, ok := p.entries[key]
+	if !ok {
+		return
+	}
+
+	p.segmentList(entry.segment).Remove(entry.element)
+	delete(p.entries, key)
+}
+
+// Victim выбирает запись для вытеснения. Если LRU-окно больше своей доли, то его самая давняя запись
+// либо вытесняется, либо переходит в основную часть кеша вместо вытесняемой оттуда записи
+func (p *TinyLFUPolicy) Victim() (string, bool) {
+	if len(p.entries) == 0 {
+		return "", false
+	}
+
+	windowSize := len(p.entries) * tinyLFUWindowPercent / 100
+	if windowSize < 1 {
+		windowSize = 1
+	}
+
+	for p.window.Len() > windowSize {
+		candidate := p.window.Back().Value.(*tinyLFUEntry)
+
+		mainVictim := p.mainVictim()
+		if mainVictim == nil || p.window.Len() > windowSize+1 {
+			// Основная часть кеша меньше своей доли (например, пока кеш заполнялся, вытеснений не было
+			// и все записи остались в окне): лишние записи окна переходят в неё без соревнования
+			p.admit(candidate)
+			continue
+		}
+
+		if p.sketch.Estimate(candidate.key) <= p.sketch.Estimate(mainVictim.key) {
+			return candidate.key, true
+		}
+
+		// Кандидат полезнее: он переходит в испытательный сегмент, а вытесняется запись из основной части
+		p.admit(candidate)
+		return mainVictim.key, true
+	}
+
+	if mainVictim := p.mainVictim(); mainVictim != nil {
+		return mainVictim.key, true
+	}
+	return p.window.Back().Value.(*tinyLFUEntry).key, true
+}
+
+// mainVictim возвращает кандидата на вытеснение из основной части кеша или nil, если она пуста
+func (p *TinyLFUPolicy) mainVictim() *tinyLFUEntry {
+	if back := p.probation.Back(); back != nil {
+		return back.Value.(*tinyLFUEntry)
+	}
+	if back := p.protected.Back(); back != nil {
+		return back.Value.(*tinyLFUEntry)
+	}
+	return nil
+}
+
+// admit переносит запись из LRU-окна в испытательный сегмент
+func (p *TinyLFUPolicy) admit(entry *tinyLFUEntry) {
+	p.window.Remove(entry.element)
+	entry.segment = segmentProbation
+	entry.element = p.probation.PushFront(entry)
+}
+
+// segmentList возвращает список записей сегмента
+func (p *TinyLFUPolicy) segmentList(segment tinyLFUSegment) *list.List {
+	switch segment {
+	case segmentProbation:
+		return &p.probation
+	case segmentProtected:
+		return &p.protected
+	}
+	return &p.window
+}
@@ -0,0 +1,89 @@
+package embedded
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	sketch := newCountMinSketch(100)
+
+	for i := 0; i < 5; i++ {
+		sketch.Increment("hot")
+	}
+	sketch.Increment("cold")
+
+	assert.GreaterOrEqual(t, sketch.Estimate("hot"), uint8(5))
+	assert.GreaterOrEqual(t, sketch.Estimate("cold"), uint8(1))
+	assert.Less(t, sketch.Estimate("cold"), sketch.Estimate("hot"))
+
+	// Счётчики не переполняются
+	for i := 0; i < 100; i++ {
+		sketch.Increment("hot")
+	}
+	assert.Equal(t, uint8(sketchMaxCount), sketch.Estimate("hot"))
+
+	// После большого количества обращений счётчики уменьшаются вдвое
+	for i := 0; i < sketch.resetAt; i++ {
+		sketch.Increment(fmt.Sprintf("key-%d", i))
+	}
+	assert.Less(t, sketch.Estimate("hot"), uint8(sketchMaxCount))
+}
+
+func TestTinyLFUPolicy_Victim(t *testing.T) {
+	policy := NewTinyLFUPolicy(100)
+	assert.Equal(t, []string(nil), victims(policy))
+
+	policy.Add("a")
+	key, ok := policy.Victim()
+	assert.True(t, ok)
+	assert.Equal(t, "a", key)
+
+	policy.Remove("a")
+	_, ok = policy.Victim()
+	assert.False(t, ok)
+}
+
+// simulateHitRatio прогоняет через кеш на capacity записей последовательность обращений и возвращает долю попаданий
+func simulateHitRatio(policy EvictionPolicy, capacity int, accesses []string) float64 {
+	cached := make(map[string]struct{}, capacity)
+	hits := 0
+	for _, key := range accesses {
+		if _, ok := cached[key]; ok {
+			hits++
+			policy.Access(key)
+			continue
+		}
+
+		cached[key] = struct{}{}
+		policy.Add(key)
+		for len(cached) > capacity {
+			victim, _ := policy.Victim()
+			policy.Remove(victim)
+			delete(cached, victim)
+		}
+	}
+	return float64(hits) / float64(len(accesses))
+}
+
+func TestTinyLFUPolicy_ScanResistance(t *testing.T) {
+	const capacity = 100
+
+	// Небольшой набор часто используемых ключей вперемешку с однократным сканированием большого числа ключей
+	random := rand.New(rand.NewSource(1))
+	var accesses []string
+	for i := 0; i < 20000; i++ {
+		accesses = append(accesses, fmt.Sprintf("hot-%d", random.Intn(50)))
+		accesses = append(accesses, fmt.Sprintf("scan-%d", i))
+	}
+
+	lru := simulateHitRatio(NewLRUPolicy(), capacity, accesses)
+	tinyLFU := simulateHitRatio(NewTinyLFUPolicy(capacity), capacity, accesses)
+
+	// Часто используемые ключи дают половину обращений, и W-TinyLFU должен удерживать их в кеше
+	assert.Greater(t, tinyLFU, 0.45)
+	assert.Greater(t, tinyLFU, lru)
+}
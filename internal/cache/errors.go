@@ -0,0 +1,19 @@
+package cache
+
+import "errors"
+
+var (
+	// ErrNotFound записи с таким ключом в хранилище нет
+	ErrNotFound = errors.New("not found")
+	// ErrNotNumeric значение записи не является целым неотрицательным числом
+	ErrNotNumeric = errors.New("value is not a number")
+	// ErrTooLarge запись больше, чем может поместиться в хранилище
+	ErrTooLarge = errors.New("item is too large")
+	// ErrNotStored запись не сохранена, так как не выполнено условие записи: для Add запись уже есть,
+	// для Replace - её нет
+	ErrNotStored = errors.New("item is not stored")
+	// ErrExists запись не сохранена CompareAndSwap, так как была изменена с момента последнего чтения
+	ErrExists = errors.New("item has been modified since last fetch")
+	// ErrSlowConsumer подписчик не успевает обрабатывать события, поэтому подписка закрыта
+	ErrSlowConsumer = errors.New("consumer is too slow")
+)
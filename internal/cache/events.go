@@ -0,0 +1,57 @@
+package cache
+
+// EventType тип события изменения записи
+type EventType int
+
+const (
+	// EventSet запись создана или изменена
+	EventSet EventType = iota + 1
+	// EventDelete запись удалена
+	EventDelete
+	// EventExpire запись удалена по истечении времени жизни
+	EventExpire
+)
+
+// String возвращает название типа события
+func (t EventType) String() string {
+	switch t {
+	case EventSet:
+		return "set"
+	case EventDelete:
+		return "delete"
+	case EventExpire:
+		return "expire"
+	}
+	return "unknown"
+}
+
+// Event событие изменения записи
+type Event struct {
+	// Тип события
+	Type EventType
+	// Ключ записи
+	Key string
+	// Количество событий, пропущенных подписчиком перед этим событием из-за переполнения его буфера
+	Dropped uint64
+}
+
+// KeyFilter отбирает ключи, об изменении которых нужно сообщать: ключи из списка Keys и ключи с префиксом Prefix
+type KeyFilter struct {
+	Keys   []string
+	Prefix string
+}
+
+// Empty проверяет, что фильтр не отбирает ни одного ключа
+func (f KeyFilter) Empty() bool {
+	return len(f.Keys) == 0 && f.Prefix == ""
+}
+
+// Subscription подписка на события изменения записей
+type Subscription interface {
+	// Events возвращает канал событий. Канал закрывается при закрытии подписки
+	Events() <-chan Event
+	// Err возвращает причину закрытия подписки хранилищем (например, ErrSlowConsumer) или nil
+	Err() error
+	// Close закрывает подписку
+	Close()
+}
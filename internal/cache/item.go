@@ -0,0 +1,15 @@
+package cache
+
+import "time"
+
+// Item запись хранилища вместе с метаданными
+type Item struct {
+	// Значение
+	Value []byte
+	// Произвольные флаги, сохранённые вместе со значением
+	Flags uint32
+	// Идентификатор версии записи, меняется при каждом изменении значения
+	CasID uint64
+	// Оставшееся время жизни (0 - бессрочная запись)
+	TTL time.Duration
+}
@@ -1,22 +1,48 @@
 package memcache
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/dimuska139/cacher/internal/cache"
+	memcacheClient "github.com/dimuska139/cacher/libs/memcache"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// MinServerVersion минимальная поддерживаемая версия Memcache: Get использует мета-команду mg, которая появилась в 1.6
+const MinServerVersion = "1.6"
+
+// ErrUnsupportedVersion версия сервера Memcache меньше MinServerVersion
+var ErrUnsupportedVersion = errors.New("unsupported memcache version")
+
 //go:generate mockgen -source=memcache.go -destination=./memcache_mock.go -package=memcache
 
 // Memcacher интерфейс для библиотеки-клиента Memcache
 type Memcacher interface {
-	Get(key string) ([]byte, error)
-	Set(key string, value []byte, expiration int64) error
-	Delete(key string) error
+	GetItem(ctx context.Context, key string) (*memcacheClient.Item, error)
+	GetMulti(ctx context.Context, keys []string) (map[string]memcacheClient.Item, error)
+	GetAndTouch(ctx context.Context, key string, expiration int64) (*memcacheClient.Item, error)
+	Set(ctx context.Context, key string, value []byte, expiration int64) error
+	Add(ctx context.Context, key string, value []byte, expiration int64) error
+	Replace(ctx context.Context, key string, value []byte, expiration int64) error
+	CompareAndSwap(ctx context.Context, item *memcacheClient.Item, casID uint64) error
+	Delete(ctx context.Context, key string) error
+	DeleteExisting(ctx context.Context, key string) error
+	Increment(ctx context.Context, key string, delta uint64) (uint64, error)
+	Decrement(ctx context.Context, key string, delta uint64) (uint64, error)
+	Touch(ctx context.Context, key string, expiration int64) error
+	Stats(ctx context.Context) (map[string]map[string]string, error)
+	Version(ctx context.Context) (map[string]string, error)
+	SetMulti(ctx context.Context, items []*memcacheClient.Item) []error
+	DeleteMulti(ctx context.Context, keys []string) []error
 }
 
 // MemcacheStorage реализация кеша через Memcache
 type MemcacheStorage struct {
 	memcacheClient Memcacher
+	counters       cache.Counters
 }
 
 // NewMemcacheStorage создаёт реализацию кеша через Memcache
@@ -26,32 +52,350 @@ func NewMemcacheStorage(memcacheClient Memcacher) *MemcacheStorage {
 	}
 }
 
-// Get возвращает закешированные данные
-func (s *MemcacheStorage) Get(key string) ([]byte, error) {
-	value, err := s.memcacheClient.Get(key)
+// Get возвращает закешированные данные вместе с метаданными. Если записи нет, возвращается cache.ErrNotFound
+func (s *MemcacheStorage) Get(ctx context.Context, key string) (*cache.Item, error) {
+	item, err := s.memcacheClient.GetItem(ctx, key)
+	if err != nil {
+		s.countMiss(err)
+		return nil, fmt.Errorf("can't get data from memcache: %w", convertError(err))
+	}
+	s.counters.Hit()
+
+	return convertItem(item), nil
+}
+
+// GetAndTouch возвращает закешированные данные и одновременно устанавливает новое время жизни записи.
+// Если записи нет, возвращается cache.ErrNotFound
+func (s *MemcacheStorage) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error) {
+	item, err := s.memcacheClient.GetAndTouch(ctx, key, expiration(ttl))
+	if err != nil {
+		s.countMiss(err)
+		return nil, fmt.Errorf("can't get and touch data in memcache: %w", convertError(err))
+	}
+	s.counters.Hit()
+
+	return convertItem(item), nil
+}
+
+// GetMulti возвращает закешированные данные по нескольким ключам. Отсутствующих в кеше ключей в результате нет
+func (s *MemcacheStorage) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	items, err := s.memcacheClient.GetMulti(ctx, keys)
 	if err != nil {
 		return nil, fmt.Errorf("can't get data from memcache: %w", err)
 	}
 
-	return value, nil
+	values := make(map[string][]byte, len(items))
+	for key, item := range items {
+		values[key] = item.Value
+	}
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			s.counters.Hit()
+		} else {
+			s.counters.Miss()
+		}
+	}
+
+	return values, nil
+}
+
+// GetBatch возвращает записи по нескольким ключам в том же порядке, что и keys. Все ключи одного сервера
+// запрашиваются одной командой. Если записи нет, в результате для неё cache.ErrNotFound. Если хотя бы один
+// сервер недоступен, ошибку получают все ключи
+func (s *MemcacheStorage) GetBatch(ctx context.Context, keys []string) []cache.GetResult {
+	results := make([]cache.GetResult, len(keys))
+
+	items, err := s.memcacheClient.GetMulti(ctx, keys)
+	if err != nil {
+		err = fmt.Errorf("can't get data from memcache: %w", err)
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	for i, key := range keys {
+		item, ok := items[key]
+		if !ok {
+			s.counters.Miss()
+			results[i].Err = cache.ErrNotFound
+			continue
+		}
+
+		s.counters.Hit()
+		results[i].Item = &cache.Item{
+			Value: item.Value,
+			Flags: item.Flags,
+			CasID: item.CasID,
+		}
+	}
+
+	return results
+}
+
+// SetBatch записывает несколько записей. Записи одного сервера отправляются ему конвейером команд.
+// Возвращает ошибку для каждой записи в том же порядке, что и entries (nil - запись сохранена)
+func (s *MemcacheStorage) SetBatch(ctx context.Context, entries []cache.Entry) []error {
+	items := make([]*memcacheClient.Item, len(entries))
+	for i, entry := range entries {
+		items[i] = &memcacheClient.Item{
+			Key:        entry.Key,
+			Value:      entry.Value,
+			Expiration: expiration(entry.TTL),
+		}
+	}
+
+	errs := s.memcacheClient.SetMulti(ctx, items)
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("can't write data to memcache: %w", err)
+			continue
+		}
+		s.counters.Set()
+	}
+
+	return errs
+}
+
+// DeleteBatch удаляет записи по нескольким ключам. Ключи одного сервера отправляются ему конвейером команд.
+// Возвращает ошибку для каждого ключа в том же порядке, что и keys. Отсутствие записи ошибкой не является
+func (s *MemcacheStorage) DeleteBatch(ctx context.Context, keys []string) []error {
+	errs := s.memcacheClient.DeleteMulti(ctx, keys)
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("can't delete data from memcache: %w", err)
+			continue
+		}
+		s.counters.Delete()
+	}
+
+	return errs
 }
 
 // Set записывает информацию в кеш. Если запись в кеше уже есть, то она обновится
-func (s *MemcacheStorage) Set(key string, value []byte, ttl time.Duration) error {
-	err := s.memcacheClient.Set(key, value, int64(ttl.Seconds()))
+func (s *MemcacheStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := s.memcacheClient.Set(ctx, key, value, expiration(ttl))
 	if err != nil {
 		return fmt.Errorf("can't get write data to memcache: %w", err)
 	}
+	s.counters.Set()
+
+	return nil
+}
+
+// Add записывает информацию в кеш, только если записи с таким ключом ещё нет.
+// Если запись уже есть, возвращается cache.ErrNotStored
+func (s *MemcacheStorage) Add(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := s.memcacheClient.Add(ctx, key, value, expiration(ttl))
+	if err != nil {
+		return fmt.Errorf("can't add data to memcache: %w", convertError(err))
+	}
+	s.counters.Set()
+
+	return nil
+}
+
+// Replace перезаписывает значение, только если запись с таким ключом уже есть.
+// Если записи нет, возвращается cache.ErrNotStored
+func (s *MemcacheStorage) Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := s.memcacheClient.Replace(ctx, key, value, expiration(ttl))
+	if err != nil {
+		return fmt.Errorf("can't replace data in memcache: %w", convertError(err))
+	}
+	s.counters.Set()
+
+	return nil
+}
+
+// CompareAndSwap перезаписывает значение, только если с момента чтения запись не менялась.
+// Если запись изменилась, возвращается cache.ErrExists, если записи нет - cache.ErrNotFound
+func (s *MemcacheStorage) CompareAndSwap(ctx context.Context, key string, value []byte, ttl time.Duration, casID uint64) error {
+	item := &memcacheClient.Item{
+		Key:        key,
+		Value:      value,
+		Expiration: expiration(ttl),
+	}
+	if err := s.memcacheClient.CompareAndSwap(ctx, item, casID); err != nil {
+		return fmt.Errorf("can't compare and swap data in memcache: %w", convertError(err))
+	}
+	s.counters.Set()
 
 	return nil
 }
 
 // Delete удаляет запись из кеша по ключу
-func (s *MemcacheStorage) Delete(key string) error {
-	err := s.memcacheClient.Delete(key)
+func (s *MemcacheStorage) Delete(ctx context.Context, key string) error {
+	err := s.memcacheClient.Delete(ctx, key)
 	if err != nil {
 		return fmt.Errorf("can't delete data from memcache: %w", err)
 	}
+	s.counters.Delete()
 
 	return nil
 }
+
+// DeleteExisting удаляет запись из кеша по ключу. Если записи нет, возвращается cache.ErrNotFound
+func (s *MemcacheStorage) DeleteExisting(ctx context.Context, key string) error {
+	err := s.memcacheClient.DeleteExisting(ctx, key)
+	if err != nil {
+		return fmt.Errorf("can't delete data from memcache: %w", convertError(err))
+	}
+	s.counters.Delete()
+
+	return nil
+}
+
+// Touch устанавливает новое время жизни записи
+func (s *MemcacheStorage) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	err := s.memcacheClient.Touch(ctx, key, expiration(ttl))
+	if err != nil {
+		return fmt.Errorf("can't touch data in memcache: %w", convertError(err))
+	}
+
+	return nil
+}
+
+// Increment увеличивает числовое значение записи на delta и возвращает новое значение
+func (s *MemcacheStorage) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	value, err := s.memcacheClient.Increment(ctx, key, delta)
+	if err != nil {
+		return 0, fmt.Errorf("can't increment value in memcache: %w", convertError(err))
+	}
+
+	return value, nil
+}
+
+// Decrement уменьшает числовое значение записи на delta и возвращает новое значение
+func (s *MemcacheStorage) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	value, err := s.memcacheClient.Decrement(ctx, key, delta)
+	if err != nil {
+		return 0, fmt.Errorf("can't decrement value in memcache: %w", convertError(err))
+	}
+
+	return value, nil
+}
+
+// Stats возвращает статистику операций хранилища вместе со статистикой серверов Memcache.
+// Количество и размер записей, вытеснения и истечения суммируются по всем серверам
+func (s *MemcacheStorage) Stats(ctx context.Context) (*cache.Stats, error) {
+	servers, err := s.memcacheClient.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get stats from memcache: %w", err)
+	}
+
+	stats := s.counters.Stats()
+	stats.Servers = servers
+	for _, serverStats := range servers {
+		stats.Items += statValue(serverStats, "curr_items")
+		stats.Bytes += statValue(serverStats, "bytes")
+		stats.Evictions += statValue(serverStats, "evictions")
+		stats.Expirations += statValue(serverStats, "reclaimed") + statValue(serverStats, "crawler_reclaimed")
+	}
+
+	return stats, nil
+}
+
+// HealthCheck проверяет доступность всех серверов Memcache: каждый сервер должен ответить на команду version
+func (s *MemcacheStorage) HealthCheck(ctx context.Context) error {
+	if _, err := s.memcacheClient.Version(ctx); err != nil {
+		return fmt.Errorf("memcache is unavailable: %w", err)
+	}
+
+	return nil
+}
+
+// CheckVersion проверяет, что версия всех серверов Memcache не меньше MinServerVersion.
+// Если версия какого-то сервера меньше или не распознана, возвращается ErrUnsupportedVersion
+func (s *MemcacheStorage) CheckVersion(ctx context.Context) error {
+	versions, err := s.memcacheClient.Version(ctx)
+	if err != nil {
+		return fmt.Errorf("can't get memcache version: %w", err)
+	}
+
+	for address, version := range versions {
+		if !supportedVersion(version) {
+			return fmt.Errorf("%w: %s has version %q, %s or newer is required",
+				ErrUnsupportedVersion, address, version, MinServerVersion)
+		}
+	}
+
+	return nil
+}
+
+// supportedVersion проверяет, что версия Memcache (например, 1.6.21) не меньше MinServerVersion
+func supportedVersion(version string) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+
+	return major > 1 || major == 1 && minor >= 6
+}
+
+// countMiss учитывает промах, если записи нет в кеше. Остальные ошибки в статистике не учитываются
+func (s *MemcacheStorage) countMiss(err error) {
+	if errors.Is(err, memcacheClient.ErrNotFound) {
+		s.counters.Miss()
+	}
+}
+
+// statValue возвращает числовое значение статистики сервера Memcache. Отсутствующие и нечисловые значения считаются нулевыми
+func statValue(stats map[string]string, name string) uint64 {
+	value, err := strconv.ParseUint(stats[name], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// maxRelativeExpiration время жизни больше 30 дней Memcache считает Unix-временем истечения
+const maxRelativeExpiration = 30 * 24 * time.Hour
+
+// expiration преобразует время жизни записи во время истечения в формате Memcache. Время жизни округляется
+// вверх до целых секунд, чтобы запись со временем жизни меньше секунды не стала бессрочной, а время жизни
+// больше 30 дней передаётся как Unix-время истечения. Отрицательное время жизни означает уже истёкшую запись
+func expiration(ttl time.Duration) int64 {
+	switch {
+	case ttl == 0:
+		return 0
+	case ttl < 0:
+		return -1
+	case ttl > maxRelativeExpiration:
+		return time.Now().Add(ttl + time.Second - 1).Unix()
+	}
+	return int64((ttl + time.Second - 1) / time.Second)
+}
+
+// convertItem преобразует запись Memcache в запись хранилища
+func convertItem(item *memcacheClient.Item) *cache.Item {
+	return &cache.Item{
+		Value: item.Value,
+		Flags: item.Flags,
+		CasID: item.CasID,
+		TTL:   time.Duration(item.Expiration) * time.Second,
+	}
+}
+
+// convertError дополняет ошибки библиотеки-клиента Memcache соответствующими ошибками хранилища
+func convertError(err error) error {
+	switch {
+	case errors.Is(err, memcacheClient.ErrNotFound):
+		return fmt.Errorf("%w: %w", cache.ErrNotFound, err)
+	case errors.Is(err, memcacheClient.ErrNonNumeric):
+		return fmt.Errorf("%w: %w", cache.ErrNotNumeric, err)
+	case errors.Is(err, memcacheClient.ErrNotStored):
+		return fmt.Errorf("%w: %w", cache.ErrNotStored, err)
+	case errors.Is(err, memcacheClient.ErrExists):
+		return fmt.Errorf("%w: %w", cache.ErrExists, err)
+	}
+	return err
+}
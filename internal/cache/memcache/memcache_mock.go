@@ -5,8 +5,10 @@
 package memcache
 
 import (
+	context "context"
 	reflect "reflect"
 
+	memcache "github.com/dimuska139/cacher/libs/memcache"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -33,45 +35,233 @@ func (m *MockMemcacher) EXPECT() *MockMemcacherMockRecorder {
 	return m.recorder
 }
 
+// Add mocks base method.
+func (m *MockMemcacher) Add(ctx context.Context, key string, value []byte, expiration int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, key, value, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockMemcacherMockRecorder) Add(ctx, key, value, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockMemcacher)(nil).Add), ctx, key, value, expiration)
+}
+
+// CompareAndSwap mocks base method.
+func (m *MockMemcacher) CompareAndSwap(ctx context.Context, item *memcache.Item, casID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSwap", ctx, item, casID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareAndSwap indicates an expected call of CompareAndSwap.
+func (mr *MockMemcacherMockRecorder) CompareAndSwap(ctx, item, casID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSwap", reflect.TypeOf((*MockMemcacher)(nil).CompareAndSwap), ctx, item, casID)
+}
+
+// Decrement mocks base method.
+func (m *MockMemcacher) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrement", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrement indicates an expected call of Decrement.
+func (mr *MockMemcacherMockRecorder) Decrement(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrement", reflect.TypeOf((*MockMemcacher)(nil).Decrement), ctx, key, delta)
+}
+
 // Delete mocks base method.
-func (m *MockMemcacher) Delete(key string) error {
+func (m *MockMemcacher) Delete(ctx context.Context, key string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", key)
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockMemcacherMockRecorder) Delete(key interface{}) *gomock.Call {
+func (mr *MockMemcacherMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMemcacher)(nil).Delete), ctx, key)
+}
+
+// DeleteExisting mocks base method.
+func (m *MockMemcacher) DeleteExisting(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExisting", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExisting indicates an expected call of DeleteExisting.
+func (mr *MockMemcacherMockRecorder) DeleteExisting(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExisting", reflect.TypeOf((*MockMemcacher)(nil).DeleteExisting), ctx, key)
+}
+
+// DeleteMulti mocks base method.
+func (m *MockMemcacher) DeleteMulti(ctx context.Context, keys []string) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMulti", ctx, keys)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// DeleteMulti indicates an expected call of DeleteMulti.
+func (mr *MockMemcacherMockRecorder) DeleteMulti(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMulti", reflect.TypeOf((*MockMemcacher)(nil).DeleteMulti), ctx, keys)
+}
+
+// GetAndTouch mocks base method.
+func (m *MockMemcacher) GetAndTouch(ctx context.Context, key string, expiration int64) (*memcache.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAndTouch", ctx, key, expiration)
+	ret0, _ := ret[0].(*memcache.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAndTouch indicates an expected call of GetAndTouch.
+func (mr *MockMemcacherMockRecorder) GetAndTouch(ctx, key, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAndTouch", reflect.TypeOf((*MockMemcacher)(nil).GetAndTouch), ctx, key, expiration)
+}
+
+// GetItem mocks base method.
+func (m *MockMemcacher) GetItem(ctx context.Context, key string) (*memcache.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, key)
+	ret0, _ := ret[0].(*memcache.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockMemcacherMockRecorder) GetItem(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMemcacher)(nil).Delete), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockMemcacher)(nil).GetItem), ctx, key)
 }
 
-// Get mocks base method.
-func (m *MockMemcacher) Get(key string) ([]byte, error) {
+// GetMulti mocks base method.
+func (m *MockMemcacher) GetMulti(ctx context.Context, keys []string) (map[string]memcache.Item, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", key)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "GetMulti", ctx, keys)
+	ret0, _ := ret[0].(map[string]memcache.Item)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get.
-func (mr *MockMemcacherMockRecorder) Get(key interface{}) *gomock.Call {
+// GetMulti indicates an expected call of GetMulti.
+func (mr *MockMemcacherMockRecorder) GetMulti(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMulti", reflect.TypeOf((*MockMemcacher)(nil).GetMulti), ctx, keys)
+}
+
+// Increment mocks base method.
+func (m *MockMemcacher) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockMemcacherMockRecorder) Increment(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockMemcacher)(nil).Increment), ctx, key, delta)
+}
+
+// Replace mocks base method.
+func (m *MockMemcacher) Replace(ctx context.Context, key string, value []byte, expiration int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, key, value, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockMemcacherMockRecorder) Replace(ctx, key, value, expiration interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMemcacher)(nil).Get), key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockMemcacher)(nil).Replace), ctx, key, value, expiration)
 }
 
 // Set mocks base method.
-func (m *MockMemcacher) Set(key string, value []byte, expiration int64) error {
+func (m *MockMemcacher) Set(ctx context.Context, key string, value []byte, expiration int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", key, value, expiration)
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, expiration)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockMemcacherMockRecorder) Set(key, value, expiration interface{}) *gomock.Call {
+func (mr *MockMemcacherMockRecorder) Set(ctx, key, value, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockMemcacher)(nil).Set), ctx, key, value, expiration)
+}
+
+// SetMulti mocks base method.
+func (m *MockMemcacher) SetMulti(ctx context.Context, items []*memcache.Item) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMulti", ctx, items)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// SetMulti indicates an expected call of SetMulti.
+func (mr *MockMemcacherMockRecorder) SetMulti(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMulti", reflect.TypeOf((*MockMemcacher)(nil).SetMulti), ctx, items)
+}
+
+// Stats mocks base method.
+func (m *MockMemcacher) Stats(ctx context.Context) (map[string]map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(map[string]map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockMemcacherMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockMemcacher)(nil).Stats), ctx)
+}
+
+// Touch mocks base method.
+func (m *MockMemcacher) Touch(ctx context.Context, key string, expiration int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, key, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockMemcacherMockRecorder) Touch(ctx, key, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockMemcacher)(nil).Touch), ctx, key, expiration)
+}
+
+// Version mocks base method.
+func (m *MockMemcacher) Version(ctx context.Context) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", ctx)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockMemcacherMockRecorder) Version(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockMemcacher)(nil).Set), key, value, expiration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockMemcacher)(nil).Version), ctx)
 }
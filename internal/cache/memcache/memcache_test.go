@@ -1,7 +1,10 @@
 package memcache
 
 import (
+	"context"
 	"errors"
+	"github.com/dimuska139/cacher/internal/cache"
+	memcacheClient "github.com/dimuska139/cacher/libs/memcache"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"reflect"
@@ -25,7 +28,7 @@ func TestMemcacheStorage_Delete(t *testing.T) {
 				ctrl := gomock.NewController(t)
 				mockedClient := NewMockMemcacher(ctrl)
 				mockedClient.EXPECT().
-					Delete("testkey").
+					Delete(gomock.Any(), "testkey").
 					Return(errors.New("something went wrong")).
 					Times(1)
 				return mockedClient
@@ -41,7 +44,7 @@ func TestMemcacheStorage_Delete(t *testing.T) {
 				ctrl := gomock.NewController(t)
 				mockedClient := NewMockMemcacher(ctrl)
 				mockedClient.EXPECT().
-					Delete("testkey").
+					Delete(gomock.Any(), "testkey").
 					Return(nil).
 					Times(1)
 				return mockedClient
@@ -59,14 +62,49 @@ func TestMemcacheStorage_Delete(t *testing.T) {
 			}
 
 			if tt.wantErr {
-				assert.Error(t, s.Delete(tt.args.key))
+				assert.Error(t, s.Delete(context.Background(), tt.args.key))
 			} else {
-				assert.NoError(t, s.Delete(tt.args.key))
+				assert.NoError(t, s.Delete(context.Background(), tt.args.key))
 			}
 		})
 	}
 }
 
+func TestMemcacheStorage_DeleteExisting(t *testing.T) {
+	tests := []struct {
+		name      string
+		clientErr error
+		wantErr   error
+	}{
+		{
+			name: "existing",
+		},
+		{
+			name:      "not existing",
+			clientErr: memcacheClient.ErrNotFound,
+			wantErr:   cache.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockedClient := NewMockMemcacher(ctrl)
+			mockedClient.EXPECT().
+				DeleteExisting(gomock.Any(), "testkey").
+				Return(tt.clientErr).
+				Times(1)
+			s := NewMemcacheStorage(mockedClient)
+
+			err := s.DeleteExisting(context.Background(), "testkey")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestMemcacheStorage_Get(t *testing.T) {
 	type args struct {
 		key string
@@ -75,7 +113,97 @@ func TestMemcacheStorage_Get(t *testing.T) {
 		name              string
 		getMemcacheClient func() Memcacher
 		args              args
-		want              []byte
+		want              *cache.Item
+		wantErr           error
+	}{
+		{
+			name: "with error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetItem(gomock.Any(), "testkey").
+					Return(nil, memcacheClient.ErrMalformedResponse).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key: "testkey",
+			},
+			want:    nil,
+			wantErr: memcacheClient.ErrMalformedResponse,
+		},
+		{
+			name: "not found",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetItem(gomock.Any(), "testkey").
+					Return(nil, memcacheClient.ErrNotFound).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key: "testkey",
+			},
+			want:    nil,
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "without error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetItem(gomock.Any(), "testkey").
+					Return(&memcacheClient.Item{
+						Key:        "testkey",
+						Value:      []byte("data"),
+						Flags:      42,
+						CasID:      7,
+						Expiration: 30,
+					}, nil).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key: "testkey",
+			},
+			want: &cache.Item{
+				Value: []byte("data"),
+				Flags: 42,
+				CasID: 7,
+				TTL:   30 * time.Second,
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MemcacheStorage{
+				memcacheClient: tt.getMemcacheClient(),
+			}
+			got, err := s.Get(context.Background(), tt.args.key)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMemcacheStorage_GetMulti(t *testing.T) {
+	type args struct {
+		keys []string
+	}
+	tests := []struct {
+		name              string
+		getMemcacheClient func() Memcacher
+		args              args
+		want              map[string][]byte
 		wantErr           bool
 	}{
 		{
@@ -84,13 +212,13 @@ func TestMemcacheStorage_Get(t *testing.T) {
 				ctrl := gomock.NewController(t)
 				mockedClient := NewMockMemcacher(ctrl)
 				mockedClient.EXPECT().
-					Get("testkey").
+					GetMulti(gomock.Any(), []string{"first", "second"}).
 					Return(nil, errors.New("something went wrong")).
 					Times(1)
 				return mockedClient
 			},
 			args: args{
-				key: "testkey",
+				keys: []string{"first", "second"},
 			},
 			want:    nil,
 			wantErr: true,
@@ -101,15 +229,23 @@ func TestMemcacheStorage_Get(t *testing.T) {
 				ctrl := gomock.NewController(t)
 				mockedClient := NewMockMemcacher(ctrl)
 				mockedClient.EXPECT().
-					Get("testkey").
-					Return([]byte("data"), nil).
+					GetMulti(gomock.Any(), []string{"first", "second"}).
+					Return(map[string]memcacheClient.Item{
+						"first": {
+							Key:   "first",
+							Value: []byte("data"),
+							CasID: 1,
+						},
+					}, nil).
 					Times(1)
 				return mockedClient
 			},
 			args: args{
-				key: "testkey",
+				keys: []string{"first", "second"},
+			},
+			want: map[string][]byte{
+				"first": []byte("data"),
 			},
-			want:    []byte("data"),
 			wantErr: false,
 		},
 	}
@@ -118,7 +254,7 @@ func TestMemcacheStorage_Get(t *testing.T) {
 			s := &MemcacheStorage{
 				memcacheClient: tt.getMemcacheClient(),
 			}
-			got, err := s.Get(tt.args.key)
+			got, err := s.GetMulti(context.Background(), tt.args.keys)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -147,7 +283,7 @@ func TestMemcacheStorage_Set(t *testing.T) {
 				ctrl := gomock.NewController(t)
 				mockedClient := NewMockMemcacher(ctrl)
 				mockedClient.EXPECT().
-					Set("testkey", []byte("data"), int64((time.Second * 5).Seconds())).
+					Set(gomock.Any(), "testkey", []byte("data"), int64((time.Second * 5).Seconds())).
 					Return(errors.New("something went wrong")).
 					Times(1)
 				return mockedClient
@@ -165,7 +301,7 @@ func TestMemcacheStorage_Set(t *testing.T) {
 				ctrl := gomock.NewController(t)
 				mockedClient := NewMockMemcacher(ctrl)
 				mockedClient.EXPECT().
-					Set("testkey", []byte("data"), int64((time.Second * 5).Seconds())).
+					Set(gomock.Any(), "testkey", []byte("data"), int64((time.Second * 5).Seconds())).
 					Return(nil).
 					Times(1)
 				return mockedClient
@@ -185,9 +321,9 @@ func TestMemcacheStorage_Set(t *testing.T) {
 			}
 
 			if tt.wantErr {
-				assert.Error(t, s.Set(tt.args.key, tt.args.value, tt.args.ttl))
+				assert.Error(t, s.Set(context.Background(), tt.args.key, tt.args.value, tt.args.ttl))
 			} else {
-				assert.NoError(t, s.Set(tt.args.key, tt.args.value, tt.args.ttl))
+				assert.NoError(t, s.Set(context.Background(), tt.args.key, tt.args.value, tt.args.ttl))
 			}
 		})
 	}
@@ -223,3 +359,764 @@ func TestNewMemcacheStorage(t *testing.T) {
 		})
 	}
 }
+
+func TestMemcacheStorage_Increment(t *testing.T) {
+	type args struct {
+		key   string
+		delta uint64
+	}
+	tests := []struct {
+		name              string
+		getMemcacheClient func() Memcacher
+		args              args
+		want              uint64
+		wantErr           error
+	}{
+		{
+			name: "not found",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Increment(gomock.Any(), "counter", uint64(1)).
+					Return(uint64(0), memcacheClient.ErrNotFound).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key:   "counter",
+				delta: 1,
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "not numeric",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Increment(gomock.Any(), "counter", uint64(1)).
+					Return(uint64(0), memcacheClient.ErrNonNumeric).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key:   "counter",
+				delta: 1,
+			},
+			wantErr: cache.ErrNotNumeric,
+		},
+		{
+			name: "without error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Increment(gomock.Any(), "counter", uint64(5)).
+					Return(uint64(15), nil).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key:   "counter",
+				delta: 5,
+			},
+			want: 15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MemcacheStorage{
+				memcacheClient: tt.getMemcacheClient(),
+			}
+			got, err := s.Increment(context.Background(), tt.args.key, tt.args.delta)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMemcacheStorage_Decrement(t *testing.T) {
+	type args struct {
+		key   string
+		delta uint64
+	}
+	tests := []struct {
+		name              string
+		getMemcacheClient func() Memcacher
+		args              args
+		want              uint64
+		wantErr           error
+	}{
+		{
+			name: "not found",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Decrement(gomock.Any(), "counter", uint64(1)).
+					Return(uint64(0), memcacheClient.ErrNotFound).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key:   "counter",
+				delta: 1,
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "without error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Decrement(gomock.Any(), "counter", uint64(5)).
+					Return(uint64(10), nil).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key:   "counter",
+				delta: 5,
+			},
+			want: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MemcacheStorage{
+				memcacheClient: tt.getMemcacheClient(),
+			}
+			got, err := s.Decrement(context.Background(), tt.args.key, tt.args.delta)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMemcacheStorage_Touch(t *testing.T) {
+	type args struct {
+		key string
+		ttl time.Duration
+	}
+	tests := []struct {
+		name              string
+		getMemcacheClient func() Memcacher
+		args              args
+		wantErr           error
+	}{
+		{
+			name: "not found",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Touch(gomock.Any(), "testkey", int64(60)).
+					Return(memcacheClient.ErrNotFound).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key: "testkey",
+				ttl: time.Minute,
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "without error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Touch(gomock.Any(), "testkey", int64(60)).
+					Return(nil).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key: "testkey",
+				ttl: time.Minute,
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MemcacheStorage{
+				memcacheClient: tt.getMemcacheClient(),
+			}
+
+			err := s.Touch(context.Background(), tt.args.key, tt.args.ttl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMemcacheStorage_GetAndTouch(t *testing.T) {
+	type args struct {
+		key string
+		ttl time.Duration
+	}
+	tests := []struct {
+		name              string
+		getMemcacheClient func() Memcacher
+		args              args
+		want              *cache.Item
+		wantErr           error
+	}{
+		{
+			name: "not found",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetAndTouch(gomock.Any(), "testkey", int64(60)).
+					Return(nil, memcacheClient.ErrNotFound).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key: "testkey",
+				ttl: time.Minute,
+			},
+			want:    nil,
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "without error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetAndTouch(gomock.Any(), "testkey", int64(60)).
+					Return(&memcacheClient.Item{
+						Key:        "testkey",
+						Value:      []byte("data"),
+						CasID:      7,
+						Expiration: 60,
+					}, nil).
+					Times(1)
+				return mockedClient
+			},
+			args: args{
+				key: "testkey",
+				ttl: time.Minute,
+			},
+			want: &cache.Item{
+				Value: []byte("data"),
+				CasID: 7,
+				TTL:   time.Minute,
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MemcacheStorage{
+				memcacheClient: tt.getMemcacheClient(),
+			}
+			got, err := s.GetAndTouch(context.Background(), tt.args.key, tt.args.ttl)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMemcacheStorage_Stats(t *testing.T) {
+	tests := []struct {
+		name              string
+		getMemcacheClient func() Memcacher
+		want              *cache.Stats
+		wantErr           bool
+	}{
+		{
+			name: "with error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetItem(gomock.Any(), "found").
+					Return(&memcacheClient.Item{Key: "found", Value: []byte("data")}, nil).
+					Times(1)
+				mockedClient.EXPECT().
+					Stats(gomock.Any()).
+					Return(nil, errors.New("something went wrong")).
+					Times(1)
+				return mockedClient
+			},
+			wantErr: true,
+		},
+		{
+			name: "without error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetItem(gomock.Any(), "found").
+					Return(&memcacheClient.Item{Key: "found", Value: []byte("data")}, nil).
+					Times(1)
+				mockedClient.EXPECT().
+					Stats(gomock.Any()).
+					Return(map[string]map[string]string{
+						"127.0.0.1:11211": {
+							"curr_items":        "10",
+							"bytes":             "1000",
+							"evictions":         "2",
+							"reclaimed":         "3",
+							"crawler_reclaimed": "1",
+						},
+						"127.0.0.1:11212": {
+							"curr_items": "5",
+							"bytes":      "500",
+							"evictions":  "not a number",
+						},
+					}, nil).
+					Times(1)
+				return mockedClient
+			},
+			want: &cache.Stats{
+				GetHits:     1,
+				Items:       15,
+				Bytes:       1500,
+				Evictions:   2,
+				Expirations: 4,
+				Servers: map[string]map[string]string{
+					"127.0.0.1:11211": {
+						"curr_items":        "10",
+						"bytes":             "1000",
+						"evictions":         "2",
+						"reclaimed":         "3",
+						"crawler_reclaimed": "1",
+					},
+					"127.0.0.1:11212": {
+						"curr_items": "5",
+						"bytes":      "500",
+						"evictions":  "not a number",
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MemcacheStorage{
+				memcacheClient: tt.getMemcacheClient(),
+			}
+			_, err := s.Get(context.Background(), "found")
+			assert.NoError(t, err)
+
+			got, err := s.Stats(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMemcacheStorage_Counters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockMemcacher(ctrl)
+	mockedClient.EXPECT().
+		GetItem(gomock.Any(), "missing").
+		Return(nil, memcacheClient.ErrNotFound).
+		Times(1)
+	mockedClient.EXPECT().
+		GetItem(gomock.Any(), "broken").
+		Return(nil, errors.New("something went wrong")).
+		Times(1)
+	mockedClient.EXPECT().
+		GetMulti(gomock.Any(), []string{"first", "second", "third"}).
+		Return(map[string]memcacheClient.Item{
+			"first": {Key: "first", Value: []byte("data")},
+		}, nil).
+		Times(1)
+	mockedClient.EXPECT().
+		Set(gomock.Any(), "first", []byte("data"), int64(0)).
+		Return(nil).
+		Times(1)
+	mockedClient.EXPECT().
+		Delete(gomock.Any(), "first").
+		Return(nil).
+		Times(1)
+
+	s := NewMemcacheStorage(mockedClient)
+	ctx := context.Background()
+	_, err := s.Get(ctx, "missing")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+	_, err = s.Get(ctx, "broken")
+	assert.Error(t, err)
+	_, err = s.GetMulti(ctx, []string{"first", "second", "third"})
+	assert.NoError(t, err)
+	assert.NoError(t, s.Set(ctx, "first", []byte("data"), 0))
+	assert.NoError(t, s.Delete(ctx, "first"))
+
+	// Ошибки, не связанные с отсутствием записи, промахами не считаются
+	assert.Equal(t, &cache.Stats{
+		GetHits:   1,
+		GetMisses: 3,
+		Sets:      1,
+		Deletes:   1,
+	}, s.counters.Stats())
+}
+
+func TestMemcacheStorage_HealthCheck(t *testing.T) {
+	tests := []struct {
+		name              string
+		getMemcacheClient func() Memcacher
+		wantErr           bool
+	}{
+		{
+			name: "with error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Version(gomock.Any()).
+					Return(nil, errors.New("something went wrong")).
+					Times(1)
+				return mockedClient
+			},
+			wantErr: true,
+		},
+		{
+			name: "without error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					Version(gomock.Any()).
+					Return(map[string]string{"127.0.0.1:11211": "1.6.21"}, nil).
+					Times(1)
+				return mockedClient
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MemcacheStorage{
+				memcacheClient: tt.getMemcacheClient(),
+			}
+
+			if tt.wantErr {
+				assert.Error(t, s.HealthCheck(context.Background()))
+			} else {
+				assert.NoError(t, s.HealthCheck(context.Background()))
+			}
+		})
+	}
+}
+
+func TestMemcacheStorage_GetBatch(t *testing.T) {
+	tests := []struct {
+		name              string
+		getMemcacheClient func() Memcacher
+		want              []cache.GetResult
+		wantErr           bool
+	}{
+		{
+			name: "with error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetMulti(gomock.Any(), []string{"first", "second"}).
+					Return(nil, errors.New("something went wrong")).
+					Times(1)
+				return mockedClient
+			},
+			wantErr: true,
+		},
+		{
+			name: "without error",
+			getMemcacheClient: func() Memcacher {
+				ctrl := gomock.NewController(t)
+				mockedClient := NewMockMemcacher(ctrl)
+				mockedClient.EXPECT().
+					GetMulti(gomock.Any(), []string{"first", "second"}).
+					Return(map[string]memcacheClient.Item{
+						"first": {
+							Key:   "first",
+							Value: []byte("data"),
+							Flags: 2,
+							CasID: 1,
+						},
+					}, nil).
+					Times(1)
+				return mockedClient
+			},
+			want: []cache.GetResult{
+				{Item: &cache.Item{Value: []byte("data"), Flags: 2, CasID: 1}},
+				{Err: cache.ErrNotFound},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MemcacheStorage{
+				memcacheClient: tt.getMemcacheClient(),
+			}
+			got := s.GetBatch(context.Background(), []string{"first", "second"})
+			assert.Len(t, got, 2)
+			if tt.wantErr {
+				for _, result := range got {
+					assert.Error(t, result.Err)
+					assert.Nil(t, result.Item)
+				}
+			} else {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMemcacheStorage_SetBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockMemcacher(ctrl)
+	mockedClient.EXPECT().
+		SetMulti(gomock.Any(), []*memcacheClient.Item{
+			{Key: "first", Value: []byte("data"), Expiration: 10},
+			{Key: "second", Value: []byte("data")},
+		}).
+		Return([]error{nil, memcacheClient.ErrServerError}).
+		Times(1)
+
+	s := NewMemcacheStorage(mockedClient)
+	errs := s.SetBatch(context.Background(), []cache.Entry{
+		{Key: "first", Value: []byte("data"), TTL: 10 * time.Second},
+		{Key: "second", Value: []byte("data")},
+	})
+	assert.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], memcacheClient.ErrServerError)
+	assert.Equal(t, uint64(1), s.counters.Stats().Sets)
+}
+
+func TestMemcacheStorage_DeleteBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockMemcacher(ctrl)
+	mockedClient.EXPECT().
+		DeleteMulti(gomock.Any(), []string{"first", "second"}).
+		Return([]error{errors.New("something went wrong"), nil}).
+		Times(1)
+
+	s := NewMemcacheStorage(mockedClient)
+	errs := s.DeleteBatch(context.Background(), []string{"first", "second"})
+	assert.Len(t, errs, 2)
+	assert.Error(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Equal(t, uint64(1), s.counters.Stats().Deletes)
+}
+
+func TestMemcacheStorage_ConditionalWrites(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(mockedClient *MockMemcacher)
+		call    func(s *MemcacheStorage) error
+		wantErr error
+	}{
+		{
+			name: "add",
+			prepare: func(mockedClient *MockMemcacher) {
+				mockedClient.EXPECT().Add(gomock.Any(), "testkey", []byte("data"), int64(10)).Return(nil)
+			},
+			call: func(s *MemcacheStorage) error {
+				return s.Add(context.Background(), "testkey", []byte("data"), 10*time.Second)
+			},
+		},
+		{
+			name: "add existing",
+			prepare: func(mockedClient *MockMemcacher) {
+				mockedClient.EXPECT().Add(gomock.Any(), "testkey", []byte("data"), int64(0)).Return(memcacheClient.ErrNotStored)
+			},
+			call: func(s *MemcacheStorage) error {
+				return s.Add(context.Background(), "testkey", []byte("data"), 0)
+			},
+			wantErr: cache.ErrNotStored,
+		},
+		{
+			name: "replace missing",
+			prepare: func(mockedClient *MockMemcacher) {
+				mockedClient.EXPECT().Replace(gomock.Any(), "testkey", []byte("data"), int64(0)).Return(memcacheClient.ErrNotStored)
+			},
+			call: func(s *MemcacheStorage) error {
+				return s.Replace(context.Background(), "testkey", []byte("data"), 0)
+			},
+			wantErr: cache.ErrNotStored,
+		},
+		{
+			name: "compare and swap",
+			prepare: func(mockedClient *MockMemcacher) {
+				mockedClient.EXPECT().
+					CompareAndSwap(gomock.Any(), &memcacheClient.Item{Key: "testkey", Value: []byte("data"), Expiration: 10}, uint64(7)).
+					Return(nil)
+			},
+			call: func(s *MemcacheStorage) error {
+				return s.CompareAndSwap(context.Background(), "testkey", []byte("data"), 10*time.Second, 7)
+			},
+		},
+		{
+			name: "compare and swap modified",
+			prepare: func(mockedClient *MockMemcacher) {
+				mockedClient.EXPECT().CompareAndSwap(gomock.Any(), gomock.Any(), uint64(7)).Return(memcacheClient.ErrExists)
+			},
+			call: func(s *MemcacheStorage) error {
+				return s.CompareAndSwap(context.Background(), "testkey", []byte("data"), 0, 7)
+			},
+			wantErr: cache.ErrExists,
+		},
+		{
+			name: "compare and swap missing",
+			prepare: func(mockedClient *MockMemcacher) {
+				mockedClient.EXPECT().CompareAndSwap(gomock.Any(), gomock.Any(), uint64(7)).Return(memcacheClient.ErrNotFound)
+			},
+			call: func(s *MemcacheStorage) error {
+				return s.CompareAndSwap(context.Background(), "testkey", []byte("data"), 0, 7)
+			},
+			wantErr: cache.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockedClient := NewMockMemcacher(gomock.NewController(t))
+			tt.prepare(mockedClient)
+			s := NewMemcacheStorage(mockedClient)
+
+			err := tt.call(s)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Zero(t, s.counters.Stats().Sets)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(1), s.counters.Stats().Sets)
+			}
+		})
+	}
+}
+
+func TestMemcacheStorage_CheckVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		versions        map[string]string
+		err             error
+		wantErr         bool
+		wantUnsupported bool
+	}{
+		{
+			name: "supported",
+			versions: map[string]string{
+				"127.0.0.1:11211": "1.6.21",
+				"127.0.0.1:11212": "2.0.0",
+			},
+		},
+		{
+			name: "too old",
+			versions: map[string]string{
+				"127.0.0.1:11211": "1.6.21",
+				"127.0.0.1:11212": "1.5.22",
+			},
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+		{
+			name:            "unknown format",
+			versions:        map[string]string{"127.0.0.1:11211": "unknown"},
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+		{
+			name:    "unavailable",
+			err:     errors.New("something went wrong"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockedClient := NewMockMemcacher(ctrl)
+			mockedClient.EXPECT().
+				Version(gomock.Any()).
+				Return(tt.versions, tt.err).
+				Times(1)
+
+			err := NewMemcacheStorage(mockedClient).CheckVersion(context.Background())
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantUnsupported, errors.Is(err, ErrUnsupportedVersion))
+		})
+	}
+}
+
+func Test_expiration(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want int64
+	}{
+		{
+			name: "eternal",
+			ttl:  0,
+			want: 0,
+		},
+		{
+			name: "expired",
+			ttl:  -time.Millisecond,
+			want: -1,
+		},
+		{
+			name: "less than a second",
+			ttl:  time.Millisecond,
+			want: 1,
+		},
+		{
+			name: "fractional seconds",
+			ttl:  1500 * time.Millisecond,
+			want: 2,
+		},
+		{
+			name: "whole seconds",
+			ttl:  time.Minute,
+			want: 60,
+		},
+		{
+			name: "30 days",
+			ttl:  maxRelativeExpiration,
+			want: 60 * 60 * 24 * 30,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, expiration(tt.ttl))
+		})
+	}
+
+	// Время жизни больше 30 дней передаётся как Unix-время истечения
+	ttl := maxRelativeExpiration + time.Hour
+	assert.InDelta(t, time.Now().Add(ttl).Unix(), expiration(ttl), 1)
+}
@@ -0,0 +1,312 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dimuska139/cacher/internal/cache"
+	redisClient "github.com/dimuska139/cacher/libs/redis"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=redis.go -destination=./redis_mock.go -package=redis
+
+// Rediser интерфейс для библиотеки-клиента Redis
+type Rediser interface {
+	GetItem(ctx context.Context, key string) (*redisClient.Item, error)
+	GetMulti(ctx context.Context, keys []string) (map[string][]byte, error)
+	GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*redisClient.Item, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	DeleteExisting(ctx context.Context, key string) error
+	Increment(ctx context.Context, key string, delta uint64) (uint64, error)
+	Decrement(ctx context.Context, key string, delta uint64) (uint64, error)
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+	Info(ctx context.Context) (map[string]map[string]string, error)
+	Ping(ctx context.Context) error
+	SetMulti(ctx context.Context, items []*redisClient.Item) []error
+	DeleteMulti(ctx context.Context, keys []string) []error
+}
+
+// RedisStorage реализация кеша через Redis. Флаги и CAS-идентификаторы Redis не хранит,
+// поэтому в записях они всегда нулевые
+type RedisStorage struct {
+	redisClient Rediser
+	counters    cache.Counters
+}
+
+// NewRedisStorage создаёт реализацию кеша через Redis
+func NewRedisStorage(redisClient Rediser) *RedisStorage {
+	return &RedisStorage{
+		redisClient: redisClient,
+	}
+}
+
+// Get возвращает закешированные данные вместе с оставшимся временем жизни. Если записи нет, возвращается cache.ErrNotFound
+func (s *RedisStorage) Get(ctx context.Context, key string) (*cache.Item, error) {
+	item, err := s.redisClient.GetItem(ctx, key)
+	if err != nil {
+		s.countMiss(err)
+		return nil, fmt.Errorf("can't get data from redis: %w", convertError(err))
+	}
+	s.counters.Hit()
+
+	return convertItem(item), nil
+}
+
+// GetAndTouch возвращает закешированные данные и одновременно устанавливает новое время жизни записи.
+// Если записи нет, возвращается cache.ErrNotFound
+func (s *RedisStorage) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error) {
+	item, err := s.redisClient.GetAndTouch(ctx, key, ttl)
+	if err != nil {
+		s.countMiss(err)
+		return nil, fmt.Errorf("can't get and touch data in redis: %w", convertError(err))
+	}
+	s.counters.Hit()
+
+	return convertItem(item), nil
+}
+
+// GetBatch возвращает записи по нескольким ключам в том же порядке, что и keys. Все ключи одного сервера
+// запрашиваются одной командой MGET, поэтому время жизни в результатах не заполняется. Если записи нет,
+// в результате для неё cache.ErrNotFound. Если хотя бы один сервер недоступен, ошибку получают все ключи
+func (s *RedisStorage) GetBatch(ctx context.Context, keys []string) []cache.GetResult {
+	results := make([]cache.GetResult, len(keys))
+
+	values, err := s.redisClient.GetMulti(ctx, keys)
+	if err != nil {
+		err = fmt.Errorf("can't get data from redis: %w", err)
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	for i, key := range keys {
+		value, ok := values[key]
+		if !ok {
+			s.counters.Miss()
+			results[i].Err = cache.ErrNotFound
+			continue
+		}
+
+		s.counters.Hit()
+		results[i].Item = &cache.Item{
+			Value: value,
+		}
+	}
+
+	return results
+}
+
+// SetBatch записывает несколько записей. Записи одного сервера отправляются ему конвейером команд.
+// Возвращает ошибку для каждой записи в том же порядке, что и entries (nil - запись сохранена)
+func (s *RedisStorage) SetBatch(ctx context.Context, entries []cache.Entry) []error {
+	items := make([]*redisClient.Item, len(entries))
+	for i, entry := range entries {
+		items[i] = &redisClient.Item{
+			Key:   entry.Key,
+			Value: entry.Value,
+			TTL:   entry.TTL,
+		}
+	}
+
+	errs := s.redisClient.SetMulti(ctx, items)
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("can't write data to redis: %w", err)
+			continue
+		}
+		s.counters.Set()
+	}
+
+	return errs
+}
+
+// DeleteBatch удаляет записи по нескольким ключам. Ключи одного сервера отправляются ему конвейером команд.
+// Возвращает ошибку для каждого ключа в том же порядке, что и keys. Отсутствие записи ошибкой не является
+func (s *RedisStorage) DeleteBatch(ctx context.Context, keys []string) []error {
+	errs := s.redisClient.DeleteMulti(ctx, keys)
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("can't delete data from redis: %w", err)
+			continue
+		}
+		s.counters.Delete()
+	}
+
+	return errs
+}
+
+// Set записывает информацию в кеш. Если запись в кеше уже есть, то она обновится
+func (s *RedisStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.redisClient.Set(ctx, key, value, ttl); err != nil {
+		return fmt.Errorf("can't write data to redis: %w", err)
+	}
+	s.counters.Set()
+
+	return nil
+}
+
+// Add записывает информацию в кеш, только если записи с таким ключом ещё нет.
+// Если запись уже есть, возвращается cache.ErrNotStored
+func (s *RedisStorage) Add(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.redisClient.Add(ctx, key, value, ttl); err != nil {
+		return fmt.Errorf("can't add data to redis: %w", convertError(err))
+	}
+	s.counters.Set()
+
+	return nil
+}
+
+// Replace перезаписывает значение, только если запись с таким ключом уже есть.
+// Если записи нет, возвращается cache.ErrNotStored
+func (s *RedisStorage) Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.redisClient.Replace(ctx, key, value, ttl); err != nil {
+		return fmt.Errorf("can't replace data in redis: %w", convertError(err))
+	}
+	s.counters.Set()
+
+	return nil
+}
+
+// Delete удаляет запись из кеша по ключу
+func (s *RedisStorage) Delete(ctx context.Context, key string) error {
+	if err := s.redisClient.Delete(ctx, key); err != nil {
+		return fmt.Errorf("can't delete data from redis: %w", err)
+	}
+	s.counters.Delete()
+
+	return nil
+}
+
+// DeleteExisting удаляет запись из кеша по ключу. Если записи нет, возвращается cache.ErrNotFound
+func (s *RedisStorage) DeleteExisting(ctx context.Context, key string) error {
+	if err := s.redisClient.DeleteExisting(ctx, key); err != nil {
+		return fmt.Errorf("can't delete data from redis: %w", convertError(err))
+	}
+	s.counters.Delete()
+
+	return nil
+}
+
+// Touch устанавливает новое время жизни записи
+func (s *RedisStorage) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if err := s.redisClient.Touch(ctx, key, ttl); err != nil {
+		return fmt.Errorf("can't touch data in redis: %w", convertError(err))
+	}
+
+	return nil
+}
+
+// Increment увеличивает числовое значение записи на delta и возвращает новое значение
+func (s *RedisStorage) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	value, err := s.redisClient.Increment(ctx, key, delta)
+	if err != nil {
+		return 0, fmt.Errorf("can't increment value in redis: %w", convertError(err))
+	}
+
+	return value, nil
+}
+
+// Decrement уменьшает числовое значение записи на delta и возвращает новое значение
+func (s *RedisStorage) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	value, err := s.redisClient.Decrement(ctx, key, delta)
+	if err != nil {
+		return 0, fmt.Errorf("can't decrement value in redis: %w", convertError(err))
+	}
+
+	return value, nil
+}
+
+// Stats возвращает статистику операций хранилища вместе с показателями серверов Redis.
+// Количество записей (по всем базам данных), занятая память, вытеснения и истечения суммируются по всем серверам
+func (s *RedisStorage) Stats(ctx context.Context) (*cache.Stats, error) {
+	servers, err := s.redisClient.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get stats from redis: %w", err)
+	}
+
+	stats := s.counters.Stats()
+	stats.Servers = servers
+	for _, info := range servers {
+		stats.Items += keyspaceKeys(info)
+		stats.Bytes += statValue(info, "used_memory")
+		stats.Evictions += statValue(info, "evicted_keys")
+		stats.Expirations += statValue(info, "expired_keys")
+	}
+
+	return stats, nil
+}
+
+// HealthCheck проверяет доступность всех серверов Redis: каждый сервер должен ответить на команду PING
+func (s *RedisStorage) HealthCheck(ctx context.Context) error {
+	if err := s.redisClient.Ping(ctx); err != nil {
+		return fmt.Errorf("redis is unavailable: %w", err)
+	}
+
+	return nil
+}
+
+// countMiss учитывает промах, если записи нет в кеше. Остальные ошибки в статистике не учитываются
+func (s *RedisStorage) countMiss(err error) {
+	if errors.Is(err, redisClient.ErrNotFound) {
+		s.counters.Miss()
+	}
+}
+
+// statValue возвращает числовой показатель сервера Redis. Отсутствующие и нечисловые значения считаются нулевыми
+func statValue(info map[string]string, name string) uint64 {
+	value, err := strconv.ParseUint(info[name], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// keyspaceKeys возвращает количество ключей во всех базах данных сервера Redis
+// по строкам секции Keyspace вида "db0:keys=1,expires=0,avg_ttl=0"
+func keyspaceKeys(info map[string]string) uint64 {
+	var keys uint64
+	for name, value := range info {
+		if !strings.HasPrefix(name, "db") {
+			continue
+		}
+		if _, err := strconv.Atoi(name[len("db"):]); err != nil {
+			continue
+		}
+
+		for _, field := range strings.Split(value, ",") {
+			if count, ok := strings.CutPrefix(field, "keys="); ok {
+				n, _ := strconv.ParseUint(count, 10, 64)
+				keys += n
+			}
+		}
+	}
+	return keys
+}
+
+// convertItem преобразует запись Redis в запись хранилища
+func convertItem(item *redisClient.Item) *cache.Item {
+	return &cache.Item{
+		Value: item.Value,
+		TTL:   item.TTL,
+	}
+}
+
+// convertError дополняет ошибки библиотеки-клиента Redis соответствующими ошибками хранилища
+func convertError(err error) error {
+	switch {
+	case errors.Is(err, redisClient.ErrNotFound):
+		return fmt.Errorf("%w: %w", cache.ErrNotFound, err)
+	case errors.Is(err, redisClient.ErrNonNumeric):
+		return fmt.Errorf("%w: %w", cache.ErrNotNumeric, err)
+	case errors.Is(err, redisClient.ErrNotStored):
+		return fmt.Errorf("%w: %w", cache.ErrNotStored, err)
+	}
+	return err
+}
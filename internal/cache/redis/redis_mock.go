@@ -0,0 +1,253 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: redis.go
+
+// Package redis is a generated GoMock package.
+package redis
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	redis "github.com/dimuska139/cacher/libs/redis"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRediser is a mock of Rediser interface.
+type MockRediser struct {
+	ctrl     *gomock.Controller
+	recorder *MockRediserMockRecorder
+}
+
+// MockRediserMockRecorder is the mock recorder for MockRediser.
+type MockRediserMockRecorder struct {
+	mock *MockRediser
+}
+
+// NewMockRediser creates a new mock instance.
+func NewMockRediser(ctrl *gomock.Controller) *MockRediser {
+	mock := &MockRediser{ctrl: ctrl}
+	mock.recorder = &MockRediserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRediser) EXPECT() *MockRediserMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockRediser) Add(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockRediserMockRecorder) Add(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRediser)(nil).Add), ctx, key, value, ttl)
+}
+
+// Decrement mocks base method.
+func (m *MockRediser) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrement", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrement indicates an expected call of Decrement.
+func (mr *MockRediserMockRecorder) Decrement(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrement", reflect.TypeOf((*MockRediser)(nil).Decrement), ctx, key, delta)
+}
+
+// Delete mocks base method.
+func (m *MockRediser) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRediserMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRediser)(nil).Delete), ctx, key)
+}
+
+// DeleteExisting mocks base method.
+func (m *MockRediser) DeleteExisting(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExisting", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExisting indicates an expected call of DeleteExisting.
+func (mr *MockRediserMockRecorder) DeleteExisting(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExisting", reflect.TypeOf((*MockRediser)(nil).DeleteExisting), ctx, key)
+}
+
+// DeleteMulti mocks base method.
+func (m *MockRediser) DeleteMulti(ctx context.Context, keys []string) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMulti", ctx, keys)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// DeleteMulti indicates an expected call of DeleteMulti.
+func (mr *MockRediserMockRecorder) DeleteMulti(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMulti", reflect.TypeOf((*MockRediser)(nil).DeleteMulti), ctx, keys)
+}
+
+// GetAndTouch mocks base method.
+func (m *MockRediser) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*redis.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAndTouch", ctx, key, ttl)
+	ret0, _ := ret[0].(*redis.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAndTouch indicates an expected call of GetAndTouch.
+func (mr *MockRediserMockRecorder) GetAndTouch(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAndTouch", reflect.TypeOf((*MockRediser)(nil).GetAndTouch), ctx, key, ttl)
+}
+
+// GetItem mocks base method.
+func (m *MockRediser) GetItem(ctx context.Context, key string) (*redis.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, key)
+	ret0, _ := ret[0].(*redis.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockRediserMockRecorder) GetItem(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockRediser)(nil).GetItem), ctx, key)
+}
+
+// GetMulti mocks base method.
+func (m *MockRediser) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMulti", ctx, keys)
+	ret0, _ := ret[0].(map[string][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMulti indicates an expected call of GetMulti.
+func (mr *MockRediserMockRecorder) GetMulti(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMulti", reflect.TypeOf((*MockRediser)(nil).GetMulti), ctx, keys)
+}
+
+// Increment mocks base method.
+func (m *MockRediser) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockRediserMockRecorder) Increment(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockRediser)(nil).Increment), ctx, key, delta)
+}
+
+// Info mocks base method.
+func (m *MockRediser) Info(ctx context.Context) (map[string]map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info", ctx)
+	ret0, _ := ret[0].(map[string]map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockRediserMockRecorder) Info(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockRediser)(nil).Info), ctx)
+}
+
+// Ping mocks base method.
+func (m *MockRediser) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockRediserMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRediser)(nil).Ping), ctx)
+}
+
+// Replace mocks base method.
+func (m *MockRediser) Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockRediserMockRecorder) Replace(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockRediser)(nil).Replace), ctx, key, value, ttl)
+}
+
+// Set mocks base method.
+func (m *MockRediser) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockRediserMockRecorder) Set(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockRediser)(nil).Set), ctx, key, value, ttl)
+}
+
+// SetMulti mocks base method.
+func (m *MockRediser) SetMulti(ctx context.Context, items []*redis.Item) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMulti", ctx, items)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// SetMulti indicates an expected call of SetMulti.
+func (mr *MockRediserMockRecorder) SetMulti(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMulti", reflect.TypeOf((*MockRediser)(nil).SetMulti), ctx, items)
+}
+
+// Touch mocks base method.
+func (m *MockRediser) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, key, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockRediserMockRecorder) Touch(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockRediser)(nil).Touch), ctx, key, ttl)
+}
@@ -0,0 +1,259 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/dimuska139/cacher/internal/cache"
+	redisClient "github.com/dimuska139/cacher/libs/redis"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRedisStorage_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		getRedisClient func(ctrl *gomock.Controller) Rediser
+		want           *cache.Item
+		wantErr        error
+	}{
+		{
+			name: "with error",
+			getRedisClient: func(ctrl *gomock.Controller) Rediser {
+				mockedClient := NewMockRediser(ctrl)
+				mockedClient.EXPECT().
+					GetItem(gomock.Any(), "testkey").
+					Return(nil, redisClient.ErrMalformedResponse).
+					Times(1)
+				return mockedClient
+			},
+			wantErr: redisClient.ErrMalformedResponse,
+		},
+		{
+			name: "not found",
+			getRedisClient: func(ctrl *gomock.Controller) Rediser {
+				mockedClient := NewMockRediser(ctrl)
+				mockedClient.EXPECT().
+					GetItem(gomock.Any(), "testkey").
+					Return(nil, redisClient.ErrNotFound).
+					Times(1)
+				return mockedClient
+			},
+			wantErr: cache.ErrNotFound,
+		},
+		{
+			name: "without error",
+			getRedisClient: func(ctrl *gomock.Controller) Rediser {
+				mockedClient := NewMockRediser(ctrl)
+				mockedClient.EXPECT().
+					GetItem(gomock.Any(), "testkey").
+					Return(&redisClient.Item{Key: "testkey", Value: []byte("value"), TTL: time.Minute}, nil).
+					Times(1)
+				return mockedClient
+			},
+			want: &cache.Item{Value: []byte("value"), TTL: time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewRedisStorage(tt.getRedisClient(gomock.NewController(t)))
+
+			got, err := s.Get(context.Background(), "testkey")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRedisStorage_GetAndTouch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockRediser(ctrl)
+	gomock.InOrder(
+		mockedClient.EXPECT().
+			GetAndTouch(gomock.Any(), "testkey", time.Hour).
+			Return(&redisClient.Item{Key: "testkey", Value: []byte("value"), TTL: time.Hour}, nil),
+		mockedClient.EXPECT().
+			GetAndTouch(gomock.Any(), "missing", time.Hour).
+			Return(nil, redisClient.ErrNotFound),
+	)
+	s := NewRedisStorage(mockedClient)
+
+	got, err := s.GetAndTouch(context.Background(), "testkey", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, &cache.Item{Value: []byte("value"), TTL: time.Hour}, got)
+
+	_, err = s.GetAndTouch(context.Background(), "missing", time.Hour)
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+}
+
+func TestRedisStorage_Writes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockRediser(ctrl)
+	gomock.InOrder(
+		mockedClient.EXPECT().Set(gomock.Any(), "testkey", []byte("value"), time.Minute).Return(nil),
+		mockedClient.EXPECT().Set(gomock.Any(), "testkey", []byte("value"), time.Duration(0)).
+			Return(errors.New("something went wrong")),
+		mockedClient.EXPECT().Add(gomock.Any(), "testkey", []byte("value"), time.Duration(0)).
+			Return(redisClient.ErrNotStored),
+		mockedClient.EXPECT().Replace(gomock.Any(), "testkey", []byte("value"), time.Duration(0)).
+			Return(redisClient.ErrNotStored),
+		mockedClient.EXPECT().Replace(gomock.Any(), "testkey", []byte("value"), time.Duration(0)).Return(nil),
+		mockedClient.EXPECT().Touch(gomock.Any(), "testkey", time.Minute).Return(redisClient.ErrNotFound),
+		mockedClient.EXPECT().Delete(gomock.Any(), "testkey").Return(nil),
+		mockedClient.EXPECT().Delete(gomock.Any(), "testkey").Return(errors.New("something went wrong")),
+		mockedClient.EXPECT().DeleteExisting(gomock.Any(), "testkey").Return(nil),
+		mockedClient.EXPECT().DeleteExisting(gomock.Any(), "testkey").Return(redisClient.ErrNotFound),
+	)
+	s := NewRedisStorage(mockedClient)
+	ctx := context.Background()
+
+	assert.NoError(t, s.Set(ctx, "testkey", []byte("value"), time.Minute))
+	assert.Error(t, s.Set(ctx, "testkey", []byte("value"), 0))
+	assert.ErrorIs(t, s.Add(ctx, "testkey", []byte("value"), 0), cache.ErrNotStored)
+	assert.ErrorIs(t, s.Replace(ctx, "testkey", []byte("value"), 0), cache.ErrNotStored)
+	assert.NoError(t, s.Replace(ctx, "testkey", []byte("value"), 0))
+	assert.ErrorIs(t, s.Touch(ctx, "testkey", time.Minute), cache.ErrNotFound)
+	assert.NoError(t, s.Delete(ctx, "testkey"))
+	assert.Error(t, s.Delete(ctx, "testkey"))
+	assert.NoError(t, s.DeleteExisting(ctx, "testkey"))
+	assert.ErrorIs(t, s.DeleteExisting(ctx, "testkey"), cache.ErrNotFound)
+
+	stats := s.counters.Stats()
+	assert.Equal(t, uint64(2), stats.Sets)
+	assert.Equal(t, uint64(2), stats.Deletes)
+}
+
+func TestRedisStorage_IncrementDecrement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockRediser(ctrl)
+	gomock.InOrder(
+		mockedClient.EXPECT().Increment(gomock.Any(), "counter", uint64(5)).Return(uint64(15), nil),
+		mockedClient.EXPECT().Increment(gomock.Any(), "missing", uint64(1)).Return(uint64(0), redisClient.ErrNotFound),
+		mockedClient.EXPECT().Decrement(gomock.Any(), "text", uint64(1)).Return(uint64(0), redisClient.ErrNonNumeric),
+		mockedClient.EXPECT().Decrement(gomock.Any(), "counter", uint64(20)).Return(uint64(0), nil),
+	)
+	s := NewRedisStorage(mockedClient)
+	ctx := context.Background()
+
+	value, err := s.Increment(ctx, "counter", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(15), value)
+
+	_, err = s.Increment(ctx, "missing", 1)
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+
+	_, err = s.Decrement(ctx, "text", 1)
+	assert.ErrorIs(t, err, cache.ErrNotNumeric)
+
+	value, err = s.Decrement(ctx, "counter", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), value)
+}
+
+func TestRedisStorage_Batch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockRediser(ctrl)
+	unavailable := errors.New("connection refused")
+	gomock.InOrder(
+		mockedClient.EXPECT().
+			GetMulti(gomock.Any(), []string{"first", "second"}).
+			Return(map[string][]byte{"first": []byte("1")}, nil),
+		mockedClient.EXPECT().
+			GetMulti(gomock.Any(), []string{"first", "second"}).
+			Return(nil, unavailable),
+		mockedClient.EXPECT().
+			SetMulti(gomock.Any(), []*redisClient.Item{
+				{Key: "first", Value: []byte("1"), TTL: time.Minute},
+				{Key: "second", Value: []byte("2")},
+			}).
+			Return([]error{nil, unavailable}),
+		mockedClient.EXPECT().
+			DeleteMulti(gomock.Any(), []string{"first", "second"}).
+			Return([]error{unavailable, nil}),
+	)
+	s := NewRedisStorage(mockedClient)
+	ctx := context.Background()
+
+	results := s.GetBatch(ctx, []string{"first", "second"})
+	assert.Equal(t, []cache.GetResult{
+		{Item: &cache.Item{Value: []byte("1")}},
+		{Err: cache.ErrNotFound},
+	}, results)
+
+	results = s.GetBatch(ctx, []string{"first", "second"})
+	assert.ErrorIs(t, results[0].Err, unavailable)
+	assert.ErrorIs(t, results[1].Err, unavailable)
+
+	errs := s.SetBatch(ctx, []cache.Entry{
+		{Key: "first", Value: []byte("1"), TTL: time.Minute},
+		{Key: "second", Value: []byte("2")},
+	})
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], unavailable)
+
+	errs = s.DeleteBatch(ctx, []string{"first", "second"})
+	assert.ErrorIs(t, errs[0], unavailable)
+	assert.NoError(t, errs[1])
+
+	stats := s.counters.Stats()
+	assert.Equal(t, uint64(1), stats.GetHits)
+	assert.Equal(t, uint64(1), stats.GetMisses)
+	assert.Equal(t, uint64(1), stats.Sets)
+	assert.Equal(t, uint64(1), stats.Deletes)
+}
+
+func TestRedisStorage_Stats(t *testing.T) {
+	servers := map[string]map[string]string{
+		"10.0.0.1:6379": {
+			"used_memory":  "1000",
+			"evicted_keys": "2",
+			"expired_keys": "3",
+			"db0":          "keys=10,expires=1,avg_ttl=0",
+			"db1":          "keys=5,expires=0,avg_ttl=0",
+		},
+		"10.0.0.2:6379": {
+			"used_memory":  "500",
+			"evicted_keys": "not a number",
+			"db0":          "keys=1,expires=0,avg_ttl=0",
+			"dbsize":       "keys=100",
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockRediser(ctrl)
+	gomock.InOrder(
+		mockedClient.EXPECT().GetItem(gomock.Any(), "missing").Return(nil, redisClient.ErrNotFound),
+		mockedClient.EXPECT().Info(gomock.Any()).Return(servers, nil),
+		mockedClient.EXPECT().Info(gomock.Any()).Return(nil, errors.New("connection refused")),
+	)
+	s := NewRedisStorage(mockedClient)
+	_, _ = s.Get(context.Background(), "missing")
+
+	stats, err := s.Stats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &cache.Stats{
+		GetMisses:   1,
+		Items:       16,
+		Bytes:       1500,
+		Evictions:   2,
+		Expirations: 3,
+		Servers:     servers,
+	}, stats)
+
+	_, err = s.Stats(context.Background())
+	assert.Error(t, err)
+}
+
+func TestRedisStorage_HealthCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockedClient := NewMockRediser(ctrl)
+	gomock.InOrder(
+		mockedClient.EXPECT().Ping(gomock.Any()).Return(nil),
+		mockedClient.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused")),
+	)
+	s := NewRedisStorage(mockedClient)
+
+	assert.NoError(t, s.HealthCheck(context.Background()))
+	assert.ErrorContains(t, s.HealthCheck(context.Background()), "redis is unavailable")
+}
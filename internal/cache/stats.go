@@ -0,0 +1,64 @@
+package cache
+
+import "sync/atomic"
+
+// Stats статистика работы хранилища
+type Stats struct {
+	// Количество чтений, нашедших запись
+	GetHits uint64
+	// Количество чтений, не нашедших запись
+	GetMisses uint64
+	// Количество записей значений
+	Sets uint64
+	// Количество удалений
+	Deletes uint64
+	// Количество записей, вытесненных из-за нехватки места
+	Evictions uint64
+	// Количество записей, удалённых по истечении времени жизни
+	Expirations uint64
+	// Текущее количество записей
+	Items uint64
+	// Текущий суммарный размер записей в байтах
+	Bytes uint64
+	// Статистика отдельных серверов хранилища (ключ - адрес сервера), если хранилище распределённое
+	Servers map[string]map[string]string
+}
+
+// Counters счётчики операций хранилища. Счётчики атомарные, поэтому их можно увеличивать
+// из разных горутин без блокировок
+type Counters struct {
+	getHits   atomic.Uint64
+	getMisses atomic.Uint64
+	sets      atomic.Uint64
+	deletes   atomic.Uint64
+}
+
+// Hit учитывает чтение, нашедшее запись
+func (c *Counters) Hit() {
+	c.getHits.Add(1)
+}
+
+// Miss учитывает чтение, не нашедшее запись
+func (c *Counters) Miss() {
+	c.getMisses.Add(1)
+}
+
+// Set учитывает запись значения
+func (c *Counters) Set() {
+	c.sets.Add(1)
+}
+
+// Delete учитывает удаление
+func (c *Counters) Delete() {
+	c.deletes.Add(1)
+}
+
+// Stats возвращает статистику с текущими значениями счётчиков
+func (c *Counters) Stats() *Stats {
+	return &Stats{
+		GetHits:   c.getHits.Load(),
+		GetMisses: c.getMisses.Load(),
+		Sets:      c.sets.Load(),
+		Deletes:   c.deletes.Load(),
+	}
+}
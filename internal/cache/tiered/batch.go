@@ -0,0 +1,73 @@
+package tiered
+
+import (
+	"context"
+	"github.com/dimuska139/cacher/internal/cache"
+)
+
+// GetBatch возвращает записи по нескольким ключам в том же порядке, что и keys. Ключи, на которые L1 ответить
+// не может, запрашиваются у L2 одним пакетом и сохраняются в L1. Если записи нет, в результате для неё cache.ErrNotFound
+func (s *TieredStorage) GetBatch(ctx context.Context, keys []string) []cache.GetResult {
+	results := make([]cache.GetResult, len(keys))
+
+	var (
+		missing  []string
+		indices  []int
+		versions []uint64
+	)
+	for i, local := range s.l1.GetBatch(ctx, keys) {
+		item, found, err := s.lookup(local.Item, local.Err)
+		if found {
+			results[i] = cache.GetResult{Item: item, Err: err}
+			continue
+		}
+
+		missing = append(missing, keys[i])
+		indices = append(indices, i)
+		versions = append(versions, s.version(keys[i]).Load())
+	}
+
+	if len(missing) == 0 {
+		return results
+	}
+
+	for n, remote := range s.l2.GetBatch(ctx, missing) {
+		s.fill(missing[n], versions[n], remote.Item, remote.Err)
+
+		item, err := s.count(remote.Item, remote.Err)
+		results[indices[n]] = cache.GetResult{Item: item, Err: err}
+	}
+
+	return results
+}
+
+// SetBatch записывает несколько записей в L2 одним пакетом, после чего обновляет L1 в соответствии с политикой записи.
+// Возвращает ошибку для каждой записи в том же порядке, что и entries (nil - запись сохранена)
+func (s *TieredStorage) SetBatch(ctx context.Context, entries []cache.Entry) []error {
+	capped := make([]cache.Entry, len(entries))
+	for i, entry := range entries {
+		entry.TTL = capTTL(entry.TTL, s.config.L2MaxTTL())
+		capped[i] = entry
+	}
+
+	errs := s.l2.SetBatch(ctx, capped)
+	for i, entry := range capped {
+		s.written(entry.Key, entry.Value, entry.TTL, errs[i])
+	}
+
+	return errs
+}
+
+// DeleteBatch удаляет записи по нескольким ключам из L2 одним пакетом и из L1.
+// Возвращает ошибку для каждого ключа в том же порядке, что и keys. Отсутствие записи ошибкой не является
+func (s *TieredStorage) DeleteBatch(ctx context.Context, keys []string) []error {
+	errs := s.l2.DeleteBatch(ctx, keys)
+	for i, key := range keys {
+		s.invalidate(key)
+		if errs[i] == nil {
+			s.counters.Delete()
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,113 @@
+package tiered
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTieredStorage_GetBatch(t *testing.T) {
+	s, _, l2 := newTestStorage(t, NewConfig(time.Minute).WithNegativeTTL(time.Minute))
+	ctx := context.Background()
+
+	l2.EXPECT().Get(gomock.Any(), "cached").Return(&cache.Item{Value: []byte("cached")}, nil).Times(1)
+	_, err := s.Get(ctx, "cached")
+	assert.NoError(t, err)
+
+	// L2 запрашиваются только ключи, которых нет в L1
+	l2.EXPECT().
+		GetBatch(gomock.Any(), []string{"key", "missing"}).
+		Return([]cache.GetResult{
+			{Item: &cache.Item{Value: []byte("value")}},
+			{Err: cache.ErrNotFound},
+		}).
+		Times(1)
+
+	keys := []string{"key", "cached", "missing"}
+	for i := 0; i < 2; i++ {
+		results := s.GetBatch(ctx, keys)
+		assert.Len(t, results, len(keys))
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, []byte("value"), results[0].Item.Value)
+		assert.NoError(t, results[1].Err)
+		assert.Equal(t, []byte("cached"), results[1].Item.Value)
+		assert.ErrorIs(t, results[2].Err, cache.ErrNotFound)
+		assert.Nil(t, results[2].Item)
+	}
+}
+
+func TestTieredStorage_GetBatch_Error(t *testing.T) {
+	s, _, l2 := newTestStorage(t, NewConfig(time.Minute).WithNegativeTTL(time.Minute))
+	ctx := context.Background()
+
+	unavailable := errors.New("memcache is unavailable")
+	l2.EXPECT().
+		GetBatch(gomock.Any(), []string{"key"}).
+		Return([]cache.GetResult{{Err: unavailable}}).
+		Times(2)
+
+	for i := 0; i < 2; i++ {
+		results := s.GetBatch(ctx, []string{"key"})
+		assert.ErrorIs(t, results[0].Err, unavailable)
+	}
+}
+
+func TestTieredStorage_SetBatch(t *testing.T) {
+	config := NewConfig(time.Minute).WithL2MaxTTL(time.Hour).WithWritePolicy(WriteThrough)
+	s, l1, l2 := newTestStorage(t, config)
+	ctx := context.Background()
+
+	l2.EXPECT().
+		SetBatch(gomock.Any(), []cache.Entry{
+			{Key: "key", Value: []byte("value"), TTL: time.Hour},
+			{Key: "failed", Value: []byte("value"), TTL: time.Second},
+		}).
+		Return([]error{nil, errors.New("something went wrong")}).
+		Times(1)
+
+	errs := s.SetBatch(ctx, []cache.Entry{
+		{Key: "key", Value: []byte("value")},
+		{Key: "failed", Value: []byte("value"), TTL: time.Second},
+	})
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+
+	item, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), item.Value)
+
+	_, err = l1.Get(ctx, "failed")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+
+	stats, err := l1.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), stats.Items)
+}
+
+func TestTieredStorage_DeleteBatch(t *testing.T) {
+	s, l1, l2 := newTestStorage(t, NewConfig(time.Minute).WithWritePolicy(WriteThrough))
+	ctx := context.Background()
+
+	l2.EXPECT().Set(gomock.Any(), gomock.Any(), []byte("value"), time.Duration(0)).Return(nil).Times(2)
+	assert.NoError(t, s.Set(ctx, "first", []byte("value"), 0))
+	assert.NoError(t, s.Set(ctx, "second", []byte("value"), 0))
+
+	l2.EXPECT().
+		DeleteBatch(gomock.Any(), []string{"first", "second"}).
+		Return([]error{nil, errors.New("something went wrong")}).
+		Times(1)
+
+	errs := s.DeleteBatch(ctx, []string{"first", "second"})
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+
+	// Из L1 удаляются все ключи: состояние записи в L2 после ошибки неизвестно
+	stats, err := l1.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), stats.Items)
+}
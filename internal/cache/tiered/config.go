@@ -0,0 +1,105 @@
+package tiered
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultL1MaxTTL максимальное время жизни записи в L1 по умолчанию
+const DefaultL1MaxTTL = 5 * time.Second
+
+// WritePolicy определяет, что происходит с записью в L1 после изменения значения в L2
+type WritePolicy string
+
+const (
+	// WriteInvalidate удалять запись из L1: следующее чтение получит значение из L2
+	WriteInvalidate WritePolicy = "invalidate"
+	// WriteThrough записывать новое значение и в L1. Идентификатор версии (CasID) такой записи неизвестен,
+	// поэтому до следующего чтения из L2 она возвращается с нулевым CasID: CompareAndSwap с ним завершится
+	// ошибкой cache.ErrExists и удалит запись L1. Запись с помощью CompareAndSwap всегда удаляет запись L1
+	WriteThrough WritePolicy = "write-through"
+	// DefaultWritePolicy политика записи по умолчанию
+	DefaultWritePolicy = WriteInvalidate
+)
+
+// ParseWritePolicy возвращает политику записи по названию
+func ParseWritePolicy(name string) (WritePolicy, error) {
+	switch policy := WritePolicy(name); policy {
+	case WriteInvalidate, WriteThrough:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown tiered write policy: %s", name)
+}
+
+// Config конфигурация двухуровневого кеша
+type Config struct {
+	l1MaxTTL    time.Duration
+	l2MaxTTL    time.Duration
+	negativeTTL time.Duration
+	writePolicy WritePolicy
+}
+
+// NewConfig создаёт конфигурацию двухуровневого кеша с максимальным временем жизни записи в L1
+// (0 - DefaultL1MaxTTL). По умолчанию время жизни в L2 не ограничено, а промахи не кешируются
+func NewConfig(l1MaxTTL time.Duration) *Config {
+	return &Config{
+		l1MaxTTL: l1MaxTTL,
+	}
+}
+
+// L1MaxTTL возвращает максимальное время жизни записи в L1. Оно же ограничивает время, в течение которого
+// L1 может возвращать устаревшее значение, изменённое в L2 в обход этого экземпляра сервиса
+func (c *Config) L1MaxTTL() time.Duration {
+	if c.l1MaxTTL <= 0 {
+		return DefaultL1MaxTTL
+	}
+	return c.l1MaxTTL
+}
+
+// WithL2MaxTTL устанавливает максимальное время жизни записи в L2 (0 - без ограничений).
+// Бессрочные записи тоже получают это время жизни
+func (c *Config) WithL2MaxTTL(ttl time.Duration) *Config {
+	c.l2MaxTTL = ttl
+	return c
+}
+
+// L2MaxTTL возвращает максимальное время жизни записи в L2 (0 - без ограничений)
+func (c *Config) L2MaxTTL() time.Duration {
+	return c.l2MaxTTL
+}
+
+// WithNegativeTTL устанавливает время, в течение которого L1 помнит об отсутствии записи в L2 (0 - промахи не кешируются)
+func (c *Config) WithNegativeTTL(ttl time.Duration) *Config {
+	c.negativeTTL = ttl
+	return c
+}
+
+// NegativeTTL возвращает время, в течение которого L1 помнит об отсутствии записи в L2. Оно не больше L1MaxTTL
+func (c *Config) NegativeTTL() time.Duration {
+	if c.negativeTTL <= 0 {
+		return 0
+	}
+	return capTTL(c.negativeTTL, c.L1MaxTTL())
+}
+
+// WithWritePolicy устанавливает политику записи
+func (c *Config) WithWritePolicy(policy WritePolicy) *Config {
+	c.writePolicy = policy
+	return c
+}
+
+// WritePolicy возвращает политику записи (по умолчанию DefaultWritePolicy)
+func (c *Config) WritePolicy() WritePolicy {
+	if c.writePolicy == "" {
+		return DefaultWritePolicy
+	}
+	return c.writePolicy
+}
+
+// capTTL ограничивает время жизни значением max (0 - без ограничений). Бессрочное время жизни (0) тоже ограничивается
+func capTTL(ttl time.Duration, max time.Duration) time.Duration {
+	if max > 0 && (ttl <= 0 || ttl > max) {
+		return max
+	}
+	return ttl
+}
@@ -0,0 +1,72 @@
+package tiered
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/dimuska139/cacher/internal/cache"
+	"time"
+)
+
+// Типы записей L1
+const (
+	// entryValue значение из L2 вместе с его метаданными
+	entryValue byte = iota + 1
+	// entryMissing записи в L2 нет
+	entryMissing
+)
+
+// entryHeaderSize размер заголовка записи L1 со значением: тип, момент истечения времени жизни в L2,
+// идентификатор версии и флаги
+const entryHeaderSize = 1 + 8 + 8 + 4
+
+// errStaleEntry запись L1 не может быть использована (повреждена или время жизни значения в L2 уже истекло),
+// поэтому значение нужно прочитать из L2
+var errStaleEntry = errors.New("stale entry")
+
+// encodeItem кодирует значение L2 в запись L1. Метаданные сохраняются вместе со значением, чтобы при чтении из L1
+// возвращались время жизни, идентификатор версии и флаги записи L2, а не L1
+func encodeItem(item *cache.Item, now time.Time) []byte {
+	var expiration int64
+	if item.TTL > 0 {
+		expiration = now.Add(item.TTL).UnixNano()
+	}
+
+	data := make([]byte, entryHeaderSize+len(item.Value))
+	data[0] = entryValue
+	binary.BigEndian.PutUint64(data[1:], uint64(expiration))
+	binary.BigEndian.PutUint64(data[9:], item.CasID)
+	binary.BigEndian.PutUint32(data[17:], item.Flags)
+	copy(data[entryHeaderSize:], item.Value)
+
+	return data
+}
+
+// encodeMissing кодирует запись L1 об отсутствии записи в L2
+func encodeMissing() []byte {
+	return []byte{entryMissing}
+}
+
+// decodeEntry декодирует запись L1. Если в L2 записи нет, возвращается cache.ErrNotFound,
+// если запись L1 использовать нельзя - errStaleEntry
+func decodeEntry(data []byte, now time.Time) (*cache.Item, error) {
+	if len(data) == 1 && data[0] == entryMissing {
+		return nil, cache.ErrNotFound
+	}
+	if len(data) < entryHeaderSize || data[0] != entryValue {
+		return nil, errStaleEntry
+	}
+
+	item := &cache.Item{
+		Value: data[entryHeaderSize:],
+		CasID: binary.BigEndian.Uint64(data[9:]),
+		Flags: binary.BigEndian.Uint32(data[17:]),
+	}
+	if expiration := int64(binary.BigEndian.Uint64(data[1:])); expiration != 0 {
+		item.TTL = time.Unix(0, expiration).Sub(now)
+		if item.TTL <= 0 {
+			return nil, errStaleEntry
+		}
+	}
+
+	return item, nil
+}
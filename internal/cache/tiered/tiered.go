@@ -0,0 +1,341 @@
+package tiered
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dimuska139/cacher/internal/cache"
+	"hash/maphash"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+//go:generate mockgen -source=tiered.go -destination=./tiered_mock.go -package=tiered
+
+// L1StatsKey ключ статистики L1 в cache.Stats.Servers
+const L1StatsKey = "l1"
+
+// versionStripes количество счётчиков версий ключей. Ключи распределяются по счётчикам хешем,
+// поэтому изменение одного ключа может помешать заполнению L1 другими ключами того же счётчика, но не наоборот
+const versionStripes = 256
+
+// L1Storage интерфейс для быстрого локального хранилища первого уровня (встроенного кеша)
+type L1Storage interface {
+	Get(ctx context.Context, key string) (*cache.Item, error)
+	GetBatch(ctx context.Context, keys []string) []cache.GetResult
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Stats(ctx context.Context) (*cache.Stats, error)
+	HealthCheck(ctx context.Context) error
+}
+
+// L2Storage интерфейс для общего хранилища второго уровня (Memcache)
+type L2Storage interface {
+	Get(ctx context.Context, key string) (*cache.Item, error)
+	GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error)
+	GetBatch(ctx context.Context, keys []string) []cache.GetResult
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	SetBatch(ctx context.Context, entries []cache.Entry) []error
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	CompareAndSwap(ctx context.Context, key string, value []byte, ttl time.Duration, casID uint64) error
+	Delete(ctx context.Context, key string) error
+	DeleteExisting(ctx context.Context, key string) error
+	DeleteBatch(ctx context.Context, keys []string) []error
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+	Increment(ctx context.Context, key string, delta uint64) (uint64, error)
+	Decrement(ctx context.Context, key string, delta uint64) (uint64, error)
+	Stats(ctx context.Context) (*cache.Stats, error)
+	HealthCheck(ctx context.Context) error
+}
+
+// TieredStorage двухуровневый кеш: небольшой локальный L1 с коротким временем жизни записей перед общим L2.
+// Чтение сначала ищет запись в L1, а при промахе читает её из L2 и сохраняет в L1 (в том числе, если включено,
+// отсутствие записи). Все изменения выполняются в L2, после чего запись L1 удаляется или обновляется
+// в соответствии с WritePolicy. L1 не знает об изменениях L2, сделанных другими экземплярами сервиса,
+// поэтому может возвращать устаревшие значения не дольше Config.L1MaxTTL
+type TieredStorage struct {
+	config *Config
+	l1     L1Storage
+	l2     L2Storage
+
+	counters cache.Counters
+	// Количество чтений, обслуженных L1, в том числе промахов из-за отсутствия записи в L2
+	l1Hits atomic.Uint64
+	// Количество чтений, обслуженных записями L1 об отсутствии записи в L2
+	negativeHits atomic.Uint64
+
+	seed maphash.Seed
+	// Версии ключей: увеличиваются при каждом изменении, чтобы чтение из L2, начатое до изменения,
+	// не записало в L1 старое значение
+	versions [versionStripes]atomic.Uint64
+}
+
+// NewTieredStorage создаёт двухуровневый кеш
+func NewTieredStorage(config *Config, l1 L1Storage, l2 L2Storage) *TieredStorage {
+	return &TieredStorage{
+		config: config,
+		l1:     l1,
+		l2:     l2,
+		seed:   maphash.MakeSeed(),
+	}
+}
+
+// Get возвращает закешированные данные вместе с метаданными. Если записи нет, возвращается cache.ErrNotFound
+func (s *TieredStorage) Get(ctx context.Context, key string) (*cache.Item, error) {
+	local, err := s.l1.Get(ctx, key)
+	if item, found, err := s.lookup(local, err); found {
+		return item, err
+	}
+
+	version := s.version(key).Load()
+	item, err := s.l2.Get(ctx, key)
+	s.fill(key, version, item, err)
+
+	return s.count(item, err)
+}
+
+// GetAndTouch возвращает закешированные данные и одновременно устанавливает новое время жизни записи в L2.
+// Если записи нет, возвращается cache.ErrNotFound
+func (s *TieredStorage) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error) {
+	version := s.version(key).Load()
+	item, err := s.l2.GetAndTouch(ctx, key, capTTL(ttl, s.config.L2MaxTTL()))
+	s.fill(key, version, item, err)
+
+	return s.count(item, err)
+}
+
+// Set записывает информацию в кеш. Если запись в кеше уже есть, то она обновится
+func (s *TieredStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ttl = capTTL(ttl, s.config.L2MaxTTL())
+	err := s.l2.Set(ctx, key, value, ttl)
+	s.written(key, value, ttl, err)
+
+	return err
+}
+
+// Add записывает информацию в кеш, только если записи с таким ключом ещё нет.
+// Если запись уже есть, возвращается cache.ErrNotStored
+func (s *TieredStorage) Add(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ttl = capTTL(ttl, s.config.L2MaxTTL())
+	err := s.l2.Add(ctx, key, value, ttl)
+	s.written(key, value, ttl, err)
+
+	return err
+}
+
+// Replace перезаписывает значение, только если запись с таким ключом уже есть.
+// Если записи нет, возвращается cache.ErrNotStored
+func (s *TieredStorage) Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ttl = capTTL(ttl, s.config.L2MaxTTL())
+	err := s.l2.Replace(ctx, key, value, ttl)
+	s.written(key, value, ttl, err)
+
+	return err
+}
+
+// CompareAndSwap перезаписывает значение, только если с момента чтения запись не менялась.
+// Если запись изменилась, возвращается cache.ErrExists, если записи нет - cache.ErrNotFound.
+// Запись L1 удаляется при любой политике записи: клиентам, которые используют CAS, нужен новый CasID
+// записи, а он известен только L2. Неудачная попытка (в том числе с нулевым CasID записи, сохранённой
+// с WriteThrough) тоже удаляет запись L1, поэтому следующее чтение вернёт действительный CasID
+func (s *TieredStorage) CompareAndSwap(ctx context.Context, key string, value []byte, ttl time.Duration, casID uint64) error {
+	ttl = capTTL(ttl, s.config.L2MaxTTL())
+	err := s.l2.CompareAndSwap(ctx, key, value, ttl, casID)
+	if err == nil {
+		s.counters.Set()
+	}
+	s.invalidate(key)
+
+	return err
+}
+
+// Delete удаляет запись из кеша по ключу
+func (s *TieredStorage) Delete(ctx context.Context, key string) error {
+	err := s.l2.Delete(ctx, key)
+	s.invalidate(key)
+	if err != nil {
+		return err
+	}
+	s.counters.Delete()
+
+	return nil
+}
+
+// DeleteExisting удаляет запись из кеша по ключу. Если записи в L2 нет, возвращается cache.ErrNotFound
+func (s *TieredStorage) DeleteExisting(ctx context.Context, key string) error {
+	err := s.l2.DeleteExisting(ctx, key)
+	s.invalidate(key)
+	if err != nil {
+		return err
+	}
+	s.counters.Delete()
+
+	return nil
+}
+
+// Touch устанавливает новое время жизни записи
+func (s *TieredStorage) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	err := s.l2.Touch(ctx, key, capTTL(ttl, s.config.L2MaxTTL()))
+	s.invalidate(key)
+
+	return err
+}
+
+// Increment увеличивает числовое значение записи на delta и возвращает новое значение.
+// Время жизни записи после изменения неизвестно, поэтому запись L1 удаляется при любой политике записи
+func (s *TieredStorage) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	value, err := s.l2.Increment(ctx, key, delta)
+	s.invalidate(key)
+
+	return value, err
+}
+
+// Decrement уменьшает числовое значение записи на delta и возвращает новое значение.
+// Как и Increment, удаляет запись L1
+func (s *TieredStorage) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	value, err := s.l2.Decrement(ctx, key, delta)
+	s.invalidate(key)
+
+	return value, err
+}
+
+// Stats возвращает статистику L2, в которой количество операций заменено количеством операций с двухуровневым
+// кешем. Статистика L1 (в том числе количество обслуженных им чтений) добавляется в Servers с ключом L1StatsKey
+func (s *TieredStorage) Stats(ctx context.Context) (*cache.Stats, error) {
+	stats, err := s.l2.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l1Stats, err := s.l1.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get l1 stats: %w", err)
+	}
+
+	counters := s.counters.Stats()
+	stats.GetHits = counters.GetHits
+	stats.GetMisses = counters.GetMisses
+	stats.Sets = counters.Sets
+	stats.Deletes = counters.Deletes
+
+	servers := make(map[string]map[string]string, len(stats.Servers)+1)
+	for address, serverStats := range stats.Servers {
+		servers[address] = serverStats
+	}
+	servers[L1StatsKey] = map[string]string{
+		"curr_items":    strconv.FormatUint(l1Stats.Items, 10),
+		"bytes":         strconv.FormatUint(l1Stats.Bytes, 10),
+		"evictions":     strconv.FormatUint(l1Stats.Evictions, 10),
+		"expirations":   strconv.FormatUint(l1Stats.Expirations, 10),
+		"get_hits":      strconv.FormatUint(s.l1Hits.Load(), 10),
+		"negative_hits": strconv.FormatUint(s.negativeHits.Load(), 10),
+	}
+	stats.Servers = servers
+
+	return stats, nil
+}
+
+// HealthCheck проверяет работоспособность обоих уровней кеша
+func (s *TieredStorage) HealthCheck(ctx context.Context) error {
+	if err := s.l1.HealthCheck(ctx); err != nil {
+		return fmt.Errorf("l1 is unhealthy: %w", err)
+	}
+
+	return s.l2.HealthCheck(ctx)
+}
+
+// lookup разбирает результат чтения из L1. found = false, если L1 ответить не может и запись нужно читать из L2
+func (s *TieredStorage) lookup(local *cache.Item, localErr error) (item *cache.Item, found bool, err error) {
+	if localErr != nil {
+		return nil, false, nil
+	}
+
+	item, err = decodeEntry(local.Value, time.Now())
+	if errors.Is(err, errStaleEntry) {
+		return nil, false, nil
+	}
+
+	s.l1Hits.Add(1)
+	if errors.Is(err, cache.ErrNotFound) {
+		s.negativeHits.Add(1)
+	}
+
+	item, err = s.count(item, err)
+	return item, true, err
+}
+
+// count учитывает результат чтения в статистике
+func (s *TieredStorage) count(item *cache.Item, err error) (*cache.Item, error) {
+	switch {
+	case err == nil:
+		s.counters.Hit()
+	case errors.Is(err, cache.ErrNotFound):
+		s.counters.Miss()
+	}
+	return item, err
+}
+
+// version возвращает счётчик версий ключа
+func (s *TieredStorage) version(key string) *atomic.Uint64 {
+	return &s.versions[maphash.String(s.seed, key)%versionStripes]
+}
+
+// fill сохраняет в L1 результат чтения из L2, если с начала чтения (версия version) ключ не изменялся.
+// Ошибки L1 не мешают работе кеша, поэтому игнорируются
+func (s *TieredStorage) fill(key string, version uint64, item *cache.Item, err error) {
+	if s.version(key).Load() != version {
+		return
+	}
+
+	switch {
+	case err == nil:
+		s.store(key, item)
+	case errors.Is(err, cache.ErrNotFound) && s.config.NegativeTTL() > 0:
+		_ = s.l1.Set(context.Background(), key, encodeMissing(), s.config.NegativeTTL())
+	default:
+		return
+	}
+
+	// Если ключ изменился между проверкой версии и записью в L1, то изменение могло удалить запись L1 раньше,
+	// чем она была записана, поэтому записанное значение может быть устаревшим
+	if s.version(key).Load() != version {
+		_ = s.l1.Delete(context.Background(), key)
+	}
+}
+
+// written обновляет L1 после записи значения в L2 в соответствии с политикой записи.
+// Если запись в L2 не удалась, запись L1 удаляется: значение в L2 могло измениться
+func (s *TieredStorage) written(key string, value []byte, ttl time.Duration, err error) {
+	if err == nil {
+		s.counters.Set()
+	}
+	if err != nil || s.config.WritePolicy() != WriteThrough {
+		s.invalidate(key)
+		return
+	}
+
+	s.version(key).Add(1)
+	s.store(key, &cache.Item{
+		Value: value,
+		TTL:   ttl,
+	})
+}
+
+// store записывает значение L2 в L1 с ограниченным временем жизни. Если записать не удалось (например,
+// значение слишком большое для L1), то старая запись L1 удаляется, чтобы не возвращать устаревшее значение
+func (s *TieredStorage) store(key string, item *cache.Item) {
+	// L1 обновляется и после отмены запроса: иначе в нём останется значение, уже изменённое в L2
+	ctx := context.Background()
+	if err := s.l1.Set(ctx, key, encodeItem(item, time.Now()), capTTL(item.TTL, s.config.L1MaxTTL())); err != nil {
+		_ = s.l1.Delete(ctx, key)
+	}
+}
+
+// invalidate удаляет запись из L1 и увеличивает версию ключа, чтобы чтение из L2, начатое до изменения,
+// не записало в L1 старое значение
+func (s *TieredStorage) invalidate(key string) {
+	s.version(key).Add(1)
+	_ = s.l1.Delete(context.Background(), key)
+}
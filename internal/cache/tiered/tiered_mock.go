@@ -0,0 +1,375 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tiered.go
+
+// Package tiered is a generated GoMock package.
+package tiered
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	cache "github.com/dimuska139/cacher/internal/cache"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockL1Storage is a mock of L1Storage interface.
+type MockL1Storage struct {
+	ctrl     *gomock.Controller
+	recorder *MockL1StorageMockRecorder
+}
+
+// MockL1StorageMockRecorder is the mock recorder for MockL1Storage.
+type MockL1StorageMockRecorder struct {
+	mock *MockL1Storage
+}
+
+// NewMockL1Storage creates a new mock instance.
+func NewMockL1Storage(ctrl *gomock.Controller) *MockL1Storage {
+	mock := &MockL1Storage{ctrl: ctrl}
+	mock.recorder = &MockL1StorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockL1Storage) EXPECT() *MockL1StorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockL1Storage) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockL1StorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockL1Storage)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockL1Storage) Get(ctx context.Context, key string) (*cache.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*cache.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockL1StorageMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockL1Storage)(nil).Get), ctx, key)
+}
+
+// GetBatch mocks base method.
+func (m *MockL1Storage) GetBatch(ctx context.Context, keys []string) []cache.GetResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, keys)
+	ret0, _ := ret[0].([]cache.GetResult)
+	return ret0
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockL1StorageMockRecorder) GetBatch(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockL1Storage)(nil).GetBatch), ctx, keys)
+}
+
+// HealthCheck mocks base method.
+func (m *MockL1Storage) HealthCheck(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthCheck", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealthCheck indicates an expected call of HealthCheck.
+func (mr *MockL1StorageMockRecorder) HealthCheck(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockL1Storage)(nil).HealthCheck), ctx)
+}
+
+// Set mocks base method.
+func (m *MockL1Storage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockL1StorageMockRecorder) Set(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockL1Storage)(nil).Set), ctx, key, value, ttl)
+}
+
+// Stats mocks base method.
+func (m *MockL1Storage) Stats(ctx context.Context) (*cache.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(*cache.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockL1StorageMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockL1Storage)(nil).Stats), ctx)
+}
+
+// MockL2Storage is a mock of L2Storage interface.
+type MockL2Storage struct {
+	ctrl     *gomock.Controller
+	recorder *MockL2StorageMockRecorder
+}
+
+// MockL2StorageMockRecorder is the mock recorder for MockL2Storage.
+type MockL2StorageMockRecorder struct {
+	mock *MockL2Storage
+}
+
+// NewMockL2Storage creates a new mock instance.
+func NewMockL2Storage(ctrl *gomock.Controller) *MockL2Storage {
+	mock := &MockL2Storage{ctrl: ctrl}
+	mock.recorder = &MockL2StorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockL2Storage) EXPECT() *MockL2StorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockL2Storage) Add(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockL2StorageMockRecorder) Add(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockL2Storage)(nil).Add), ctx, key, value, ttl)
+}
+
+// CompareAndSwap mocks base method.
+func (m *MockL2Storage) CompareAndSwap(ctx context.Context, key string, value []byte, ttl time.Duration, casID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSwap", ctx, key, value, ttl, casID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareAndSwap indicates an expected call of CompareAndSwap.
+func (mr *MockL2StorageMockRecorder) CompareAndSwap(ctx, key, value, ttl, casID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSwap", reflect.TypeOf((*MockL2Storage)(nil).CompareAndSwap), ctx, key, value, ttl, casID)
+}
+
+// Decrement mocks base method.
+func (m *MockL2Storage) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrement", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrement indicates an expected call of Decrement.
+func (mr *MockL2StorageMockRecorder) Decrement(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrement", reflect.TypeOf((*MockL2Storage)(nil).Decrement), ctx, key, delta)
+}
+
+// Delete mocks base method.
+func (m *MockL2Storage) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockL2StorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockL2Storage)(nil).Delete), ctx, key)
+}
+
+// DeleteBatch mocks base method.
+func (m *MockL2Storage) DeleteBatch(ctx context.Context, keys []string) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBatch", ctx, keys)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// DeleteBatch indicates an expected call of DeleteBatch.
+func (mr *MockL2StorageMockRecorder) DeleteBatch(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockL2Storage)(nil).DeleteBatch), ctx, keys)
+}
+
+// DeleteExisting mocks base method.
+func (m *MockL2Storage) DeleteExisting(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExisting", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExisting indicates an expected call of DeleteExisting.
+func (mr *MockL2StorageMockRecorder) DeleteExisting(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExisting", reflect.TypeOf((*MockL2Storage)(nil).DeleteExisting), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockL2Storage) Get(ctx context.Context, key string) (*cache.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*cache.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockL2StorageMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockL2Storage)(nil).Get), ctx, key)
+}
+
+// GetAndTouch mocks base method.
+func (m *MockL2Storage) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*cache.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAndTouch", ctx, key, ttl)
+	ret0, _ := ret[0].(*cache.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAndTouch indicates an expected call of GetAndTouch.
+func (mr *MockL2StorageMockRecorder) GetAndTouch(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAndTouch", reflect.TypeOf((*MockL2Storage)(nil).GetAndTouch), ctx, key, ttl)
+}
+
+// GetBatch mocks base method.
+func (m *MockL2Storage) GetBatch(ctx context.Context, keys []string) []cache.GetResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, keys)
+	ret0, _ := ret[0].([]cache.GetResult)
+	return ret0
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockL2StorageMockRecorder) GetBatch(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockL2Storage)(nil).GetBatch), ctx, keys)
+}
+
+// HealthCheck mocks base method.
+func (m *MockL2Storage) HealthCheck(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthCheck", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealthCheck indicates an expected call of HealthCheck.
+func (mr *MockL2StorageMockRecorder) HealthCheck(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockL2Storage)(nil).HealthCheck), ctx)
+}
+
+// Increment mocks base method.
+func (m *MockL2Storage) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, delta)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockL2StorageMockRecorder) Increment(ctx, key, delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockL2Storage)(nil).Increment), ctx, key, delta)
+}
+
+// Replace mocks base method.
+func (m *MockL2Storage) Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockL2StorageMockRecorder) Replace(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockL2Storage)(nil).Replace), ctx, key, value, ttl)
+}
+
+// Set mocks base method.
+func (m *MockL2Storage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockL2StorageMockRecorder) Set(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockL2Storage)(nil).Set), ctx, key, value, ttl)
+}
+
+// SetBatch mocks base method.
+func (m *MockL2Storage) SetBatch(ctx context.Context, entries []cache.Entry) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBatch", ctx, entries)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// SetBatch indicates an expected call of SetBatch.
+func (mr *MockL2StorageMockRecorder) SetBatch(ctx, entries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBatch", reflect.TypeOf((*MockL2Storage)(nil).SetBatch), ctx, entries)
+}
+
+// Stats mocks base method.
+func (m *MockL2Storage) Stats(ctx context.Context) (*cache.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(*cache.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockL2StorageMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockL2Storage)(nil).Stats), ctx)
+}
+
+// Touch mocks base method.
+func (m *MockL2Storage) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, key, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockL2StorageMockRecorder) Touch(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockL2Storage)(nil).Touch), ctx, key, ttl)
+}
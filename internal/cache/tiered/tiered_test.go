@@ -0,0 +1,495 @@
+package tiered
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/dimuska139/cacher/internal/cache/embedded"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStorage(t *testing.T, config *Config) (*TieredStorage, *embedded.EmbeddedStorage, *MockL2Storage) {
+	ctrl := gomock.NewController(t)
+	l1 := embedded.NewEmbeddedStorage(embedded.NewConfig(time.Hour))
+	l2 := NewMockL2Storage(ctrl)
+
+	return NewTieredStorage(config, l1, l2), l1, l2
+}
+
+func TestTieredStorage_Get(t *testing.T) {
+	s, l1, l2 := newTestStorage(t, NewConfig(time.Minute))
+	ctx := context.Background()
+
+	l2.EXPECT().
+		Get(gomock.Any(), "key").
+		Return(&cache.Item{Value: []byte("value"), Flags: 3, CasID: 42, TTL: time.Hour}, nil).
+		Times(1)
+
+	for i := 0; i < 3; i++ {
+		item, err := s.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), item.Value)
+		assert.Equal(t, uint32(3), item.Flags)
+		assert.Equal(t, uint64(42), item.CasID)
+		// Время жизни - оставшееся время жизни в L2, а не в L1
+		assert.InDelta(t, time.Hour, item.TTL, float64(time.Second))
+	}
+
+	local, err := l1.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Minute, local.TTL, float64(time.Second))
+}
+
+func TestTieredStorage_Get_Miss(t *testing.T) {
+	tests := []struct {
+		name        string
+		negativeTTL time.Duration
+		err         error
+		wantCalls   int
+	}{
+		{
+			name:      "without negative caching",
+			err:       cache.ErrNotFound,
+			wantCalls: 3,
+		},
+		{
+			name:        "with negative caching",
+			negativeTTL: time.Minute,
+			err:         cache.ErrNotFound,
+			wantCalls:   1,
+		},
+		{
+			name:        "errors are not cached",
+			negativeTTL: time.Minute,
+			err:         errors.New("something went wrong"),
+			wantCalls:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, l2 := newTestStorage(t, NewConfig(time.Minute).WithNegativeTTL(tt.negativeTTL))
+			l2.EXPECT().
+				Get(gomock.Any(), "key").
+				Return(nil, tt.err).
+				Times(tt.wantCalls)
+
+			for i := 0; i < 3; i++ {
+				item, err := s.Get(context.Background(), "key")
+				assert.Nil(t, item)
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTieredStorage_Get_ConcurrentWrite(t *testing.T) {
+	s, _, l2 := newTestStorage(t, NewConfig(time.Minute))
+	ctx := context.Background()
+
+	// Значение изменяется, пока чтение из L2 ещё не завершено: старое значение не должно попасть в L1
+	l2.EXPECT().
+		Get(gomock.Any(), "key").
+		DoAndReturn(func(ctx context.Context, key string) (*cache.Item, error) {
+			assert.NoError(t, s.Set(ctx, key, []byte("new"), 0))
+			return &cache.Item{Value: []byte("old")}, nil
+		}).
+		Times(1)
+	l2.EXPECT().Set(gomock.Any(), "key", []byte("new"), time.Duration(0)).Return(nil).Times(1)
+	l2.EXPECT().Get(gomock.Any(), "key").Return(&cache.Item{Value: []byte("new")}, nil).Times(1)
+
+	item, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("old"), item.Value)
+
+	item, err = s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("new"), item.Value)
+}
+
+func TestTieredStorage_Get_WriteDuringFill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	l1 := NewMockL1Storage(ctrl)
+	l2 := NewMockL2Storage(ctrl)
+	s := NewTieredStorage(NewConfig(time.Minute), l1, l2)
+	ctx := context.Background()
+
+	// Запись L1 старым значением задерживается, пока значение изменяется и запись L1 удаляется:
+	// после этого старое значение должно быть удалено из L1
+	filling := make(chan struct{})
+	release := make(chan struct{})
+	l1.EXPECT().Get(gomock.Any(), "key").Return(nil, cache.ErrNotFound).Times(1)
+	l2.EXPECT().Get(gomock.Any(), "key").Return(&cache.Item{Value: []byte("old")}, nil).Times(1)
+	l2.EXPECT().Set(gomock.Any(), "key", []byte("new"), time.Duration(0)).Return(nil).Times(1)
+	gomock.InOrder(
+		l1.EXPECT().
+			Set(gomock.Any(), "key", gomock.Any(), time.Minute).
+			DoAndReturn(func(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+				close(filling)
+				<-release
+				return nil
+			}).
+			Times(1),
+		l1.EXPECT().Delete(gomock.Any(), "key").Return(nil).Times(2),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		item, err := s.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("old"), item.Value)
+	}()
+
+	<-filling
+	assert.NoError(t, s.Set(ctx, "key", []byte("new"), 0))
+	close(release)
+	<-done
+}
+
+func TestTieredStorage_GetAndTouch(t *testing.T) {
+	s, _, l2 := newTestStorage(t, NewConfig(time.Minute).WithL2MaxTTL(time.Hour))
+	ctx := context.Background()
+
+	l2.EXPECT().
+		GetAndTouch(gomock.Any(), "key", time.Hour).
+		Return(&cache.Item{Value: []byte("value"), TTL: time.Hour}, nil).
+		Times(1)
+
+	item, err := s.GetAndTouch(ctx, "key", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), item.Value)
+
+	// Запись уже в L1
+	item, err = s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), item.Value)
+}
+
+func TestTieredStorage_Writes(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy WritePolicy
+		write  func(s *TieredStorage, l2 *MockL2Storage) error
+		// Значение, которое должно оказаться в L1 после записи (nil - записи в L1 нет)
+		want []byte
+	}{
+		{
+			name:   "set with invalidation",
+			policy: WriteInvalidate,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Set(gomock.Any(), "key", []byte("new"), time.Hour).Return(nil).Times(1)
+				return s.Set(context.Background(), "key", []byte("new"), time.Hour)
+			},
+		},
+		{
+			name:   "set with write-through",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Set(gomock.Any(), "key", []byte("new"), time.Hour).Return(nil).Times(1)
+				return s.Set(context.Background(), "key", []byte("new"), time.Hour)
+			},
+			want: []byte("new"),
+		},
+		{
+			name:   "failed set with write-through",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Set(gomock.Any(), "key", []byte("new"), time.Hour).Return(errors.New("something went wrong")).Times(1)
+				return s.Set(context.Background(), "key", []byte("new"), time.Hour)
+			},
+		},
+		{
+			name:   "add with write-through",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Add(gomock.Any(), "key", []byte("new"), time.Hour).Return(cache.ErrNotStored).Times(1)
+				return s.Add(context.Background(), "key", []byte("new"), time.Hour)
+			},
+		},
+		{
+			name:   "replace with write-through",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Replace(gomock.Any(), "key", []byte("new"), time.Hour).Return(nil).Times(1)
+				return s.Replace(context.Background(), "key", []byte("new"), time.Hour)
+			},
+			want: []byte("new"),
+		},
+		{
+			name:   "compare and swap invalidates with write-through",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().CompareAndSwap(gomock.Any(), "key", []byte("new"), time.Hour, uint64(1)).Return(nil).Times(1)
+				return s.CompareAndSwap(context.Background(), "key", []byte("new"), time.Hour, 1)
+			},
+		},
+		{
+			name:   "delete",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Delete(gomock.Any(), "key").Return(nil).Times(1)
+				return s.Delete(context.Background(), "key")
+			},
+		},
+		{
+			name:   "delete existing",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().DeleteExisting(gomock.Any(), "key").Return(nil).Times(1)
+				return s.DeleteExisting(context.Background(), "key")
+			},
+		},
+		{
+			name:   "touch",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Touch(gomock.Any(), "key", time.Hour).Return(nil).Times(1)
+				return s.Touch(context.Background(), "key", time.Hour)
+			},
+		},
+		{
+			name:   "increment",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Increment(gomock.Any(), "key", uint64(1)).Return(uint64(2), nil).Times(1)
+				_, err := s.Increment(context.Background(), "key", 1)
+				return err
+			},
+		},
+		{
+			name:   "decrement",
+			policy: WriteThrough,
+			write: func(s *TieredStorage, l2 *MockL2Storage) error {
+				l2.EXPECT().Decrement(gomock.Any(), "key", uint64(1)).Return(uint64(0), nil).Times(1)
+				_, err := s.Decrement(context.Background(), "key", 1)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, l1, l2 := newTestStorage(t, NewConfig(time.Minute).WithWritePolicy(tt.policy))
+			ctx := context.Background()
+
+			l2.EXPECT().Get(gomock.Any(), "key").Return(&cache.Item{Value: []byte("old")}, nil).Times(1)
+			_, err := s.Get(ctx, "key")
+			assert.NoError(t, err)
+
+			_ = tt.write(s, l2)
+
+			local, err := l1.Get(ctx, "key")
+			if tt.want == nil {
+				assert.ErrorIs(t, err, cache.ErrNotFound)
+				return
+			}
+
+			assert.NoError(t, err)
+			item, err := decodeEntry(local.Value, time.Now())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, item.Value)
+			assert.InDelta(t, time.Hour, item.TTL, float64(time.Second))
+			assert.InDelta(t, time.Minute, local.TTL, float64(time.Second))
+		})
+	}
+}
+
+func TestTieredStorage_WriteThrough_CompareAndSwap(t *testing.T) {
+	s, l1, l2 := newTestStorage(t, NewConfig(time.Minute).WithWritePolicy(WriteThrough))
+	ctx := context.Background()
+
+	l2.EXPECT().Set(gomock.Any(), "key", []byte("value"), time.Duration(0)).Return(nil).Times(1)
+	assert.NoError(t, s.Set(ctx, "key", []byte("value"), 0))
+
+	// CasID записи, сохранённой в L1 при записи, неизвестен
+	item, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Zero(t, item.CasID)
+
+	// Неудачная попытка удаляет запись L1, и следующее чтение возвращает CasID из L2
+	l2.EXPECT().CompareAndSwap(gomock.Any(), "key", []byte("new"), time.Duration(0), uint64(0)).
+		Return(cache.ErrExists).Times(1)
+	assert.ErrorIs(t, s.CompareAndSwap(ctx, "key", []byte("new"), 0, item.CasID), cache.ErrExists)
+
+	l2.EXPECT().Get(gomock.Any(), "key").Return(&cache.Item{Value: []byte("value"), CasID: 7}, nil).Times(1)
+	item, err = s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), item.CasID)
+
+	l2.EXPECT().CompareAndSwap(gomock.Any(), "key", []byte("new"), time.Duration(0), uint64(7)).Return(nil).Times(1)
+	assert.NoError(t, s.CompareAndSwap(ctx, "key", []byte("new"), 0, item.CasID))
+
+	_, err = l1.Get(ctx, "key")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+}
+
+func TestTieredStorage_Set_NegativeEntry(t *testing.T) {
+	s, _, l2 := newTestStorage(t, NewConfig(time.Minute).WithNegativeTTL(time.Minute))
+	ctx := context.Background()
+
+	gomock.InOrder(
+		l2.EXPECT().Get(gomock.Any(), "key").Return(nil, cache.ErrNotFound).Times(1),
+		l2.EXPECT().Set(gomock.Any(), "key", []byte("value"), time.Duration(0)).Return(nil).Times(1),
+		l2.EXPECT().Get(gomock.Any(), "key").Return(&cache.Item{Value: []byte("value")}, nil).Times(1),
+	)
+
+	_, err := s.Get(ctx, "key")
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+
+	assert.NoError(t, s.Set(ctx, "key", []byte("value"), 0))
+
+	item, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), item.Value)
+}
+
+func TestTieredStorage_TTLCaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		wantL2   time.Duration
+		wantL1   time.Duration
+		l2MaxTTL time.Duration
+	}{
+		{
+			name:   "short ttl",
+			ttl:    time.Second * 30,
+			wantL2: time.Second * 30,
+			wantL1: time.Second * 30,
+		},
+		{
+			name:   "without l2 cap",
+			ttl:    0,
+			wantL2: 0,
+			wantL1: time.Minute,
+		},
+		{
+			name:     "with l2 cap",
+			ttl:      0,
+			l2MaxTTL: time.Hour,
+			wantL2:   time.Hour,
+			wantL1:   time.Minute,
+		},
+		{
+			name:     "longer than l2 cap",
+			ttl:      time.Hour * 2,
+			l2MaxTTL: time.Hour,
+			wantL2:   time.Hour,
+			wantL1:   time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig(time.Minute).WithL2MaxTTL(tt.l2MaxTTL).WithWritePolicy(WriteThrough)
+			s, l1, l2 := newTestStorage(t, config)
+			ctx := context.Background()
+
+			l2.EXPECT().Set(gomock.Any(), "key", []byte("value"), tt.wantL2).Return(nil).Times(1)
+			assert.NoError(t, s.Set(ctx, "key", []byte("value"), tt.ttl))
+
+			local, err := l1.Get(ctx, "key")
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.wantL1, local.TTL, float64(time.Second))
+
+			item, err := s.Get(ctx, "key")
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.wantL2, item.TTL, float64(time.Second))
+		})
+	}
+}
+
+func TestTieredStorage_Stats(t *testing.T) {
+	s, _, l2 := newTestStorage(t, NewConfig(time.Minute).WithNegativeTTL(time.Minute))
+	ctx := context.Background()
+
+	l2.EXPECT().Get(gomock.Any(), "key").Return(&cache.Item{Value: []byte("value")}, nil).Times(1)
+	l2.EXPECT().Get(gomock.Any(), "missing").Return(nil, cache.ErrNotFound).Times(1)
+	l2.EXPECT().Stats(gomock.Any()).Return(&cache.Stats{
+		GetHits: 100,
+		Items:   10,
+		Servers: map[string]map[string]string{
+			"127.0.0.1:11211": {"curr_items": "10"},
+		},
+	}, nil).Times(1)
+
+	for i := 0; i < 3; i++ {
+		_, _ = s.Get(ctx, "key")
+		_, _ = s.Get(ctx, "missing")
+	}
+
+	stats, err := s.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), stats.GetHits)
+	assert.Equal(t, uint64(3), stats.GetMisses)
+	assert.Equal(t, uint64(10), stats.Items)
+	assert.Equal(t, map[string]string{"curr_items": "10"}, stats.Servers["127.0.0.1:11211"])
+	assert.Equal(t, "2", stats.Servers[L1StatsKey]["curr_items"])
+	assert.Equal(t, "4", stats.Servers[L1StatsKey]["get_hits"])
+	assert.Equal(t, "2", stats.Servers[L1StatsKey]["negative_hits"])
+}
+
+func TestTieredStorage_HealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{
+			name: "healthy",
+		},
+		{
+			name:    "l2 is unavailable",
+			err:     errors.New("memcache is unavailable"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, l2 := newTestStorage(t, NewConfig(time.Minute))
+			l2.EXPECT().HealthCheck(gomock.Any()).Return(tt.err).Times(1)
+
+			err := s.HealthCheck(context.Background())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDecodeEntry(t *testing.T) {
+	now := time.Now()
+	data := encodeItem(&cache.Item{Value: []byte("value"), Flags: 1, CasID: 2, TTL: time.Minute}, now)
+
+	item, err := decodeEntry(data, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, &cache.Item{Value: []byte("value"), Flags: 1, CasID: 2, TTL: time.Second * 59}, item)
+
+	// Время жизни значения в L2 истекло
+	_, err = decodeEntry(data, now.Add(time.Minute))
+	assert.ErrorIs(t, err, errStaleEntry)
+
+	_, err = decodeEntry(encodeMissing(), now)
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+
+	_, err = decodeEntry([]byte("value"), now)
+	assert.ErrorIs(t, err, errStaleEntry)
+}
+
+func TestParseWritePolicy(t *testing.T) {
+	policy, err := ParseWritePolicy("write-through")
+	assert.NoError(t, err)
+	assert.Equal(t, WriteThrough, policy)
+
+	_, err = ParseWritePolicy("write-back")
+	assert.Error(t, err)
+
+	config := NewConfig(0).WithNegativeTTL(time.Hour)
+	assert.Equal(t, DefaultL1MaxTTL, config.L1MaxTTL())
+	assert.Equal(t, DefaultL1MaxTTL, config.NegativeTTL())
+	assert.Equal(t, DefaultWritePolicy, config.WritePolicy())
+}
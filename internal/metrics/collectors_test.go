@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+	"github.com/dimuska139/cacher/libs/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+// statsProviderFunc позволяет использовать функцию в качестве StatsProvider
+type statsProviderFunc func(ctx context.Context) (*cache.Stats, error)
+
+func (f statsProviderFunc) Stats(ctx context.Context) (*cache.Stats, error) {
+	return f(ctx)
+}
+
+// poolStatsProviderFunc позволяет использовать функцию в качестве PoolStatsProvider
+type poolStatsProviderFunc func() map[string]memcache.PoolStats
+
+func (f poolStatsProviderFunc) PoolStats() map[string]memcache.PoolStats {
+	return f()
+}
+
+// testLogger запоминает сообщения об ошибках
+type testLogger struct {
+	messages []string
+}
+
+func (l *testLogger) Error(msg string, _ ...interface{}) {
+	l.messages = append(l.messages, msg)
+}
+
+// values возвращает значения метрик без меток по именам
+func values(families []Family) map[string]float64 {
+	values := make(map[string]float64, len(families))
+	for _, family := range families {
+		values[family.Name] = family.Samples[0].Value
+	}
+	return values
+}
+
+func TestStorageCollector_Collect(t *testing.T) {
+	tests := []struct {
+		name         string
+		storage      StatsProvider
+		want         map[string]float64
+		wantMessages []string
+	}{
+		{
+			name: "without error",
+			storage: statsProviderFunc(func(ctx context.Context) (*cache.Stats, error) {
+				_, ok := ctx.Deadline()
+				assert.True(t, ok)
+				return &cache.Stats{
+					GetHits:     10,
+					GetMisses:   5,
+					Sets:        7,
+					Deletes:     2,
+					Evictions:   3,
+					Expirations: 1,
+					Items:       4,
+					Bytes:       100,
+				}, nil
+			}),
+			want: map[string]float64{
+				"cacher_storage_get_hits_total":    10,
+				"cacher_storage_get_misses_total":  5,
+				"cacher_storage_sets_total":        7,
+				"cacher_storage_deletes_total":     2,
+				"cacher_storage_evictions_total":   3,
+				"cacher_storage_expirations_total": 1,
+				"cacher_storage_items":             4,
+				"cacher_storage_bytes":             100,
+			},
+		},
+		{
+			name: "with error",
+			storage: statsProviderFunc(func(ctx context.Context) (*cache.Stats, error) {
+				return nil, errors.New("something went wrong")
+			}),
+			want:         map[string]float64{},
+			wantMessages: []string{"Can't get stats from storage"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &testLogger{}
+			collector := NewStorageCollector(tt.storage, logger, time.Second)
+			assert.Equal(t, tt.want, values(collector.Collect()))
+			assert.Equal(t, tt.wantMessages, logger.messages)
+		})
+	}
+}
+
+func TestPoolCollector_Collect(t *testing.T) {
+	collector := NewPoolCollector(poolStatsProviderFunc(func() map[string]memcache.PoolStats {
+		return map[string]memcache.PoolStats{
+			"127.0.0.1:11212": {Open: 1, Idle: 1},
+			"127.0.0.1:11211": {
+				Open:         5,
+				Idle:         2,
+				InUse:        3,
+				Waiting:      1,
+				WaitCount:    4,
+				WaitDuration: 1500 * time.Millisecond,
+				DialErrors:   2,
+			},
+		}
+	}))
+
+	families := collector.Collect()
+	assert.Len(t, families, 7)
+	for _, family := range families {
+		assert.Len(t, family.Samples, 2, family.Name)
+		assert.Equal(t, []Label{{Name: "server", Value: "127.0.0.1:11211"}}, family.Samples[0].Labels)
+	}
+	assert.Equal(t, map[string]float64{
+		"cacher_memcache_pool_open_connections":   5,
+		"cacher_memcache_pool_idle_connections":   2,
+		"cacher_memcache_pool_in_use_connections": 3,
+		"cacher_memcache_pool_waiting":            1,
+		"cacher_memcache_pool_waits_total":        4,
+		"cacher_memcache_pool_wait_seconds_total": 1.5,
+		"cacher_memcache_pool_dial_errors_total":  2,
+	}, values(families))
+}
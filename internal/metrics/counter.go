@@ -0,0 +1,52 @@
+package metrics
+
+// CounterVec счётчик с метками. Значения счётчика только растут
+type CounterVec struct {
+	name string
+	help string
+	vec  vec[atomicFloat]
+}
+
+// NewCounterVec создаёт счётчик с метками labelNames
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name: name,
+		help: help,
+		vec: vec[atomicFloat]{
+			labelNames: labelNames,
+			newValue:   func() *atomicFloat { return &atomicFloat{} },
+			values:     make(map[string]*atomicFloat),
+		},
+	}
+}
+
+// Inc увеличивает на единицу счётчик с указанными значениями меток
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add увеличивает на delta счётчик с указанными значениями меток. Отрицательные delta игнорируются
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.vec.get(labelValues).Add(delta)
+}
+
+// Value возвращает значение счётчика с указанными значениями меток
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	return c.vec.get(labelValues).Load()
+}
+
+// Collect возвращает значения счётчика
+func (c *CounterVec) Collect() []Family {
+	family := Family{
+		Name: c.name,
+		Help: c.help,
+		Type: TypeCounter,
+	}
+	c.vec.each(func(labels []Label, value *atomicFloat) {
+		family.Samples = append(family.Samples, Sample{Labels: labels, Value: value.Load()})
+	})
+	return []Family{family}
+}
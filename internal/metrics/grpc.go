@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCMetrics метрики gRPC-сервера: количество запросов по методам и кодам ответа и время их выполнения
+type GRPCMetrics struct {
+	requests *CounterVec
+	duration *HistogramVec
+}
+
+// NewGRPCMetrics создаёт метрики gRPC-сервера
+func NewGRPCMetrics() *GRPCMetrics {
+	return &GRPCMetrics{
+		requests: NewCounterVec("cacher_grpc_requests_total",
+			"Total number of gRPC requests by method and response code.", "method", "code"),
+		duration: NewHistogramVec("cacher_grpc_request_duration_seconds",
+			"gRPC request duration in seconds.", nil, "method"),
+	}
+}
+
+// UnaryServerInterceptor возвращает перехватчик, учитывающий унарные запросы
+func (m *GRPCMetrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor возвращает перехватчик, учитывающий потоковые запросы. Временем выполнения
+// считается всё время жизни потока
+func (m *GRPCMetrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+// observe учитывает выполненный запрос
+func (m *GRPCMetrics) observe(method string, err error, duration time.Duration) {
+	m.requests.Inc(method, status.Code(err).String())
+	m.duration.Observe(duration.Seconds(), method)
+}
+
+// Collect возвращает метрики gRPC-сервера
+func (m *GRPCMetrics) Collect() []Family {
+	return append(m.requests.Collect(), m.duration.Collect()...)
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCMetrics_UnaryServerInterceptor(t *testing.T) {
+	m := NewGRPCMetrics()
+	interceptor := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/cacher.cache.v1.CacheAPI/Get"}
+
+	tests := []struct {
+		name    string
+		handler grpc.UnaryHandler
+		code    string
+	}{
+		{
+			name: "ok",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "response", nil
+			},
+			code: "OK",
+		},
+		{
+			name: "status error",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Errorf(codes.NotFound, "key not found")
+			},
+			code: "NotFound",
+		},
+		{
+			name: "other error",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, errors.New("something went wrong")
+			},
+			code: "Unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantResp, wantErr := tt.handler(context.Background(), nil)
+			resp, err := interceptor(context.Background(), nil, info, tt.handler)
+			assert.Equal(t, wantResp, resp)
+			assert.Equal(t, wantErr, err)
+			assert.Equal(t, float64(1), m.requests.Value(info.FullMethod, tt.code))
+		})
+	}
+
+	assert.Equal(t, uint64(len(tests)), m.duration.Count(info.FullMethod))
+}
+
+func TestGRPCMetrics_StreamServerInterceptor(t *testing.T) {
+	m := NewGRPCMetrics()
+	info := &grpc.StreamServerInfo{FullMethod: "/cacher.cache.v1.CacheAPI/Watch"}
+
+	err := m.StreamServerInterceptor()(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return status.Errorf(codes.Canceled, "request canceled")
+	})
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assert.Equal(t, float64(1), m.requests.Value(info.FullMethod, "Canceled"))
+	assert.Equal(t, uint64(1), m.duration.Count(info.FullMethod))
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// DefBuckets границы корзин гистограммы по умолчанию, подходят для времени выполнения запросов в секундах
+var DefBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+// histogram значения гистограммы с одним набором меток
+type histogram struct {
+	// Количество наблюдений в каждой корзине (не накопительное), последняя корзина - +Inf
+	counts []atomic.Uint64
+	sum    atomicFloat
+	count  atomic.Uint64
+}
+
+// HistogramVec гистограмма с метками
+type HistogramVec struct {
+	name    string
+	help    string
+	buckets []float64
+	vec     vec[histogram]
+}
+
+// NewHistogramVec создаёт гистограмму с верхними границами корзин buckets (nil - DefBuckets) и метками labelNames
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &HistogramVec{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		vec: vec[histogram]{
+			labelNames: labelNames,
+			newValue: func() *histogram {
+				return &histogram{counts: make([]atomic.Uint64, len(buckets)+1)}
+			},
+			values: make(map[string]*histogram),
+		},
+	}
+}
+
+// Observe учитывает наблюдение value в гистограмме с указанными значениями меток
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	hist := h.vec.get(labelValues)
+	hist.counts[sort.SearchFloat64s(h.buckets, value)].Add(1)
+	hist.sum.Add(value)
+	hist.count.Add(1)
+}
+
+// Count возвращает количество наблюдений в гистограмме с указанными значениями меток
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	return h.vec.get(labelValues).count.Load()
+}
+
+// Collect возвращает значения гистограммы: накопительные корзины, сумму и количество наблюдений
+func (h *HistogramVec) Collect() []Family {
+	family := Family{
+		Name: h.name,
+		Help: h.help,
+		Type: TypeHistogram,
+	}
+	h.vec.each(func(labels []Label, hist *histogram) {
+		var cumulative uint64
+		for i := range hist.counts {
+			cumulative += hist.counts[i].Load()
+
+			bound := math.Inf(1)
+			if i < len(h.buckets) {
+				bound = h.buckets[i]
+			}
+			bucketLabels := append(append(make([]Label, 0, len(labels)+1), labels...),
+				Label{Name: "le", Value: formatBound(bound)})
+			family.Samples = append(family.Samples, Sample{Suffix: "_bucket", Labels: bucketLabels, Value: float64(cumulative)})
+		}
+		family.Samples = append(family.Samples,
+			Sample{Suffix: "_sum", Labels: labels, Value: hist.sum.Load()},
+			Sample{Suffix: "_count", Labels: labels, Value: float64(hist.count.Load())},
+		)
+	})
+	return []Family{family}
+}
+
+// formatBound форматирует границу корзины гистограммы
+func formatBound(bound float64) string {
+	if math.IsInf(bound, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(bound, 'g', -1, 64)
+}
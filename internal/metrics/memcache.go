@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"sort"
+
+	"github.com/dimuska139/cacher/libs/memcache"
+)
+
+// PoolStatsProvider клиент Memcache, который ведёт статистику пула соединений
+type PoolStatsProvider interface {
+	PoolStats() map[string]memcache.PoolStats
+}
+
+// PoolCollector отдаёт статистику пула соединений с Memcache в виде метрик с меткой server
+type PoolCollector struct {
+	client PoolStatsProvider
+}
+
+// NewPoolCollector создаёт источник метрик пула соединений с Memcache
+func NewPoolCollector(client PoolStatsProvider) *PoolCollector {
+	return &PoolCollector{
+		client: client,
+	}
+}
+
+// Collect возвращает метрики пула соединений по каждому серверу
+func (c *PoolCollector) Collect() []Family {
+	families := []Family{
+		{Name: "cacher_memcache_pool_open_connections", Help: "Number of open connections.", Type: TypeGauge},
+		{Name: "cacher_memcache_pool_idle_connections", Help: "Number of idle connections.", Type: TypeGauge},
+		{Name: "cacher_memcache_pool_in_use_connections", Help: "Number of connections in use.", Type: TypeGauge},
+		{Name: "cacher_memcache_pool_waiting", Help: "Number of callers waiting for a connection.", Type: TypeGauge},
+		{Name: "cacher_memcache_pool_waits_total", Help: "Total number of waits for a connection.", Type: TypeCounter},
+		{Name: "cacher_memcache_pool_wait_seconds_total", Help: "Total time spent waiting for a connection in seconds.", Type: TypeCounter},
+		{Name: "cacher_memcache_pool_dial_errors_total", Help: "Total number of failed connection attempts.", Type: TypeCounter},
+	}
+
+	poolStats := c.client.PoolStats()
+	servers := make([]string, 0, len(poolStats))
+	for server := range poolStats {
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+
+	for _, server := range servers {
+		stats := poolStats[server]
+		labels := []Label{{Name: "server", Value: server}}
+		values := []float64{
+			float64(stats.Open),
+			float64(stats.Idle),
+			float64(stats.InUse),
+			float64(stats.Waiting),
+			float64(stats.WaitCount),
+			stats.WaitDuration.Seconds(),
+			float64(stats.DialErrors),
+		}
+		for i, value := range values {
+			families[i].Samples = append(families[i].Samples, Sample{Labels: labels, Value: value})
+		}
+	}
+
+	return families
+}
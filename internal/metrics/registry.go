@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Типы метрик в текстовом формате Prometheus
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// contentType тип содержимого текстового формата Prometheus
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Label метка значения метрики
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample значение метрики с метками. Suffix дописывается к имени метрики (например, _bucket у гистограмм)
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Family метрика со всеми её значениями
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector источник метрик. Collect вызывается при каждом запросе метрик
+type Collector interface {
+	Collect() []Family
+}
+
+// CollectorFunc позволяет использовать функцию в качестве Collector
+type CollectorFunc func() []Family
+
+// Collect возвращает метрики, вычисленные функцией
+func (f CollectorFunc) Collect() []Family {
+	return f()
+}
+
+// Registry набор источников метрик, отдаваемых по HTTP в текстовом формате Prometheus
+type Registry struct {
+	mx         sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry создаёт пустой набор источников метрик
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register добавляет источники метрик
+func (r *Registry) Register(collectors ...Collector) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// Gather собирает метрики всех источников, отсортированные по имени. Метрики с пустым набором значений пропускаются
+func (r *Registry) Gather() []Family {
+	r.mx.RLock()
+	collectors := r.collectors
+	r.mx.RUnlock()
+
+	var families []Family
+	for _, collector := range collectors {
+		for _, family := range collector.Collect() {
+			if len(family.Samples) > 0 {
+				families = append(families, family)
+			}
+		}
+	}
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+
+	return families
+}
+
+// WriteTo записывает метрики в текстовом формате Prometheus
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	buf := bufio.NewWriter(cw)
+	for _, family := range r.Gather() {
+		writeFamily(buf, family)
+	}
+	err := buf.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP отдаёт метрики в текстовом формате Prometheus
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_, _ = r.WriteTo(w)
+}
+
+// writeFamily записывает метрику вместе с описанием и типом
+func writeFamily(w *bufio.Writer, family Family) {
+	if family.Help != "" {
+		w.WriteString("# HELP " + family.Name + " " + escapeHelp(family.Help) + "\n")
+	}
+	w.WriteString("# TYPE " + family.Name + " " + family.Type + "\n")
+
+	for _, sample := range family.Samples {
+		w.WriteString(family.Name)
+		w.WriteString(sample.Suffix)
+		if len(sample.Labels) > 0 {
+			w.WriteByte('{')
+			for i, label := range sample.Labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.WriteString(label.Name + `="` + escapeLabelValue(label.Value) + `"`)
+			}
+			w.WriteByte('}')
+		}
+		w.WriteByte(' ')
+		w.WriteString(formatValue(sample.Value))
+		w.WriteByte('\n')
+	}
+}
+
+// formatValue форматирует значение метрики
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// escapeHelp экранирует описание метрики
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+// escapeLabelValue экранирует значение метки
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// countingWriter считает количество записанных байт
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
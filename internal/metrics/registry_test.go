@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	counter := NewCounterVec("test_requests_total", "Total requests.\nSecond line.", "method")
+	counter.Inc("get")
+	counter.Add(2, "get")
+	counter.Add(-1, "get")
+	counter.Inc(`set "quoted" \ value`)
+
+	histogram := NewHistogramVec("test_duration_seconds", "Duration.", []float64{1, 0.1}, "method")
+	histogram.Observe(0.05, "get")
+	histogram.Observe(0.1, "get")
+	histogram.Observe(5, "get")
+
+	empty := NewCounterVec("test_empty_total", "Never incremented.")
+
+	registry := NewRegistry()
+	registry.Register(histogram, counter, empty, CollectorFunc(func() []Family {
+		return []Family{single("test_items", "", TypeGauge, 3)}
+	}))
+
+	var buf bytes.Buffer
+	n, err := registry.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, `# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{method="get",le="0.1"} 2
+test_duration_seconds_bucket{method="get",le="1"} 2
+test_duration_seconds_bucket{method="get",le="+Inf"} 3
+test_duration_seconds_sum{method="get"} 5.15
+test_duration_seconds_count{method="get"} 3
+# TYPE test_items gauge
+test_items 3
+# HELP test_requests_total Total requests.\nSecond line.
+# TYPE test_requests_total counter
+test_requests_total{method="get"} 3
+test_requests_total{method="set \"quoted\" \\ value"} 1
+`, buf.String())
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	counter := NewCounterVec("test_requests_total", "Total requests.")
+	counter.Inc()
+
+	registry := NewRegistry()
+	registry.Register(counter)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, contentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "test_requests_total 1\n")
+}
+
+func TestCounterVec_WrongLabels(t *testing.T) {
+	counter := NewCounterVec("test_requests_total", "Total requests.", "method", "code")
+	assert.Panics(t, func() {
+		counter.Inc("get")
+	})
+}
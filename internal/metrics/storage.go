@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/dimuska139/cacher/internal/cache"
+)
+
+// DefaultStatsTimeout ограничение времени получения статистики хранилища при запросе метрик
+const DefaultStatsTimeout = 5 * time.Second
+
+// Logger интерфейс для логгера
+type Logger interface {
+	Error(msg string, args ...interface{})
+}
+
+// StatsProvider хранилище, которое ведёт статистику своей работы
+type StatsProvider interface {
+	Stats(ctx context.Context) (*cache.Stats, error)
+}
+
+// StorageCollector отдаёт статистику хранилища в виде метрик
+type StorageCollector struct {
+	storage StatsProvider
+	logger  Logger
+	timeout time.Duration
+}
+
+// NewStorageCollector создаёт источник метрик хранилища. Статистика запрашивается у хранилища
+// при каждом запросе метрик, но не дольше timeout (0 - DefaultStatsTimeout)
+func NewStorageCollector(storage StatsProvider, logger Logger, timeout time.Duration) *StorageCollector {
+	if timeout <= 0 {
+		timeout = DefaultStatsTimeout
+	}
+	return &StorageCollector{
+		storage: storage,
+		logger:  logger,
+		timeout: timeout,
+	}
+}
+
+// Collect возвращает метрики хранилища. Если статистику получить не удалось, метрик хранилища нет
+func (c *StorageCollector) Collect() []Family {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	stats, err := c.storage.Stats(ctx)
+	if err != nil {
+		c.logger.Error("Can't get stats from storage", "err", err)
+		return nil
+	}
+
+	return []Family{
+		single("cacher_storage_get_hits_total", "Total number of reads that found the key.", TypeCounter, stats.GetHits),
+		single("cacher_storage_get_misses_total", "Total number of reads that did not find the key.", TypeCounter, stats.GetMisses),
+		single("cacher_storage_sets_total", "Total number of writes.", TypeCounter, stats.Sets),
+		single("cacher_storage_deletes_total", "Total number of deletes.", TypeCounter, stats.Deletes),
+		single("cacher_storage_evictions_total", "Total number of items evicted to free space.", TypeCounter, stats.Evictions),
+		single("cacher_storage_expirations_total", "Total number of items removed after expiration.", TypeCounter, stats.Expirations),
+		single("cacher_storage_items", "Current number of items.", TypeGauge, stats.Items),
+		single("cacher_storage_bytes", "Current size of items in bytes.", TypeGauge, stats.Bytes),
+	}
+}
+
+// single возвращает метрику с единственным значением без меток
+func single(name, help, typ string, value uint64) Family {
+	return Family{
+		Name:    name,
+		Help:    help,
+		Type:    typ,
+		Samples: []Sample{{Value: float64(value)}},
+	}
+}
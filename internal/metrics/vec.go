@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// labelSeparator разделитель значений меток в ключе значения метрики. Не может встретиться в UTF-8 строке
+const labelSeparator = "\xff"
+
+// vec набор значений метрики, различающихся значениями меток
+type vec[T any] struct {
+	labelNames []string
+	newValue   func() *T
+
+	mx     sync.RWMutex
+	values map[string]*T
+}
+
+// get возвращает значение метрики для значений меток, создавая его при первом обращении.
+// Количество значений меток должно совпадать с количеством меток метрики
+func (v *vec[T]) get(labelValues []string) *T {
+	if len(labelValues) != len(v.labelNames) {
+		panic("metrics: wrong number of label values")
+	}
+	key := strings.Join(labelValues, labelSeparator)
+
+	v.mx.RLock()
+	value, ok := v.values[key]
+	v.mx.RUnlock()
+	if ok {
+		return value
+	}
+
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	if value, ok = v.values[key]; !ok {
+		value = v.newValue()
+		v.values[key] = value
+	}
+	return value
+}
+
+// each вызывает fn для каждого значения метрики в порядке значений меток
+func (v *vec[T]) each(fn func(labels []Label, value *T)) {
+	v.mx.RLock()
+	keys := make([]string, 0, len(v.values))
+	values := make(map[string]*T, len(v.values))
+	for key, value := range v.values {
+		keys = append(keys, key)
+		values[key] = value
+	}
+	v.mx.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		labels := make([]Label, len(v.labelNames))
+		if len(v.labelNames) > 0 {
+			for i, labelValue := range strings.Split(key, labelSeparator) {
+				labels[i] = Label{Name: v.labelNames[i], Value: labelValue}
+			}
+		}
+		fn(labels, values[key])
+	}
+}
+
+// atomicFloat число с плавающей точкой, которое можно атомарно увеличивать
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+// Add атомарно увеличивает значение на delta
+func (f *atomicFloat) Add(delta float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+// Load возвращает текущее значение
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
@@ -2,10 +2,14 @@ package memcache
 
 import (
 	"bufio"
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"runtime"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Client клиент для работы с Memcache
@@ -27,114 +31,583 @@ func NewMemcacheClient(cfg *Config) *Client {
 
 // finalizer вызывается сборщиком мусора для корректного завершения работы MemcacheClient
 func finalizer(c *Client) {
-	c.connPool.closeAllConnections()
+	c.connPool.Close()
 }
 
-// Get получает запись из Memcache
-func (c *Client) Get(key string) ([]byte, error) {
-	serverAddress := c.connPool.GetServerAddr(key)
-	conn, err := c.connPool.AcquireConnection(serverAddress)
+// PoolStats возвращает статистику пула соединений по каждому серверу (ключ - адрес сервера)
+func (c *Client) PoolStats() map[string]PoolStats {
+	return c.connPool.Stats()
+}
+
+// execute получает соединение с сервером из пула, выполняет на нём fn и возвращает соединение в пул.
+// Дедлайн ctx ограничивает как ожидание соединения, так и операции ввода-вывода, а отмена ctx прерывает их.
+// Если fn завершилась ошибкой ввода-вывода или протокола, то состояние соединения неизвестно
+// (в нём может остаться непрочитанная часть ответа), поэтому оно закрывается, а не возвращается в пул
+func (c *Client) execute(ctx context.Context, serverAddress net.Addr, fn func(buf *bufio.ReadWriter) error) error {
+	conn, err := c.connPool.AcquireConnection(ctx, serverAddress)
 	if err != nil {
-		return nil, fmt.Errorf("can't get connection from pool: %w", err)
+		return withContextError(ctx, fmt.Errorf("can't get connection from pool: %w", err))
 	}
 
-	defer c.connPool.ReleaseConnection(serverAddress, conn)
+	stopWatching := watchCancel(ctx, conn)
+	err = withContextError(ctx, fn(bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))))
+	stopWatching()
+
+	if err != nil && !isResumableError(err) {
+		c.connPool.DiscardConnection(serverAddress, conn)
+		return err
+	}
 
-	buf := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	c.connPool.ReleaseConnection(serverAddress, conn)
+	return err
+}
 
-	if _, err = fmt.Fprintf(buf, "gets %s\r\n", key); err != nil {
-		return nil, fmt.Errorf("can't format command and write bytes: %w", err)
+// executeKey выполняет fn на соединении с сервером, на котором хранится ключ
+func (c *Client) executeKey(ctx context.Context, key string, fn func(buf *bufio.ReadWriter) error) error {
+	addr, err := c.connPool.GetServerAddr(key)
+	if err != nil {
+		return err
 	}
 
-	if err := buf.Flush(); err != nil {
-		return nil, fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+	return c.execute(ctx, addr, fn)
+}
+
+// watchCancel прерывает операции ввода-вывода на соединении при отмене ctx. Возвращаемую функцию нужно вызвать
+// по завершении работы с соединением: после её возврата дедлайн соединения больше не изменится
+func watchCancel(ctx context.Context, conn net.Conn) func() {
+	if ctx.Done() == nil {
+		return func() {}
 	}
 
-	for {
-		row, err := buf.ReadSlice('\n')
-		if err != nil {
-			return nil, fmt.Errorf("can't read slice: %w", err)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+			// Дедлайн в прошлом немедленно прерывает текущие и будущие операции чтения и записи
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
 		}
+	}()
 
-		if bytes.HasPrefix(row, []byte("VALUE ")) {
-			continue
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// withContextError дополняет err ошибкой ctx, если операция была прервана отменой ctx или истечением его дедлайна.
+// Дедлайн соединения совпадает с дедлайном ctx и может сработать чуть раньше, чем ctx будет отменён
+func withContextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+
+	var netErr net.Error
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) && errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+
+	return err
+}
+
+// isResumableError проверяет, что ошибка - штатный ответ сервера, после которого соединением можно пользоваться дальше
+func isResumableError(err error) bool {
+	return errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrNotStored) ||
+		errors.Is(err, ErrExists) ||
+		errors.Is(err, ErrNonNumeric)
+}
+
+// Get получает значение записи из Memcache. Если записи нет, возвращается ErrNotFound
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	item, err := c.retrieve(ctx, key, fmt.Sprintf("gets %s\r\n", key))
+	if err != nil {
+		return nil, err
+	}
+
+	return item.Value, nil
+}
+
+// GetItem получает запись из Memcache вместе с метаданными: флагами, CAS-идентификатором и оставшимся
+// временем жизни. Использует мета-команду mg, поэтому требует Memcache 1.6 и новее (более старые серверы
+// отвечают на неё ERROR, и возвращается ErrServerError). Версию серверов можно проверить с помощью Version.
+// Если записи нет, возвращается ErrNotFound
+func (c *Client) GetItem(ctx context.Context, key string) (*Item, error) {
+	var item *Item
+	err := c.executeKey(ctx, key, func(buf *bufio.ReadWriter) error {
+		if _, err := fmt.Fprintf(buf, "mg %s k v f c t\r\n", key); err != nil {
+			return fmt.Errorf("can't format command and write bytes: %w", err)
 		}
 
-		if string(row) == "END\r\n" {
-			break
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
 		}
 
-		if !bytes.HasSuffix(row, []byte("\r\n")) {
-			return nil, fmt.Errorf("invalid data in cache: %s", string(row))
+		var err error
+		item, err = readMetaItem(buf.Reader)
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
+
+		if item.Key != key {
+			return fmt.Errorf("%w: expected %q, got %q", ErrUnexpectedKey, key, item.Key)
 		}
 
-		return row[0 : len(row)-2], nil // Удаляем \r\n в конце строки
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+
+	return item, nil
 }
 
-// Set делает запись в Memcache
-func (c *Client) Set(key string, value []byte, expiration int64) error {
-	serverAddress := c.connPool.GetServerAddr(key)
+// GetAndTouch получает запись из Memcache и одновременно обновляет её время жизни (команда gats).
+// Если записи нет, возвращается ErrNotFound
+func (c *Client) GetAndTouch(ctx context.Context, key string, expiration int64) (*Item, error) {
+	item, err := c.retrieve(ctx, key, fmt.Sprintf("gats %d %s\r\n", expiration, key))
+	if err != nil {
+		return nil, err
+	}
+
+	item.Expiration = expiration
+	return item, nil
+}
+
+// retrieve выполняет команду получения одной записи и возвращает её. Если записи нет, возвращается ErrNotFound
+func (c *Client) retrieve(ctx context.Context, key string, command string) (*Item, error) {
+	var found *Item
+	err := c.executeKey(ctx, key, func(buf *bufio.ReadWriter) error {
+		if _, err := buf.WriteString(command); err != nil {
+			return fmt.Errorf("can't format command and write bytes: %w", err)
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+		}
+
+		err := readItems(buf.Reader, func(item *Item) error {
+			if item.Key != key {
+				return fmt.Errorf("%w: expected %q, got %q", ErrUnexpectedKey, key, item.Key)
+			}
+			found = item
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
+
+		if found == nil {
+			return ErrNotFound
+		}
 
-	conn, err := c.connPool.AcquireConnection(serverAddress)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("can't get connection from pool: %w", err)
+		return nil, err
 	}
 
-	defer c.connPool.ReleaseConnection(serverAddress, conn)
+	return found, nil
+}
+
+// GetMulti получает несколько записей из Memcache. Ключи группируются по серверам,
+// и каждому серверу одновременно отправляется одна команда gets со всеми его ключами.
+// Ключей, которых нет в Memcache, в результате не будет
+func (c *Client) GetMulti(ctx context.Context, keys []string) (map[string]Item, error) {
+	addrs := make(map[string]net.Addr)
+	keysByServer := make(map[string][]string)
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
 
-	buf := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
-	if _, err = fmt.Fprintf(buf, "set %s 0 %d %d\r\n", key, expiration, len(value)); err != nil {
-		return fmt.Errorf("can't format command and write bytes: %w", err)
+		addr, err := c.connPool.GetServerAddr(key)
+		if err != nil {
+			return nil, err
+		}
+		addrs[addr.String()] = addr
+		keysByServer[addr.String()] = append(keysByServer[addr.String()], key)
 	}
 
-	if _, err := buf.Write(append(value, []byte("\r\n")...)); err != nil {
-		return fmt.Errorf("can't write bytes: %w", err)
+	var (
+		mx   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+
+	result := make(map[string]Item, len(seen))
+	for serverAddress, serverKeys := range keysByServer {
+		wg.Add(1)
+		go func(addr net.Addr, serverKeys []string) {
+			defer wg.Done()
+
+			items, err := c.getMultiFromServer(ctx, addr, serverKeys)
+
+			mx.Lock()
+			defer mx.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("can't get data from %s: %w", addr.String(), err))
+				return
+			}
+
+			for key, item := range items {
+				result[key] = item
+			}
+		}(addrs[serverAddress], serverKeys)
 	}
+	wg.Wait()
 
-	if err := buf.Flush(); err != nil {
-		return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
-	row, err := buf.ReadSlice('\n')
+	return result, nil
+}
+
+// getMultiFromServer получает записи с одного сервера Memcache одной командой gets
+func (c *Client) getMultiFromServer(ctx context.Context, serverAddress net.Addr, keys []string) (map[string]Item, error) {
+	requested := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		requested[key] = struct{}{}
+	}
+
+	items := make(map[string]Item, len(keys))
+	err := c.execute(ctx, serverAddress, func(buf *bufio.ReadWriter) error {
+		if _, err := fmt.Fprintf(buf, "gets %s\r\n", strings.Join(keys, " ")); err != nil {
+			return fmt.Errorf("can't format command and write bytes: %w", err)
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+		}
+
+		err := readItems(buf.Reader, func(item *Item) error {
+			if _, ok := requested[item.Key]; !ok {
+				return fmt.Errorf("%w: %q was not requested", ErrUnexpectedKey, item.Key)
+			}
+			items[item.Key] = *item
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("can't read slice: %w", err)
+		return nil, err
 	}
-	if string(row) == "STORED\r\n" {
+
+	return items, nil
+}
+
+// Set делает запись в Memcache
+func (c *Client) Set(ctx context.Context, key string, value []byte, expiration int64) error {
+	return c.store(ctx, "set", &Item{Key: key, Value: value, Expiration: expiration}, 0)
+}
+
+// Add делает запись в Memcache, только если записи с таким ключом ещё нет.
+// Если запись уже есть, возвращается ErrNotStored
+func (c *Client) Add(ctx context.Context, key string, value []byte, expiration int64) error {
+	return c.store(ctx, "add", &Item{Key: key, Value: value, Expiration: expiration}, 0)
+}
+
+// Replace перезаписывает значение в Memcache, только если запись с таким ключом уже есть.
+// Если записи нет, возвращается ErrNotStored
+func (c *Client) Replace(ctx context.Context, key string, value []byte, expiration int64) error {
+	return c.store(ctx, "replace", &Item{Key: key, Value: value, Expiration: expiration}, 0)
+}
+
+// Append дописывает данные в конец существующего значения. Если записи нет, возвращается ErrNotStored
+func (c *Client) Append(ctx context.Context, key string, value []byte) error {
+	return c.store(ctx, "append", &Item{Key: key, Value: value}, 0)
+}
+
+// Prepend дописывает данные в начало существующего значения. Если записи нет, возвращается ErrNotStored
+func (c *Client) Prepend(ctx context.Context, key string, value []byte) error {
+	return c.store(ctx, "prepend", &Item{Key: key, Value: value}, 0)
+}
+
+// CompareAndSwap записывает item, только если с момента чтения запись не менялась,
+// то есть её текущий CAS-идентификатор равен casID. Если запись изменилась, возвращается ErrExists,
+// если запись была удалена или её время жизни истекло - ErrNotFound
+func (c *Client) CompareAndSwap(ctx context.Context, item *Item, casID uint64) error {
+	return c.store(ctx, "cas", item, casID)
+}
+
+// store выполняет команду записи (set, add, replace, append, prepend или cas)
+func (c *Client) store(ctx context.Context, command string, item *Item, casID uint64) error {
+	return c.executeKey(ctx, item.Key, func(buf *bufio.ReadWriter) error {
+		var err error
+		if command == "cas" {
+			_, err = fmt.Fprintf(buf, "cas %s %d %d %d %d\r\n", item.Key, item.Flags, item.Expiration, len(item.Value), casID)
+		} else {
+			_, err = fmt.Fprintf(buf, "%s %s %d %d %d\r\n", command, item.Key, item.Flags, item.Expiration, len(item.Value))
+		}
+		if err != nil {
+			return fmt.Errorf("can't format command and write bytes: %w", err)
+		}
+
+		if _, err := buf.Write(item.Value); err != nil {
+			return fmt.Errorf("can't write bytes: %w", err)
+		}
+
+		if _, err := buf.Write(crlf); err != nil {
+			return fmt.Errorf("can't write bytes: %w", err)
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+		}
+
+		row, err := readLine(buf.Reader)
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
+
+		if err := parseStorageResponse(row); err != nil {
+			return fmt.Errorf("can't store data: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Increment увеличивает числовое значение записи на delta и возвращает новое значение.
+// При переполнении 64-битного беззнакового числа значение начинается с нуля.
+// Если записи нет, возвращается ErrNotFound, если значение не является числом - ErrNonNumeric
+func (c *Client) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	return c.incrDecr(ctx, "incr", key, delta)
+}
+
+// Decrement уменьшает числовое значение записи на delta и возвращает новое значение.
+// Значение не может стать меньше нуля. Если записи нет, возвращается ErrNotFound,
+// если значение не является числом - ErrNonNumeric
+func (c *Client) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	return c.incrDecr(ctx, "decr", key, delta)
+}
+
+// incrDecr выполняет команду incr или decr
+func (c *Client) incrDecr(ctx context.Context, command string, key string, delta uint64) (uint64, error) {
+	var value uint64
+	err := c.executeKey(ctx, key, func(buf *bufio.ReadWriter) error {
+		if _, err := fmt.Fprintf(buf, "%s %s %d\r\n", command, key, delta); err != nil {
+			return fmt.Errorf("can't format command and write bytes: %w", err)
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+		}
+
+		row, err := readLine(buf.Reader)
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
+
+		value, err = parseIncrDecrResponse(row)
+		if err != nil {
+			return fmt.Errorf("can't %s value: %w", command, err)
+		}
+
 		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
-	return fmt.Errorf("can't store data: %s", string(row))
+
+	return value, nil
 }
 
-// Delete удаляет запись из Memcache
-func (c *Client) Delete(key string) error {
-	serverAddress := c.connPool.GetServerAddr(key)
-	conn, err := c.connPool.AcquireConnection(serverAddress)
+// Touch обновляет время жизни записи, не получая её. Если записи нет, возвращается ErrNotFound
+func (c *Client) Touch(ctx context.Context, key string, expiration int64) error {
+	return c.executeKey(ctx, key, func(buf *bufio.ReadWriter) error {
+		if _, err := fmt.Fprintf(buf, "touch %s %d\r\n", key, expiration); err != nil {
+			return fmt.Errorf("can't format command and write bytes: %w", err)
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+		}
+
+		row, err := readLine(buf.Reader)
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
+
+		if err := parseTouchResponse(row); err != nil {
+			return fmt.Errorf("can't touch item: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Delete удаляет запись из Memcache. Отсутствие записи ошибкой не является
+func (c *Client) Delete(ctx context.Context, key string) error {
+	if err := c.DeleteExisting(ctx, key); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExisting удаляет запись из Memcache. Если записи нет, возвращается ErrNotFound
+func (c *Client) DeleteExisting(ctx context.Context, key string) error {
+	return c.executeKey(ctx, key, func(buf *bufio.ReadWriter) error {
+		if _, err := fmt.Fprintf(buf, "delete %s\r\n", key); err != nil {
+			return fmt.Errorf("can't format command and write bytes: %w", err)
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+		}
+
+		row, err := readLine(buf.Reader)
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
+
+		switch string(row) {
+		case "DELETED\r\n":
+			return nil
+		case "NOT_FOUND\r\n":
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("can't delete item: %s", string(row))
+	})
+}
+
+// Stats возвращает статистику каждого сервера Memcache (ответ команды stats). Ключ результата - адрес сервера,
+// значение - показатели сервера по названиям (curr_items, bytes, evictions и т.д.)
+func (c *Client) Stats(ctx context.Context) (map[string]map[string]string, error) {
+	var mx sync.Mutex
+	result := make(map[string]map[string]string, len(c.cfg.servers))
+	err := c.eachServer(func(addr net.Addr) error {
+		stats, err := c.statsFromServer(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("can't get stats from %s: %w", addr.String(), err)
+		}
+
+		mx.Lock()
+		defer mx.Unlock()
+		result[addr.String()] = stats
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("can't get connection from pool: %w", err)
+		return nil, err
 	}
 
-	defer c.connPool.ReleaseConnection(serverAddress, conn)
+	return result, nil
+}
 
-	buf := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+// Version возвращает версию каждого сервера Memcache (ответ команды version). Ключ результата - адрес сервера.
+// Подходит для проверки доступности серверов: каждый сервер должен ответить на команду
+func (c *Client) Version(ctx context.Context) (map[string]string, error) {
+	var mx sync.Mutex
+	result := make(map[string]string, len(c.cfg.servers))
+	err := c.eachServer(func(addr net.Addr) error {
+		version, err := c.versionFromServer(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("can't get version from %s: %w", addr.String(), err)
+		}
 
-	if _, err = fmt.Fprintf(buf, "delete %s\r\n", key); err != nil {
-		return fmt.Errorf("can't format command and write bytes: %w", err)
+		mx.Lock()
+		defer mx.Unlock()
+		result[addr.String()] = version
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := buf.Flush(); err != nil {
-		return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+	return result, nil
+}
+
+// eachServer параллельно выполняет fn для каждого сервера Memcache и объединяет ошибки всех серверов
+func (c *Client) eachServer(fn func(addr net.Addr) error) error {
+	var (
+		mx   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+
+	for _, addr := range c.cfg.servers {
+		wg.Add(1)
+		go func(addr net.Addr) {
+			defer wg.Done()
+
+			if err := fn(addr); err != nil {
+				mx.Lock()
+				defer mx.Unlock()
+				errs = append(errs, err)
+			}
+		}(addr)
 	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// statsFromServer получает статистику одного сервера Memcache
+func (c *Client) statsFromServer(ctx context.Context, serverAddress net.Addr) (map[string]string, error) {
+	var stats map[string]string
+	err := c.execute(ctx, serverAddress, func(buf *bufio.ReadWriter) error {
+		if _, err := buf.WriteString("stats\r\n"); err != nil {
+			return fmt.Errorf("can't write command: %w", err)
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+		}
+
+		var err error
+		stats, err = readStats(buf.Reader)
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
 
-	row, err := buf.ReadSlice('\n')
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("can't read slice: %w", err)
+		return nil, err
 	}
 
-	if string(row) == "DELETED\r\n" || string(row) == "NOT_FOUND\r\n" {
+	return stats, nil
+}
+
+// versionFromServer получает версию одного сервера Memcache
+func (c *Client) versionFromServer(ctx context.Context, serverAddress net.Addr) (string, error) {
+	var version string
+	err := c.execute(ctx, serverAddress, func(buf *bufio.ReadWriter) error {
+		if _, err := buf.WriteString("version\r\n"); err != nil {
+			return fmt.Errorf("can't write command: %w", err)
+		}
+
+		if err := buf.Flush(); err != nil {
+			return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+		}
+
+		var err error
+		version, err = readVersion(buf.Reader)
+		if err != nil {
+			return fmt.Errorf("can't read response: %w", err)
+		}
+
 		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
-	return fmt.Errorf("can't delete item: %s", string(row))
+	return version, nil
 }
@@ -0,0 +1,710 @@
+package memcache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Get(t *testing.T) {
+	random := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	tests := []struct {
+		name  string
+		value []byte
+	}{
+		{
+			name:  "plain text",
+			value: []byte("test"),
+		},
+		{
+			name:  "empty value",
+			value: []byte{},
+		},
+		{
+			name:  "value with CRLF",
+			value: []byte("first\r\nsecond\r\n"),
+		},
+		{
+			name:  "value with protocol keywords",
+			value: []byte("\r\nEND\r\nVALUE key 0 1\r\n"),
+		},
+		{
+			name:  "value with zero bytes",
+			value: []byte{0, 0, '\r', 0, '\n', 0},
+		},
+		{
+			name:  "large binary value",
+			value: random,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeServer(t)
+			client := newTestClient(srv.Addr())
+
+			assert.NoError(t, client.Set(context.Background(), "key", tt.value, 0))
+
+			got, err := client.Get(context.Background(), "key")
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(tt.value, got))
+		})
+	}
+}
+
+func TestClient_Get_NotFound(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	got, err := client.Get(context.Background(), "not-existing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, got)
+}
+
+func TestClient_Set_DoesNotModifyValue(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	backing := []byte("value-and-tail")
+	value := backing[:5]
+	assert.NoError(t, client.Set(context.Background(), "key", value, 0))
+	assert.Equal(t, []byte("value-and-tail"), backing)
+}
+
+func TestClient_Get_MalformedResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr error
+	}{
+		{
+			name:    "data block shorter than header says",
+			reply:   "VALUE key 0 10 1\r\nshort\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "data block without CRLF",
+			reply:   "VALUE key 0 4 1\r\ntestXXEND\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "header without size",
+			reply:   "VALUE key 0\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "negative size",
+			reply:   "VALUE key 0 -1 1\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "invalid flags",
+			reply:   "VALUE key flags 4 1\r\ntest\r\nEND\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "invalid cas",
+			reply:   "VALUE key 0 4 cas\r\ntest\r\nEND\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "unexpected line",
+			reply:   "STORED\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "line without CR",
+			reply:   "END\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "another key",
+			reply:   "VALUE another 0 4 1\r\ntest\r\nEND\r\n",
+			wantErr: ErrUnexpectedKey,
+		},
+		{
+			name:    "server error",
+			reply:   "SERVER_ERROR out of memory\r\n",
+			wantErr: ErrServerError,
+		},
+		{
+			name:    "client error",
+			reply:   "CLIENT_ERROR bad command line format\r\n",
+			wantErr: ErrServerError,
+		},
+		{
+			name:    "unknown command",
+			reply:   "ERROR\r\n",
+			wantErr: ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(newRawServer(t, []byte(tt.reply)))
+
+			got, err := client.Get(context.Background(), "key")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestClient_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr error
+	}{
+		{
+			name:    "stored",
+			reply:   "STORED\r\n",
+			wantErr: nil,
+		},
+		{
+			name:    "not stored",
+			reply:   "NOT_STORED\r\n",
+			wantErr: ErrNotStored,
+		},
+		{
+			name:    "exists",
+			reply:   "EXISTS\r\n",
+			wantErr: ErrExists,
+		},
+		{
+			name:    "not found",
+			reply:   "NOT_FOUND\r\n",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "server error",
+			reply:   "SERVER_ERROR object too large for cache\r\n",
+			wantErr: ErrServerError,
+		},
+		{
+			name:    "unexpected line",
+			reply:   "DELETED\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(newRawServer(t, []byte(tt.reply)))
+
+			err := client.Set(context.Background(), "key", []byte("value"), 0)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_Add(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.NoError(t, client.Add(context.Background(), "key", []byte("first"), 0))
+	assert.ErrorIs(t, client.Add(context.Background(), "key", []byte("second"), 0), ErrNotStored)
+
+	got, err := client.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first"), got)
+}
+
+func TestClient_Replace(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.ErrorIs(t, client.Replace(context.Background(), "key", []byte("first"), 0), ErrNotStored)
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("first"), 0))
+	assert.NoError(t, client.Replace(context.Background(), "key", []byte("second"), 0))
+
+	got, err := client.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("second"), got)
+}
+
+func TestClient_AppendPrepend(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.ErrorIs(t, client.Append(context.Background(), "key", []byte("tail")), ErrNotStored)
+	assert.ErrorIs(t, client.Prepend(context.Background(), "key", []byte("head")), ErrNotStored)
+
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("\r\n"), 0))
+	assert.NoError(t, client.Append(context.Background(), "key", []byte("tail")))
+	assert.NoError(t, client.Prepend(context.Background(), "key", []byte("head")))
+
+	got, err := client.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("head\r\ntail"), got)
+}
+
+func TestClient_CompareAndSwap(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	item := &Item{
+		Key:   "key",
+		Value: []byte("first"),
+	}
+	assert.ErrorIs(t, client.CompareAndSwap(context.Background(), item, 1), ErrNotFound)
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("first"), 0))
+
+	items, err := client.GetMulti(context.Background(), []string{"key"})
+	assert.NoError(t, err)
+	casID := items["key"].CasID
+
+	item.Value = []byte("second")
+	assert.NoError(t, client.CompareAndSwap(context.Background(), item, casID))
+
+	item.Value = []byte("third")
+	assert.ErrorIs(t, client.CompareAndSwap(context.Background(), item, casID), ErrExists)
+
+	got, err := client.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("second"), got)
+}
+
+func TestClient_IncrementDecrement(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	_, err := client.Increment(context.Background(), "counter", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.Decrement(context.Background(), "counter", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoError(t, client.Set(context.Background(), "text", []byte("text"), 0))
+	_, err = client.Increment(context.Background(), "text", 1)
+	assert.ErrorIs(t, err, ErrNonNumeric)
+
+	assert.NoError(t, client.Set(context.Background(), "counter", []byte("10"), 0))
+
+	got, err := client.Increment(context.Background(), "counter", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(15), got)
+
+	got, err = client.Decrement(context.Background(), "counter", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), got)
+
+	got, err = client.Decrement(context.Background(), "counter", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), got)
+
+	got, err = client.Increment(context.Background(), "counter", math.MaxUint64)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), got)
+
+	got, err = client.Increment(context.Background(), "counter", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), got)
+
+	value, err := client.Get(context.Background(), "counter")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+}
+
+func TestClient_Increment_MalformedResponse(t *testing.T) {
+	client := newTestClient(newRawServer(t, []byte("STORED\r\n")))
+
+	_, err := client.Increment(context.Background(), "counter", 1)
+	assert.ErrorIs(t, err, ErrMalformedResponse)
+}
+
+func TestClient_Touch(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.ErrorIs(t, client.Touch(context.Background(), "key", 100), ErrNotFound)
+
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("value"), 10))
+	assert.NoError(t, client.Touch(context.Background(), "key", 100))
+	assert.Equal(t, int64(100), srv.items["key"].expiration)
+}
+
+func TestClient_GetAndTouch(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	got, err := client.GetAndTouch(context.Background(), "key", 100)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, got)
+
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("value\r\n"), 10))
+
+	got, err = client.GetAndTouch(context.Background(), "key", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value\r\n"), got.Value)
+	assert.Equal(t, int64(100), got.Expiration)
+	assert.Equal(t, srv.items["key"].casID, got.CasID)
+	assert.Equal(t, int64(100), srv.items["key"].expiration)
+}
+
+func TestClient_GetItem(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	srv.items["expiring"] = fakeItem{value: []byte("value\r\n"), flags: 42, casID: 7, expiration: 30}
+	srv.items["eternal"] = fakeItem{value: []byte{}, casID: 8}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    *Item
+		wantErr error
+	}{
+		{
+			name: "with ttl",
+			key:  "expiring",
+			want: &Item{Key: "expiring", Value: []byte("value\r\n"), Flags: 42, CasID: 7, Expiration: 30},
+		},
+		{
+			name: "without ttl",
+			key:  "eternal",
+			want: &Item{Key: "eternal", Value: []byte{}, CasID: 8},
+		},
+		{
+			name:    "not found",
+			key:     "missing",
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.GetItem(context.Background(), tt.key)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_GetItem_MalformedResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   []byte
+		wantErr error
+	}{
+		{
+			name:    "invalid size",
+			reply:   []byte("VA abc kkey\r\n"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "invalid ttl",
+			reply:   []byte("VA 5 kkey t-5\r\nvalue\r\n"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "short data block",
+			reply:   []byte("VA 5 kkey\r\nval"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "unexpected line",
+			reply:   []byte("VALUE key 0 5\r\nvalue\r\nEND\r\n"),
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "another key",
+			reply:   []byte("VA 5 kother\r\nvalue\r\n"),
+			wantErr: ErrUnexpectedKey,
+		},
+		{
+			name:    "server error",
+			reply:   []byte("SERVER_ERROR out of memory\r\n"),
+			wantErr: ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(newRawServer(t, tt.reply))
+
+			got, err := client.GetItem(context.Background(), "key")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestClient_Touch_MalformedResponse(t *testing.T) {
+	client := newTestClient(newRawServer(t, []byte("STORED\r\n")))
+
+	assert.ErrorIs(t, client.Touch(context.Background(), "key", 100), ErrMalformedResponse)
+}
+
+func TestClient_Delete(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("value"), 0))
+	assert.NoError(t, client.Delete(context.Background(), "key"))
+	assert.NoError(t, client.Delete(context.Background(), "key"))
+
+	got, err := client.Get(context.Background(), "key")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, got)
+}
+
+func TestClient_DeleteExisting(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("value"), 0))
+	assert.NoError(t, client.DeleteExisting(context.Background(), "key"))
+	assert.ErrorIs(t, client.DeleteExisting(context.Background(), "key"), ErrNotFound)
+}
+
+func TestClient_GetMulti(t *testing.T) {
+	first := newFakeServer(t)
+	second := newFakeServer(t)
+	client := newTestClient(first.Addr(), second.Addr())
+
+	want := make(map[string][]byte)
+	keys := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		keys = append(keys, key)
+		if i%10 == 0 {
+			continue // Каждый десятый ключ отсутствует в Memcache
+		}
+		want[key] = []byte(fmt.Sprintf("value\r\n%d", i))
+		assert.NoError(t, client.Set(context.Background(), key, want[key], 0))
+	}
+
+	assert.NotEmpty(t, first.items)
+	assert.NotEmpty(t, second.items)
+
+	got, err := client.GetMulti(context.Background(), append(keys, keys[1]))
+	assert.NoError(t, err)
+	assert.Len(t, got, len(want))
+	for key, value := range want {
+		assert.Equal(t, key, got[key].Key)
+		assert.Equal(t, value, got[key].Value)
+		assert.NotZero(t, got[key].CasID)
+	}
+}
+
+func TestClient_GetMulti_Empty(t *testing.T) {
+	client := newTestClient(newFakeServer(t).Addr())
+
+	got, err := client.GetMulti(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestClient_GetMulti_MalformedResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr error
+	}{
+		{
+			name:    "not requested key",
+			reply:   "VALUE another 0 4 1\r\ntest\r\nEND\r\n",
+			wantErr: ErrUnexpectedKey,
+		},
+		{
+			name:    "data block shorter than header says",
+			reply:   "VALUE first 0 10 1\r\nshort\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(newRawServer(t, []byte(tt.reply)))
+
+			got, err := client.GetMulti(context.Background(), []string{"first", "second"})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestClient_Stats(t *testing.T) {
+	first := newFakeServer(t)
+	second := newFakeServer(t)
+	client := newTestClient(first.Addr(), second.Addr())
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, client.Set(context.Background(), fmt.Sprintf("key-%d", i), []byte("value"), 0))
+	}
+
+	got, err := client.Stats(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, strconv.Itoa(len(first.items)), got[first.Addr().String()]["curr_items"])
+	assert.Equal(t, strconv.Itoa(len(second.items)), got[second.Addr().String()]["curr_items"])
+	assert.Equal(t, "1.6.21", got[first.Addr().String()]["version"])
+}
+
+func TestClient_Stats_MalformedResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr error
+	}{
+		{
+			name:    "unexpected line",
+			reply:   "VALUE key 0 4\r\ntest\r\nEND\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "invalid stat line",
+			reply:   "STAT pid\r\nEND\r\n",
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name:    "server error",
+			reply:   "SERVER_ERROR out of memory\r\n",
+			wantErr: ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(newRawServer(t, []byte(tt.reply)))
+
+			got, err := client.Stats(context.Background())
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestClient_Version(t *testing.T) {
+	first := newFakeServer(t)
+	second := newFakeServer(t)
+	client := newTestClient(first.Addr(), second.Addr())
+
+	got, err := client.Version(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		first.Addr().String():  "1.6.21",
+		second.Addr().String(): "1.6.21",
+	}, got)
+}
+
+func TestClient_Version_Errors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	unavailable := listener.Addr()
+	listener.Close()
+
+	tests := []struct {
+		name    string
+		servers func(t *testing.T) []net.Addr
+		wantErr error
+	}{
+		{
+			name: "unexpected line",
+			servers: func(t *testing.T) []net.Addr {
+				return []net.Addr{newRawServer(t, []byte("END\r\n"))}
+			},
+			wantErr: ErrMalformedResponse,
+		},
+		{
+			name: "server error",
+			servers: func(t *testing.T) []net.Addr {
+				return []net.Addr{newRawServer(t, []byte("SERVER_ERROR out of memory\r\n"))}
+			},
+			wantErr: ErrServerError,
+		},
+		{
+			name: "one of servers is unavailable",
+			servers: func(t *testing.T) []net.Addr {
+				return []net.Addr{newFakeServer(t).Addr(), unavailable}
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(tt.servers(t)...)
+
+			got, err := client.Version(context.Background())
+			assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestClient_Context(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "deadline exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "canceled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "canceled before call",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := newSilentServer(t)
+			// Таймаут клиента намного больше дедлайна запроса
+			client := NewMemcacheClient(NewConfig([]net.Addr{addr}, 1, 10*time.Second))
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			started := time.Now()
+			_, err := client.Get(ctx, "key")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Less(t, time.Since(started), time.Second)
+
+			// Соединение с недочитанным ответом не возвращается в пул
+			assert.Equal(t, 0, openCount(client.connPool, addr))
+		})
+	}
+}
+
+func TestClient_NoServers(t *testing.T) {
+	c := newTestClient()
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, "key")
+	assert.ErrorIs(t, err, ErrNoServers)
+
+	_, err = c.GetMulti(ctx, []string{"key"})
+	assert.ErrorIs(t, err, ErrNoServers)
+
+	errs := c.SetMulti(ctx, []*Item{{Key: "key", Value: []byte("value")}})
+	assert.ErrorIs(t, errs[0], ErrNoServers)
+}
@@ -15,17 +15,30 @@ type Config struct {
 	timeout  time.Duration
 	poolSize int
 	servers  []net.Addr
+	selector ServerSelector
+	maxOpen  int
+
+	idleTimeout time.Duration
+	maxLifetime time.Duration
 }
 
-// NewConfig создаёт конфигурацию для библиотеки-клиента Memcache
+// NewConfig создаёт конфигурацию для библиотеки-клиента Memcache.
+// По умолчанию сервер для ключа выбирается с помощью ModuloSelector
 func NewConfig(servers []net.Addr, poolSize int, timeout time.Duration) *Config {
 	return &Config{
 		timeout:  timeout,
 		poolSize: poolSize,
 		servers:  servers,
+		selector: NewModuloSelector(servers),
 	}
 }
 
+// WithServerSelector устанавливает способ выбора сервера для ключа
+func (c *Config) WithServerSelector(selector ServerSelector) *Config {
+	c.selector = selector
+	return c
+}
+
 // Timeout возвращает таймаут
 func (c *Config) Timeout() time.Duration {
 	if c.timeout >= 0 {
@@ -41,3 +54,41 @@ func (c *Config) PoolSize() int {
 	}
 	return DefaultPoolSize
 }
+
+// WithMaxOpen устанавливает максимальное количество открытых соединений с одним сервером (0 - без ограничений)
+func (c *Config) WithMaxOpen(maxOpen int) *Config {
+	c.maxOpen = maxOpen
+	return c
+}
+
+// MaxOpen возвращает максимальное количество открытых соединений с одним сервером (0 - без ограничений)
+func (c *Config) MaxOpen() int {
+	return c.maxOpen
+}
+
+// WithIdleTimeout устанавливает время, после которого неиспользуемое соединение закрывается (0 - без ограничений)
+func (c *Config) WithIdleTimeout(idleTimeout time.Duration) *Config {
+	c.idleTimeout = idleTimeout
+	return c
+}
+
+// IdleTimeout возвращает время, после которого неиспользуемое соединение закрывается (0 - без ограничений)
+func (c *Config) IdleTimeout() time.Duration {
+	return c.idleTimeout
+}
+
+// WithMaxLifetime устанавливает максимальное время жизни соединения (0 - без ограничений)
+func (c *Config) WithMaxLifetime(maxLifetime time.Duration) *Config {
+	c.maxLifetime = maxLifetime
+	return c
+}
+
+// MaxLifetime возвращает максимальное время жизни соединения (0 - без ограничений)
+func (c *Config) MaxLifetime() time.Duration {
+	return c.maxLifetime
+}
+
+// ServerSelector возвращает способ выбора сервера для ключа
+func (c *Config) ServerSelector() ServerSelector {
+	return c.selector
+}
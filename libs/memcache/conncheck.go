@@ -0,0 +1,49 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd || solaris || illumos
+
+package memcache
+
+import (
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// errUnexpectedRead в свободном соединении оказались данные, которых клиент не ждал
+var errUnexpectedRead = errors.New("unexpected read from socket")
+
+// checkConnection проверяет, что свободное соединение не закрыто сервером. Для этого из сокета
+// неблокирующе читается один байт: если данных нет, то соединение живо, если сокет закрыт - возвращается io.EOF
+func checkConnection(conn net.Conn) error {
+	sysConn, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil
+	}
+
+	rawConn, err := sysConn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sysErr error
+	err = rawConn.Read(func(fd uintptr) bool {
+		var buf [1]byte
+		n, err := syscall.Read(int(fd), buf[:])
+		switch {
+		case n == 0 && err == nil:
+			sysErr = io.EOF
+		case n > 0:
+			sysErr = errUnexpectedRead
+		case err == syscall.EAGAIN || err == syscall.EWOULDBLOCK:
+			sysErr = nil
+		default:
+			sysErr = err
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	return sysErr
+}
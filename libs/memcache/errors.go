@@ -0,0 +1,22 @@
+package memcache
+
+import "errors"
+
+var (
+	// ErrNoServers не указано ни одного сервера Memcache
+	ErrNoServers = errors.New("no servers configured")
+	// ErrMalformedResponse ответ сервера не соответствует протоколу Memcache
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrUnexpectedKey сервер вернул значение не для того ключа, который запрашивался
+	ErrUnexpectedKey = errors.New("unexpected key in response")
+	// ErrServerError сервер ответил ERROR, CLIENT_ERROR или SERVER_ERROR
+	ErrServerError = errors.New("server error")
+	// ErrNotStored запись не сохранена, так как не выполнено условие команды (add, replace, append, prepend)
+	ErrNotStored = errors.New("not stored")
+	// ErrExists запись не сохранена командой cas, так как была изменена с момента последнего чтения
+	ErrExists = errors.New("item has been modified since last fetch")
+	// ErrNotFound записи с таким ключом нет
+	ErrNotFound = errors.New("not found")
+	// ErrNonNumeric команды incr и decr применены к значению, которое не является числом
+	ErrNonNumeric = errors.New("cannot increment or decrement non-numeric value")
+)
@@ -0,0 +1,156 @@
+package memcache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// SetMulti записывает несколько записей в Memcache. Записи группируются по серверам, каждому серверу
+// команды set отправляются конвейером, а серверы обрабатываются параллельно.
+// Возвращает ошибку для каждой записи в том же порядке, что и items (nil - запись сохранена)
+func (c *Client) SetMulti(ctx context.Context, items []*Item) []error {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+
+	return c.pipeline(ctx, keys,
+		func(w *bufio.Writer, i int) error {
+			item := items[i]
+			if _, err := fmt.Fprintf(w, "set %s %d %d %d\r\n", item.Key, item.Flags, item.Expiration, len(item.Value)); err != nil {
+				return err
+			}
+			if _, err := w.Write(item.Value); err != nil {
+				return err
+			}
+			_, err := w.Write(crlf)
+			return err
+		},
+		func(r *bufio.Reader, _ int) error {
+			row, err := readLine(r)
+			if err != nil {
+				return fmt.Errorf("can't read response: %w", err)
+			}
+
+			if err := parseStorageResponse(row); err != nil {
+				return fmt.Errorf("can't store data: %w", err)
+			}
+			return nil
+		})
+}
+
+// DeleteMulti удаляет несколько записей из Memcache конвейером команд delete, как и SetMulti.
+// Возвращает ошибку для каждого ключа в том же порядке, что и keys. Отсутствие записи ошибкой не является
+func (c *Client) DeleteMulti(ctx context.Context, keys []string) []error {
+	return c.pipeline(ctx, keys,
+		func(w *bufio.Writer, i int) error {
+			_, err := fmt.Fprintf(w, "delete %s\r\n", keys[i])
+			return err
+		},
+		func(r *bufio.Reader, _ int) error {
+			row, err := readLine(r)
+			if err != nil {
+				return fmt.Errorf("can't read response: %w", err)
+			}
+
+			if string(row) == "DELETED\r\n" || string(row) == "NOT_FOUND\r\n" {
+				return nil
+			}
+
+			if err := checkServerError(row); err != nil {
+				return fmt.Errorf("can't delete item: %w", err)
+			}
+			return fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, row)
+		})
+}
+
+// pipelineChunkSize количество команд, которые отправляются серверу без чтения ответов на них. Если отправить
+// все команды большого пакета сразу, ответы на них переполнят буферы сокетов, сервер перестанет читать команды,
+// и запись в соединение зависнет
+const pipelineChunkSize = 256
+
+// pipeline выполняет по одной команде для каждого ключа. Для каждого сервера его команды записываются
+// в соединение пакетами по pipelineChunkSize команд, и после каждого пакета по порядку читаются ответы на него.
+// Серверы обрабатываются параллельно.
+// Ответ сервера с ошибкой (NOT_STORED, SERVER_ERROR и т.п.) относится только к своей команде.
+// Если же ответ прочитать не удалось, то ответы на оставшиеся команды потеряны, и они получают ту же ошибку
+func (c *Client) pipeline(
+	ctx context.Context,
+	keys []string,
+	write func(w *bufio.Writer, i int) error,
+	read func(r *bufio.Reader, i int) error,
+) []error {
+	errs := make([]error, len(keys))
+	addrs := make(map[string]net.Addr)
+	indicesByServer := make(map[string][]int)
+	for i, key := range keys {
+		addr, err := c.connPool.GetServerAddr(key)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		addrs[addr.String()] = addr
+		indicesByServer[addr.String()] = append(indicesByServer[addr.String()], i)
+	}
+
+	var wg sync.WaitGroup
+	for serverAddress, indices := range indicesByServer {
+		wg.Add(1)
+		go func(addr net.Addr, indices []int) {
+			defer wg.Done()
+
+			// Количество команд, ответы на которые прочитаны
+			var processed int
+			err := c.execute(ctx, addr, func(buf *bufio.ReadWriter) error {
+				// Ошибка сервера, после которой соединение лучше не возвращать в пул
+				var brokenErr error
+				for start := 0; start < len(indices); start += pipelineChunkSize {
+					chunk := indices[start:]
+					if len(chunk) > pipelineChunkSize {
+						chunk = chunk[:pipelineChunkSize]
+					}
+
+					for _, i := range chunk {
+						if err := write(buf.Writer, i); err != nil {
+							return fmt.Errorf("can't format command and write bytes: %w", err)
+						}
+					}
+
+					if err := buf.Flush(); err != nil {
+						return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+					}
+
+					for _, i := range chunk {
+						err := read(buf.Reader, i)
+						if err != nil && !isResumableError(err) && !errors.Is(err, ErrServerError) {
+							return err
+						}
+
+						errs[i] = err
+						processed++
+						if err != nil && !isResumableError(err) {
+							brokenErr = err
+						}
+					}
+				}
+
+				return brokenErr
+			})
+			if err == nil {
+				return
+			}
+
+			err = fmt.Errorf("can't execute commands on %s: %w", addr.String(), err)
+			for _, i := range indices[processed:] {
+				errs[i] = err
+			}
+		}(addrs[serverAddress], indices)
+	}
+	wg.Wait()
+
+	return errs
+}
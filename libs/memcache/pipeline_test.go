@@ -0,0 +1,162 @@
+package memcache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SetMulti(t *testing.T) {
+	first := newFakeServer(t)
+	second := newFakeServer(t)
+	client := newTestClient(first.Addr(), second.Addr())
+	ctx := context.Background()
+
+	items := make([]*Item, 0, 20)
+	keys := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		keys = append(keys, key)
+		items = append(items, &Item{Key: key, Value: []byte(fmt.Sprintf("value-%d", i)), Flags: uint32(i)})
+	}
+
+	errs := client.SetMulti(ctx, items)
+	assert.Len(t, errs, len(items))
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	// Ключи распределены по обоим серверам
+	assert.NotEmpty(t, first.items)
+	assert.NotEmpty(t, second.items)
+
+	got, err := client.GetMulti(ctx, keys)
+	assert.NoError(t, err)
+	for _, item := range items {
+		assert.Equal(t, item.Value, got[item.Key].Value, item.Key)
+		assert.Equal(t, item.Flags, got[item.Key].Flags, item.Key)
+	}
+
+	errs = client.DeleteMulti(ctx, append(keys[:10:10], "missing"))
+	assert.Len(t, errs, 11)
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	got, err = client.GetMulti(ctx, keys)
+	assert.NoError(t, err)
+	assert.Len(t, got, 10)
+	for _, key := range keys[10:] {
+		assert.Contains(t, got, key)
+	}
+}
+
+func TestClient_SetMulti_Errors(t *testing.T) {
+	items := []*Item{
+		{Key: "first", Value: []byte("value")},
+		{Key: "second", Value: []byte("value")},
+		{Key: "third", Value: []byte("value")},
+		{Key: "fourth", Value: []byte("value")},
+	}
+
+	client := newTestClient(newRawServer(t, []byte("STORED\r\nNOT_STORED\r\nSERVER_ERROR out of memory\r\nSTO")))
+	errs := client.SetMulti(context.Background(), items)
+
+	assert.Len(t, errs, 4)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], ErrNotStored)
+	assert.ErrorIs(t, errs[2], ErrServerError)
+	// Ответ на последнюю команду прочитать не удалось
+	assert.Error(t, errs[3])
+	assert.NotErrorIs(t, errs[3], ErrServerError)
+}
+
+func TestClient_DeleteMulti_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr []error
+	}{
+		{
+			name:    "server error",
+			reply:   "DELETED\r\nCLIENT_ERROR bad command line format\r\n",
+			wantErr: []error{nil, ErrServerError},
+		},
+		{
+			name:    "malformed response",
+			reply:   "STORED\r\nDELETED\r\n",
+			wantErr: []error{ErrMalformedResponse, ErrMalformedResponse},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(newRawServer(t, []byte(tt.reply)))
+
+			errs := client.DeleteMulti(context.Background(), []string{"first", "second"})
+			assert.Len(t, errs, len(tt.wantErr))
+			for i, wantErr := range tt.wantErr {
+				if wantErr == nil {
+					assert.NoError(t, errs[i])
+				} else {
+					assert.ErrorIs(t, errs[i], wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_SetMulti_Unavailable(t *testing.T) {
+	client := newTestClient(newSilentServer(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := client.SetMulti(ctx, []*Item{{Key: "first"}, {Key: "second"}})
+	assert.Len(t, errs, 2)
+	for _, err := range errs {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func TestClient_DeleteMulti_Large(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	// Сервер отвечает на каждую команду и, как и Memcache, отправляет ответы, когда прочитаны все полученные команды
+	srv := &fakeServer{listener: listener}
+	go srv.serve(func(rw *bufio.ReadWriter) error {
+		if _, err := rw.ReadSlice('\n'); err != nil {
+			return err
+		}
+		if _, err := rw.WriteString("DELETED\r\n"); err != nil {
+			return err
+		}
+		if rw.Reader.Buffered() == 0 {
+			return rw.Flush()
+		}
+		return nil
+	})
+	client := NewMemcacheClient(NewConfig([]net.Addr{listener.Addr()}, 1, 5*time.Second))
+
+	// Ответы на все команды не помещаются в буферы сокетов, поэтому если отправить все команды,
+	// не читая ответов, сервер перестанет читать команды, и клиент зависнет
+	keys := make([]string, 1_000_000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	errs := client.DeleteMulti(context.Background(), keys)
+	assert.Len(t, errs, len(keys))
+	for _, err := range errs {
+		if !assert.NoError(t, err) {
+			break
+		}
+	}
+}
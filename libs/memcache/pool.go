@@ -1,55 +1,292 @@
 package memcache
 
 import (
+	"container/list"
+	"context"
+	"errors"
 	"fmt"
-	"hash/crc32"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// ErrUnknownServer сервер не входит в конфигурацию пула
+var ErrUnknownServer = errors.New("unknown server")
+
+// errConnectionExpired соединение превысило IdleTimeout или MaxLifetime
+var errConnectionExpired = errors.New("connection expired")
+
+// pooledConn соединение, принадлежащее пулу
+type pooledConn struct {
+	net.Conn
+	// Время установки соединения
+	createdAt time.Time
+	// Время последнего возврата соединения в пул
+	releasedAt time.Time
+}
+
+// expired проверяет, что соединение прожило дольше maxLifetime или простаивало дольше idleTimeout
+// (нулевые значения - без ограничений)
+func (pc *pooledConn) expired(now time.Time, idleTimeout, maxLifetime time.Duration) bool {
+	if maxLifetime > 0 && now.Sub(pc.createdAt) >= maxLifetime {
+		return true
+	}
+	return idleTimeout > 0 && now.Sub(pc.releasedAt) >= idleTimeout
+}
+
+// waitResult результат ожидания соединения: либо освободившееся соединение,
+// либо (если conn == nil) разрешение открыть новое соединение вместо закрытого
+type waitResult struct {
+	conn *pooledConn
+}
+
+// waiter горутина, ожидающая соединения
+type waiter struct {
+	result chan waitResult
+	// Признак того, что ожидающий уже получил результат и удалён из очереди
+	served bool
+}
+
+// serverPool пул соединений с одним сервером Memcache
+type serverPool struct {
+	mx sync.Mutex
+	// Свободные соединения. Новые добавляются в конец и берутся оттуда же, поэтому в начале
+	// оказываются давно не использовавшиеся соединения
+	idle []*pooledConn
+	// Количество открытых соединений (свободных, выданных и устанавливаемых в данный момент)
+	open int
+	// Очередь ожидающих соединения (FIFO), элементы - *waiter
+	waiters list.List
+
+	// Количество ожиданий соединения в очереди
+	waitCount atomic.Uint64
+	// Суммарное время ожидания соединения в очереди в наносекундах
+	waitDuration atomic.Int64
+	// Количество неудачных попыток установить соединение
+	dialErrors atomic.Uint64
+}
+
+// serveWaiter передаёт результат первому в очереди ожидающему. Возвращает false, если очередь пуста.
+// Вызывается под мьютексом
+func (sp *serverPool) serveWaiter(result waitResult) bool {
+	front := sp.waiters.Front()
+	if front == nil {
+		return false
+	}
+
+	w := sp.waiters.Remove(front).(*waiter)
+	w.served = true
+	w.result <- result
+	return true
+}
+
+// forget уменьшает счётчик открытых соединений после закрытия соединения или неудачной попытки его открыть.
+// Если соединения кто-то ждёт, то первый в очереди получает разрешение открыть новое. Вызывается под мьютексом
+func (sp *serverPool) forget() {
+	if !sp.serveWaiter(waitResult{}) {
+		sp.open--
+	}
+}
+
+// PoolStats статистика пула соединений с одним сервером Memcache
+type PoolStats struct {
+	// Количество открытых соединений (свободных, выданных и устанавливаемых в данный момент)
+	Open int
+	// Количество свободных соединений
+	Idle int
+	// Количество выданных соединений
+	InUse int
+	// Количество горутин, ожидающих соединения в данный момент
+	Waiting int
+	// Общее количество ожиданий соединения из-за достижения MaxOpen
+	WaitCount uint64
+	// Суммарное время ожидания соединения
+	WaitDuration time.Duration
+	// Количество неудачных попыток установить соединение
+	DialErrors uint64
+}
+
 // Pool отвечает за пулл соединений с Memcache
 type Pool struct {
-	mx                   sync.Mutex
-	availableConnections map[string][]net.Conn
-	cfg                  *Config
+	servers map[string]*serverPool
+	cfg     *Config
+
+	stopReaper chan struct{}
+	closeOnce  sync.Once
 }
 
-// NewPool создаёт пулл соедений с Memcache
+// NewPool создаёт пулл соедений с Memcache. Если задан IdleTimeout или MaxLifetime,
+// то запускается фоновая горутина, закрывающая устаревшие свободные соединения
 func NewPool(cfg *Config) *Pool {
-	return &Pool{
-		availableConnections: make(map[string][]net.Conn),
-		cfg:                  cfg,
+	servers := make(map[string]*serverPool, len(cfg.servers))
+	for _, addr := range cfg.servers {
+		servers[addr.String()] = &serverPool{}
+	}
+
+	pool := &Pool{
+		servers:    servers,
+		cfg:        cfg,
+		stopReaper: make(chan struct{}),
+	}
+
+	if interval := pool.reapInterval(); interval > 0 {
+		go pool.reaper(interval)
 	}
+
+	return pool
+}
+
+// Close останавливает фоновую горутину и закрывает все свободные соединения
+func (c *Pool) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stopReaper)
+		c.closeAllConnections()
+	})
 }
 
-// ReleaseConnection возвращает соединение в пулл
+// ReleaseConnection возвращает соединение в пулл. Если соединения ждёт другая горутина,
+// соединение передаётся ей напрямую. Соединение, превысившее MaxLifetime, закрывается
 func (c *Pool) ReleaseConnection(addr net.Addr, conn net.Conn) {
-	c.mx.Lock()
-	defer c.mx.Unlock()
+	sp, ok := c.servers[addr.String()]
+	if !ok {
+		conn.Close()
+		return
+	}
+
+	pc := c.wrapConnection(conn)
+	pc.releasedAt = time.Now()
+	if pc.expired(pc.releasedAt, 0, c.cfg.MaxLifetime()) {
+		c.DiscardConnection(addr, conn)
+		return
+	}
+
+	sp.mx.Lock()
+	defer sp.mx.Unlock()
+
+	if sp.serveWaiter(waitResult{conn: pc}) {
+		return
+	}
 
-	availableServerConn := c.availableConnections[addr.String()]
 	// Если размер предельный размер пула достигнут, то просто закрываем соединение, иначе возвращаем в пул
-	if len(availableServerConn) < c.cfg.PoolSize() {
-		c.availableConnections[addr.String()] = append(availableServerConn, conn)
-	} else {
-		conn.Close()
+	if len(sp.idle) < c.cfg.PoolSize() {
+		sp.idle = append(sp.idle, pc)
+		return
+	}
+
+	sp.open--
+	pc.Close()
+}
+
+// DiscardConnection закрывает соединение, не возвращая его в пул. Используется, если после ошибки
+// ввода-вывода или протокола состояние соединения неизвестно
+func (c *Pool) DiscardConnection(addr net.Addr, conn net.Conn) {
+	conn.Close()
+
+	sp, ok := c.servers[addr.String()]
+	if !ok {
+		return
+	}
+
+	c.forgetConnection(sp)
+}
+
+// wrapConnection возвращает conn как соединение пула. Соединения, полученные не из пула, считаются новыми
+func (c *Pool) wrapConnection(conn net.Conn) *pooledConn {
+	if pc, ok := conn.(*pooledConn); ok {
+		return pc
+	}
+	return &pooledConn{Conn: conn, createdAt: time.Now()}
+}
+
+// reapInterval возвращает период проверки свободных соединений: половину наименьшего из IdleTimeout и MaxLifetime.
+// Если ни одно из ограничений не задано, то возвращает 0
+func (c *Pool) reapInterval() time.Duration {
+	var interval time.Duration
+	for _, d := range []time.Duration{c.cfg.IdleTimeout(), c.cfg.MaxLifetime()} {
+		if d > 0 && (interval == 0 || d < interval) {
+			interval = d
+		}
+	}
+	return interval / 2
+}
+
+// reaper периодически закрывает свободные соединения, превысившие IdleTimeout или MaxLifetime
+func (c *Pool) reaper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.reapIdleConnections()
+		case <-c.stopReaper:
+			return
+		}
+	}
+}
+
+// reapIdleConnections закрывает свободные соединения, превысившие IdleTimeout или MaxLifetime
+func (c *Pool) reapIdleConnections() {
+	now := time.Now()
+	for _, sp := range c.servers {
+		sp.mx.Lock()
+		alive := sp.idle[:0]
+		for _, conn := range sp.idle {
+			if conn.expired(now, c.cfg.IdleTimeout(), c.cfg.MaxLifetime()) {
+				conn.Close()
+				sp.forget()
+				continue
+			}
+			alive = append(alive, conn)
+		}
+		// Обнуляем хвост, чтобы закрытые соединения не удерживались в памяти
+		for i := len(alive); i < len(sp.idle); i++ {
+			sp.idle[i] = nil
+		}
+		sp.idle = alive
+		sp.mx.Unlock()
 	}
 }
 
-// releaseAllConnections закрывает все соединения
+// closeAllConnections закрывает все свободные соединения
 func (c *Pool) closeAllConnections() {
-	for _, addr := range c.cfg.servers {
-		for _, conn := range c.availableConnections[addr.String()] {
+	for _, sp := range c.servers {
+		sp.mx.Lock()
+		for _, conn := range sp.idle {
 			conn.Close()
 		}
+		sp.open -= len(sp.idle)
+		sp.idle = nil
+		sp.mx.Unlock()
 	}
-	c.availableConnections = make(map[string][]net.Conn)
+}
+
+// Stats возвращает статистику пула по каждому серверу (ключ - адрес сервера)
+func (c *Pool) Stats() map[string]PoolStats {
+	stats := make(map[string]PoolStats, len(c.servers))
+	for addr, sp := range c.servers {
+		sp.mx.Lock()
+		open, idle, waiting := sp.open, len(sp.idle), sp.waiters.Len()
+		sp.mx.Unlock()
+
+		stats[addr] = PoolStats{
+			Open:         open,
+			Idle:         idle,
+			InUse:        open - idle,
+			Waiting:      waiting,
+			WaitCount:    sp.waitCount.Load(),
+			WaitDuration: time.Duration(sp.waitDuration.Load()),
+			DialErrors:   sp.dialErrors.Load(),
+		}
+	}
+	return stats
 }
 
 // connect устанавливает новое соединение
-func (c *Pool) connect(addr net.Addr) (net.Conn, error) {
-	conn, err := net.DialTimeout(addr.Network(), addr.String(), c.cfg.Timeout())
+func (c *Pool) connect(ctx context.Context, addr net.Addr) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: c.cfg.Timeout()}
+	conn, err := dialer.DialContext(ctx, addr.Network(), addr.String())
 	if err == nil {
 		return conn, nil
 	}
@@ -61,49 +298,153 @@ func (c *Pool) connect(addr net.Addr) (net.Conn, error) {
 	return nil, fmt.Errorf("can't connect to the address %s: %w", addr.String(), err)
 }
 
-// getFreeConnection возвращает доступное соединение, если оно есть
-func (c *Pool) getFreeConnection(addr net.Addr) (net.Conn, error) {
-	availableServerConn, ok := c.availableConnections[addr.String()]
-	if !ok || len(availableServerConn) == 0 {
-		return nil, nil
+// forgetConnection уменьшает счётчик открытых соединений после закрытия соединения или неудачной
+// попытки его открыть. Если соединения кто-то ждёт, то первый в очереди получает разрешение открыть новое
+func (c *Pool) forgetConnection(sp *serverPool) {
+	sp.mx.Lock()
+	defer sp.mx.Unlock()
+
+	sp.forget()
+}
+
+// GetServerAddr возвращает адрес сервера Memcache, на котором хранится ключ. Если серверов нет, возвращает ErrNoServers
+func (c *Pool) GetServerAddr(key string) (net.Addr, error) {
+	return c.cfg.ServerSelector().PickServer(key)
+}
+
+// AcquireConnection получает соединение из пула. Если свободных соединений нет и достигнут лимит MaxOpen,
+// то вызывающий встаёт в очередь и ждёт, пока соединение освободится. Ожидание прерывается при отмене ctx,
+// а если у ctx нет дедлайна - не позже чем через Timeout.
+// Свободное соединение перед выдачей проверяется: устаревшие и закрытые сервером соединения
+// закрываются, а вместо них берётся другое свободное соединение или открывается новое
+func (c *Pool) AcquireConnection(ctx context.Context, addr net.Addr) (net.Conn, error) {
+	sp, ok := c.servers[addr.String()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownServer, addr.String())
 	}
 
-	conn := availableServerConn[0]
-	c.availableConnections[addr.String()] = availableServerConn[1:]
-	err := conn.SetDeadline(time.Now().Add(c.cfg.Timeout()))
-	if err != nil {
-		return nil, fmt.Errorf("can't set new connection deadline: %w", err)
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("can't get connection to %s: %w", addr.String(), err)
 	}
 
-	return conn, nil
-}
+	waitCtx := ctx
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, c.cfg.Timeout())
+		defer cancel()
+	}
 
-// GetServerAddr возвращает адрес сервера Memcache
-func (c *Pool) GetServerAddr(key string) net.Addr {
-	// См. https://habr.com/ru/articles/42972/
-	return c.cfg.servers[crc32.Checksum([]byte(key), crc32.MakeTable(crc32.IEEE))%uint32(len(c.cfg.servers))]
-}
+	sp.mx.Lock()
 
-// AcquireConnection получает соединение из пула
-func (c *Pool) AcquireConnection(addr net.Addr) (net.Conn, error) {
-	c.mx.Lock()
-	defer c.mx.Unlock()
+	// Ищем доступное соединение, начиная с последнего использованного
+	for len(sp.idle) > 0 {
+		conn := sp.idle[len(sp.idle)-1]
+		sp.idle[len(sp.idle)-1] = nil
+		sp.idle = sp.idle[:len(sp.idle)-1]
+		sp.mx.Unlock()
 
-	// Ищем доступное соединение
-	existingConnection, err := c.getFreeConnection(addr)
-	if err != nil {
-		return nil, fmt.Errorf("can't get free connection: %w", err)
+		if c.checkIdleConnection(conn) == nil {
+			return c.prepareConnection(ctx, sp, conn)
+		}
+		conn.Close()
+
+		// Место закрытого соединения в счётчике открытых соединений переходит к нам:
+		// если свободных соединений больше нет, то открываем на этом месте новое
+		sp.mx.Lock()
+		if len(sp.idle) == 0 {
+			sp.mx.Unlock()
+			return c.openConnection(ctx, sp, addr)
+		}
+		sp.open--
+	}
+
+	// Если не нашли доступное и лимит не достигнут, то открываем новое
+	if maxOpen := c.cfg.MaxOpen(); maxOpen <= 0 || sp.open < maxOpen {
+		sp.open++
+		sp.mx.Unlock()
+		return c.openConnection(ctx, sp, addr)
 	}
 
-	if existingConnection != nil {
-		return existingConnection, nil
+	// Иначе ждём своей очереди
+	w := &waiter{
+		result: make(chan waitResult, 1),
+	}
+	element := sp.waiters.PushBack(w)
+	sp.mx.Unlock()
+
+	sp.waitCount.Add(1)
+	waitStart := time.Now()
+	defer func() {
+		sp.waitDuration.Add(int64(time.Since(waitStart)))
+	}()
+
+	select {
+	case result := <-w.result:
+		if result.conn != nil {
+			return c.prepareConnection(ctx, sp, result.conn)
+		}
+		return c.openConnection(ctx, sp, addr)
+
+	case <-waitCtx.Done():
+		sp.mx.Lock()
+		if !w.served {
+			sp.waiters.Remove(element)
+			sp.mx.Unlock()
+		} else {
+			// Между отменой контекста и захватом мьютекса нам уже передали соединение
+			// или разрешение открыть новое. Их нужно отдать следующему в очереди
+			sp.mx.Unlock()
+			if result := <-w.result; result.conn != nil {
+				c.ReleaseConnection(addr, result.conn)
+			} else {
+				c.forgetConnection(sp)
+			}
+		}
+
+		return nil, fmt.Errorf("can't wait for free connection to %s: %w", addr.String(), waitCtx.Err())
 	}
+}
 
-	// Если не нашли доступное, то открываем новое
-	newConn, err := c.connect(addr)
+// openConnection открывает новое соединение. Место под него в счётчике открытых соединений уже занято
+func (c *Pool) openConnection(ctx context.Context, sp *serverPool, addr net.Addr) (net.Conn, error) {
+	conn, err := c.connect(ctx, addr)
 	if err != nil {
+		sp.dialErrors.Add(1)
+		c.forgetConnection(sp)
 		return nil, fmt.Errorf("can't create new connection: %w", err)
 	}
 
-	return newConn, nil
+	return c.prepareConnection(ctx, sp, &pooledConn{Conn: conn, createdAt: time.Now()})
+}
+
+// checkIdleConnection проверяет, что свободным соединением можно пользоваться: оно не превысило IdleTimeout
+// и MaxLifetime, и сервер его не закрыл
+func (c *Pool) checkIdleConnection(conn *pooledConn) error {
+	if conn.expired(time.Now(), c.cfg.IdleTimeout(), c.cfg.MaxLifetime()) {
+		return errConnectionExpired
+	}
+
+	// Дедлайн, установленный при прошлой выдаче, мог уже пройти
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("can't reset connection deadline: %w", err)
+	}
+
+	return checkConnection(conn.Conn)
+}
+
+// prepareConnection подготавливает соединение к выдаче: устанавливает дедлайн на операции ввода-вывода.
+// Дедлайн не позже чем через Timeout, но и не позже дедлайна ctx
+func (c *Pool) prepareConnection(ctx context.Context, sp *serverPool, conn *pooledConn) (net.Conn, error) {
+	deadline := time.Now().Add(c.cfg.Timeout())
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		c.forgetConnection(sp)
+		return nil, fmt.Errorf("can't set new connection deadline: %w", err)
+	}
+
+	return conn, nil
 }
@@ -0,0 +1,356 @@
+package memcache
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPool создаёт пул соединений с ограничением maxOpen
+func newTestPool(maxOpen int, servers ...net.Addr) *Pool {
+	return NewPool(NewConfig(servers, 1, time.Second).WithMaxOpen(maxOpen))
+}
+
+// waitersCount возвращает количество ожидающих соединения с сервером
+func waitersCount(pool *Pool, addr net.Addr) int {
+	sp := pool.servers[addr.String()]
+	sp.mx.Lock()
+	defer sp.mx.Unlock()
+	return sp.waiters.Len()
+}
+
+// openCount возвращает количество открытых соединений с сервером
+func openCount(pool *Pool, addr net.Addr) int {
+	sp := pool.servers[addr.String()]
+	sp.mx.Lock()
+	defer sp.mx.Unlock()
+	return sp.open
+}
+
+func TestPool_AcquireConnection_MaxOpen(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := newTestPool(2, srv.Addr())
+
+	first, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+	second, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	third, err := pool.AcquireConnection(ctx, srv.Addr())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, third)
+	assert.Equal(t, 0, waitersCount(pool, srv.Addr()))
+	assert.Equal(t, 2, openCount(pool, srv.Addr()))
+
+	pool.ReleaseConnection(srv.Addr(), first)
+	pool.ReleaseConnection(srv.Addr(), second)
+	assert.Equal(t, int32(2), srv.accepted.Load())
+}
+
+func TestPool_AcquireConnection_FIFO(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := newTestPool(1, srv.Addr())
+
+	conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	var (
+		mx    sync.Mutex
+		wg    sync.WaitGroup
+		order []int
+	)
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			mx.Lock()
+			order = append(order, i)
+			mx.Unlock()
+
+			pool.ReleaseConnection(srv.Addr(), conn)
+		}(i)
+
+		// Следующая горутина встаёт в очередь только после того, как в ней оказалась предыдущая
+		assert.Eventually(t, func() bool {
+			return waitersCount(pool, srv.Addr()) == i+1
+		}, time.Second, time.Millisecond)
+	}
+
+	pool.ReleaseConnection(srv.Addr(), conn)
+	wg.Wait()
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+	assert.Equal(t, int32(1), srv.accepted.Load())
+}
+
+func TestPool_AcquireConnection_CancelledWaiter(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := newTestPool(1, srv.Addr())
+
+	conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := pool.AcquireConnection(ctx, srv.Addr())
+		done <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		return waitersCount(pool, srv.Addr()) == 1
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// Отменённый ожидающий не должен забрать соединение, освобождённое после отмены
+	pool.ReleaseConnection(srv.Addr(), conn)
+
+	conn, err = pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+	assert.NotNil(t, conn)
+	assert.Equal(t, 1, openCount(pool, srv.Addr()))
+}
+
+func TestPool_AcquireConnection_DialError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr()
+	listener.Close()
+
+	pool := newTestPool(1, addr)
+
+	for i := 0; i < 3; i++ {
+		conn, err := pool.AcquireConnection(context.Background(), addr)
+		assert.Error(t, err)
+		assert.Nil(t, conn)
+	}
+
+	// Неудачные попытки соединения не занимают место в пуле
+	assert.Equal(t, 0, openCount(pool, addr))
+	assert.Equal(t, uint64(3), pool.Stats()[addr.String()].DialErrors)
+}
+
+func TestPool_Stats(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := newTestPool(2, srv.Addr())
+
+	first, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+	second, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+	pool.ReleaseConnection(srv.Addr(), first)
+
+	stats := pool.Stats()[srv.Addr().String()]
+	assert.Equal(t, 2, stats.Open)
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, 1, stats.InUse)
+	assert.Zero(t, stats.WaitCount)
+
+	third, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	// Третье соединение ждёт, пока освободится одно из выданных
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		pool.ReleaseConnection(srv.Addr(), second)
+	}()
+	fourth, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	stats = pool.Stats()[srv.Addr().String()]
+	assert.Equal(t, 2, stats.InUse)
+	assert.Equal(t, 0, stats.Waiting)
+	assert.Equal(t, uint64(1), stats.WaitCount)
+	assert.GreaterOrEqual(t, stats.WaitDuration, 10*time.Millisecond)
+	assert.Zero(t, stats.DialErrors)
+
+	pool.ReleaseConnection(srv.Addr(), third)
+	pool.ReleaseConnection(srv.Addr(), fourth)
+}
+
+func TestPool_AcquireConnection_UnknownServer(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := newTestPool(1, srv.Addr())
+
+	_, err := pool.AcquireConnection(context.Background(), &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 11211})
+	assert.ErrorIs(t, err, ErrUnknownServer)
+}
+
+func TestClient_MaxOpen(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewMemcacheClient(NewConfig([]net.Addr{srv.Addr()}, 3, time.Second).WithMaxOpen(3))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key-%d", i)
+			assert.NoError(t, client.Set(context.Background(), key, []byte("value"), 0))
+
+			got, err := client.Get(context.Background(), key)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value"), got)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, srv.accepted.Load(), int32(3))
+}
+
+// idleCount возвращает количество свободных соединений с сервером
+func idleCount(pool *Pool, addr net.Addr) int {
+	sp := pool.servers[addr.String()]
+	sp.mx.Lock()
+	defer sp.mx.Unlock()
+	return len(sp.idle)
+}
+
+func TestPool_IdleTimeout(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := NewPool(NewConfig([]net.Addr{srv.Addr()}, 2, time.Second).WithIdleTimeout(50 * time.Millisecond))
+	defer pool.Close()
+
+	first, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+	second, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	pool.ReleaseConnection(srv.Addr(), first)
+	pool.ReleaseConnection(srv.Addr(), second)
+	assert.Equal(t, 2, idleCount(pool, srv.Addr()))
+
+	// Фоновая горутина закрывает простаивающие соединения
+	assert.Eventually(t, func() bool {
+		return openCount(pool, srv.Addr()) == 0 && srv.connectionsCount() == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 0, idleCount(pool, srv.Addr()))
+}
+
+func TestPool_MaxLifetime(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := NewPool(NewConfig([]net.Addr{srv.Addr()}, 1, time.Second).WithMaxLifetime(50 * time.Millisecond))
+	defer pool.Close()
+
+	conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	// Соединение, прожившее дольше MaxLifetime, не возвращается в пул
+	pool.ReleaseConnection(srv.Addr(), conn)
+	assert.Equal(t, 0, openCount(pool, srv.Addr()))
+	assert.Equal(t, 0, idleCount(pool, srv.Addr()))
+
+	conn, err = pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+	pool.ReleaseConnection(srv.Addr(), conn)
+	assert.Eventually(t, func() bool {
+		return srv.accepted.Load() == 2
+	}, time.Second, time.Millisecond)
+}
+
+func TestPool_DiscardConnection(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := newTestPool(1, srv.Addr())
+
+	conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+		if err == nil {
+			pool.ReleaseConnection(srv.Addr(), conn)
+		}
+		done <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		return waitersCount(pool, srv.Addr()) == 1
+	}, time.Second, time.Millisecond)
+
+	// Ожидающий получает разрешение открыть новое соединение вместо закрытого
+	pool.DiscardConnection(srv.Addr(), conn)
+	assert.NoError(t, <-done)
+	assert.Eventually(t, func() bool {
+		return srv.accepted.Load() == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 1, openCount(pool, srv.Addr()))
+}
+
+func TestClient_ServerClosedIdleConnection(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("value"), 0))
+
+	// Сервер закрыл соединение, пока оно лежало в пуле (например, был перезапущен)
+	srv.closeConnections()
+	assert.Eventually(t, func() bool {
+		return srv.connectionsCount() == 0
+	}, time.Second, time.Millisecond)
+
+	got, err := client.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+	assert.Equal(t, int32(2), srv.accepted.Load())
+	assert.Equal(t, 1, openCount(client.connPool, srv.Addr()))
+}
+
+func TestClient_DiscardBrokenConnection(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply []byte
+	}{
+		{
+			name:  "malformed response",
+			reply: []byte("VALUE key 0 5\r\nval"),
+		},
+		{
+			name:  "server error",
+			reply: []byte("SERVER_ERROR out of memory\r\n"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := newRawServer(t, tt.reply)
+			client := newTestClient(addr)
+
+			_, err := client.Get(context.Background(), "key")
+			assert.Error(t, err)
+			assert.Equal(t, 0, openCount(client.connPool, addr))
+			assert.Equal(t, 0, idleCount(client.connPool, addr))
+		})
+	}
+}
+
+func TestClient_KeepConnectionOnNotFound(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.ErrorIs(t, client.Touch(context.Background(), "missing", 10), ErrNotFound)
+	assert.ErrorIs(t, client.Replace(context.Background(), "missing", []byte("value"), 0), ErrNotStored)
+	assert.Equal(t, 1, idleCount(client.connPool, srv.Addr()))
+
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("value"), 0))
+	assert.Equal(t, int32(1), srv.accepted.Load())
+}
@@ -0,0 +1,339 @@
+package memcache
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var (
+	crlf           = []byte("\r\n")
+	valuePrefix    = []byte("VALUE ")
+	endLine        = []byte("END\r\n")
+	storedLine     = []byte("STORED\r\n")
+	notStoredLine  = []byte("NOT_STORED\r\n")
+	existsLine     = []byte("EXISTS\r\n")
+	notFoundLine   = []byte("NOT_FOUND\r\n")
+	touchedLine    = []byte("TOUCHED\r\n")
+	errorLine      = []byte("ERROR\r\n")
+	clientErrorPfx = []byte("CLIENT_ERROR ")
+	serverErrorPfx = []byte("SERVER_ERROR ")
+	nonNumericMsg  = []byte("non-numeric value")
+	metaValuePfx   = []byte("VA ")
+	metaMissLine   = []byte("EN\r\n")
+	statPrefix     = []byte("STAT ")
+	versionPrefix  = []byte("VERSION ")
+)
+
+// Item запись Memcache
+type Item struct {
+	// Ключ
+	Key string
+	// Значение
+	Value []byte
+	// Произвольные флаги, сохранённые вместе со значением
+	Flags uint32
+	// Уникальный идентификатор версии записи (возвращается командой gets)
+	CasID uint64
+	// При записи - время жизни в секундах или Unix-время истечения. При чтении командой GetItem -
+	// оставшееся время жизни в секундах, а командой GetAndTouch - установленное время жизни (0 - бессрочно)
+	Expiration int64
+}
+
+// readLine читает одну строку ответа вместе с завершающим \r\n
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("%w: response line is too long", ErrMalformedResponse)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read line: %w", err)
+	}
+
+	if !bytes.HasSuffix(line, crlf) {
+		return nil, fmt.Errorf("%w: line is not terminated with CRLF: %q", ErrMalformedResponse, line)
+	}
+
+	return line, nil
+}
+
+// checkServerError возвращает ошибку, если строка является ответом ERROR, CLIENT_ERROR или SERVER_ERROR
+func checkServerError(line []byte) error {
+	switch {
+	case bytes.Equal(line, errorLine):
+		return fmt.Errorf("%w: unknown command", ErrServerError)
+	case bytes.HasPrefix(line, clientErrorPfx), bytes.HasPrefix(line, serverErrorPfx):
+		return fmt.Errorf("%w: %s", ErrServerError, bytes.TrimSuffix(line, crlf))
+	}
+	return nil
+}
+
+// parseStorageResponse разбирает ответ на команды set, add, replace, append, prepend и cas
+func parseStorageResponse(line []byte) error {
+	switch {
+	case bytes.Equal(line, storedLine):
+		return nil
+	case bytes.Equal(line, notStoredLine):
+		return ErrNotStored
+	case bytes.Equal(line, existsLine):
+		return ErrExists
+	case bytes.Equal(line, notFoundLine):
+		return ErrNotFound
+	}
+
+	if err := checkServerError(line); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, line)
+}
+
+// parseTouchResponse разбирает ответ на команду touch
+func parseTouchResponse(line []byte) error {
+	switch {
+	case bytes.Equal(line, touchedLine):
+		return nil
+	case bytes.Equal(line, notFoundLine):
+		return ErrNotFound
+	}
+
+	if err := checkServerError(line); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, line)
+}
+
+// parseIncrDecrResponse разбирает ответ на команды incr и decr
+func parseIncrDecrResponse(line []byte) (uint64, error) {
+	if bytes.Equal(line, notFoundLine) {
+		return 0, ErrNotFound
+	}
+
+	if bytes.HasPrefix(line, clientErrorPfx) && bytes.Contains(line, nonNumericMsg) {
+		return 0, ErrNonNumeric
+	}
+
+	if err := checkServerError(line); err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(string(bytes.TrimSpace(line)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, line)
+	}
+
+	return value, nil
+}
+
+// parseValueHeader разбирает заголовок "VALUE <key> <flags> <bytes> [<cas unique>]\r\n"
+func parseValueHeader(line []byte) (key string, flags uint32, size int, casID uint64, err error) {
+	fields := bytes.Fields(bytes.TrimSuffix(line, crlf))
+	if len(fields) != 4 && len(fields) != 5 {
+		return "", 0, 0, 0, fmt.Errorf("%w: invalid VALUE header: %q", ErrMalformedResponse, line)
+	}
+
+	parsedFlags, err := strconv.ParseUint(string(fields[2]), 10, 32)
+	if err != nil {
+		return "", 0, 0, 0, fmt.Errorf("%w: invalid flags in VALUE header: %q", ErrMalformedResponse, line)
+	}
+
+	parsedSize, err := strconv.ParseUint(string(fields[3]), 10, 31)
+	if err != nil {
+		return "", 0, 0, 0, fmt.Errorf("%w: invalid size in VALUE header: %q", ErrMalformedResponse, line)
+	}
+
+	if len(fields) == 5 {
+		casID, err = strconv.ParseUint(string(fields[4]), 10, 64)
+		if err != nil {
+			return "", 0, 0, 0, fmt.Errorf("%w: invalid cas in VALUE header: %q", ErrMalformedResponse, line)
+		}
+	}
+
+	return string(fields[1]), uint32(parsedFlags), int(parsedSize), casID, nil
+}
+
+// readValue читает блок данных ровно из size байт и завершающий его \r\n
+func readValue(r *bufio.Reader, size int) ([]byte, error) {
+	buf := make([]byte, size+len(crlf))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: data block is shorter than %d bytes", ErrMalformedResponse, size)
+		}
+		return nil, fmt.Errorf("can't read data block: %w", err)
+	}
+
+	if !bytes.HasSuffix(buf, crlf) {
+		return nil, fmt.Errorf("%w: data block is not terminated with CRLF", ErrMalformedResponse)
+	}
+
+	return buf[:size], nil
+}
+
+// readItems читает ответ на команды get/gets до строки END и вызывает cb для каждой полученной записи
+func readItems(r *bufio.Reader, cb func(item *Item) error) error {
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(line, endLine) {
+			return nil
+		}
+
+		if err := checkServerError(line); err != nil {
+			return err
+		}
+
+		if !bytes.HasPrefix(line, valuePrefix) {
+			return fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, line)
+		}
+
+		key, flags, size, casID, err := parseValueHeader(line)
+		if err != nil {
+			return err
+		}
+
+		value, err := readValue(r, size)
+		if err != nil {
+			return err
+		}
+
+		if err := cb(&Item{
+			Key:   key,
+			Value: value,
+			Flags: flags,
+			CasID: casID,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// readMetaItem читает ответ на команду мета-протокола "mg <key> k v f c t":
+// "VA <bytes> <flags>*\r\n<data>\r\n" или "EN\r\n", если записи нет
+func readMetaItem(r *bufio.Reader) (*Item, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(line, metaMissLine) {
+		return nil, ErrNotFound
+	}
+
+	if err := checkServerError(line); err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(line, metaValuePfx) {
+		return nil, fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, line)
+	}
+
+	item, size, err := parseMetaValueHeader(line)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Value, err = readValue(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// parseMetaValueHeader разбирает заголовок "VA <bytes> <flags>*\r\n". Поддерживаются флаги
+// k (ключ), f (флаги записи), c (CAS-идентификатор) и t (оставшееся время жизни, -1 - бессрочно)
+func parseMetaValueHeader(line []byte) (*Item, int, error) {
+	fields := bytes.Fields(bytes.TrimSuffix(line, crlf))
+	if len(fields) < 2 {
+		return nil, 0, fmt.Errorf("%w: invalid VA header: %q", ErrMalformedResponse, line)
+	}
+
+	size, err := strconv.ParseUint(string(fields[1]), 10, 31)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: invalid size in VA header: %q", ErrMalformedResponse, line)
+	}
+
+	item := &Item{}
+	for _, field := range fields[2:] {
+		token := string(field[1:])
+		switch field[0] {
+		case 'k':
+			item.Key = token
+		case 'f':
+			flags, err := strconv.ParseUint(token, 10, 32)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%w: invalid flags in VA header: %q", ErrMalformedResponse, line)
+			}
+			item.Flags = uint32(flags)
+		case 'c':
+			item.CasID, err = strconv.ParseUint(token, 10, 64)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%w: invalid cas in VA header: %q", ErrMalformedResponse, line)
+			}
+		case 't':
+			ttl, err := strconv.ParseInt(token, 10, 64)
+			if err != nil || ttl < -1 {
+				return nil, 0, fmt.Errorf("%w: invalid ttl in VA header: %q", ErrMalformedResponse, line)
+			}
+			if ttl > 0 {
+				item.Expiration = ttl
+			}
+		}
+	}
+
+	return item, int(size), nil
+}
+
+// readStats читает ответ на команду stats: строки "STAT <name> <value>\r\n" до строки END
+func readStats(r *bufio.Reader) (map[string]string, error) {
+	stats := make(map[string]string)
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Equal(line, endLine) {
+			return stats, nil
+		}
+
+		if err := checkServerError(line); err != nil {
+			return nil, err
+		}
+
+		if !bytes.HasPrefix(line, statPrefix) {
+			return nil, fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, line)
+		}
+
+		name, value, ok := bytes.Cut(bytes.TrimSuffix(line[len(statPrefix):], crlf), []byte(" "))
+		if !ok || len(name) == 0 {
+			return nil, fmt.Errorf("%w: invalid STAT line: %q", ErrMalformedResponse, line)
+		}
+		stats[string(name)] = string(value)
+	}
+}
+
+// readVersion читает ответ на команду version
+func readVersion(r *bufio.Reader) (string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkServerError(line); err != nil {
+		return "", err
+	}
+
+	if !bytes.HasPrefix(line, versionPrefix) {
+		return "", fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, line)
+	}
+
+	return string(bytes.TrimSuffix(line[len(versionPrefix):], crlf)), nil
+}
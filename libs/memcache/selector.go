@@ -0,0 +1,126 @@
+package memcache
+
+import (
+	"crypto/md5"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"net"
+	"sort"
+)
+
+const (
+	// ketamaPointsPerServer количество точек на кольце для сервера с весом, равным среднему (как в libmemcached)
+	ketamaPointsPerServer = 160
+	// ketamaPointsPerHash количество точек, получаемых из одного MD5-хеша
+	ketamaPointsPerHash = 4
+)
+
+// ServerSelector выбирает сервер Memcache, на котором хранится ключ. Если серверов нет, возвращает ErrNoServers
+type ServerSelector interface {
+	PickServer(key string) (net.Addr, error)
+}
+
+// ModuloSelector выбирает сервер как остаток от деления CRC32 ключа на количество серверов.
+// При добавлении или удалении сервера почти все ключи переезжают на другие серверы
+type ModuloSelector struct {
+	servers []net.Addr
+}
+
+// NewModuloSelector создаёт ModuloSelector
+func NewModuloSelector(servers []net.Addr) *ModuloSelector {
+	return &ModuloSelector{
+		servers: servers,
+	}
+}
+
+// PickServer возвращает адрес сервера Memcache для ключа
+func (s *ModuloSelector) PickServer(key string) (net.Addr, error) {
+	if len(s.servers) == 0 {
+		return nil, ErrNoServers
+	}
+
+	// См. https://habr.com/ru/articles/42972/
+	return s.servers[crc32.ChecksumIEEE([]byte(key))%uint32(len(s.servers))], nil
+}
+
+// ketamaPoint точка на кольце консистентного хеширования
+type ketamaPoint struct {
+	hash   uint32
+	server net.Addr
+}
+
+// KetamaSelector выбирает сервер с помощью консистентного хеширования, совместимого с ketama (libmemcached).
+// Каждый сервер представлен на кольце набором виртуальных узлов, количество которых пропорционально его весу.
+// При изменении состава серверов на другие серверы переезжает только около 1/N ключей
+type KetamaSelector struct {
+	points []ketamaPoint
+}
+
+// NewKetamaSelector создаёт KetamaSelector. В weights указываются веса серверов (ключ - адрес сервера
+// в формате addr.String()). Серверы, для которых вес не указан или не положителен, получают вес 1
+func NewKetamaSelector(servers []net.Addr, weights map[string]int) *KetamaSelector {
+	totalWeight := 0
+	for _, server := range servers {
+		totalWeight += serverWeight(server, weights)
+	}
+
+	points := make([]ketamaPoint, 0, len(servers)*ketamaPointsPerServer)
+	for _, server := range servers {
+		pct := float64(serverWeight(server, weights)) / float64(totalWeight)
+		hashesCount := int(math.Floor(pct*ketamaPointsPerServer/ketamaPointsPerHash*float64(len(servers)) + 0.0000000001))
+
+		for i := 0; i < hashesCount; i++ {
+			digest := md5.Sum([]byte(fmt.Sprintf("%s-%d", server.String(), i)))
+			for h := 0; h < ketamaPointsPerHash; h++ {
+				points = append(points, ketamaPoint{
+					hash:   ketamaHash(digest, h),
+					server: server,
+				})
+			}
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].hash < points[j].hash
+	})
+
+	return &KetamaSelector{
+		points: points,
+	}
+}
+
+// PickServer возвращает адрес сервера Memcache для ключа
+func (s *KetamaSelector) PickServer(key string) (net.Addr, error) {
+	if len(s.points) == 0 {
+		return nil, ErrNoServers
+	}
+
+	hash := ketamaHash(md5.Sum([]byte(key)), 0)
+
+	// Ищем первую точку на кольце, которая не меньше хеша ключа. Если такой нет, то кольцо замыкается на первую точку
+	i := sort.Search(len(s.points), func(i int) bool {
+		return s.points[i].hash >= hash
+	})
+	if i == len(s.points) {
+		i = 0
+	}
+
+	return s.points[i].server, nil
+}
+
+// ketamaHash возвращает 32-битное число из четвёрки байтов MD5-хеша с номером n (little-endian, как в ketama)
+func ketamaHash(digest [md5.Size]byte, n int) uint32 {
+	return uint32(digest[3+n*4])<<24 |
+		uint32(digest[2+n*4])<<16 |
+		uint32(digest[1+n*4])<<8 |
+		uint32(digest[n*4])
+}
+
+// serverWeight возвращает вес сервера
+func serverWeight(server net.Addr, weights map[string]int) int {
+	if weight, ok := weights[server.String()]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
@@ -0,0 +1,141 @@
+package memcache
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testServers возвращает count адресов серверов
+func testServers(count int) []net.Addr {
+	servers := make([]net.Addr, 0, count)
+	for i := 0; i < count; i++ {
+		servers = append(servers, &net.TCPAddr{
+			IP:   net.IPv4(10, 0, 0, byte(i+1)),
+			Port: 11211,
+		})
+	}
+	return servers
+}
+
+// pickServer возвращает адрес сервера для ключа в виде строки
+func pickServer(selector ServerSelector, key string) string {
+	addr, err := selector.PickServer(key)
+	if err != nil {
+		return err.Error()
+	}
+	return addr.String()
+}
+
+// movedKeysShare возвращает долю ключей, которые после смены селектора попали на другой сервер
+func movedKeysShare(before, after ServerSelector, keysCount int) float64 {
+	moved := 0
+	for i := 0; i < keysCount; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if pickServer(before, key) != pickServer(after, key) {
+			moved++
+		}
+	}
+	return float64(moved) / float64(keysCount)
+}
+
+func TestKetamaSelector_MembershipChange(t *testing.T) {
+	const keysCount = 100000
+
+	tests := []struct {
+		name   string
+		before []net.Addr
+		after  []net.Addr
+		want   float64
+	}{
+		{
+			name:   "add server",
+			before: testServers(10),
+			after:  testServers(11),
+			want:   1.0 / 11,
+		},
+		{
+			name:   "remove server",
+			before: testServers(10),
+			after:  testServers(9),
+			want:   1.0 / 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moved := movedKeysShare(
+				NewKetamaSelector(tt.before, nil),
+				NewKetamaSelector(tt.after, nil),
+				keysCount,
+			)
+			assert.InDelta(t, tt.want, moved, 0.03)
+		})
+	}
+}
+
+func TestModuloSelector_MembershipChange(t *testing.T) {
+	moved := movedKeysShare(
+		NewModuloSelector(testServers(10)),
+		NewModuloSelector(testServers(11)),
+		10000,
+	)
+	assert.Greater(t, moved, 0.8)
+}
+
+func TestKetamaSelector_Distribution(t *testing.T) {
+	const keysCount = 100000
+
+	servers := testServers(4)
+	weights := map[string]int{
+		servers[0].String(): 2,
+	}
+	selector := NewKetamaSelector(servers, weights)
+
+	distribution := make(map[string]int)
+	for i := 0; i < keysCount; i++ {
+		distribution[pickServer(selector, fmt.Sprintf("key-%d", i))]++
+	}
+
+	// Сервер с весом 2 получает 2/5 ключей, остальные - по 1/5
+	assert.InDelta(t, 0.4, float64(distribution[servers[0].String()])/keysCount, 0.05)
+	for _, server := range servers[1:] {
+		assert.InDelta(t, 0.2, float64(distribution[server.String()])/keysCount, 0.05)
+	}
+}
+
+func TestKetamaSelector_PickServer(t *testing.T) {
+	servers := testServers(3)
+
+	first := NewKetamaSelector(servers, nil)
+	second := NewKetamaSelector([]net.Addr{servers[2], servers[0], servers[1]}, nil)
+
+	// Выбор сервера не зависит от порядка серверов в конфигурации
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		assert.Equal(t, pickServer(first, key), pickServer(second, key))
+	}
+}
+
+func TestModuloSelector_PickServer(t *testing.T) {
+	servers := testServers(3)
+	selector := NewModuloSelector(servers)
+
+	// Совпадает с тем, как сервер выбирался до появления ServerSelector
+	assert.Equal(t, servers[1].String(), pickServer(selector, "key"))
+}
+
+func TestSelector_NoServers(t *testing.T) {
+	selectors := map[string]ServerSelector{
+		"ketama": NewKetamaSelector(nil, nil),
+		"modulo": NewModuloSelector(nil),
+	}
+	for name, selector := range selectors {
+		t.Run(name, func(t *testing.T) {
+			addr, err := selector.PickServer("key")
+			assert.Nil(t, addr)
+			assert.ErrorIs(t, err, ErrNoServers)
+		})
+	}
+}
@@ -0,0 +1,466 @@
+package memcache
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeItem запись, хранящаяся в fakeServer
+type fakeItem struct {
+	value      []byte
+	flags      uint32
+	casID      uint64
+	expiration int64
+}
+
+// fakeServer простейший Memcache-сервер, работающий внутри процесса тестов
+type fakeServer struct {
+	listener net.Listener
+	mx       sync.Mutex
+	items    map[string]fakeItem
+	casSeq   uint64
+	// Количество принятых соединений
+	accepted atomic.Int32
+	// Открытые соединения
+	conns map[net.Conn]struct{}
+}
+
+// newFakeServer запускает fakeServer на случайном порту
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can't start fake server: %v", err)
+	}
+
+	srv := &fakeServer{
+		listener: listener,
+		items:    make(map[string]fakeItem),
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	go srv.serve(srv.handle)
+	return srv
+}
+
+// newRawServer запускает сервер, который на первую команду отвечает заранее заданными байтами и закрывает соединение
+func newRawServer(t *testing.T, reply []byte) net.Addr {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can't start raw server: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	srv := &fakeServer{listener: listener}
+	go srv.serve(func(rw *bufio.ReadWriter) error {
+		if _, err := rw.ReadSlice('\n'); err != nil {
+			return err
+		}
+		if _, err := rw.Write(reply); err != nil {
+			return err
+		}
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		return io.EOF
+	})
+
+	return listener.Addr()
+}
+
+// newSilentServer запускает сервер, который принимает команды, но никогда на них не отвечает
+func newSilentServer(t *testing.T) net.Addr {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can't start silent server: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	srv := &fakeServer{listener: listener}
+	go srv.serve(func(rw *bufio.ReadWriter) error {
+		_, err := rw.ReadSlice('\n')
+		return err
+	})
+
+	return listener.Addr()
+}
+
+// Addr возвращает адрес, на котором слушает сервер
+func (s *fakeServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// closeConnections закрывает все принятые сервером соединения, как при перезапуске сервера
+func (s *fakeServer) closeConnections() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// connectionsCount возвращает количество открытых соединений на стороне сервера
+func (s *fakeServer) connectionsCount() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return len(s.conns)
+}
+
+// newTestClient создаёт клиента, подключенного к указанным серверам
+func newTestClient(servers ...net.Addr) *Client {
+	return NewMemcacheClient(NewConfig(servers, 1, time.Second))
+}
+
+// serve принимает соединения и обрабатывает команды до закрытия соединения
+func (s *fakeServer) serve(handle func(rw *bufio.ReadWriter) error) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.accepted.Add(1)
+
+		s.mx.Lock()
+		if s.conns == nil {
+			s.conns = make(map[net.Conn]struct{})
+		}
+		s.conns[conn] = struct{}{}
+		s.mx.Unlock()
+
+		go func(conn net.Conn) {
+			defer func() {
+				s.mx.Lock()
+				delete(s.conns, conn)
+				s.mx.Unlock()
+				conn.Close()
+			}()
+			rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+			for {
+				if err := handle(rw); err != nil {
+					return
+				}
+			}
+		}(conn)
+	}
+}
+
+// handle обрабатывает одну команду текстового протокола Memcache
+func (s *fakeServer) handle(rw *bufio.ReadWriter) error {
+	line, err := rw.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return s.reply(rw, "ERROR")
+	}
+
+	switch fields[0] {
+	case "get", "gets":
+		return s.handleGet(rw, fields[0] == "gets", fields[1:])
+	case "set", "add", "replace", "append", "prepend", "cas":
+		return s.handleStore(rw, fields[0], fields[1:])
+	case "delete":
+		return s.handleDelete(rw, fields[1:])
+	case "incr", "decr":
+		return s.handleIncrDecr(rw, fields[0], fields[1:])
+	case "touch":
+		return s.handleTouch(rw, fields[1:])
+	case "gat", "gats":
+		if len(fields) < 2 {
+			return s.reply(rw, "ERROR")
+		}
+		expiration, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return s.reply(rw, "CLIENT_ERROR invalid exptime argument")
+		}
+		s.touch(fields[2:], expiration)
+		return s.handleGet(rw, fields[0] == "gats", fields[2:])
+	case "mg":
+		return s.handleMetaGet(rw, fields[1:])
+	case "stats":
+		return s.handleStats(rw)
+	case "version":
+		return s.reply(rw, "VERSION 1.6.21")
+	}
+
+	return s.reply(rw, "ERROR")
+}
+
+// handleStats отвечает на команду stats количеством записей и их суммарным размером
+func (s *fakeServer) handleStats(rw *bufio.ReadWriter) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	size := 0
+	for _, item := range s.items {
+		size += len(item.value)
+	}
+
+	fmt.Fprintf(rw, "STAT pid 1\r\n")
+	fmt.Fprintf(rw, "STAT version 1.6.21\r\n")
+	fmt.Fprintf(rw, "STAT curr_items %d\r\n", len(s.items))
+	fmt.Fprintf(rw, "STAT bytes %d\r\n", size)
+	fmt.Fprintf(rw, "STAT evictions 0\r\n")
+	return s.reply(rw, "END")
+}
+
+func (s *fakeServer) handleGet(rw *bufio.ReadWriter, withCas bool, keys []string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for _, key := range keys {
+		item, ok := s.items[key]
+		if !ok {
+			continue
+		}
+
+		if withCas {
+			fmt.Fprintf(rw, "VALUE %s %d %d %d\r\n", key, item.flags, len(item.value), item.casID)
+		} else {
+			fmt.Fprintf(rw, "VALUE %s %d %d\r\n", key, item.flags, len(item.value))
+		}
+		rw.Write(item.value)
+		rw.WriteString("\r\n")
+	}
+
+	return s.reply(rw, "END")
+}
+
+// handleMetaGet обрабатывает мета-команду mg с флагами k, v, f, c и t.
+// Оставшееся время жизни fakeServer не отсчитывает и возвращает время жизни, указанное при записи
+func (s *fakeServer) handleMetaGet(rw *bufio.ReadWriter, args []string) error {
+	if len(args) < 1 {
+		return s.reply(rw, "CLIENT_ERROR bad command line format")
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	item, ok := s.items[args[0]]
+	if !ok {
+		return s.reply(rw, "EN")
+	}
+
+	header := fmt.Sprintf("VA %d", len(item.value))
+	for _, flag := range args[1:] {
+		switch flag {
+		case "k":
+			header += " k" + args[0]
+		case "f":
+			header += fmt.Sprintf(" f%d", item.flags)
+		case "c":
+			header += fmt.Sprintf(" c%d", item.casID)
+		case "t":
+			if item.expiration == 0 {
+				header += " t-1"
+			} else {
+				header += fmt.Sprintf(" t%d", item.expiration)
+			}
+		}
+	}
+
+	fmt.Fprintf(rw, "%s\r\n", header)
+	rw.Write(item.value)
+	return s.reply(rw, "")
+}
+
+func (s *fakeServer) handleStore(rw *bufio.ReadWriter, command string, args []string) error {
+	if len(args) < 4 || (command == "cas" && len(args) < 5) {
+		return s.reply(rw, "ERROR")
+	}
+
+	flags, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil {
+		return s.reply(rw, "CLIENT_ERROR bad command line format")
+	}
+
+	expiration, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return s.reply(rw, "CLIENT_ERROR bad command line format")
+	}
+
+	size, err := strconv.Atoi(args[3])
+	if err != nil {
+		return s.reply(rw, "CLIENT_ERROR bad command line format")
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(rw, data); err != nil {
+		return err
+	}
+
+	if !bytes.HasSuffix(data, []byte("\r\n")) {
+		return s.reply(rw, "CLIENT_ERROR bad data chunk")
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	key, value := args[0], data[:size]
+	existing, exists := s.items[key]
+	switch command {
+	case "add":
+		if exists {
+			return s.reply(rw, "NOT_STORED")
+		}
+	case "replace":
+		if !exists {
+			return s.reply(rw, "NOT_STORED")
+		}
+	case "append", "prepend":
+		if !exists {
+			return s.reply(rw, "NOT_STORED")
+		}
+		flags = uint64(existing.flags)
+		if command == "append" {
+			value = append(append([]byte{}, existing.value...), value...)
+		} else {
+			value = append(append([]byte{}, value...), existing.value...)
+		}
+	case "cas":
+		if !exists {
+			return s.reply(rw, "NOT_FOUND")
+		}
+		casID, err := strconv.ParseUint(args[4], 10, 64)
+		if err != nil {
+			return s.reply(rw, "CLIENT_ERROR bad command line format")
+		}
+		if casID != existing.casID {
+			return s.reply(rw, "EXISTS")
+		}
+	}
+
+	s.casSeq++
+	s.items[key] = fakeItem{
+		value:      value,
+		flags:      uint32(flags),
+		casID:      s.casSeq,
+		expiration: expiration,
+	}
+
+	return s.reply(rw, "STORED")
+}
+
+func (s *fakeServer) handleDelete(rw *bufio.ReadWriter, args []string) error {
+	if len(args) < 1 {
+		return s.reply(rw, "ERROR")
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if _, ok := s.items[args[0]]; !ok {
+		return s.reply(rw, "NOT_FOUND")
+	}
+
+	delete(s.items, args[0])
+	return s.reply(rw, "DELETED")
+}
+
+func (s *fakeServer) handleIncrDecr(rw *bufio.ReadWriter, command string, args []string) error {
+	if len(args) < 2 {
+		return s.reply(rw, "ERROR")
+	}
+
+	delta, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return s.reply(rw, "CLIENT_ERROR invalid numeric delta argument")
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	item, ok := s.items[args[0]]
+	if !ok {
+		return s.reply(rw, "NOT_FOUND")
+	}
+
+	value, err := strconv.ParseUint(string(item.value), 10, 64)
+	if err != nil {
+		return s.reply(rw, "CLIENT_ERROR cannot increment or decrement non-numeric value")
+	}
+
+	switch {
+	case command == "incr":
+		value += delta
+	case delta > value:
+		value = 0
+	default:
+		value -= delta
+	}
+
+	s.casSeq++
+	item.value = []byte(strconv.FormatUint(value, 10))
+	item.casID = s.casSeq
+	s.items[args[0]] = item
+
+	return s.reply(rw, string(item.value))
+}
+
+func (s *fakeServer) handleTouch(rw *bufio.ReadWriter, args []string) error {
+	if len(args) < 2 {
+		return s.reply(rw, "ERROR")
+	}
+
+	expiration, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return s.reply(rw, "CLIENT_ERROR invalid exptime argument")
+	}
+
+	if s.touch(args[:1], expiration) == 0 {
+		return s.reply(rw, "NOT_FOUND")
+	}
+
+	return s.reply(rw, "TOUCHED")
+}
+
+// touch обновляет время жизни записей и возвращает количество найденных записей
+func (s *fakeServer) touch(keys []string, expiration int64) int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	touched := 0
+	for _, key := range keys {
+		item, ok := s.items[key]
+		if !ok {
+			continue
+		}
+		item.expiration = expiration
+		s.items[key] = item
+		touched++
+	}
+
+	return touched
+}
+
+// reply отправляет клиенту строку ответа
+func (s *fakeServer) reply(rw *bufio.ReadWriter, line string) error {
+	if _, err := rw.WriteString(line + "\r\n"); err != nil {
+		return err
+	}
+	return rw.Flush()
+}
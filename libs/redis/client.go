@@ -0,0 +1,637 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// updateScript Lua-скрипт, который атомарно изменяет числовое значение записи так же, как incr и decr в Memcache:
+// значение - беззнаковое 64-битное число, при переполнении увеличение начинается с нуля, а уменьшение
+// останавливается на нуле. Числа Lua - double, поэтому значения обрабатываются как две половины по 10 десятичных
+// разрядов. KEYS[1] - ключ, ARGV[1] - delta, ARGV[2] - операция ("incr" или "decr"). Возвращает новое значение
+// строкой, nil, если записи нет, и статус nonNumericStatus, если значение не является числом
+const updateScript = `
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return false
+end
+
+local max = '18446744073709551615'
+current = string.gsub(current, '^0+(%d)', '%1')
+if not string.match(current, '^%d+$') or #current > #max or (#current == #max and current > max) then
+	return redis.status_reply('NONNUMERIC')
+end
+
+local base = 10000000000
+local function split(value)
+	if #value <= 10 then
+		return 0, tonumber(value)
+	end
+	return tonumber(string.sub(value, 1, #value - 10)), tonumber(string.sub(value, #value - 9))
+end
+
+local hi, lo = split(current)
+local deltaHi, deltaLo = split(ARGV[1])
+if ARGV[2] == 'incr' then
+	hi, lo = hi + deltaHi, lo + deltaLo
+	if lo >= base then
+		hi, lo = hi + 1, lo - base
+	end
+	-- При переполнении вычитается 2^64 = 1844674407 * 10^10 + 3709551616
+	if hi > 1844674407 or (hi == 1844674407 and lo >= 3709551616) then
+		hi, lo = hi - 1844674407, lo - 3709551616
+		if lo < 0 then
+			hi, lo = hi - 1, lo + base
+		end
+	end
+elseif hi < deltaHi or (hi == deltaHi and lo < deltaLo) then
+	hi, lo = 0, 0
+else
+	hi, lo = hi - deltaHi, lo - deltaLo
+	if lo < 0 then
+		hi, lo = hi - 1, lo + base
+	end
+end
+
+local result
+if hi > 0 then
+	result = string.format('%d%010d', hi, lo)
+else
+	result = string.format('%d', lo)
+end
+redis.call('SET', KEYS[1], result, 'KEEPTTL')
+return result
+`
+
+// nonNumericStatus статус, которым updateScript отвечает, если значение не является числом
+const nonNumericStatus = "NONNUMERIC"
+
+// Item запись Redis
+type Item struct {
+	// Ключ
+	Key string
+	// Значение
+	Value []byte
+	// При записи - время жизни, при чтении - оставшееся время жизни (0 - бессрочная запись)
+	TTL time.Duration
+}
+
+// Client клиент для работы с Redis. Ключи распределяются между серверами с помощью ServerSelector,
+// с каждым сервером поддерживается пул соединений. Использует команды Redis 6.2 и новее
+type Client struct {
+	cfg      *Config
+	connPool *Pool
+}
+
+// NewRedisClient создаёт клиента Redis
+func NewRedisClient(cfg *Config) *Client {
+	client := &Client{
+		cfg:      cfg,
+		connPool: NewPool(cfg),
+	}
+	runtime.SetFinalizer(client, finalizer)
+	return client
+}
+
+// finalizer вызывается сборщиком мусора для корректного завершения работы клиента
+func finalizer(c *Client) {
+	c.connPool.Close()
+}
+
+// PoolStats возвращает статистику пула соединений по каждому серверу (ключ - адрес сервера)
+func (c *Client) PoolStats() map[string]PoolStats {
+	return c.connPool.Stats()
+}
+
+// execute получает соединение с сервером из пула, выполняет на нём fn и возвращает соединение в пул.
+// Дедлайн ctx ограничивает как ожидание соединения, так и операции ввода-вывода, а отмена ctx прерывает их.
+// Если fn завершилась ошибкой ввода-вывода или протокола, то состояние соединения неизвестно
+// (в нём может остаться непрочитанная часть ответа), поэтому оно закрывается, а не возвращается в пул
+func (c *Client) execute(ctx context.Context, serverAddress net.Addr, fn func(cn *conn) error) error {
+	netConn, err := c.connPool.AcquireConnection(ctx, serverAddress)
+	if err != nil {
+		return withContextError(ctx, fmt.Errorf("can't get connection from pool: %w", err))
+	}
+
+	stopWatching := watchCancel(ctx, netConn)
+	err = withContextError(ctx, fn(newConn(netConn)))
+	stopWatching()
+
+	if err != nil && !isResumableError(err) {
+		c.connPool.DiscardConnection(serverAddress, netConn)
+		return err
+	}
+
+	c.connPool.ReleaseConnection(serverAddress, netConn)
+	return err
+}
+
+// watchCancel прерывает операции ввода-вывода на соединении при отмене ctx. Возвращаемую функцию нужно вызвать
+// по завершении работы с соединением: после её возврата дедлайн соединения больше не изменится
+func watchCancel(ctx context.Context, conn net.Conn) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+			// Дедлайн в прошлом немедленно прерывает текущие и будущие операции чтения и записи
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// withContextError дополняет err ошибкой ctx, если операция была прервана отменой ctx или истечением его дедлайна.
+// Дедлайн соединения совпадает с дедлайном ctx и может сработать чуть раньше, чем ctx будет отменён
+func withContextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+
+	var netErr net.Error
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) && errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+
+	return err
+}
+
+// isResumableError проверяет, что ошибка - штатный ответ сервера, после которого соединением можно пользоваться дальше
+func isResumableError(err error) bool {
+	return errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrNotStored) ||
+		errors.Is(err, ErrNonNumeric) ||
+		errors.Is(err, ErrServerError)
+}
+
+// milliseconds возвращает время жизни в миллисекундах. Положительное время жизни меньше миллисекунды
+// округляется до неё, чтобы не превратиться в бессрочное
+func milliseconds(ttl time.Duration) int64 {
+	ms := ttl.Milliseconds()
+	if ms == 0 && ttl > 0 {
+		return 1
+	}
+	return ms
+}
+
+// remainingTTL преобразует ответ PTTL в оставшееся время жизни. Если записи нет, возвращается ErrNotFound
+func remainingTTL(reply interface{}) (time.Duration, error) {
+	pttl, err := replyInt(reply)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case pttl == -2:
+		return 0, ErrNotFound
+	case pttl < 0:
+		return 0, nil
+	}
+	return time.Duration(pttl) * time.Millisecond, nil
+}
+
+// Get получает значение записи из Redis. Если записи нет, возвращается ErrNotFound
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := c.execute(ctx, c.connPool.GetServerAddr(key), func(cn *conn) error {
+		reply, err := cn.do("GET", key)
+		if err != nil {
+			return err
+		}
+
+		value, err = replyBytes(reply)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// GetItem получает запись из Redis вместе с оставшимся временем жизни. Команды GET и PTTL отправляются
+// конвейером, то есть за одно обращение к серверу. Если записи нет, возвращается ErrNotFound
+func (c *Client) GetItem(ctx context.Context, key string) (*Item, error) {
+	item := &Item{Key: key}
+	err := c.execute(ctx, c.connPool.GetServerAddr(key), func(cn *conn) error {
+		if err := cn.send("GET", key); err != nil {
+			return err
+		}
+		if err := cn.send("PTTL", key); err != nil {
+			return err
+		}
+		if err := cn.flush(); err != nil {
+			return err
+		}
+
+		replies, err := cn.receiveAll(2)
+		if err != nil {
+			return err
+		}
+
+		if item.Value, err = replyBytes(replies[0]); err != nil {
+			return err
+		}
+		// Запись могла истечь между GET и PTTL: тогда её уже нет
+		item.TTL, err = remainingTTL(replies[1])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// GetAndTouch получает запись из Redis и одновременно устанавливает новое время жизни (команда GETEX,
+// 0 - бессрочно). Если записи нет, возвращается ErrNotFound
+func (c *Client) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*Item, error) {
+	item := &Item{Key: key, TTL: ttl}
+	err := c.execute(ctx, c.connPool.GetServerAddr(key), func(cn *conn) error {
+		var (
+			reply interface{}
+			err   error
+		)
+		if ttl > 0 {
+			reply, err = cn.do("GETEX", key, "PX", milliseconds(ttl))
+		} else {
+			reply, err = cn.do("GETEX", key, "PERSIST")
+		}
+		if err != nil {
+			return err
+		}
+
+		item.Value, err = replyBytes(reply)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// GetMulti получает несколько значений из Redis. Ключи группируются по серверам, и каждому серверу
+// одновременно отправляется одна команда MGET со всеми его ключами. Ключей, которых нет в Redis, в результате не будет
+func (c *Client) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	addrs := make(map[string]net.Addr)
+	keysByServer := make(map[string][]string)
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		addr := c.connPool.GetServerAddr(key)
+		addrs[addr.String()] = addr
+		keysByServer[addr.String()] = append(keysByServer[addr.String()], key)
+	}
+
+	var (
+		mx   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+
+	result := make(map[string][]byte, len(seen))
+	for serverAddress, serverKeys := range keysByServer {
+		wg.Add(1)
+		go func(addr net.Addr, serverKeys []string) {
+			defer wg.Done()
+
+			values, err := c.getMultiFromServer(ctx, addr, serverKeys)
+
+			mx.Lock()
+			defer mx.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("can't get data from %s: %w", addr.String(), err))
+				return
+			}
+
+			for key, value := range values {
+				result[key] = value
+			}
+		}(addrs[serverAddress], serverKeys)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return result, nil
+}
+
+// getMultiFromServer получает значения с одного сервера Redis одной командой MGET
+func (c *Client) getMultiFromServer(ctx context.Context, serverAddress net.Addr, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	err := c.execute(ctx, serverAddress, func(cn *conn) error {
+		args := make([]interface{}, 0, len(keys)+1)
+		args = append(args, "MGET")
+		for _, key := range keys {
+			args = append(args, key)
+		}
+
+		reply, err := cn.do(args...)
+		if err != nil {
+			return err
+		}
+
+		elements, ok := reply.([]interface{})
+		if !ok || len(elements) != len(keys) {
+			return fmt.Errorf("%w: unexpected MGET reply: %v", ErrMalformedResponse, reply)
+		}
+
+		for i, element := range elements {
+			value, err := replyBytes(element)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			values[keys[i]] = value
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Set делает запись в Redis (0 - бессрочная запись)
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.store(ctx, key, value, ttl, "")
+}
+
+// Add делает запись в Redis, только если записи с таким ключом ещё нет (SET NX).
+// Если запись уже есть, возвращается ErrNotStored
+func (c *Client) Add(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.store(ctx, key, value, ttl, "NX")
+}
+
+// Replace перезаписывает значение в Redis, только если запись с таким ключом уже есть (SET XX).
+// Если записи нет, возвращается ErrNotStored
+func (c *Client) Replace(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.store(ctx, key, value, ttl, "XX")
+}
+
+// store выполняет команду SET с условием condition (NX, XX или пустая строка - без условия)
+func (c *Client) store(ctx context.Context, key string, value []byte, ttl time.Duration, condition string) error {
+	return c.execute(ctx, c.connPool.GetServerAddr(key), func(cn *conn) error {
+		reply, err := cn.do(setCommand(key, value, ttl, condition)...)
+		if err != nil {
+			return fmt.Errorf("can't store data: %w", err)
+		}
+
+		return parseSetReply(reply)
+	})
+}
+
+// setCommand возвращает аргументы команды SET
+func setCommand(key string, value []byte, ttl time.Duration, condition string) []interface{} {
+	args := []interface{}{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", milliseconds(ttl))
+	}
+	if condition != "" {
+		args = append(args, condition)
+	}
+	return args
+}
+
+// parseSetReply разбирает ответ на команду SET: OK или nil, если не выполнено условие NX или XX
+func parseSetReply(reply interface{}) error {
+	if reply == nil {
+		return ErrNotStored
+	}
+	return replyStatus(reply, "OK")
+}
+
+// Delete удаляет запись из Redis. Отсутствие записи ошибкой не является
+func (c *Client) Delete(ctx context.Context, key string) error {
+	if err := c.DeleteExisting(ctx, key); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExisting удаляет запись из Redis. Если записи нет, возвращается ErrNotFound
+func (c *Client) DeleteExisting(ctx context.Context, key string) error {
+	return c.execute(ctx, c.connPool.GetServerAddr(key), func(cn *conn) error {
+		reply, err := cn.do("DEL", key)
+		if err != nil {
+			return fmt.Errorf("can't delete item: %w", err)
+		}
+
+		deleted, err := replyInt(reply)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// Touch устанавливает новое время жизни записи (0 - бессрочно). Если записи нет, возвращается ErrNotFound
+func (c *Client) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	return c.execute(ctx, c.connPool.GetServerAddr(key), func(cn *conn) error {
+		if ttl > 0 {
+			reply, err := cn.do("PEXPIRE", key, milliseconds(ttl))
+			if err != nil {
+				return fmt.Errorf("can't touch item: %w", err)
+			}
+
+			updated, err := replyInt(reply)
+			if err != nil {
+				return err
+			}
+			if updated == 0 {
+				return ErrNotFound
+			}
+			return nil
+		}
+
+		// PERSIST возвращает 0 и для бессрочной записи, поэтому существование записи проверяется отдельно
+		if err := cn.send("PERSIST", key); err != nil {
+			return err
+		}
+		if err := cn.send("EXISTS", key); err != nil {
+			return err
+		}
+		if err := cn.flush(); err != nil {
+			return err
+		}
+
+		replies, err := cn.receiveAll(2)
+		if err != nil {
+			return fmt.Errorf("can't touch item: %w", err)
+		}
+		if _, err := replyInt(replies[0]); err != nil {
+			return fmt.Errorf("can't touch item: %w", err)
+		}
+
+		exists, err := replyInt(replies[1])
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// Increment увеличивает числовое значение записи на delta и возвращает новое значение. В отличие от INCRBY,
+// работает как в Memcache: значение - беззнаковое 64-битное число, при переполнении начинается с нуля,
+// а если записи нет, возвращается ErrNotFound. Если значение не является числом, возвращается ErrNonNumeric
+func (c *Client) Increment(ctx context.Context, key string, delta uint64) (uint64, error) {
+	return c.update(ctx, key, "incr", delta)
+}
+
+// Decrement уменьшает числовое значение записи на delta и возвращает новое значение.
+// Как и в Memcache, значение не может стать меньше нуля. Если записи нет, возвращается ErrNotFound,
+// если значение не является числом - ErrNonNumeric
+func (c *Client) Decrement(ctx context.Context, key string, delta uint64) (uint64, error) {
+	return c.update(ctx, key, "decr", delta)
+}
+
+// update атомарно изменяет числовое значение записи одним вызовом updateScript с сохранением времени жизни
+func (c *Client) update(ctx context.Context, key string, operation string, delta uint64) (uint64, error) {
+	var value uint64
+	err := c.execute(ctx, c.connPool.GetServerAddr(key), func(cn *conn) error {
+		reply, err := cn.do("EVAL", updateScript, 1, key, delta, operation)
+		if err != nil {
+			return fmt.Errorf("can't update value: %w", err)
+		}
+
+		switch reply := reply.(type) {
+		case nil:
+			return ErrNotFound
+		case string:
+			if reply == nonNumericStatus {
+				return ErrNonNumeric
+			}
+		case []byte:
+			value, err = strconv.ParseUint(string(reply), 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: invalid value: %q", ErrMalformedResponse, reply)
+			}
+			return nil
+		}
+
+		return fmt.Errorf("%w: unexpected EVAL reply: %v", ErrMalformedResponse, reply)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// Info возвращает показатели каждого сервера Redis (ответ команды INFO). Ключ результата - адрес сервера,
+// значение - показатели сервера по названиям (used_memory, evicted_keys, db0 и т.д.)
+func (c *Client) Info(ctx context.Context) (map[string]map[string]string, error) {
+	var mx sync.Mutex
+	result := make(map[string]map[string]string, len(c.cfg.servers))
+	err := c.eachServer(func(addr net.Addr) error {
+		var info map[string]string
+		err := c.execute(ctx, addr, func(cn *conn) error {
+			reply, err := cn.do("INFO")
+			if err != nil {
+				return err
+			}
+
+			raw, err := replyBytes(reply)
+			if err != nil {
+				return err
+			}
+			info = parseInfo(raw)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("can't get info from %s: %w", addr.String(), err)
+		}
+
+		mx.Lock()
+		defer mx.Unlock()
+		result[addr.String()] = info
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Ping проверяет доступность всех серверов Redis: каждый сервер должен ответить на команду PING
+func (c *Client) Ping(ctx context.Context) error {
+	return c.eachServer(func(addr net.Addr) error {
+		err := c.execute(ctx, addr, func(cn *conn) error {
+			reply, err := cn.do("PING")
+			if err != nil {
+				return err
+			}
+			return replyStatus(reply, "PONG")
+		})
+		if err != nil {
+			return fmt.Errorf("can't ping %s: %w", addr.String(), err)
+		}
+		return nil
+	})
+}
+
+// eachServer параллельно выполняет fn для каждого сервера Redis и объединяет ошибки всех серверов
+func (c *Client) eachServer(fn func(addr net.Addr) error) error {
+	var (
+		mx   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+
+	for _, addr := range c.cfg.servers {
+		wg.Add(1)
+		go func(addr net.Addr) {
+			defer wg.Done()
+
+			if err := fn(addr); err != nil {
+				mx.Lock()
+				defer mx.Unlock()
+				errs = append(errs, err)
+			}
+		}(addr)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,424 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Get(t *testing.T) {
+	random := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	tests := []struct {
+		name  string
+		value []byte
+	}{
+		{
+			name:  "plain text",
+			value: []byte("test"),
+		},
+		{
+			name:  "empty value",
+			value: []byte{},
+		},
+		{
+			name:  "value with protocol keywords",
+			value: []byte("\r\n$-1\r\n*2\r\n+OK\r\n"),
+		},
+		{
+			name:  "value with zero bytes",
+			value: []byte{0, 0, '\r', 0, '\n', 0},
+		},
+		{
+			name:  "large binary value",
+			value: random,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeServer(t)
+			client := newTestClient(srv.Addr())
+
+			assert.NoError(t, client.Set(context.Background(), "key", tt.value, 0))
+
+			got, err := client.Get(context.Background(), "key")
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(tt.value, got))
+		})
+	}
+}
+
+func TestClient_Get_NotFound(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	got, err := client.Get(context.Background(), "not-existing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, got)
+}
+
+func TestClient_Get_MalformedResponse(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		err   error
+	}{
+		{
+			name:  "unknown reply type",
+			reply: "?\r\n",
+			err:   ErrMalformedResponse,
+		},
+		{
+			name:  "line without CRLF",
+			reply: "$4\n",
+			err:   ErrMalformedResponse,
+		},
+		{
+			name:  "invalid bulk length",
+			reply: "$x\r\n",
+			err:   ErrMalformedResponse,
+		},
+		{
+			name:  "truncated bulk string",
+			reply: "$10\r\nvalue\r\n",
+			err:   ErrMalformedResponse,
+		},
+		{
+			name:  "bulk string without CRLF",
+			reply: "$5\r\nvalue!!",
+			err:   ErrMalformedResponse,
+		},
+		{
+			name:  "integer instead of bulk string",
+			reply: ":1\r\n",
+			err:   ErrMalformedResponse,
+		},
+		{
+			name:  "server error",
+			reply: "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n",
+			err:   ErrServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(newRawServer(t, []byte(tt.reply)))
+
+			_, err := client.Get(context.Background(), "key")
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestClient_Set(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	assert.NoError(t, client.Set(ctx, "key", []byte("first"), 0))
+	assert.NoError(t, client.Set(ctx, "key", []byte("second"), time.Minute))
+
+	item, err := client.GetItem(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("second"), item.Value)
+	assert.InDelta(t, time.Minute, item.TTL, float64(time.Second))
+
+	// Время жизни меньше миллисекунды не должно превращаться в бессрочное
+	assert.NoError(t, client.Set(ctx, "short", []byte("value"), time.Microsecond))
+	time.Sleep(5 * time.Millisecond)
+	_, err = client.Get(ctx, "short")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_AddReplace(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	assert.ErrorIs(t, client.Replace(ctx, "key", []byte("value"), 0), ErrNotStored)
+	assert.NoError(t, client.Add(ctx, "key", []byte("first"), 0))
+	assert.ErrorIs(t, client.Add(ctx, "key", []byte("second"), 0), ErrNotStored)
+	assert.NoError(t, client.Replace(ctx, "key", []byte("third"), 0))
+
+	got, err := client.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("third"), got)
+	assert.Equal(t, int32(1), srv.accepted.Load())
+}
+
+func TestClient_IncrementDecrement(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	_, err := client.Increment(ctx, "counter", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoError(t, client.Set(ctx, "counter", []byte("10"), time.Minute))
+
+	value, err := client.Increment(ctx, "counter", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(15), value)
+
+	value, err = client.Decrement(ctx, "counter", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), value)
+
+	// Время жизни при изменении значения сохраняется
+	item, err := client.GetItem(ctx, "counter")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("0"), item.Value)
+	assert.Greater(t, item.TTL, time.Duration(0))
+
+	assert.NoError(t, client.Set(ctx, "counter", []byte(strconv.FormatUint(math.MaxUint64, 10)), 0))
+	value, err = client.Increment(ctx, "counter", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), value)
+
+	assert.NoError(t, client.Set(ctx, "text", []byte("abc"), 0))
+	_, err = client.Increment(ctx, "text", 1)
+	assert.ErrorIs(t, err, ErrNonNumeric)
+
+	// После ошибок соединение остаётся в пуле
+	assert.NoError(t, client.Set(ctx, "text", []byte("1"), 0))
+	value, err = client.Increment(ctx, "text", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), value)
+	assert.Equal(t, int32(1), srv.accepted.Load())
+}
+
+func TestClient_Increment_Concurrent(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	assert.NoError(t, client.Set(ctx, "counter", []byte("0"), 0))
+
+	// Изменение выполняется одной командой, поэтому одновременные изменения не мешают друг другу
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, err := client.Increment(ctx, "counter", 2)
+				assert.NoError(t, err)
+				_, err = client.Decrement(ctx, "counter", 1)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	item, err := client.GetItem(ctx, "counter")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1000"), item.Value)
+}
+
+func TestClient_Touch(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	assert.ErrorIs(t, client.Touch(ctx, "key", time.Minute), ErrNotFound)
+	assert.ErrorIs(t, client.Touch(ctx, "key", 0), ErrNotFound)
+
+	assert.NoError(t, client.Set(ctx, "key", []byte("value"), 0))
+	assert.NoError(t, client.Touch(ctx, "key", time.Minute))
+
+	item, err := client.GetItem(ctx, "key")
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Minute, item.TTL, float64(time.Second))
+
+	assert.NoError(t, client.Touch(ctx, "key", 0))
+	// Повторное снятие времени жизни у бессрочной записи ошибкой не является
+	assert.NoError(t, client.Touch(ctx, "key", 0))
+
+	item, err = client.GetItem(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), item.TTL)
+}
+
+func TestClient_GetAndTouch(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	_, err := client.GetAndTouch(ctx, "key", time.Minute)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoError(t, client.Set(ctx, "key", []byte("value"), time.Second))
+
+	item, err := client.GetAndTouch(ctx, "key", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, &Item{Key: "key", Value: []byte("value"), TTL: time.Hour}, item)
+
+	item, err = client.GetItem(ctx, "key")
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Hour, item.TTL, float64(time.Second))
+
+	_, err = client.GetAndTouch(ctx, "key", 0)
+	assert.NoError(t, err)
+	item, err = client.GetItem(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), item.TTL)
+}
+
+func TestClient_GetItem_NotFound(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	_, err := client.GetItem(context.Background(), "key")
+	assert.ErrorIs(t, err, ErrNotFound)
+	// Оба ответа конвейера прочитаны, поэтому соединение переиспользуется
+	_, err = client.GetItem(context.Background(), "key")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, int32(1), srv.accepted.Load())
+}
+
+func TestClient_Delete(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	assert.NoError(t, client.Delete(ctx, "key"))
+	assert.NoError(t, client.Set(ctx, "key", []byte("value"), 0))
+	assert.NoError(t, client.Delete(ctx, "key"))
+
+	_, err := client.Get(ctx, "key")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_DeleteExisting(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	assert.NoError(t, client.Set(ctx, "key", []byte("value"), 0))
+	assert.NoError(t, client.DeleteExisting(ctx, "key"))
+	assert.ErrorIs(t, client.DeleteExisting(ctx, "key"), ErrNotFound)
+}
+
+func TestClient_GetMulti(t *testing.T) {
+	servers := []*fakeServer{newFakeServer(t), newFakeServer(t), newFakeServer(t)}
+	client := newTestClient(servers[0].Addr(), servers[1].Addr(), servers[2].Addr())
+	ctx := context.Background()
+
+	keys := make([]string, 0, 30)
+	for i := 0; i < 30; i++ {
+		key := "key" + strconv.Itoa(i)
+		keys = append(keys, key)
+		if i%3 != 0 {
+			assert.NoError(t, client.Set(ctx, key, []byte("value"+strconv.Itoa(i)), 0))
+		}
+	}
+
+	// Ключи распределены по всем серверам
+	for _, srv := range servers {
+		assert.Greater(t, srv.keysCount(), 0)
+	}
+
+	values, err := client.GetMulti(ctx, append(keys, "key1"))
+	assert.NoError(t, err)
+	assert.Len(t, values, 20)
+	for i, key := range keys {
+		if i%3 == 0 {
+			assert.NotContains(t, values, key)
+			continue
+		}
+		assert.Equal(t, []byte("value"+strconv.Itoa(i)), values[key])
+	}
+
+	values, err = client.GetMulti(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestClient_GetMulti_Unavailable(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr(), newRawServer(t, []byte("*1\r\n$-1\r\n")))
+
+	keys := make([]string, 20)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+
+	_, err := client.GetMulti(context.Background(), keys)
+	assert.ErrorIs(t, err, ErrMalformedResponse)
+}
+
+func TestClient_Info(t *testing.T) {
+	servers := []*fakeServer{newFakeServer(t), newFakeServer(t)}
+	client := newTestClient(servers[0].Addr(), servers[1].Addr())
+	ctx := context.Background()
+
+	assert.NoError(t, client.Set(ctx, "key", []byte("value"), time.Minute))
+
+	info, err := client.Info(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, info, 2)
+
+	keys := 0
+	for _, srv := range servers {
+		serverInfo := info[srv.Addr().String()]
+		assert.Equal(t, "7.2.0", serverInfo["redis_version"])
+		if serverInfo["db0"] == "keys=1,expires=1,avg_ttl=0" {
+			keys++
+			assert.Equal(t, "5", serverInfo["used_memory"])
+		}
+	}
+	assert.Equal(t, 1, keys)
+}
+
+func TestClient_Ping(t *testing.T) {
+	srv := newFakeServer(t)
+	assert.NoError(t, newTestClient(srv.Addr()).Ping(context.Background()))
+
+	client := newTestClient(srv.Addr(), newRawServer(t, []byte("-LOADING Redis is loading the dataset in memory\r\n")))
+	assert.ErrorIs(t, client.Ping(context.Background()), ErrServerError)
+}
+
+func TestClient_Handshake(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.password = "secret"
+	ctx := context.Background()
+
+	client := NewRedisClient(NewConfig([]net.Addr{srv.Addr()}, 1, time.Second).
+		WithPassword("secret").
+		WithDB(3))
+	assert.NoError(t, client.Set(ctx, "key", []byte("value"), 0))
+	assert.Equal(t, int32(3), srv.selectedDB.Load())
+
+	client = NewRedisClient(NewConfig([]net.Addr{srv.Addr()}, 1, time.Second).WithPassword("wrong"))
+	err := client.Set(ctx, "key", []byte("value"), 0)
+	assert.ErrorIs(t, err, ErrServerError)
+	assert.ErrorContains(t, err, "AUTH failed")
+
+	client = newTestClient(srv.Addr())
+	assert.ErrorIs(t, client.Set(ctx, "key", []byte("value"), 0), ErrServerError)
+}
+
+func TestClient_Context(t *testing.T) {
+	client := newTestClient(newSilentServer(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Get(ctx, "key")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = client.Get(ctx, "key")
+	assert.ErrorIs(t, err, context.Canceled)
+}
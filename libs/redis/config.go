@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"net"
+	"time"
+)
+
+const (
+	DefaultTimeout  = time.Second
+	DefaultPoolSize = 5
+)
+
+// Config конфигурация для библиотеки-клиента Redis
+type Config struct {
+	timeout   time.Duration
+	poolSize  int
+	servers   []net.Addr
+	selector  ServerSelector
+	maxActive int
+
+	idleTimeout time.Duration
+	maxLifetime time.Duration
+
+	password string
+	db       int
+}
+
+// NewConfig создаёт конфигурацию для библиотеки-клиента Redis.
+// По умолчанию сервер для ключа выбирается с помощью ModuloSelector
+func NewConfig(servers []net.Addr, poolSize int, timeout time.Duration) *Config {
+	return &Config{
+		timeout:  timeout,
+		poolSize: poolSize,
+		servers:  servers,
+		selector: NewModuloSelector(servers),
+	}
+}
+
+// WithServerSelector устанавливает способ выбора сервера для ключа
+func (c *Config) WithServerSelector(selector ServerSelector) *Config {
+	c.selector = selector
+	return c
+}
+
+// ServerSelector возвращает способ выбора сервера для ключа
+func (c *Config) ServerSelector() ServerSelector {
+	return c.selector
+}
+
+// Timeout возвращает таймаут
+func (c *Config) Timeout() time.Duration {
+	if c.timeout > 0 {
+		return c.timeout
+	}
+	return DefaultTimeout
+}
+
+// PoolSize возвращает максимальное количество свободных соединений с одним сервером, хранящихся в пуле
+func (c *Config) PoolSize() int {
+	if c.poolSize >= 0 {
+		return c.poolSize
+	}
+	return DefaultPoolSize
+}
+
+// WithMaxActive устанавливает максимальное количество одновременно используемых соединений с одним сервером
+// (0 - без ограничений)
+func (c *Config) WithMaxActive(maxActive int) *Config {
+	c.maxActive = maxActive
+	return c
+}
+
+// MaxActive возвращает максимальное количество одновременно используемых соединений с одним сервером
+// (0 - без ограничений)
+func (c *Config) MaxActive() int {
+	return c.maxActive
+}
+
+// WithIdleTimeout устанавливает время, после которого неиспользуемое соединение закрывается (0 - без ограничений)
+func (c *Config) WithIdleTimeout(idleTimeout time.Duration) *Config {
+	c.idleTimeout = idleTimeout
+	return c
+}
+
+// IdleTimeout возвращает время, после которого неиспользуемое соединение закрывается (0 - без ограничений)
+func (c *Config) IdleTimeout() time.Duration {
+	return c.idleTimeout
+}
+
+// WithMaxLifetime устанавливает максимальное время жизни соединения (0 - без ограничений)
+func (c *Config) WithMaxLifetime(maxLifetime time.Duration) *Config {
+	c.maxLifetime = maxLifetime
+	return c
+}
+
+// MaxLifetime возвращает максимальное время жизни соединения (0 - без ограничений)
+func (c *Config) MaxLifetime() time.Duration {
+	return c.maxLifetime
+}
+
+// WithPassword устанавливает пароль, который отправляется командой AUTH при установке соединения
+func (c *Config) WithPassword(password string) *Config {
+	c.password = password
+	return c
+}
+
+// Password возвращает пароль (пустая строка - без аутентификации)
+func (c *Config) Password() string {
+	return c.password
+}
+
+// WithDB устанавливает номер базы данных, которая выбирается командой SELECT при установке соединения
+func (c *Config) WithDB(db int) *Config {
+	c.db = db
+	return c
+}
+
+// DB возвращает номер базы данных
+func (c *Config) DB() int {
+	return c.db
+}
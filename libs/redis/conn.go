@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// conn соединение с сервером Redis с буферами чтения и записи. Команды можно отправлять по одной (do)
+// или конвейером: несколько send, один flush и столько же receive
+type conn struct {
+	rw *bufio.ReadWriter
+}
+
+// newConn создаёт буферизованное соединение
+func newConn(c net.Conn) *conn {
+	return &conn{
+		rw: bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c)),
+	}
+}
+
+// send записывает команду в буфер, не отправляя её
+func (cn *conn) send(args ...interface{}) error {
+	if err := writeCommand(cn.rw.Writer, args...); err != nil {
+		return fmt.Errorf("can't write command: %w", err)
+	}
+	return nil
+}
+
+// flush отправляет все записанные в буфер команды
+func (cn *conn) flush() error {
+	if err := cn.rw.Flush(); err != nil {
+		return fmt.Errorf("can't write buffered data to io.Writer: %w", err)
+	}
+	return nil
+}
+
+// receive читает ответ на очередную отправленную команду
+func (cn *conn) receive() (interface{}, error) {
+	reply, err := readReply(cn.rw.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response: %w", err)
+	}
+	return reply, nil
+}
+
+// do отправляет команду и читает ответ на неё
+func (cn *conn) do(args ...interface{}) (interface{}, error) {
+	if err := cn.send(args...); err != nil {
+		return nil, err
+	}
+	if err := cn.flush(); err != nil {
+		return nil, err
+	}
+	return cn.receive()
+}
+
+// receiveAll читает ответы на n отправленных конвейером команд. Ответы с ошибкой возвращаются как элементы
+// результата, чтобы ответы на следующие команды всё равно были прочитаны и соединением можно было пользоваться
+func (cn *conn) receiveAll(n int) ([]interface{}, error) {
+	replies := make([]interface{}, n)
+	for i := range replies {
+		reply, err := cn.receive()
+		if err != nil && !errors.Is(err, ErrServerError) {
+			return nil, err
+		}
+		if err != nil {
+			reply = err
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || solaris || illumos)
+
+package redis
+
+import "net"
+
+// checkConnection на этой платформе не проверяет соединение: закрытое сервером соединение
+// обнаружится при первой операции и будет закрыто клиентом
+func checkConnection(conn net.Conn) error {
+	return nil
+}
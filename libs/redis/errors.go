@@ -0,0 +1,16 @@
+package redis
+
+import "errors"
+
+var (
+	// ErrMalformedResponse ответ сервера не соответствует протоколу RESP
+	ErrMalformedResponse = errors.New("malformed response")
+	// ErrServerError сервер ответил ошибкой ("-ERR ...")
+	ErrServerError = errors.New("server error")
+	// ErrNotFound записи с таким ключом нет
+	ErrNotFound = errors.New("not found")
+	// ErrNotStored запись не сохранена, так как не выполнено условие команды (NX или XX)
+	ErrNotStored = errors.New("not stored")
+	// ErrNonNumeric значение, которое нужно увеличить или уменьшить, не является целым неотрицательным числом
+	ErrNonNumeric = errors.New("cannot increment or decrement non-numeric value")
+)
@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// SetMulti записывает несколько записей в Redis. Записи группируются по серверам, каждому серверу
+// команды SET отправляются конвейером, а серверы обрабатываются параллельно.
+// Возвращает ошибку для каждой записи в том же порядке, что и items (nil - запись сохранена)
+func (c *Client) SetMulti(ctx context.Context, items []*Item) []error {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+
+	return c.pipeline(ctx, keys,
+		func(i int) []interface{} {
+			return setCommand(items[i].Key, items[i].Value, items[i].TTL, "")
+		},
+		func(reply interface{}) error {
+			if err := parseSetReply(reply); err != nil {
+				return fmt.Errorf("can't store data: %w", err)
+			}
+			return nil
+		})
+}
+
+// DeleteMulti удаляет несколько записей из Redis конвейером команд DEL, как и SetMulti.
+// Возвращает ошибку для каждого ключа в том же порядке, что и keys. Отсутствие записи ошибкой не является
+func (c *Client) DeleteMulti(ctx context.Context, keys []string) []error {
+	return c.pipeline(ctx, keys,
+		func(i int) []interface{} {
+			return []interface{}{"DEL", keys[i]}
+		},
+		func(reply interface{}) error {
+			if _, err := replyInt(reply); err != nil {
+				return fmt.Errorf("can't delete item: %w", err)
+			}
+			return nil
+		})
+}
+
+// pipelineChunkSize количество команд, которые отправляются серверу без чтения ответов на них. Если отправить
+// все команды большого пакета сразу, ответы на них переполнят буферы сокетов, сервер перестанет читать команды,
+// и запись в соединение зависнет
+const pipelineChunkSize = 256
+
+// pipeline выполняет по одной команде для каждого ключа. Для каждого сервера его команды записываются
+// в соединение пакетами по pipelineChunkSize команд, и после каждого пакета по порядку читаются ответы на него.
+// Серверы обрабатываются параллельно. Ответ сервера с ошибкой относится только к своей команде. Если же ответы
+// прочитать не удалось, то все команды этого сервера, ответы на которые не получены, получают одну и ту же ошибку
+func (c *Client) pipeline(
+	ctx context.Context,
+	keys []string,
+	command func(i int) []interface{},
+	parse func(reply interface{}) error,
+) []error {
+	addrs := make(map[string]net.Addr)
+	indicesByServer := make(map[string][]int)
+	for i, key := range keys {
+		addr := c.connPool.GetServerAddr(key)
+		addrs[addr.String()] = addr
+		indicesByServer[addr.String()] = append(indicesByServer[addr.String()], i)
+	}
+
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for serverAddress, indices := range indicesByServer {
+		wg.Add(1)
+		go func(addr net.Addr, indices []int) {
+			defer wg.Done()
+
+			// Количество команд, ответы на которые получены
+			var processed int
+			err := c.execute(ctx, addr, func(cn *conn) error {
+				for start := 0; start < len(indices); start += pipelineChunkSize {
+					chunk := indices[start:]
+					if len(chunk) > pipelineChunkSize {
+						chunk = chunk[:pipelineChunkSize]
+					}
+
+					for _, i := range chunk {
+						if err := cn.send(command(i)...); err != nil {
+							return err
+						}
+					}
+					if err := cn.flush(); err != nil {
+						return err
+					}
+
+					replies, err := cn.receiveAll(len(chunk))
+					if err != nil {
+						return err
+					}
+
+					for n, i := range chunk {
+						errs[i] = parse(replies[n])
+					}
+					processed += len(chunk)
+				}
+				return nil
+			})
+			if err == nil {
+				return
+			}
+
+			err = fmt.Errorf("can't execute commands on %s: %w", addr.String(), err)
+			for _, i := range indices[processed:] {
+				errs[i] = err
+			}
+		}(addrs[serverAddress], indices)
+	}
+	wg.Wait()
+
+	return errs
+}
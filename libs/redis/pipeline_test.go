@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SetMulti(t *testing.T) {
+	servers := []*fakeServer{newFakeServer(t), newFakeServer(t)}
+	client := newTestClient(servers[0].Addr(), servers[1].Addr())
+	ctx := context.Background()
+
+	items := make([]*Item, 20)
+	keys := make([]string, len(items))
+	for i := range items {
+		keys[i] = "key" + strconv.Itoa(i)
+		items[i] = &Item{Key: keys[i], Value: []byte("value" + strconv.Itoa(i)), TTL: time.Minute}
+	}
+
+	errs := client.SetMulti(ctx, items)
+	assert.Equal(t, make([]error, len(items)), errs)
+	// Каждому серверу команды отправлены по одному соединению
+	for _, srv := range servers {
+		assert.Greater(t, srv.keysCount(), 0)
+		assert.Equal(t, int32(1), srv.accepted.Load())
+	}
+
+	for _, item := range items {
+		got, err := client.GetItem(ctx, item.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, item.Value, got.Value)
+		assert.InDelta(t, time.Minute, got.TTL, float64(time.Second))
+	}
+
+	errs = client.DeleteMulti(ctx, append(keys, "missing"))
+	assert.Equal(t, make([]error, len(keys)+1), errs)
+	for _, srv := range servers {
+		assert.Equal(t, 0, srv.keysCount())
+	}
+}
+
+func TestClient_SetMulti_Chunks(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+	ctx := context.Background()
+
+	// Команды отправляются несколькими пакетами, последний из которых неполный
+	items := make([]*Item, pipelineChunkSize*2+1)
+	keys := make([]string, len(items))
+	for i := range items {
+		keys[i] = "key" + strconv.Itoa(i)
+		items[i] = &Item{Key: keys[i], Value: []byte("value" + strconv.Itoa(i))}
+	}
+
+	errs := client.SetMulti(ctx, items)
+	assert.Equal(t, make([]error, len(items)), errs)
+	assert.Equal(t, len(items), srv.keysCount())
+
+	errs = client.DeleteMulti(ctx, keys)
+	assert.Equal(t, make([]error, len(keys)), errs)
+	assert.Equal(t, 0, srv.keysCount())
+}
+
+func TestClient_SetMulti_Errors(t *testing.T) {
+	client := newTestClient(newRawServer(t, []byte("+OK\r\n-OOM command not allowed when used memory > 'maxmemory'\r\n$-1\r\n")))
+
+	errs := client.SetMulti(context.Background(), []*Item{
+		{Key: "first", Value: []byte("1")},
+		{Key: "second", Value: []byte("2")},
+		{Key: "third", Value: []byte("3")},
+	})
+	assert.Len(t, errs, 3)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], ErrServerError)
+	assert.ErrorIs(t, errs[2], ErrNotStored)
+}
+
+func TestClient_DeleteMulti_Errors(t *testing.T) {
+	client := newTestClient(newRawServer(t, []byte(":1\r\n-ERR something went wrong\r\n")))
+
+	errs := client.DeleteMulti(context.Background(), []string{"first", "second", "third"})
+	assert.Len(t, errs, 3)
+	// Ответ на третью команду не получен: ошибку получают все команды сервера
+	for _, err := range errs {
+		assert.ErrorContains(t, err, "can't execute commands")
+	}
+}
+
+func TestClient_SetMulti_Unavailable(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr(), newSilentServer(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	items := make([]*Item, 20)
+	for i := range items {
+		items[i] = &Item{Key: "key" + strconv.Itoa(i), Value: []byte("value")}
+	}
+
+	errs := client.SetMulti(ctx, items)
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			failed++
+		}
+	}
+	// Команды доступного сервера выполнены, несмотря на недоступность второго
+	assert.Greater(t, failed, 0)
+	assert.Less(t, failed, len(items))
+	assert.Equal(t, len(items)-failed, srv.keysCount())
+}
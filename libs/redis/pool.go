@@ -0,0 +1,423 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrUnknownServer сервер не входит в конфигурацию пула
+var ErrUnknownServer = errors.New("unknown server")
+
+// pooledConn соединение, принадлежащее пулу
+type pooledConn struct {
+	net.Conn
+	// Время установки соединения
+	createdAt time.Time
+	// Время последнего возврата соединения в пул
+	releasedAt time.Time
+}
+
+// expired проверяет, что соединение прожило дольше maxLifetime или простаивало дольше idleTimeout
+// (нулевые значения - без ограничений)
+func (pc *pooledConn) expired(now time.Time, idleTimeout, maxLifetime time.Duration) bool {
+	if maxLifetime > 0 && now.Sub(pc.createdAt) >= maxLifetime {
+		return true
+	}
+	return idleTimeout > 0 && now.Sub(pc.releasedAt) >= idleTimeout
+}
+
+// serverPool пул соединений с одним сервером Redis
+type serverPool struct {
+	mx sync.Mutex
+	// Свободные соединения. Новые добавляются в конец и берутся оттуда же, поэтому в начале
+	// оказываются давно не использовавшиеся соединения
+	idle []*pooledConn
+	// Семафор используемых соединений: каждое выданное соединение занимает в нём место (nil - без ограничений)
+	active chan struct{}
+
+	// Количество открытых соединений (свободных и выданных)
+	open atomic.Int32
+	// Количество горутин, ожидающих соединения в данный момент
+	waiting atomic.Int32
+	// Количество ожиданий соединения из-за достижения MaxActive
+	waitCount atomic.Uint64
+	// Суммарное время ожидания соединения в наносекундах
+	waitDuration atomic.Int64
+	// Количество неудачных попыток установить соединение
+	dialErrors atomic.Uint64
+}
+
+// PoolStats статистика пула соединений с одним сервером Redis
+type PoolStats struct {
+	// Количество открытых соединений (свободных и выданных)
+	Open int
+	// Количество свободных соединений
+	Idle int
+	// Количество выданных соединений
+	InUse int
+	// Количество горутин, ожидающих соединения в данный момент
+	Waiting int
+	// Общее количество ожиданий соединения из-за достижения MaxActive
+	WaitCount uint64
+	// Суммарное время ожидания соединения
+	WaitDuration time.Duration
+	// Количество неудачных попыток установить соединение
+	DialErrors uint64
+}
+
+// Pool пул соединений с серверами Redis
+type Pool struct {
+	servers map[string]*serverPool
+	cfg     *Config
+
+	stopReaper chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewPool создаёт пул соединений с серверами Redis. Если задан IdleTimeout или MaxLifetime,
+// то запускается фоновая горутина, закрывающая устаревшие свободные соединения
+func NewPool(cfg *Config) *Pool {
+	servers := make(map[string]*serverPool, len(cfg.servers))
+	for _, addr := range cfg.servers {
+		sp := &serverPool{}
+		if maxActive := cfg.MaxActive(); maxActive > 0 {
+			sp.active = make(chan struct{}, maxActive)
+		}
+		servers[addr.String()] = sp
+	}
+
+	pool := &Pool{
+		servers:    servers,
+		cfg:        cfg,
+		stopReaper: make(chan struct{}),
+	}
+
+	if interval := pool.reapInterval(); interval > 0 {
+		go pool.reaper(interval)
+	}
+
+	return pool
+}
+
+// Close останавливает фоновую горутину и закрывает все свободные соединения
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.stopReaper)
+		for _, sp := range p.servers {
+			sp.mx.Lock()
+			for _, conn := range sp.idle {
+				conn.Close()
+				sp.open.Add(-1)
+			}
+			sp.idle = nil
+			sp.mx.Unlock()
+		}
+	})
+}
+
+// GetServerAddr возвращает адрес сервера Redis, на котором хранится ключ
+func (p *Pool) GetServerAddr(key string) net.Addr {
+	return p.cfg.ServerSelector().PickServer(key)
+}
+
+// AcquireConnection получает соединение из пула. Если достигнут лимит MaxActive, то вызывающий ждёт,
+// пока другое соединение не вернут в пул. Ожидание прерывается при отмене ctx, а если у ctx нет дедлайна -
+// не позже чем через Timeout. Свободное соединение перед выдачей проверяется: устаревшие и закрытые сервером
+// соединения закрываются, а если свободных соединений не осталось, то открывается новое
+func (p *Pool) AcquireConnection(ctx context.Context, addr net.Addr) (net.Conn, error) {
+	sp, ok := p.servers[addr.String()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownServer, addr.String())
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("can't get connection to %s: %w", addr.String(), err)
+	}
+
+	if err := p.waitActive(ctx, sp); err != nil {
+		return nil, fmt.Errorf("can't wait for free connection to %s: %w", addr.String(), err)
+	}
+
+	for {
+		sp.mx.Lock()
+		if len(sp.idle) == 0 {
+			sp.mx.Unlock()
+			break
+		}
+		conn := sp.idle[len(sp.idle)-1]
+		sp.idle[len(sp.idle)-1] = nil
+		sp.idle = sp.idle[:len(sp.idle)-1]
+		sp.mx.Unlock()
+
+		if p.checkIdleConnection(conn) == nil {
+			return p.prepareConnection(ctx, sp, conn)
+		}
+		conn.Close()
+		sp.open.Add(-1)
+	}
+
+	conn, err := p.openConnection(ctx, addr)
+	if err != nil {
+		sp.dialErrors.Add(1)
+		p.releaseActive(sp)
+		return nil, fmt.Errorf("can't create new connection: %w", err)
+	}
+	sp.open.Add(1)
+
+	return p.prepareConnection(ctx, sp, conn)
+}
+
+// ReleaseConnection возвращает соединение в пул. Если свободных соединений уже PoolSize
+// или соединение превысило MaxLifetime, то оно закрывается
+func (p *Pool) ReleaseConnection(addr net.Addr, conn net.Conn) {
+	sp, ok := p.servers[addr.String()]
+	if !ok {
+		conn.Close()
+		return
+	}
+	defer p.releaseActive(sp)
+
+	pc, ok := conn.(*pooledConn)
+	if !ok {
+		pc = &pooledConn{Conn: conn, createdAt: time.Now()}
+	}
+	pc.releasedAt = time.Now()
+	if !pc.expired(pc.releasedAt, 0, p.cfg.MaxLifetime()) {
+		sp.mx.Lock()
+		if len(sp.idle) < p.cfg.PoolSize() {
+			sp.idle = append(sp.idle, pc)
+			sp.mx.Unlock()
+			return
+		}
+		sp.mx.Unlock()
+	}
+
+	pc.Close()
+	sp.open.Add(-1)
+}
+
+// DiscardConnection закрывает соединение, не возвращая его в пул. Используется, если после ошибки
+// ввода-вывода или протокола состояние соединения неизвестно
+func (p *Pool) DiscardConnection(addr net.Addr, conn net.Conn) {
+	conn.Close()
+
+	sp, ok := p.servers[addr.String()]
+	if !ok {
+		return
+	}
+	sp.open.Add(-1)
+	p.releaseActive(sp)
+}
+
+// Stats возвращает статистику пула по каждому серверу (ключ - адрес сервера)
+func (p *Pool) Stats() map[string]PoolStats {
+	stats := make(map[string]PoolStats, len(p.servers))
+	for addr, sp := range p.servers {
+		sp.mx.Lock()
+		idle := len(sp.idle)
+		sp.mx.Unlock()
+		open := int(sp.open.Load())
+
+		stats[addr] = PoolStats{
+			Open:         open,
+			Idle:         idle,
+			InUse:        open - idle,
+			Waiting:      int(sp.waiting.Load()),
+			WaitCount:    sp.waitCount.Load(),
+			WaitDuration: time.Duration(sp.waitDuration.Load()),
+			DialErrors:   sp.dialErrors.Load(),
+		}
+	}
+	return stats
+}
+
+// waitActive занимает место в семафоре используемых соединений, при необходимости дожидаясь его освобождения
+func (p *Pool) waitActive(ctx context.Context, sp *serverPool) error {
+	if sp.active == nil {
+		return nil
+	}
+
+	select {
+	case sp.active <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.cfg.Timeout())
+		defer cancel()
+	}
+
+	sp.waiting.Add(1)
+	sp.waitCount.Add(1)
+	waitStart := time.Now()
+	defer func() {
+		sp.waiting.Add(-1)
+		sp.waitDuration.Add(int64(time.Since(waitStart)))
+	}()
+
+	select {
+	case sp.active <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseActive освобождает место в семафоре используемых соединений
+func (p *Pool) releaseActive(sp *serverPool) {
+	if sp.active != nil {
+		<-sp.active
+	}
+}
+
+// openConnection устанавливает новое соединение и, если нужно, аутентифицируется и выбирает базу данных
+func (p *Pool) openConnection(ctx context.Context, addr net.Addr) (*pooledConn, error) {
+	dialer := net.Dialer{Timeout: p.cfg.Timeout()}
+	conn, err := dialer.DialContext(ctx, addr.Network(), addr.String())
+	if err != nil {
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			return nil, fmt.Errorf("connection timeout deadline exceeded (%s): %w", addr.String(), err)
+		}
+		return nil, fmt.Errorf("can't connect to the address %s: %w", addr.String(), err)
+	}
+
+	if err := p.handshake(ctx, conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("can't initialize connection to %s: %w", addr.String(), err)
+	}
+
+	return &pooledConn{Conn: conn, createdAt: time.Now()}, nil
+}
+
+// handshake отправляет команды AUTH и SELECT, если в конфигурации заданы пароль и база данных
+func (p *Pool) handshake(ctx context.Context, conn net.Conn) error {
+	var commands [][]interface{}
+	if password := p.cfg.Password(); password != "" {
+		commands = append(commands, []interface{}{"AUTH", password})
+	}
+	if db := p.cfg.DB(); db != 0 {
+		commands = append(commands, []interface{}{"SELECT", db})
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(p.cfg.Timeout())
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("can't set connection deadline: %w", err)
+	}
+
+	cn := newConn(conn)
+	for _, command := range commands {
+		if err := cn.send(command...); err != nil {
+			return err
+		}
+	}
+	if err := cn.flush(); err != nil {
+		return err
+	}
+	for _, command := range commands {
+		reply, err := cn.receive()
+		if err != nil {
+			return fmt.Errorf("%s failed: %w", command[0], err)
+		}
+		if err := replyStatus(reply, "OK"); err != nil {
+			return fmt.Errorf("%s failed: %w", command[0], err)
+		}
+	}
+
+	return nil
+}
+
+// checkIdleConnection проверяет, что свободным соединением можно пользоваться: оно не превысило IdleTimeout
+// и MaxLifetime, и сервер его не закрыл
+func (p *Pool) checkIdleConnection(conn *pooledConn) error {
+	if conn.expired(time.Now(), p.cfg.IdleTimeout(), p.cfg.MaxLifetime()) {
+		return errors.New("connection expired")
+	}
+
+	// Дедлайн, установленный при прошлой выдаче, мог уже пройти
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("can't reset connection deadline: %w", err)
+	}
+
+	return checkConnection(conn.Conn)
+}
+
+// prepareConnection подготавливает соединение к выдаче: устанавливает дедлайн на операции ввода-вывода.
+// Дедлайн не позже чем через Timeout, но и не позже дедлайна ctx
+func (p *Pool) prepareConnection(ctx context.Context, sp *serverPool, conn *pooledConn) (net.Conn, error) {
+	deadline := time.Now().Add(p.cfg.Timeout())
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		sp.open.Add(-1)
+		p.releaseActive(sp)
+		return nil, fmt.Errorf("can't set connection deadline: %w", err)
+	}
+
+	return conn, nil
+}
+
+// reapInterval возвращает период проверки свободных соединений: половину наименьшего из IdleTimeout и MaxLifetime.
+// Если ни одно из ограничений не задано, то возвращает 0
+func (p *Pool) reapInterval() time.Duration {
+	var interval time.Duration
+	for _, d := range []time.Duration{p.cfg.IdleTimeout(), p.cfg.MaxLifetime()} {
+		if d > 0 && (interval == 0 || d < interval) {
+			interval = d
+		}
+	}
+	return interval / 2
+}
+
+// reaper периодически закрывает свободные соединения, превысившие IdleTimeout или MaxLifetime
+func (p *Pool) reaper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.reapIdleConnections()
+		case <-p.stopReaper:
+			return
+		}
+	}
+}
+
+// reapIdleConnections закрывает свободные соединения, превысившие IdleTimeout или MaxLifetime
+func (p *Pool) reapIdleConnections() {
+	now := time.Now()
+	for _, sp := range p.servers {
+		sp.mx.Lock()
+		alive := sp.idle[:0]
+		for _, conn := range sp.idle {
+			if conn.expired(now, p.cfg.IdleTimeout(), p.cfg.MaxLifetime()) {
+				conn.Close()
+				sp.open.Add(-1)
+				continue
+			}
+			alive = append(alive, conn)
+		}
+		// Обнуляем хвост, чтобы закрытые соединения не удерживались в памяти
+		for i := len(alive); i < len(sp.idle); i++ {
+			sp.idle[i] = nil
+		}
+		sp.idle = alive
+		sp.mx.Unlock()
+	}
+}
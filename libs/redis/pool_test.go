@@ -0,0 +1,192 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// poolStats возвращает статистику пула для сервера
+func poolStats(pool *Pool, addr net.Addr) PoolStats {
+	return pool.Stats()[addr.String()]
+}
+
+func TestPool_AcquireConnection_MaxActive(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := NewPool(NewConfig([]net.Addr{srv.Addr()}, 1, time.Second).WithMaxActive(1))
+	defer pool.Close()
+
+	conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	// Второе соединение не выдаётся, пока не освободится первое
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = pool.AcquireConnection(ctx, srv.Addr())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done := make(chan net.Conn)
+	go func() {
+		conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+		assert.NoError(t, err)
+		done <- conn
+	}()
+	assert.Eventually(t, func() bool {
+		return poolStats(pool, srv.Addr()).Waiting == 1
+	}, time.Second, time.Millisecond)
+
+	pool.ReleaseConnection(srv.Addr(), conn)
+	second := <-done
+	// Ожидавший получил то же соединение из пула
+	assert.Same(t, conn, second)
+	pool.ReleaseConnection(srv.Addr(), second)
+
+	stats := poolStats(pool, srv.Addr())
+	assert.Equal(t, 1, stats.Open)
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, 0, stats.Waiting)
+	assert.Equal(t, uint64(2), stats.WaitCount)
+	assert.Greater(t, stats.WaitDuration, time.Duration(0))
+	assert.Equal(t, int32(1), srv.accepted.Load())
+}
+
+func TestPool_AcquireConnection_Errors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closed := listener.Addr()
+	listener.Close()
+
+	pool := NewPool(NewConfig([]net.Addr{closed}, 1, time.Second).WithMaxActive(1))
+	defer pool.Close()
+
+	_, err = pool.AcquireConnection(context.Background(), closed)
+	assert.ErrorContains(t, err, "can't create new connection")
+	// Место неудачного соединения освобождается
+	_, err = pool.AcquireConnection(context.Background(), closed)
+	assert.ErrorContains(t, err, "can't create new connection")
+	assert.Equal(t, uint64(2), poolStats(pool, closed).DialErrors)
+	assert.Equal(t, 0, poolStats(pool, closed).Open)
+
+	_, err = pool.AcquireConnection(context.Background(), &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 6379})
+	assert.ErrorIs(t, err, ErrUnknownServer)
+}
+
+func TestPool_IdleTimeout(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := NewPool(NewConfig([]net.Addr{srv.Addr()}, 2, time.Second).WithIdleTimeout(50 * time.Millisecond))
+	defer pool.Close()
+
+	first, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+	second, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	pool.ReleaseConnection(srv.Addr(), first)
+	pool.ReleaseConnection(srv.Addr(), second)
+	assert.Equal(t, 2, poolStats(pool, srv.Addr()).Idle)
+
+	// Фоновая горутина закрывает простаивающие соединения
+	assert.Eventually(t, func() bool {
+		return poolStats(pool, srv.Addr()).Open == 0 && srv.connectionsCount() == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestPool_MaxLifetime(t *testing.T) {
+	srv := newFakeServer(t)
+	pool := NewPool(NewConfig([]net.Addr{srv.Addr()}, 1, time.Second).WithMaxLifetime(50 * time.Millisecond))
+	defer pool.Close()
+
+	conn, err := pool.AcquireConnection(context.Background(), srv.Addr())
+	assert.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	// Соединение, прожившее дольше MaxLifetime, не возвращается в пул
+	pool.ReleaseConnection(srv.Addr(), conn)
+	assert.Equal(t, PoolStats{}, poolStats(pool, srv.Addr()))
+}
+
+func TestClient_MaxActive(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewRedisClient(NewConfig([]net.Addr{srv.Addr()}, 3, time.Second).WithMaxActive(3))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key-%d", i)
+			assert.NoError(t, client.Set(context.Background(), key, []byte("value"), 0))
+
+			got, err := client.Get(context.Background(), key)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("value"), got)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, srv.accepted.Load(), int32(3))
+}
+
+func TestClient_ServerClosedIdleConnection(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newTestClient(srv.Addr())
+
+	assert.NoError(t, client.Set(context.Background(), "key", []byte("value"), 0))
+
+	// Сервер закрыл соединение, пока оно лежало в пуле (например, был перезапущен)
+	srv.closeConnections()
+	assert.Eventually(t, func() bool {
+		return srv.connectionsCount() == 0
+	}, time.Second, time.Millisecond)
+
+	got, err := client.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+	assert.Equal(t, int32(2), srv.accepted.Load())
+	assert.Equal(t, 1, poolStats(client.connPool, srv.Addr()).Open)
+}
+
+func TestClient_Connections(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		// Остаётся ли соединение в пуле после ответа
+		kept bool
+	}{
+		{
+			name:  "malformed response",
+			reply: "$5\r\nval",
+		},
+		{
+			name:  "server error",
+			reply: "-ERR something went wrong\r\n",
+			kept:  true,
+		},
+		{
+			name:  "not found",
+			reply: "$-1\r\n",
+			kept:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := newRawServer(t, []byte(tt.reply))
+			client := newTestClient(addr)
+
+			_, err := client.Get(context.Background(), "key")
+			assert.Error(t, err)
+			if tt.kept {
+				assert.Equal(t, 1, poolStats(client.connPool, addr).Idle)
+			} else {
+				assert.Equal(t, PoolStats{}, poolStats(client.connPool, addr))
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// maxBulkLength максимальный размер строки в ответе, как и максимальный размер значения в Redis
+const maxBulkLength = 512 << 20
+
+var crlf = []byte("\r\n")
+
+// writeCommand записывает команду в виде массива строк RESP: "*<n>\r\n$<len>\r\n<arg>\r\n...".
+// Аргументы могут быть строками, байтами или целыми числами. Ошибки записи bufio.Writer запоминает
+// и возвращает при Flush, поэтому здесь возвращается только ошибка неподдерживаемого типа аргумента
+func writeCommand(w *bufio.Writer, args ...interface{}) error {
+	w.WriteByte('*')
+	w.WriteString(strconv.Itoa(len(args)))
+	w.Write(crlf)
+
+	var num [20]byte
+	for _, arg := range args {
+		var value []byte
+		switch v := arg.(type) {
+		case string:
+			writeBulkHeader(w, len(v))
+			w.WriteString(v)
+			w.Write(crlf)
+			continue
+		case []byte:
+			value = v
+		case int:
+			value = strconv.AppendInt(num[:0], int64(v), 10)
+		case int64:
+			value = strconv.AppendInt(num[:0], v, 10)
+		case uint64:
+			value = strconv.AppendUint(num[:0], v, 10)
+		default:
+			return fmt.Errorf("unsupported argument type %T", arg)
+		}
+
+		writeBulkHeader(w, len(value))
+		w.Write(value)
+		w.Write(crlf)
+	}
+
+	return nil
+}
+
+// writeBulkHeader записывает заголовок строки RESP "$<len>\r\n"
+func writeBulkHeader(w *bufio.Writer, size int) {
+	w.WriteByte('$')
+	w.WriteString(strconv.Itoa(size))
+	w.Write(crlf)
+}
+
+// readLine читает одну строку ответа и возвращает её без завершающего \r\n
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("%w: response line is too long", ErrMalformedResponse)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read line: %w", err)
+	}
+
+	if !bytes.HasSuffix(line, crlf) {
+		return nil, fmt.Errorf("%w: line is not terminated with CRLF: %q", ErrMalformedResponse, line)
+	}
+
+	return line[:len(line)-len(crlf)], nil
+}
+
+// readReply читает ответ сервера. Возвращает string для простой строки, int64 для числа, []byte для строки
+// и []interface{} для массива. Для отсутствующего значения ($-1 и *-1) возвращается nil. Ответ с ошибкой
+// возвращается как ошибка ErrServerError, а ошибки внутри массива - как его элементы
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 {
+		return nil, fmt.Errorf("%w: empty line", ErrMalformedResponse)
+	}
+
+	payload := line[1:]
+	switch line[0] {
+	case '+':
+		return string(payload), nil
+	case '-':
+		return nil, fmt.Errorf("%w: %s", ErrServerError, payload)
+	case ':':
+		value, err := strconv.ParseInt(string(payload), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid integer: %q", ErrMalformedResponse, line)
+		}
+		return value, nil
+	case '$':
+		size, err := strconv.Atoi(string(payload))
+		if err != nil || size < -1 || size > maxBulkLength {
+			return nil, fmt.Errorf("%w: invalid bulk length: %q", ErrMalformedResponse, line)
+		}
+		if size == -1 {
+			return nil, nil
+		}
+		return readBulk(r, size)
+	case '*':
+		count, err := strconv.Atoi(string(payload))
+		if err != nil || count < -1 {
+			return nil, fmt.Errorf("%w: invalid array length: %q", ErrMalformedResponse, line)
+		}
+		if count == -1 {
+			return nil, nil
+		}
+
+		elements := make([]interface{}, count)
+		for i := range elements {
+			elements[i], err = readReply(r)
+			if errors.Is(err, ErrServerError) {
+				elements[i] = err
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return elements, nil
+	}
+
+	return nil, fmt.Errorf("%w: unexpected line: %q", ErrMalformedResponse, line)
+}
+
+// readBulk читает строку ровно из size байт и завершающий её \r\n
+func readBulk(r *bufio.Reader, size int) ([]byte, error) {
+	buf := make([]byte, size+len(crlf))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: bulk string is shorter than %d bytes", ErrMalformedResponse, size)
+		}
+		return nil, fmt.Errorf("can't read bulk string: %w", err)
+	}
+
+	if !bytes.HasSuffix(buf, crlf) {
+		return nil, fmt.Errorf("%w: bulk string is not terminated with CRLF", ErrMalformedResponse)
+	}
+
+	return buf[:size], nil
+}
+
+// replyInt преобразует ответ в число. Здесь и далее ответ может быть ошибкой, прочитанной в составе
+// массива или конвейера: тогда она и возвращается
+func replyInt(reply interface{}) (int64, error) {
+	switch value := reply.(type) {
+	case int64:
+		return value, nil
+	case error:
+		return 0, value
+	}
+	return 0, fmt.Errorf("%w: expected integer, got %T", ErrMalformedResponse, reply)
+}
+
+// replyBytes преобразует ответ в строку. Если значения нет, возвращается ErrNotFound
+func replyBytes(reply interface{}) ([]byte, error) {
+	switch value := reply.(type) {
+	case []byte:
+		return value, nil
+	case nil:
+		return nil, ErrNotFound
+	case error:
+		return nil, value
+	}
+	return nil, fmt.Errorf("%w: expected bulk string, got %T", ErrMalformedResponse, reply)
+}
+
+// replyStatus проверяет, что ответ - простая строка status (например, OK)
+func replyStatus(reply interface{}, status string) error {
+	if err, ok := reply.(error); ok {
+		return err
+	}
+	if value, ok := reply.(string); !ok || value != status {
+		return fmt.Errorf("%w: expected %s, got %v", ErrMalformedResponse, status, reply)
+	}
+	return nil
+}
+
+// parseInfo разбирает ответ команды INFO: строки "<name>:<value>" и заголовки секций "# <section>"
+func parseInfo(info []byte) map[string]string {
+	result := make(map[string]string)
+	for _, line := range bytes.Split(info, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		name, value, ok := bytes.Cut(line, []byte(":"))
+		if ok && len(name) > 0 {
+			result[string(name)] = string(value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"hash/crc32"
+	"hash/fnv"
+	"math"
+	"net"
+	"strings"
+)
+
+// ServerSelector выбирает сервер Redis, на котором хранится ключ
+type ServerSelector interface {
+	PickServer(key string) net.Addr
+}
+
+// hashTag возвращает часть ключа, по которой выбирается сервер. Как и в Redis Cluster, если в ключе есть
+// непустой тег в фигурных скобках ("{user:1}:profile"), то учитывается только он. Так ключи с одинаковым тегом
+// гарантированно оказываются на одном сервере
+func hashTag(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+
+	return key[start+1 : start+1+end]
+}
+
+// ModuloSelector выбирает сервер как остаток от деления CRC32 ключа на количество серверов.
+// При добавлении или удалении сервера почти все ключи переезжают на другие серверы
+type ModuloSelector struct {
+	servers []net.Addr
+}
+
+// NewModuloSelector создаёт ModuloSelector
+func NewModuloSelector(servers []net.Addr) *ModuloSelector {
+	return &ModuloSelector{
+		servers: servers,
+	}
+}
+
+// PickServer возвращает адрес сервера Redis для ключа
+func (s *ModuloSelector) PickServer(key string) net.Addr {
+	return s.servers[crc32.ChecksumIEEE([]byte(hashTag(key)))%uint32(len(s.servers))]
+}
+
+// rendezvousServer сервер с заранее посчитанным хешем адреса
+type rendezvousServer struct {
+	addr   net.Addr
+	hash   uint64
+	weight float64
+}
+
+// RendezvousSelector выбирает сервер с помощью взвешенного рандеву-хеширования (Highest Random Weight):
+// для каждого сервера считается оценка, зависящая от ключа и адреса сервера, и выбирается сервер с наибольшей.
+// При удалении сервера на другие серверы переезжают только его ключи, а при добавлении - около 1/N ключей
+type RendezvousSelector struct {
+	servers []rendezvousServer
+}
+
+// NewRendezvousSelector создаёт RendezvousSelector. В weights указываются веса серверов (ключ - адрес сервера
+// в формате addr.String()). Серверы, для которых вес не указан или не положителен, получают вес 1
+func NewRendezvousSelector(servers []net.Addr, weights map[string]int) *RendezvousSelector {
+	selector := &RendezvousSelector{
+		servers: make([]rendezvousServer, len(servers)),
+	}
+	for i, server := range servers {
+		weight := 1
+		if w, ok := weights[server.String()]; ok && w > 0 {
+			weight = w
+		}
+
+		selector.servers[i] = rendezvousServer{
+			addr:   server,
+			hash:   fnv64(server.String()),
+			weight: float64(weight),
+		}
+	}
+
+	return selector
+}
+
+// PickServer возвращает адрес сервера Redis для ключа
+func (s *RendezvousSelector) PickServer(key string) net.Addr {
+	keyHash := fnv64(hashTag(key))
+
+	var (
+		best      net.Addr
+		bestScore = math.Inf(-1)
+	)
+	for _, server := range s.servers {
+		// Хеш превращается в число из интервала (0, 1), а оценка -w/ln(x) даёт каждому серверу
+		// долю ключей, пропорциональную его весу
+		x := (float64(mix64(keyHash^server.hash)>>11) + 0.5) / (1 << 53)
+		score := -server.weight / math.Log(x)
+		if score > bestScore {
+			best, bestScore = server.addr, score
+		}
+	}
+
+	return best
+}
+
+// fnv64 возвращает 64-битный хеш FNV-1a строки
+func fnv64(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mix64 перемешивает биты числа (финализатор splitmix64), чтобы близкие значения давали далёкие хеши
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testServers возвращает count адресов серверов
+func testServers(count int) []net.Addr {
+	servers := make([]net.Addr, 0, count)
+	for i := 0; i < count; i++ {
+		servers = append(servers, &net.TCPAddr{
+			IP:   net.IPv4(10, 0, 0, byte(i+1)),
+			Port: 6379,
+		})
+	}
+	return servers
+}
+
+// movedKeysShare возвращает долю ключей, которые после смены селектора попали на другой сервер
+func movedKeysShare(before, after ServerSelector, keysCount int) float64 {
+	moved := 0
+	for i := 0; i < keysCount; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if before.PickServer(key).String() != after.PickServer(key).String() {
+			moved++
+		}
+	}
+	return float64(moved) / float64(keysCount)
+}
+
+func TestRendezvousSelector_MembershipChange(t *testing.T) {
+	const keysCount = 100000
+
+	tests := []struct {
+		name   string
+		before []net.Addr
+		after  []net.Addr
+		want   float64
+	}{
+		{
+			name:   "add server",
+			before: testServers(10),
+			after:  testServers(11),
+			want:   1.0 / 11,
+		},
+		{
+			name:   "remove server",
+			before: testServers(10),
+			after:  testServers(9),
+			want:   1.0 / 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moved := movedKeysShare(
+				NewRendezvousSelector(tt.before, nil),
+				NewRendezvousSelector(tt.after, nil),
+				keysCount)
+			assert.InDelta(t, tt.want, moved, 0.01)
+		})
+	}
+}
+
+func TestModuloSelector_MembershipChange(t *testing.T) {
+	moved := movedKeysShare(NewModuloSelector(testServers(10)), NewModuloSelector(testServers(11)), 100000)
+	assert.Greater(t, moved, 0.8)
+}
+
+func TestRendezvousSelector_Distribution(t *testing.T) {
+	const keysCount = 100000
+
+	servers := testServers(3)
+	selector := NewRendezvousSelector(servers, map[string]int{
+		servers[0].String(): 2,
+	})
+
+	counts := make(map[string]int)
+	for i := 0; i < keysCount; i++ {
+		counts[selector.PickServer(fmt.Sprintf("key-%d", i)).String()]++
+	}
+
+	// Сервер с весом 2 получает половину ключей, остальные - по четверти
+	assert.InDelta(t, 0.5, float64(counts[servers[0].String()])/keysCount, 0.02)
+	assert.InDelta(t, 0.25, float64(counts[servers[1].String()])/keysCount, 0.02)
+	assert.InDelta(t, 0.25, float64(counts[servers[2].String()])/keysCount, 0.02)
+}
+
+func TestSelector_HashTag(t *testing.T) {
+	servers := testServers(10)
+	for _, selector := range []ServerSelector{NewModuloSelector(servers), NewRendezvousSelector(servers, nil)} {
+		for i := 0; i < 100; i++ {
+			tag := fmt.Sprintf("user:%d", i)
+			want := selector.PickServer(tag)
+			assert.Equal(t, want, selector.PickServer("{"+tag+"}:profile"))
+			assert.Equal(t, want, selector.PickServer("session:{"+tag+"}"))
+		}
+	}
+}
+
+func TestHashTag(t *testing.T) {
+	tests := map[string]string{
+		"key":             "key",
+		"{user}:profile":  "user",
+		"a{user}b{other}": "user",
+		"{}:profile":      "{}:profile",
+		"{user":           "{user",
+		"}{user}":         "user",
+	}
+	for key, want := range tests {
+		assert.Equal(t, want, hashTag(key), key)
+	}
+}
@@ -0,0 +1,477 @@
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type (
+	// fakeStatus простая строка ответа ("+OK")
+	fakeStatus string
+	// fakeError ответ с ошибкой ("-ERR ...")
+	fakeError string
+)
+
+// fakeItem запись, хранящаяся в fakeServer
+type fakeItem struct {
+	value []byte
+	// Время истечения (нулевое - бессрочная запись)
+	expireAt time.Time
+}
+
+// fakeServer простейший Redis-сервер, работающий внутри процесса тестов. Поддерживает только команды,
+// которые использует клиент. Lua-скрипты не выполняются: EVAL поддерживает только updateScript,
+// результат которого вычисляется на Go
+type fakeServer struct {
+	listener net.Listener
+	mx       sync.Mutex
+	items    map[string]*fakeItem
+	// Пароль, который нужно передать командой AUTH (пусто - без аутентификации)
+	password string
+	// Количество принятых соединений
+	accepted atomic.Int32
+	// Номер базы данных, выбранной последней командой SELECT
+	selectedDB atomic.Int32
+	// Открытые соединения
+	conns map[net.Conn]struct{}
+}
+
+// fakeSession состояние одного соединения с fakeServer
+type fakeSession struct {
+	authenticated bool
+}
+
+// newFakeServer запускает fakeServer на случайном порту
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can't start fake server: %v", err)
+	}
+
+	srv := &fakeServer{
+		listener: listener,
+		items:    make(map[string]*fakeItem),
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	go srv.serve(srv.handle)
+	return srv
+}
+
+// newRawServer запускает сервер, который на первую команду отвечает заранее заданными байтами и закрывает соединение
+func newRawServer(t *testing.T, reply []byte) net.Addr {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can't start raw server: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	srv := &fakeServer{listener: listener}
+	go srv.serve(func(rw *bufio.ReadWriter, _ *fakeSession) error {
+		if _, err := readFakeCommand(rw.Reader); err != nil {
+			return err
+		}
+		if _, err := rw.Write(reply); err != nil {
+			return err
+		}
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		return io.EOF
+	})
+
+	return listener.Addr()
+}
+
+// newSilentServer запускает сервер, который принимает команды, но никогда на них не отвечает
+func newSilentServer(t *testing.T) net.Addr {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can't start silent server: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	srv := &fakeServer{listener: listener}
+	go srv.serve(func(rw *bufio.ReadWriter, _ *fakeSession) error {
+		_, err := readFakeCommand(rw.Reader)
+		return err
+	})
+
+	return listener.Addr()
+}
+
+// newTestClient создаёт клиента, подключенного к указанным серверам
+func newTestClient(servers ...net.Addr) *Client {
+	return NewRedisClient(NewConfig(servers, 1, time.Second))
+}
+
+// Addr возвращает адрес, на котором слушает сервер
+func (s *fakeServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// closeConnections закрывает все принятые сервером соединения, как при перезапуске сервера
+func (s *fakeServer) closeConnections() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// connectionsCount возвращает количество открытых соединений на стороне сервера
+func (s *fakeServer) connectionsCount() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return len(s.conns)
+}
+
+// keysCount возвращает количество записей на сервере
+func (s *fakeServer) keysCount() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return len(s.items)
+}
+
+// serve принимает соединения и обрабатывает команды до закрытия соединения
+func (s *fakeServer) serve(handle func(rw *bufio.ReadWriter, session *fakeSession) error) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.accepted.Add(1)
+
+		s.mx.Lock()
+		if s.conns == nil {
+			s.conns = make(map[net.Conn]struct{})
+		}
+		s.conns[conn] = struct{}{}
+		s.mx.Unlock()
+
+		go func(conn net.Conn) {
+			defer func() {
+				s.mx.Lock()
+				delete(s.conns, conn)
+				s.mx.Unlock()
+				conn.Close()
+			}()
+			rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+			session := &fakeSession{}
+			for {
+				if err := handle(rw, session); err != nil {
+					return
+				}
+			}
+		}(conn)
+	}
+}
+
+// readFakeCommand читает команду в виде массива строк RESP
+func readFakeCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected line %q", line)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+
+	return args, nil
+}
+
+// writeFakeReply записывает ответ в формате RESP
+func writeFakeReply(w *bufio.Writer, reply interface{}) {
+	switch v := reply.(type) {
+	case fakeStatus:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case fakeError:
+		fmt.Fprintf(w, "-%s\r\n", v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case []byte:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, element := range v {
+			writeFakeReply(w, element)
+		}
+	case nil:
+		w.WriteString("$-1\r\n")
+	}
+}
+
+// handle обрабатывает одну команду
+func (s *fakeServer) handle(rw *bufio.ReadWriter, session *fakeSession) error {
+	args, err := readFakeCommand(rw.Reader)
+	if err != nil {
+		return err
+	}
+
+	writeFakeReply(rw.Writer, s.execute(session, args))
+	if rw.Reader.Buffered() > 0 {
+		// Клиент отправил команды конвейером: ответы отправятся вместе
+		return nil
+	}
+	return rw.Flush()
+}
+
+// execute выполняет команду в рамках сессии и возвращает ответ
+func (s *fakeServer) execute(session *fakeSession, args []string) interface{} {
+	if len(args) == 0 {
+		return fakeError("ERR empty command")
+	}
+
+	name := strings.ToUpper(args[0])
+	if name == "AUTH" {
+		if len(args) != 2 || args[1] != s.password {
+			return fakeError("WRONGPASS invalid username-password pair or user is disabled.")
+		}
+		session.authenticated = true
+		return fakeStatus("OK")
+	}
+	if s.password != "" && !session.authenticated {
+		return fakeError("NOAUTH Authentication required.")
+	}
+
+	if name == "SELECT" {
+		db, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fakeError("ERR value is not an integer or out of range")
+		}
+		s.selectedDB.Store(int32(db))
+		return fakeStatus("OK")
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.command(name, args[1:])
+}
+
+// expire удаляет запись, если её время жизни истекло. Вызывается под мьютексом
+func (s *fakeServer) expire(key string) {
+	item, ok := s.items[key]
+	if ok && !item.expireAt.IsZero() && !time.Now().Before(item.expireAt) {
+		delete(s.items, key)
+	}
+}
+
+// lookup возвращает живую запись. Вызывается под мьютексом
+func (s *fakeServer) lookup(key string) (*fakeItem, bool) {
+	s.expire(key)
+	item, ok := s.items[key]
+	return item, ok
+}
+
+// command выполняет команду работы с данными. Вызывается под мьютексом
+func (s *fakeServer) command(name string, args []string) interface{} {
+	switch name {
+	case "PING":
+		return fakeStatus("PONG")
+	case "GET":
+		if item, ok := s.lookup(args[0]); ok {
+			return item.value
+		}
+		return nil
+	case "MGET":
+		values := make([]interface{}, len(args))
+		for i, key := range args {
+			if item, ok := s.lookup(key); ok {
+				values[i] = item.value
+			}
+		}
+		return values
+	case "SET":
+		return s.set(args)
+	case "GETEX":
+		item, ok := s.lookup(args[0])
+		if !ok {
+			return nil
+		}
+		if len(args) == 3 && strings.ToUpper(args[1]) == "PX" {
+			ms, _ := strconv.ParseInt(args[2], 10, 64)
+			item.expireAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		} else if len(args) == 2 && strings.ToUpper(args[1]) == "PERSIST" {
+			item.expireAt = time.Time{}
+		}
+		return item.value
+	case "PTTL":
+		item, ok := s.lookup(args[0])
+		switch {
+		case !ok:
+			return int64(-2)
+		case item.expireAt.IsZero():
+			return int64(-1)
+		}
+		return time.Until(item.expireAt).Milliseconds()
+	case "PEXPIRE":
+		item, ok := s.lookup(args[0])
+		if !ok {
+			return int64(0)
+		}
+		ms, _ := strconv.ParseInt(args[1], 10, 64)
+		item.expireAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		return int64(1)
+	case "PERSIST":
+		item, ok := s.lookup(args[0])
+		if !ok || item.expireAt.IsZero() {
+			return int64(0)
+		}
+		item.expireAt = time.Time{}
+		return int64(1)
+	case "EXISTS":
+		var count int64
+		for _, key := range args {
+			if _, ok := s.lookup(key); ok {
+				count++
+			}
+		}
+		return count
+	case "DEL":
+		var count int64
+		for _, key := range args {
+			if _, ok := s.lookup(key); ok {
+				delete(s.items, key)
+				count++
+			}
+		}
+		return count
+	case "EVAL":
+		return s.eval(args)
+	case "INFO":
+		size, expires := 0, 0
+		for key := range s.items {
+			item, ok := s.lookup(key)
+			if !ok {
+				continue
+			}
+			size += len(item.value)
+			if !item.expireAt.IsZero() {
+				expires++
+			}
+		}
+		return []byte(fmt.Sprintf("# Server\r\nredis_version:7.2.0\r\n\r\n# Memory\r\nused_memory:%d\r\n\r\n"+
+			"# Stats\r\nexpired_keys:0\r\nevicted_keys:0\r\n\r\n# Keyspace\r\ndb0:keys=%d,expires=%d,avg_ttl=0\r\n",
+			size, len(s.items), expires))
+	}
+
+	return fakeError(fmt.Sprintf("ERR unknown command '%s'", name))
+}
+
+// eval выполняет команду EVAL для updateScript. Вызывается под мьютексом
+func (s *fakeServer) eval(args []string) interface{} {
+	if len(args) != 5 || args[0] != updateScript || args[1] != "1" {
+		return fakeError("ERR unsupported script")
+	}
+
+	item, ok := s.lookup(args[2])
+	if !ok {
+		return nil
+	}
+	value, err := strconv.ParseUint(string(item.value), 10, 64)
+	if err != nil {
+		return fakeStatus(nonNumericStatus)
+	}
+	delta, _ := strconv.ParseUint(args[3], 10, 64)
+
+	switch {
+	case args[4] == "incr":
+		value += delta
+	case value < delta:
+		value = 0
+	default:
+		value -= delta
+	}
+	item.value = []byte(strconv.FormatUint(value, 10))
+	return item.value
+}
+
+// set выполняет команду SET с параметрами PX, EX, NX, XX и KEEPTTL. Вызывается под мьютексом
+func (s *fakeServer) set(args []string) interface{} {
+	key, value := args[0], []byte(args[1])
+	current, exists := s.lookup(key)
+
+	var (
+		expireAt time.Time
+		keepTTL  bool
+	)
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			if exists {
+				return nil
+			}
+		case "XX":
+			if !exists {
+				return nil
+			}
+		case "KEEPTTL":
+			keepTTL = true
+		case "PX", "EX":
+			amount, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || amount <= 0 {
+				return fakeError("ERR invalid expire time in 'set' command")
+			}
+			unit := time.Millisecond
+			if strings.ToUpper(args[i]) == "EX" {
+				unit = time.Second
+			}
+			expireAt = time.Now().Add(time.Duration(amount) * unit)
+			i++
+		default:
+			return fakeError("ERR syntax error")
+		}
+	}
+
+	if keepTTL && exists {
+		expireAt = current.expireAt
+	}
+	s.items[key] = &fakeItem{value: value, expireAt: expireAt}
+	return fakeStatus("OK")
+}
@@ -4,18 +4,115 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"os"
+	"time"
 )
 
 type Config struct {
 	// Порт, на котором запустится GRPC-сервер
 	GrpcPort int `yaml:"grpc_port"`
+	// Порт, на котором запустится HTTP-сервер с метриками в формате Prometheus по пути /metrics (0 - не запускать)
+	MetricsPort int `yaml:"metrics_port"`
+	// Порт, на котором запустится сервер, работающий по текстовому протоколу Memcache (0 - не запускать)
+	MemcachedPort int `yaml:"memcached_port"`
+	// Максимальная длина строки команды протокола Memcache в байтах (0 - по умолчанию 64 KiB)
+	MemcachedMaxLineLength int `yaml:"memcached_max_line_length"`
+	// Максимальный размер значения, принимаемого по протоколу Memcache, в байтах (0 - по умолчанию 1 MiB)
+	MemcachedMaxValueSize int `yaml:"memcached_max_value_size"`
+	// Время, после которого соединение по протоколу Memcache без команд закрывается (например, 5m; 0s - без ограничений)
+	MemcachedIdleTimeout time.Duration `yaml:"memcached_idle_timeout"`
+	// Порт, на котором запустится сервер, работающий по протоколу Redis (RESP2 и RESP3; 0 - не запускать)
+	RedisPort int `yaml:"redis_port"`
+	// Максимальный размер одного аргумента команды Redis в байтах (0 - по умолчанию 1 MiB)
+	RedisMaxBulkLength int `yaml:"redis_max_bulk_length"`
+	// Время, после которого соединение по протоколу Redis без команд закрывается (например, 5m; 0s - без ограничений)
+	RedisIdleTimeout time.Duration `yaml:"redis_idle_timeout"`
+	// Адрес, на котором запустится HTTP-сервер с REST API (например, :8080 или 127.0.0.1:8080; пусто - не запускать)
+	HTTPAddress string `yaml:"http_address"`
+	// Период проверки работоспособности хранилища для сервиса grpc.health.v1.Health (например, 5s; 0s - по умолчанию 5s)
+	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
 	// Уровни логирования (debug, info, warn, error)
 	Loglevel string `yaml:"loglevel"`
-	// Тип используемого хранилища (memcache или любое другое значения для использования встроенного кеша)
+	// Тип используемого хранилища (memcache, redis, tiered - встроенный кеш перед Memcache, или любое другое значения
+	// для использования встроенного кеша)
 	Storage string `yaml:"storage"`
-	// Список серверов Memcache (при использовании storage != memcache можно не указывать)
+	// Способ сохранения встроенного кеша на диск: none (по умолчанию), snapshot (периодические снимки)
+	// или aof (журнал всех изменений)
+	Persistence string `yaml:"persistence"`
+	// Список серверов Memcache версии 1.6 и новее (при использовании storage != memcache можно не указывать)
 	// Для упрощения тут поддерживается только TCP, unix-сокеты - нет
 	MemcacheServers []string `yaml:"memcache_servers"`
+	// Способ выбора сервера Memcache для ключа: modulo (по умолчанию) или ketama (консистентное хеширование).
+	// При смене способа почти все ключи переезжают на другие серверы, поэтому кеш фактически очищается
+	MemcacheHashing string `yaml:"memcache_hashing"`
+	// Веса серверов Memcache для консистентного хеширования (ключ - адрес из memcache_servers, по умолчанию вес 1)
+	MemcacheWeights map[string]int `yaml:"memcache_weights"`
+	// Максимальное количество открытых соединений с одним сервером Memcache (0 - без ограничений)
+	MemcacheMaxOpen int `yaml:"memcache_max_open"`
+	// Время, после которого неиспользуемое соединение с Memcache закрывается (например, 5m; 0s - без ограничений)
+	MemcacheIdleTimeout time.Duration `yaml:"memcache_idle_timeout"`
+	// Максимальное время жизни соединения с Memcache (например, 1h; 0s - без ограничений)
+	MemcacheMaxLifetime time.Duration `yaml:"memcache_max_lifetime"`
+	// Список серверов Redis (при использовании storage != redis можно не указывать)
+	RedisServers []string `yaml:"redis_servers"`
+	// Пароль Redis (пусто - без аутентификации)
+	RedisPassword string `yaml:"redis_password"`
+	// Номер базы данных Redis
+	RedisDB int `yaml:"redis_db"`
+	// Способ выбора сервера Redis для ключа: rendezvous (рандеву-хеширование, по умолчанию) или modulo
+	RedisHashing string `yaml:"redis_hashing"`
+	// Веса серверов Redis для рандеву-хеширования (ключ - адрес из redis_servers, по умолчанию вес 1)
+	RedisWeights map[string]int `yaml:"redis_weights"`
+	// Максимальное количество одновременно используемых соединений с одним сервером Redis (0 - без ограничений)
+	RedisMaxActive int `yaml:"redis_max_active"`
+	// Время, после которого неиспользуемое соединение с Redis закрывается (например, 5m; 0s - без ограничений)
+	RedisConnIdleTimeout time.Duration `yaml:"redis_conn_idle_timeout"`
+	// Максимальное время жизни соединения с Redis (например, 1h; 0s - без ограничений)
+	RedisConnMaxLifetime time.Duration `yaml:"redis_conn_max_lifetime"`
+	// Максимальный суммарный размер записей встроенного кеша в байтах с учётом ключей и накладных расходов
+	// (0 - без ограничений)
+	EmbeddedMaxBytes int64 `yaml:"embedded_max_bytes"`
+	// Максимальное количество записей встроенного кеша (0 - без ограничений)
+	EmbeddedMaxItems int `yaml:"embedded_max_items"`
+	// Политика вытеснения записей встроенного кеша при превышении бюджета: lru (по умолчанию), lfu или tinylfu
+	EmbeddedEvictionPolicy string `yaml:"embedded_eviction_policy"`
+	// Количество сегментов встроенного кеша со своими блокировками, округляется вверх до степени двойки
+	// (0 - по умолчанию 16). Бюджет кеша делится между сегментами поровну
+	EmbeddedShards int `yaml:"embedded_shards"`
+	// Файл снимка встроенного кеша (persistence: snapshot). Снимок загружается при запуске и сохраняется
+	// периодически и при остановке
+	EmbeddedSnapshotPath string `yaml:"embedded_snapshot_path"`
+	// Период сохранения снимка встроенного кеша (например, 1m; 0s - только при остановке)
+	EmbeddedSnapshotInterval time.Duration `yaml:"embedded_snapshot_interval"`
+	// Файл журнала изменений встроенного кеша (persistence: aof). Журнал воспроизводится при запуске
+	EmbeddedAOFPath string `yaml:"embedded_aof_path"`
+	// Когда журнал сбрасывается на диск: always (после каждой записи), everysec (раз в секунду, по умолчанию)
+	// или no (на усмотрение операционной системы)
+	EmbeddedAOFFsync string `yaml:"embedded_aof_fsync"`
+	// Размер журнала в байтах, до которого он не перезаписывается (0 - по умолчанию 64 MiB)
+	EmbeddedAOFRewriteMinSize int64 `yaml:"embedded_aof_rewrite_min_size"`
+	// На сколько процентов журнал должен вырасти с последней перезаписи, чтобы его перезаписать
+	// (0 - по умолчанию 100, отрицательное значение отключает перезапись)
+	EmbeddedAOFRewritePercent int `yaml:"embedded_aof_rewrite_percent"`
+	// Размер буфера событий одного подписчика Watch встроенного кеша (0 - по умолчанию 1024)
+	EmbeddedWatchBuffer int `yaml:"embedded_watch_buffer"`
+	// Что делать, если подписчик Watch не успевает читать события: drop-oldest (отбрасывать самые старые,
+	// по умолчанию) или disconnect (отключать подписчика)
+	EmbeddedWatchOverflow string `yaml:"embedded_watch_overflow"`
+	// Максимальный суммарный размер записей L1 (встроенного кеша перед Memcache при storage: tiered) в байтах
+	// (0 - без ограничений). Политика вытеснения и количество сегментов такие же, как у встроенного кеша
+	TieredL1MaxBytes int64 `yaml:"tiered_l1_max_bytes"`
+	// Максимальное количество записей L1 (0 - без ограничений)
+	TieredL1MaxItems int `yaml:"tiered_l1_max_items"`
+	// Максимальное время жизни записи в L1 (0s - по умолчанию 5s). Дольше этого времени L1 не может
+	// возвращать значение, изменённое в Memcache другим экземпляром сервиса
+	TieredL1MaxTTL time.Duration `yaml:"tiered_l1_max_ttl"`
+	// Максимальное время жизни записи в Memcache при storage: tiered (0s - без ограничений)
+	TieredL2MaxTTL time.Duration `yaml:"tiered_l2_max_ttl"`
+	// Время, в течение которого L1 помнит об отсутствии записи в Memcache (0s - промахи не кешируются)
+	TieredNegativeTTL time.Duration `yaml:"tiered_negative_ttl"`
+	// Что делать с записью L1 при изменении значения: invalidate (удалять, по умолчанию) или write-through
+	// (записывать новое значение)
+	TieredWritePolicy string `yaml:"tiered_write_policy"`
 }
 
 // NewConfig инициализирует конфиг
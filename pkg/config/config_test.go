@@ -0,0 +1,23 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfig_Sample(t *testing.T) {
+	// Сервис должен запускаться с шаблоном конфигурации без изменений
+	cfg, err := NewConfig("../../config.yml.dist")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), cfg.MemcachedIdleTimeout)
+	assert.Equal(t, time.Duration(0), cfg.TieredL2MaxTTL)
+	assert.Equal(t, time.Second, cfg.TieredNegativeTTL)
+
+	// Необязательные серверы по умолчанию не запускаются
+	assert.Zero(t, cfg.MetricsPort)
+	assert.Zero(t, cfg.MemcachedPort)
+	assert.Zero(t, cfg.RedisPort)
+	assert.Empty(t, cfg.HTTPAddress)
+}
@@ -0,0 +1,133 @@
+package embedded
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dimuska139/cacher/internal/cache/embedded"
+	"github.com/dimuska139/cacher/pkg/config"
+	"io/fs"
+	"time"
+)
+
+// CleanupInterval период удаления записей с истёкшим временем жизни
+const CleanupInterval = time.Millisecond * 50
+
+const (
+	PersistenceNone     = "none"
+	PersistenceSnapshot = "snapshot"
+	PersistenceAOF      = "aof"
+)
+
+// Logger интерфейс для логгера
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// NewStorage инициирует кеш внутри памяти приложения
+func NewStorage(config *config.Config) (*embedded.EmbeddedStorage, error) {
+	policyName := config.EmbeddedEvictionPolicy
+	if policyName == "" {
+		policyName = embedded.DefaultEvictionPolicy
+	}
+
+	policy, err := embedded.NewEvictionPolicyFactory(policyName)
+	if err != nil {
+		return nil, fmt.Errorf("can't create eviction policy: %w", err)
+	}
+
+	storageConfig := embedded.NewConfig(CleanupInterval).
+		WithMaxBytes(config.EmbeddedMaxBytes).
+		WithMaxItems(config.EmbeddedMaxItems).
+		WithEvictionPolicy(policy).
+		WithShards(config.EmbeddedShards).
+		WithWatchBuffer(config.EmbeddedWatchBuffer)
+
+	if config.EmbeddedWatchOverflow != "" {
+		overflow, err := embedded.ParseOverflowPolicy(config.EmbeddedWatchOverflow)
+		if err != nil {
+			return nil, err
+		}
+		storageConfig.WithWatchOverflow(overflow)
+	}
+
+	return embedded.NewEmbeddedStorage(storageConfig), nil
+}
+
+// checkPersistence проверяет способ сохранения встроенного кеша на диск
+func checkPersistence(config *config.Config) error {
+	switch config.Persistence {
+	case "", PersistenceNone, PersistenceSnapshot, PersistenceAOF:
+		return nil
+	}
+	return fmt.Errorf("unknown persistence: %s", config.Persistence)
+}
+
+// NewSnapshotter загружает снимок встроенного кеша, если он есть, и создаёт Snapshotter, сохраняющий новые снимки.
+// Если снимки не используются (persistence != snapshot), возвращает nil. Повреждённый снимок не мешает запуску:
+// кеш остаётся пустым
+func NewSnapshotter(config *config.Config, storage *embedded.EmbeddedStorage, logger Logger) (*embedded.Snapshotter, error) {
+	if err := checkPersistence(config); err != nil {
+		return nil, err
+	}
+	if config.Persistence != PersistenceSnapshot {
+		return nil, nil
+	}
+	if config.EmbeddedSnapshotPath == "" {
+		return nil, errors.New("snapshot path is not set")
+	}
+
+	loaded, err := storage.LoadSnapshotFile(config.EmbeddedSnapshotPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		logger.Info("Snapshot not found, starting with empty cache", "path", config.EmbeddedSnapshotPath)
+	case err != nil:
+		logger.Error("Can't load snapshot, starting with empty cache", "err", err, "path", config.EmbeddedSnapshotPath)
+	default:
+		logger.Info("Snapshot loaded", "items", loaded, "path", config.EmbeddedSnapshotPath)
+	}
+
+	return embedded.NewSnapshotter(storage, config.EmbeddedSnapshotPath, config.EmbeddedSnapshotInterval, logger), nil
+}
+
+// NewAppendOnlyLog воспроизводит журнал изменений встроенного кеша и подключает его к кешу.
+// Если журнал не используется (persistence != aof), возвращает nil. Журнал, повреждённый не в последней записи,
+// не позволяет запуститься, чтобы не потерять следующие за повреждением изменения
+func NewAppendOnlyLog(config *config.Config, storage *embedded.EmbeddedStorage, logger Logger) (*embedded.AppendOnlyLog, error) {
+	if err := checkPersistence(config); err != nil {
+		return nil, err
+	}
+	if config.Persistence != PersistenceAOF {
+		return nil, nil
+	}
+	if config.EmbeddedAOFPath == "" {
+		return nil, errors.New("append-only log path is not set")
+	}
+
+	aofConfig := embedded.NewAOFConfig(config.EmbeddedAOFPath)
+	if config.EmbeddedAOFFsync != "" {
+		fsync, err := embedded.ParseFsyncPolicy(config.EmbeddedAOFFsync)
+		if err != nil {
+			return nil, err
+		}
+		aofConfig.WithFsync(fsync)
+	}
+	if config.EmbeddedAOFRewriteMinSize > 0 {
+		aofConfig.WithRewriteMinSize(config.EmbeddedAOFRewriteMinSize)
+	}
+	switch {
+	case config.EmbeddedAOFRewritePercent > 0:
+		aofConfig.WithRewritePercent(config.EmbeddedAOFRewritePercent)
+	case config.EmbeddedAOFRewritePercent < 0:
+		aofConfig.WithRewritePercent(0)
+	}
+
+	log := embedded.NewAppendOnlyLog(storage, aofConfig, logger)
+	replayed, err := log.Open()
+	if err != nil {
+		return nil, fmt.Errorf("can't open append-only log: %w", err)
+	}
+	logger.Info("Append-only log replayed", "operations", replayed, "path", config.EmbeddedAOFPath)
+
+	return log, nil
+}
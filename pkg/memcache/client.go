@@ -1,16 +1,26 @@
 package memcache
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/dimuska139/cacher/internal/cache/memcache"
 	memcacheClient "github.com/dimuska139/cacher/libs/memcache"
 	"github.com/dimuska139/cacher/pkg/config"
 	"net"
 	"time"
 )
 
+const (
+	HashingKetama  = "ketama"
+	HashingModulo  = "modulo"
+	DefaultHashing = HashingModulo
+)
+
 // NewClient инициирует библиотеку для работы с Memcache
 func NewClient(config *config.Config) (*memcacheClient.Client, error) {
 	srvs := make([]net.Addr, 0, len(config.MemcacheServers))
+	weights := make(map[string]int, len(config.MemcacheWeights))
 	// Для упрощения тут поддерживается только TCP, unix-сокеты - нет
 	for _, addr := range config.MemcacheServers {
 		tcpaddr, err := net.ResolveTCPAddr("tcp", addr)
@@ -18,8 +28,56 @@ func NewClient(config *config.Config) (*memcacheClient.Client, error) {
 			return nil, fmt.Errorf("can't resolve TCP address %s: %w", addr, err)
 		}
 		srvs = append(srvs, tcpaddr)
+
+		if weight, ok := config.MemcacheWeights[addr]; ok {
+			weights[tcpaddr.String()] = weight
+		}
+	}
+
+	memcacheClientConfig := memcacheClient.NewConfig(srvs, 5, time.Second).
+		WithMaxOpen(config.MemcacheMaxOpen).
+		WithIdleTimeout(config.MemcacheIdleTimeout).
+		WithMaxLifetime(config.MemcacheMaxLifetime)
+
+	hashing := config.MemcacheHashing
+	if hashing == "" {
+		hashing = DefaultHashing
+	}
+
+	switch hashing {
+	case HashingKetama:
+		memcacheClientConfig.WithServerSelector(memcacheClient.NewKetamaSelector(srvs, weights))
+	case HashingModulo:
+		memcacheClientConfig.WithServerSelector(memcacheClient.NewModuloSelector(srvs))
+	default:
+		return nil, fmt.Errorf("unknown memcache hashing: %s", hashing)
 	}
 
-	memcacheClientConfig := memcacheClient.NewConfig(srvs, 5, time.Second)
 	return memcacheClient.NewMemcacheClient(memcacheClientConfig), nil
 }
+
+// VersionCheckTimeout максимальное время проверки версии серверов Memcache при запуске
+const VersionCheckTimeout = 5 * time.Second
+
+// Logger интерфейс для логгера
+type Logger interface {
+	Error(msg string, args ...interface{})
+}
+
+// CheckVersion проверяет при запуске, что все серверы Memcache поддерживают мета-команды (Memcache 1.6 и новее).
+// Слишком старая версия - ошибка. Недоступность серверов запуску не мешает, так как её отслеживает
+// проверка работоспособности хранилища: в этом случае ошибка только логируется
+func CheckVersion(storage *memcache.MemcacheStorage, logger Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), VersionCheckTimeout)
+	defer cancel()
+
+	err := storage.CheckVersion(ctx)
+	if errors.Is(err, memcache.ErrUnsupportedVersion) {
+		return err
+	}
+	if err != nil {
+		logger.Error("Can't check memcache version", "err", err)
+	}
+
+	return nil
+}
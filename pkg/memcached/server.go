@@ -0,0 +1,21 @@
+package memcached
+
+import (
+	"github.com/dimuska139/cacher/internal/api/memcached"
+	"github.com/dimuska139/cacher/pkg/config"
+)
+
+// NewServer создаёт сервер, работающий по текстовому протоколу Memcache поверх хранилища.
+// Если порт для него в конфиге не указан, возвращает nil
+func NewServer(config *config.Config, storage memcached.Storage, logger memcached.Logger) *memcached.Server {
+	if config.MemcachedPort == 0 {
+		return nil
+	}
+
+	serverConfig := memcached.NewConfig().
+		WithMaxLineLength(config.MemcachedMaxLineLength).
+		WithMaxValueSize(config.MemcachedMaxValueSize).
+		WithIdleTimeout(config.MemcachedIdleTimeout)
+
+	return memcached.NewServer(serverConfig, storage, logger)
+}
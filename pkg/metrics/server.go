@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"fmt"
+	"github.com/dimuska139/cacher/internal/metrics"
+	"github.com/dimuska139/cacher/pkg/config"
+	"net/http"
+	"time"
+)
+
+// Path путь, по которому отдаются метрики
+const Path = "/metrics"
+
+// NewServer создаёт HTTP-сервер, отдающий метрики registry по пути /metrics.
+// Если порт для метрик в конфиге не указан, возвращает nil
+func NewServer(config *config.Config, registry *metrics.Registry) *http.Server {
+	if config.MetricsPort == 0 {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(Path, registry)
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", config.MetricsPort),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
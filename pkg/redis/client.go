@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"fmt"
+	redisClient "github.com/dimuska139/cacher/libs/redis"
+	"github.com/dimuska139/cacher/pkg/config"
+	"net"
+	"time"
+)
+
+const (
+	HashingRendezvous = "rendezvous"
+	HashingModulo     = "modulo"
+	DefaultHashing    = HashingRendezvous
+)
+
+// NewClient инициирует библиотеку для работы с Redis
+func NewClient(config *config.Config) (*redisClient.Client, error) {
+	if len(config.RedisServers) == 0 {
+		return nil, fmt.Errorf("no redis servers configured")
+	}
+
+	srvs := make([]net.Addr, 0, len(config.RedisServers))
+	weights := make(map[string]int, len(config.RedisWeights))
+	// Для упрощения тут поддерживается только TCP, unix-сокеты - нет
+	for _, addr := range config.RedisServers {
+		tcpaddr, err := net.ResolveTCPAddr("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("can't resolve TCP address %s: %w", addr, err)
+		}
+		srvs = append(srvs, tcpaddr)
+
+		if weight, ok := config.RedisWeights[addr]; ok {
+			weights[tcpaddr.String()] = weight
+		}
+	}
+
+	redisClientConfig := redisClient.NewConfig(srvs, 5, time.Second).
+		WithMaxActive(config.RedisMaxActive).
+		WithIdleTimeout(config.RedisConnIdleTimeout).
+		WithMaxLifetime(config.RedisConnMaxLifetime).
+		WithPassword(config.RedisPassword).
+		WithDB(config.RedisDB)
+
+	hashing := config.RedisHashing
+	if hashing == "" {
+		hashing = DefaultHashing
+	}
+
+	switch hashing {
+	case HashingRendezvous:
+		redisClientConfig.WithServerSelector(redisClient.NewRendezvousSelector(srvs, weights))
+	case HashingModulo:
+		redisClientConfig.WithServerSelector(redisClient.NewModuloSelector(srvs))
+	default:
+		return nil, fmt.Errorf("unknown redis hashing: %s", hashing)
+	}
+
+	return redisClient.NewRedisClient(redisClientConfig), nil
+}
@@ -0,0 +1,20 @@
+package resp
+
+import (
+	"github.com/dimuska139/cacher/internal/api/resp"
+	"github.com/dimuska139/cacher/pkg/config"
+)
+
+// NewServer создаёт сервер, работающий по протоколу Redis поверх хранилища.
+// Если порт для него в конфиге не указан, возвращает nil
+func NewServer(config *config.Config, storage resp.Storage, logger resp.Logger) *resp.Server {
+	if config.RedisPort == 0 {
+		return nil
+	}
+
+	serverConfig := resp.NewConfig().
+		WithMaxBulkLength(config.RedisMaxBulkLength).
+		WithIdleTimeout(config.RedisIdleTimeout)
+
+	return resp.NewServer(serverConfig, storage, logger)
+}
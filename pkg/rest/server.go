@@ -0,0 +1,22 @@
+package rest
+
+import (
+	"github.com/dimuska139/cacher/internal/api/rest"
+	"github.com/dimuska139/cacher/pkg/config"
+	"net/http"
+	"time"
+)
+
+// NewServer создаёт HTTP-сервер с REST API поверх сервиса кеширования.
+// Если адрес HTTP-сервера в конфиге не указан, возвращает nil
+func NewServer(config *config.Config, gateway *rest.Gateway) *http.Server {
+	if config.HTTPAddress == "" {
+		return nil
+	}
+
+	return &http.Server{
+		Addr:              config.HTTPAddress,
+		Handler:           gateway,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
@@ -0,0 +1,45 @@
+package tiered
+
+import (
+	"fmt"
+	"github.com/dimuska139/cacher/internal/cache/embedded"
+	"github.com/dimuska139/cacher/internal/cache/tiered"
+	"github.com/dimuska139/cacher/pkg/config"
+	"time"
+)
+
+// CleanupInterval период удаления записей L1 с истёкшим временем жизни
+const CleanupInterval = time.Millisecond * 50
+
+// NewStorage инициирует двухуровневый кеш: встроенный кеш (L1) перед хранилищем l2
+func NewStorage(config *config.Config, l2 tiered.L2Storage) (*tiered.TieredStorage, error) {
+	policyName := config.EmbeddedEvictionPolicy
+	if policyName == "" {
+		policyName = embedded.DefaultEvictionPolicy
+	}
+
+	policy, err := embedded.NewEvictionPolicyFactory(policyName)
+	if err != nil {
+		return nil, fmt.Errorf("can't create eviction policy: %w", err)
+	}
+
+	l1 := embedded.NewEmbeddedStorage(embedded.NewConfig(CleanupInterval).
+		WithMaxBytes(config.TieredL1MaxBytes).
+		WithMaxItems(config.TieredL1MaxItems).
+		WithEvictionPolicy(policy).
+		WithShards(config.EmbeddedShards))
+
+	storageConfig := tiered.NewConfig(config.TieredL1MaxTTL).
+		WithL2MaxTTL(config.TieredL2MaxTTL).
+		WithNegativeTTL(config.TieredNegativeTTL)
+
+	if config.TieredWritePolicy != "" {
+		writePolicy, err := tiered.ParseWritePolicy(config.TieredWritePolicy)
+		if err != nil {
+			return nil, err
+		}
+		storageConfig.WithWritePolicy(writePolicy)
+	}
+
+	return tiered.NewTieredStorage(storageConfig, l1, l2), nil
+}